@@ -0,0 +1,86 @@
+package vcard
+
+import (
+	"slices"
+	"strings"
+)
+
+// Value of ADR property as per https://datatracker.ietf.org/doc/html/rfc6350#section-6.3.1
+// e.g. ADR;TYPE=work;LABEL="123 Main St.\nAnytown":;;123 Main St.;Anytown;CA;91921;USA
+//
+// Adr implements [VCardFieldMarshaler] and [VCardFieldUnmarshaler], so it can be used as a type
+// of struct fields instead of string. See [Tel].
+type Adr struct {
+	POBox      string
+	Extended   string
+	Street     string
+	Locality   string
+	Region     string
+	PostalCode string
+	Country    string
+
+	// Values of TYPE parameter e.g. "work", "home" or "postal" of older versions.
+	Types []string
+
+	// Preference in range 1..100 where 1 is the most preferred. 0 means no preference.
+	Pref int
+
+	// Formatted delivery address of LABEL parameter of vCard 4.0 or LABEL property of older versions.
+	Label string
+}
+
+// Reports whether TYPE parameter of the address contains typ compared case-insensitively.
+func (a Adr) HasType(typ string) bool {
+	return slices.ContainsFunc(a.Types, func(s string) bool { return strings.EqualFold(s, typ) })
+}
+
+// Returns non-empty components joined with ", " e.g. "123 Main St., Anytown, CA, 91921, USA".
+func (a Adr) String() string {
+	parts := []string{}
+	for _, c := range []string{a.POBox, a.Extended, a.Street, a.Locality, a.Region, a.PostalCode, a.Country} {
+		if c != "" {
+			parts = append(parts, c)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Encodes the address as vCard 4.0 value with LABEL parameter.
+func (a Adr) MarshalVCardField() ([]byte, error) {
+	return a.MarshalVCardFieldVersion("4.0")
+}
+
+// Encodes the address e.g. ";TYPE=work;PREF=1:;;123 Main St.;Anytown;CA;91921;USA".
+// LABEL parameter is written only in vCard 4.0 because older versions use LABEL property.
+func (a Adr) MarshalVCardFieldVersion(version string) ([]byte, error) {
+	b := appendParam([]byte{}, "TYPE", a.Types...)
+	b = appendPref(b, a.Pref)
+	if a.Label != "" && version == "4.0" {
+		b = append(b, ";LABEL="...)
+		b = append(b, quoteParamValue(a.Label)...)
+	}
+	b = append(b, ':')
+	return append(b, JoinStructured([]string{a.POBox, a.Extended, a.Street, a.Locality, a.Region, a.PostalCode, a.Country})...), nil
+}
+
+// Decodes the address from ";TYPE=work:;;123 Main St.;Anytown;CA;91921;USA". Missing components are left empty.
+func (a *Adr) UnmarshalVCardField(data []byte) error {
+	params, value, _ := splitParamsValue(string(data))
+
+	c := SplitStructured(value)
+	for len(c) < 7 {
+		c = append(c, "")
+	}
+	*a = Adr{
+		POBox: c[0], Extended: c[1], Street: c[2], Locality: c[3], Region: c[4], PostalCode: c[5], Country: c[6],
+		Types: withoutValue(paramValues(params, "TYPE"), "pref"),
+		Pref:  paramPref(params),
+	}
+	if len(a.Types) == 0 {
+		a.Types = nil
+	}
+	if label := paramValues(params, "LABEL"); len(label) > 0 {
+		a.Label = label[0]
+	}
+	return nil
+}
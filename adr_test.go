@@ -0,0 +1,28 @@
+package vcard
+
+import "testing"
+
+func TestAdr(t *testing.T) {
+
+	a := Adr{}
+	err := a.UnmarshalVCardField([]byte(`;TYPE=work,pref;LABEL="123 Main St.^nAnytown":;;123 Main St.;Anytown;CA;91921;USA`))
+
+	assertEq(t, err, nil)
+	assertDeepEq(t, a, Adr{
+		Street: "123 Main St.", Locality: "Anytown", Region: "CA", PostalCode: "91921", Country: "USA",
+		Types: []string{"work"}, Pref: 1, Label: "123 Main St.\nAnytown",
+	})
+	assertStringsEq(t, a.String(), "123 Main St., Anytown, CA, 91921, USA")
+
+	b, err := a.MarshalVCardField()
+	assertEq(t, err, nil)
+	assertStringsEq(t, string(b), `;TYPE=work;PREF=1;LABEL="123 Main St.^nAnytown":;;123 Main St.;Anytown;CA;91921;USA`)
+
+	b, err = a.MarshalVCardFieldVersion("3.0")
+	assertEq(t, err, nil)
+	assertStringsEq(t, string(b), ";TYPE=work;PREF=1:;;123 Main St.;Anytown;CA;91921;USA")
+
+	err = a.UnmarshalVCardField([]byte(`:;;Main St.\; 5`))
+	assertEq(t, err, nil)
+	assertDeepEq(t, a, Adr{Street: "Main St.; 5"})
+}
@@ -0,0 +1,113 @@
+package vcard
+
+import (
+	"strings"
+)
+
+// Representations of the same property linked by ALTID parameter e.g. FN in several languages
+// as per https://datatracker.ietf.org/doc/html/rfc6350#section-5.4
+//
+//	FN;ALTID=1;LANGUAGE=en:Moscow
+//	FN;ALTID=1;LANGUAGE=ru:Москва
+type Alternatives struct {
+	// Value of ALTID parameter. Empty for a property without ALTID.
+	ALTID string
+
+	// Unescaped values keyed by LANGUAGE parameter as written in the vCard.
+	// Value without LANGUAGE parameter has an empty key.
+	Values map[string]string
+
+	// Keys of Values in order of appearance.
+	Languages []string
+}
+
+// Returns representations of every instance of property name. Properties sharing ALTID are
+// grouped together, every property without ALTID forms its own group. Groups are ordered by
+// the first property of a group. If a language appears twice in a group the first value is kept.
+func (c *Card) Alternatives(name string) []Alternatives {
+	name = strings.ToUpper(name)
+	alts := []Alternatives{}
+	byID := map[string]int{}
+
+	for _, p := range c.props {
+		if p.Name != name {
+			continue
+		}
+		altID, lang := "", ""
+		if v := p.Params.Values("ALTID"); len(v) > 0 {
+			altID = v[0]
+		}
+		if v := p.Params.Values("LANGUAGE"); len(v) > 0 {
+			lang = v[0]
+		}
+
+		i, found := byID[altID]
+		if !found || altID == "" {
+			i = len(alts)
+			alts = append(alts, Alternatives{ALTID: altID, Values: map[string]string{}})
+			if altID != "" {
+				byID[altID] = i
+			}
+		}
+		if _, exists := alts[i].Values[lang]; exists {
+			continue
+		}
+		alts[i].Values[lang] = unescapeText(p.Value)
+		alts[i].Languages = append(alts[i].Languages, lang)
+	}
+	return alts
+}
+
+// Returns the representation which matches BCP 47 language tag locale best e.g. "de-CH".
+//
+// Tags are compared case-insensitively. Exact match is preferred, then a match of locale with
+// trailing subtags removed ("de" for "de-CH"), then a tag with the same primary language
+// ("de-AT" for "de-CH"), then the value without a language. Otherwise the first value is
+// returned. Returns false if there are no values.
+func (a Alternatives) Best(locale string) (string, bool) {
+	if len(a.Languages) == 0 {
+		return "", false
+	}
+	lookup := func(tag string) (string, bool) {
+		for _, lang := range a.Languages {
+			if strings.EqualFold(lang, tag) {
+				return a.Values[lang], true
+			}
+		}
+		return "", false
+	}
+
+	for tag := locale; tag != ""; {
+		if v, found := lookup(tag); found {
+			return v, true
+		}
+		i := strings.LastIndexAny(tag, "-_")
+		if i < 0 {
+			break
+		}
+		tag = tag[:i]
+	}
+
+	primary, _, _ := strings.Cut(strings.ReplaceAll(locale, "_", "-"), "-")
+	for _, lang := range a.Languages {
+		p, _, _ := strings.Cut(lang, "-")
+		if primary != "" && strings.EqualFold(p, primary) {
+			return a.Values[lang], true
+		}
+	}
+
+	if v, found := a.Values[""]; found {
+		return v, true
+	}
+	return a.Values[a.Languages[0]], true
+}
+
+// Returns the value of the first representation of property name which matches locale best.
+// See [Alternatives.Best].
+func (c *Card) Localized(name string, locale string) (string, bool) {
+	alts := c.Alternatives(name)
+	if len(alts) == 0 {
+		return "", false
+	}
+	return alts[0].Best(locale)
+}
@@ -0,0 +1,49 @@
+package vcard
+
+import "testing"
+
+func TestCardAlternatives(t *testing.T) {
+
+	text := `BEGIN:VCARD
+VERSION:4.0
+FN;ALTID=1;LANGUAGE=en:Moscow
+FN;ALTID=1;LANGUAGE=ru:Москва
+FN;ALTID=1;LANGUAGE=de-AT:Moskau
+TITLE:Boss
+TITLE;ALTID=2;LANGUAGE=fr:Chef\, principal
+TITLE;ALTID=2:Head
+END:VCARD
+`
+	c := Card{}
+	err := Unmarshal([]byte(crlfy(text)), &c)
+	assertEq(t, err, nil)
+
+	fn := c.Alternatives("fn")
+	assertEq(t, len(fn), 1)
+	assertStringsEq(t, fn[0].ALTID, "1")
+	assertSlicesEq(t, fn[0].Languages, []string{"en", "ru", "de-AT"})
+	assertMapsEq(t, fn[0].Values, map[string]string{"en": "Moscow", "ru": "Москва", "de-AT": "Moskau"})
+
+	titles := c.Alternatives("TITLE")
+	assertEq(t, len(titles), 2)
+	assertMapsEq(t, titles[0].Values, map[string]string{"": "Boss"})
+	assertMapsEq(t, titles[1].Values, map[string]string{"fr": "Chef, principal", "": "Head"})
+
+	v, _ := c.Localized("FN", "RU")
+	assertStringsEq(t, v, "Москва")
+
+	v, _ = c.Localized("FN", "en-US")
+	assertStringsEq(t, v, "Moscow")
+
+	v, _ = c.Localized("FN", "de-CH")
+	assertStringsEq(t, v, "Moskau")
+
+	v, _ = c.Localized("FN", "ja")
+	assertStringsEq(t, v, "Moscow")
+
+	v, _ = titles[1].Best("ja")
+	assertStringsEq(t, v, "Head")
+
+	_, found := c.Localized("NOTE", "en")
+	assertEq(t, found, false)
+}
@@ -15,6 +15,12 @@ func assertEq[T comparable](t *testing.T, found T, expected T) {
 	}
 }
 
+func assertDeepEq[T any](t *testing.T, found T, expected T) {
+	if !reflect.DeepEqual(found, expected) {
+		t.Errorf("Values are different.\nExpected:\n\n%+v\n\nFound:\n\n%+v", expected, found)
+	}
+}
+
 func assertSlicesEq[T comparable](t *testing.T, found []T, expected []T) {
 	if !slices.Equal(found, expected) {
 		t.Errorf("Slices are different.\nExpected:\n\n%v\n\nFound:\n\n%v", expected, found)
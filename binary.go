@@ -0,0 +1,131 @@
+package vcard
+
+import (
+	"encoding/binary"
+)
+
+// Version of the binary format written by [Card.MarshalBinary]. Incremented on every incompatible
+// change, so cached cards of an older format are reported as errors instead of being misread.
+const binaryFormatVersion = 1
+
+// Magic bytes starting binary encoded cards.
+const binaryMagic = "vCB"
+
+// Encodes the card into a compact binary form for caches e.g. Redis or BoltDB, which is decoded
+// by [Card.UnmarshalBinary] much faster than a .vcf record is parsed. Groups, parameters, raw
+// values and nested records are kept exactly, so a decoded card equals the original one.
+//
+// The format is versioned but is not meant for long-term storage or interchange, use [Marshal]
+// for that. Implements [encoding.BinaryMarshaler], so cards can be encoded by encoding/gob.
+func (c Card) MarshalBinary() ([]byte, error) {
+	return c.AppendBinary(nil)
+}
+
+// Appends the card encoded like [Card.MarshalBinary] to b. Implements [encoding.BinaryAppender].
+func (c Card) AppendBinary(b []byte) ([]byte, error) {
+	b = append(b, binaryMagic...)
+	b = append(b, binaryFormatVersion)
+	b = binary.AppendUvarint(b, uint64(len(c.props)))
+	for _, p := range c.props {
+		b = appendBinaryString(b, p.Group)
+		b = appendBinaryString(b, p.Name)
+		// Like values of parameters, nil parameters are told apart from empty ones
+		if p.Params == nil {
+			b = binary.AppendUvarint(b, 0)
+		} else {
+			b = binary.AppendUvarint(b, uint64(len(p.Params))+1)
+		}
+		for _, param := range p.Params {
+			b = appendBinaryString(b, param.Name)
+			// Zero stands for nil values of nameless parameters
+			if param.Values == nil {
+				b = binary.AppendUvarint(b, 0)
+				continue
+			}
+			b = binary.AppendUvarint(b, uint64(len(param.Values))+1)
+			for _, v := range param.Values {
+				b = appendBinaryString(b, v)
+			}
+		}
+		b = appendBinaryString(b, p.Value)
+	}
+	return b, nil
+}
+
+func appendBinaryString(b []byte, s string) []byte {
+	b = binary.AppendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+// Decodes a card encoded by [Card.MarshalBinary]. Returns [ErrParsing] if data is truncated,
+// malformed or written by an unsupported version of the format. Implements [encoding.BinaryUnmarshaler].
+func (c *Card) UnmarshalBinary(data []byte) error {
+	if len(data) < len(binaryMagic)+1 || string(data[:len(binaryMagic)]) != binaryMagic {
+		return parsingErrf("data is not a binary encoded card")
+	}
+	if v := data[len(binaryMagic)]; v != binaryFormatVersion {
+		return parsingErrf("unsupported version %v of binary encoded card", v)
+	}
+
+	// Strings of the card share memory of a single copy of data
+	r := binaryReader{s: string(data[len(binaryMagic)+1:])}
+	n := r.length()
+	props := make([]Property, 0, n)
+	for i := 0; i < n && r.err == nil; i++ {
+		p := Property{Group: r.string(), Name: r.string()}
+		if params := r.length(); params > 0 {
+			p.Params = make(Params, 0, params-1)
+			for range params - 1 {
+				param := Param{Name: r.string()}
+				if values := r.length(); values > 0 {
+					param.Values = make([]string, values-1)
+					for j := range param.Values {
+						param.Values[j] = r.string()
+					}
+				}
+				p.Params = append(p.Params, param)
+			}
+		}
+		p.Value = r.string()
+		props = append(props, p)
+	}
+	if r.err != nil {
+		return r.err
+	}
+	if r.s != "" {
+		return parsingErrf("binary encoded card has %v trailing bytes", len(r.s))
+	}
+	*c = Card{props: props}
+	return nil
+}
+
+// Reader of binary encoded cards which remembers the first error.
+type binaryReader struct {
+	s   string
+	err error
+}
+
+// Reads a length which is not greater than the number of remaining bytes, since every counted
+// item takes at least a byte.
+func (r *binaryReader) length() int {
+	if r.err != nil {
+		return 0
+	}
+	n, size := binary.Uvarint([]byte(r.s[:min(len(r.s), binary.MaxVarintLen64)]))
+	if size <= 0 || n > uint64(len(r.s)-size) {
+		r.err = parsingErrf("binary encoded card is truncated or malformed")
+		return 0
+	}
+	r.s = r.s[size:]
+	return int(n)
+}
+
+func (r *binaryReader) string() string {
+	n := r.length()
+	if r.err != nil {
+		return ""
+	}
+	s := r.s[:n]
+	r.s = r.s[n:]
+	return s
+}
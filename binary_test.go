@@ -0,0 +1,96 @@
+package vcard
+
+import (
+	"bytes"
+	"encoding/gob"
+	"strings"
+	"testing"
+)
+
+var binaryTestRecord = strings.Join([]string{
+	"BEGIN:VCARD",
+	"VERSION:2.1",
+	"N:Smith;Alex;;;",
+	"FN:Alex Smith",
+	"item1.TEL;CELL;TYPE=pref:555",
+	"EMAIL;TYPE=:alex@example.com",
+	"NOTE;CHARSET=UTF-8;ENCODING=QUOTED-PRINTABLE:J=C3=B6rg",
+	"AGENT:",
+	"BEGIN:VCARD",
+	"VERSION:2.1",
+	"FN:Sam",
+	"END:VCARD",
+	"END:VCARD",
+	"",
+}, "\r\n")
+
+func TestCardBinary(t *testing.T) {
+
+	card, err := ParseRecord([]byte(binaryTestRecord))
+	assertEq(t, err, nil)
+
+	b, err := card.MarshalBinary()
+	assertEq(t, err, nil)
+
+	decoded := Card{}
+	assertEq(t, decoded.UnmarshalBinary(b), nil)
+	assertDeepEq(t, decoded.Properties(), card.Properties())
+
+	text, err := Marshal(decoded)
+	assertEq(t, err, nil)
+	assertStringLinesEq(t, string(text), binaryTestRecord)
+
+	empty, err := Card{}.MarshalBinary()
+	assertEq(t, err, nil)
+	assertEq(t, decoded.UnmarshalBinary(empty), nil)
+	assertEq(t, decoded.Len(), 0)
+}
+
+func TestCardBinaryGob(t *testing.T) {
+
+	card := newStoreCard("1", "Alex")
+	buf := bytes.Buffer{}
+	assertEq(t, gob.NewEncoder(&buf).Encode(card), nil)
+
+	decoded := Card{}
+	assertEq(t, gob.NewDecoder(&buf).Decode(&decoded), nil)
+	assertEq(t, Equal(decoded, card), true)
+}
+
+func TestCardBinaryMalformed(t *testing.T) {
+
+	card := newStoreCard("1", "Alex")
+	b, err := card.AppendBinary([]byte{})
+	assertEq(t, err, nil)
+
+	c := Card{}
+	assertErrIs(t, c.UnmarshalBinary([]byte("BEGIN:VCARD")), ErrParsing, "not a binary encoded card")
+	assertErrIs(t, c.UnmarshalBinary(append([]byte("vCB\x02"), b[4:]...)), ErrParsing, "unsupported version 2")
+	assertErrIs(t, c.UnmarshalBinary(append(b, 0)), ErrParsing, "1 trailing bytes")
+	for i := 4; i < len(b); i++ {
+		assertErrIs(t, c.UnmarshalBinary(b[:i]), ErrParsing, "truncated or malformed")
+	}
+	assertErrIs(t, c.UnmarshalBinary([]byte("vCB\x01\xff\xff\xff\xff\xff\xff\xff\xff\xff\x01")), ErrParsing, "truncated or malformed")
+}
+
+func BenchmarkCardUnmarshalBinary(b *testing.B) {
+
+	card, _ := ParseRecord([]byte(binaryTestRecord))
+	data, _ := card.MarshalBinary()
+
+	b.ReportAllocs()
+	for b.Loop() {
+		c := Card{}
+		_ = c.UnmarshalBinary(data)
+	}
+}
+
+func BenchmarkCardParseRecord(b *testing.B) {
+
+	data := []byte(binaryTestRecord)
+
+	b.ReportAllocs()
+	for b.Loop() {
+		_, _ = ParseRecord(data)
+	}
+}
@@ -0,0 +1,261 @@
+package vcard
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Builds a [Card] of a given version property by property:
+//
+//	card, err := vcard.NewCard("4.0").
+//		FN("Alex").
+//		Tel("+1 555 0100", vcard.Cell, vcard.Pref(1)).
+//		Email("alex@example.com", vcard.Work).
+//		Build()
+//
+// Values are encoded in a form defined by the version and errors are collected until
+// [CardBuilder.Build], which also checks the card against the schema of the version.
+type CardBuilder struct {
+	version string
+	props   []Property
+	err     error
+}
+
+// Option of a property added by [CardBuilder] e.g. [Cell] or [Pref].
+type PropertyOption func(p *Property, version string)
+
+// Adds values to TYPE parameter.
+func WithType(types ...string) PropertyOption {
+	return func(p *Property, _ string) { p.Params.Add("TYPE", types...) }
+}
+
+// Sets a parameter e.g. WithParam("MEDIATYPE", "audio/mp3").
+func WithParam(name string, values ...string) PropertyOption {
+	return func(p *Property, _ string) { p.Params.Set(name, values...) }
+}
+
+// Sets LANGUAGE parameter e.g. WithLanguage("fr").
+func WithLanguage(tag string) PropertyOption {
+	return WithParam("LANGUAGE", tag)
+}
+
+// Sets a group of a property e.g. "item1" of "item1.TEL".
+func WithGroup(group string) PropertyOption {
+	return func(p *Property, _ string) { p.Group = group }
+}
+
+// Sets preference in range 1..100 where 1 is the most preferred. Preference is written as PREF
+// parameter in vCard 4.0 and as TYPE=pref in older versions which have no levels of preference.
+func Pref(pref int) PropertyOption {
+	return func(p *Property, version string) {
+		if pref < 1 || pref > 100 {
+			return
+		}
+		if version == "4.0" {
+			p.Params.Set("PREF", strconv.Itoa(pref))
+			return
+		}
+		p.Params.Add("TYPE", "pref")
+	}
+}
+
+// Common values of TYPE parameter.
+var (
+	Home  = WithType("home")
+	Work  = WithType("work")
+	Cell  = WithType("cell")
+	Voice = WithType("voice")
+	Fax   = WithType("fax")
+	Pager = WithType("pager")
+	Video = WithType("video")
+)
+
+// Creates a builder of a card of a given version e.g. "4.0".
+func NewCard(version string) *CardBuilder {
+	return &CardBuilder{version: version}
+}
+
+// Adds a property with raw value e.g. Add("X-SKYPE", "alex"). Value is not escaped.
+func (b *CardBuilder) Add(name string, value string, opts ...PropertyOption) *CardBuilder {
+	return b.add(name, ":"+value, opts)
+}
+
+// Sets the formatted name.
+func (b *CardBuilder) FN(fn string, opts ...PropertyOption) *CardBuilder {
+	return b.text("FN", fn, opts)
+}
+
+// Sets the structured name.
+func (b *CardBuilder) N(n Name, opts ...PropertyOption) *CardBuilder {
+	return b.value("N", n, opts)
+}
+
+// Adds descriptive/familiar names.
+func (b *CardBuilder) Nickname(nicknames ...string) *CardBuilder {
+	return b.add("NICKNAME", ":"+joinTextList(nicknames), nil)
+}
+
+// Sets the kind of entity e.g. "individual", "group" or "org".
+func (b *CardBuilder) Kind(kind string) *CardBuilder {
+	return b.text("KIND", kind, nil)
+}
+
+// Adds a telephone number.
+func (b *CardBuilder) Tel(number string, opts ...PropertyOption) *CardBuilder {
+	return b.value("TEL", Tel{Number: number}, opts)
+}
+
+// Adds an email address. The address is validated, see [Email.Validate].
+func (b *CardBuilder) Email(address string, opts ...PropertyOption) *CardBuilder {
+	return b.value("EMAIL", Email{Address: address}, opts)
+}
+
+// Adds a delivery address. In vCard 2.1 and 3.0 Label of the address is written as LABEL property.
+func (b *CardBuilder) Adr(a Adr, opts ...PropertyOption) *CardBuilder {
+	b.value("ADR", a, opts)
+	if a.Label != "" && b.version != "4.0" {
+		b.text("LABEL", a.Label, opts)
+	}
+	return b
+}
+
+// Adds an instant messenger handle e.g. "xmpp:alex@example.com".
+func (b *CardBuilder) Impp(uri string, opts ...PropertyOption) *CardBuilder {
+	return b.value("IMPP", Impp{URI: uri}, opts)
+}
+
+// Adds a URL of a website of the person.
+func (b *CardBuilder) URL(url string, opts ...PropertyOption) *CardBuilder {
+	return b.add("URL", ":"+url, opts)
+}
+
+// Sets the organization.
+func (b *CardBuilder) Org(o Org, opts ...PropertyOption) *CardBuilder {
+	return b.value("ORG", o, opts)
+}
+
+// Sets the job title.
+func (b *CardBuilder) Title(title string, opts ...PropertyOption) *CardBuilder {
+	return b.text("TITLE", title, opts)
+}
+
+// Sets the role within an organization.
+func (b *CardBuilder) Role(role string, opts ...PropertyOption) *CardBuilder {
+	return b.text("ROLE", role, opts)
+}
+
+// Sets the birthday.
+func (b *CardBuilder) Bday(d Date, opts ...PropertyOption) *CardBuilder {
+	return b.value("BDAY", d, opts)
+}
+
+// Sets the anniversary.
+func (b *CardBuilder) Anniversary(d Date, opts ...PropertyOption) *CardBuilder {
+	return b.value("ANNIVERSARY", d, opts)
+}
+
+// Sets the photo.
+func (b *CardBuilder) Photo(p Photo, opts ...PropertyOption) *CardBuilder {
+	return b.value("PHOTO", p, opts)
+}
+
+// Adds a note.
+func (b *CardBuilder) Note(note string, opts ...PropertyOption) *CardBuilder {
+	return b.text("NOTE", note, opts)
+}
+
+// Adds tags describing the person.
+func (b *CardBuilder) Categories(categories ...string) *CardBuilder {
+	return b.add("CATEGORIES", ":"+joinTextList(categories), nil)
+}
+
+// Sets the unique identifier. See [NewUID].
+func (b *CardBuilder) UID(uid UID) *CardBuilder {
+	return b.text("UID", string(uid), nil)
+}
+
+// Returns the card or the first error occurred while adding properties.
+//
+// Returns [ErrValidation] if the card contains a property which is not defined in its version
+// e.g. KIND in 3.0, or if it does not conform to the schema of the version registered with
+// [RegisterSchema] e.g. it has no FN which is required in 3.0 and 4.0.
+func (b *CardBuilder) Build() (Card, error) {
+	if b.err != nil {
+		return Card{}, b.err
+	}
+	schema, found := SchemaForVersion(b.version)
+	if !found {
+		return Card{}, vCardErrf("unable to build a card of unknown version %q", b.version)
+	}
+	for _, p := range b.props {
+		if !propertyDefinedIn(p.Name, b.version) {
+			return Card{}, validationErrf("property %s is not defined in vCard %s", p.Name, b.version)
+		}
+	}
+	for _, req := range schema.Required() {
+		if !slices.ContainsFunc(b.props, func(p Property) bool { return p.Name == canonicalPropertyName(req) }) {
+			return Card{}, validationErrf("card does not contain property %s required by vCard %s", canonicalPropertyName(req), b.version)
+		}
+	}
+	err := (&Decoder{}).checkProperties(schema, b.props)
+	if err != nil {
+		return Card{}, err
+	}
+
+	card := Card{props: []Property{{Name: "VERSION", Value: b.version}}}
+	for _, p := range b.props {
+		card.props = append(card.props, p.clone())
+	}
+	return card, nil
+}
+
+// Returns the card as a [Contact]. See [CardBuilder.Build].
+func (b *CardBuilder) BuildContact() (Contact, error) {
+	card, err := b.Build()
+	if err != nil {
+		return Contact{}, err
+	}
+	return ContactOf(card), nil
+}
+
+func (b *CardBuilder) text(name string, s string, opts []PropertyOption) *CardBuilder {
+	return b.add(name, ":"+escapeText(s), opts)
+}
+
+func (b *CardBuilder) value(name string, v any, opts []PropertyOption) *CardBuilder {
+	var rest []byte
+	var err error
+	switch m := v.(type) {
+	case VCardFieldVersionMarshaler:
+		rest, err = m.MarshalVCardFieldVersion(b.version)
+	case VCardFieldMarshaler:
+		rest, err = m.MarshalVCardField()
+	}
+	if err != nil {
+		if b.err == nil {
+			b.err = vCardErrf("error during building %s: %w", name, err)
+		}
+		return b
+	}
+	if len(rest) == 0 {
+		return b
+	}
+	return b.add(name, string(rest), opts)
+}
+
+// Adds a property of a form "NAME" + rest e.g. ";TYPE=cell:555" and applies opts to it.
+func (b *CardBuilder) add(name string, rest string, opts []PropertyOption) *CardBuilder {
+	p, err := ParseProperty(strings.ToUpper(name) + rest)
+	if err != nil {
+		if b.err == nil {
+			b.err = vCardErrf("error during building %s: %w", name, err)
+		}
+		return b
+	}
+	for _, opt := range opts {
+		opt(&p, b.version)
+	}
+	b.props = append(b.props, p)
+	return b
+}
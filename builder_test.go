@@ -0,0 +1,76 @@
+package vcard
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCardBuilder(t *testing.T) {
+
+	card, err := NewCard("4.0").
+		FN("Alex").
+		Tel("+1 555 0100", Cell, Pref(1)).
+		Email("alex@example.com", Work).
+		Adr(Adr{Street: "1 Main St.", Label: "1 Main St."}, Home, WithGroup("item1")).
+		Bday(Date{Year: 1985, Month: time.April, Day: 15}).
+		Note("a;b").
+		Build()
+	assertEq(t, err, nil)
+
+	b, err := Marshal(card)
+	assertEq(t, err, nil)
+	assertStringLinesEq(t, string(b), crlfy(`BEGIN:VCARD
+VERSION:4.0
+FN:Alex
+TEL;TYPE=cell;PREF=1:+1 555 0100
+EMAIL;TYPE=work:alex@example.com
+item1.ADR;LABEL=1 Main St.;TYPE=home:;;1 Main St.;;;;
+BDAY:19850415
+NOTE:a\;b
+END:VCARD
+`))
+}
+
+func TestCardBuilderV3(t *testing.T) {
+
+	card, err := NewCard("3.0").
+		FN("Alex").
+		N(Name{FamilyNames: []string{"Doe"}}).
+		Tel("555", Cell, Pref(2)).
+		Adr(Adr{Street: "1 Main St.", Label: "1 Main St."}).
+		Bday(Date{Year: 1985, Month: time.April, Day: 15}).
+		Build()
+	assertEq(t, err, nil)
+
+	b, err := Marshal(card)
+	assertEq(t, err, nil)
+	assertStringLinesEq(t, string(b), crlfy(`BEGIN:VCARD
+VERSION:3.0
+FN:Alex
+N:Doe;;;;
+TEL;TYPE=cell,pref:555
+ADR:;;1 Main St.;;;;
+LABEL:1 Main St.
+BDAY:1985-04-15
+END:VCARD
+`))
+
+	c, err := NewCard("3.0").FN("Alex").N(Name{}).Tel("555", Pref(1)).BuildContact()
+	assertEq(t, err, nil)
+	assertDeepEq(t, c.Phones, []Tel{{Number: "555", Pref: 1}})
+}
+
+func TestCardBuilderErrors(t *testing.T) {
+
+	_, err := NewCard("4.0").Tel("555").Build()
+	assertErrIs(t, err, ErrValidation, "FN")
+
+	_, err = NewCard("3.0").FN("Alex").N(Name{}).Kind("org").Build()
+	assertErrIs(t, err, ErrValidation, "KIND is not defined in vCard 3.0")
+
+	_, err = NewCard("4.0").FN("Alex").Email("alex").Build()
+	assertErrIs(t, err, ErrValidation, "has to contain '@'")
+
+	_, err = NewCard("5.0").FN("Alex").Build()
+	assertErrIs(t, err, ErrVCard, "unknown version")
+}
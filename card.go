@@ -0,0 +1,470 @@
+package vcard
+
+import (
+	"iter"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Schema-less representation of a single vCard.
+//
+// Unlike user-defined structs, Card keeps every property of a record in the order it was found,
+// including repeated properties (e.g. multiple TEL) and properties which are not part of any [Schema].
+// Encoding a decoded Card preserves order, groups, parameters, unknown properties and nested records
+// like AGENT of vCard 2.1, so a document passes through unchanged up to line folding and case of names.
+//
+// Card can be used as an argument to [Marshal] and [Unmarshal] like any other struct. The schema
+// passed to [Encoder] is only used to write VERSION when the card does not contain one.
+type Card struct {
+	props []Property
+}
+
+// Single content line of a vCard e.g. "item1.TEL;TYPE=CELL:555" is
+// Group="item1", Name="TEL", Params=[TYPE=CELL], Value="555".
+//
+// Property is the low-level representation shared by [Decoder], [Card] and [Encoder.WriteProperty].
+// Name is upper-cased by the decoder, Value is kept raw e.g. TEXT values are not unescaped.
+type Property struct {
+	Group  string
+	Name   string
+	Params Params
+	Value  string
+}
+
+// Returns name of a property with a group e.g. "item1.TEL".
+func (p Property) fullName() string {
+	if p.Group == "" {
+		return p.Name
+	}
+	return p.Group + "." + p.Name
+}
+
+// Returns parameters and value of a property e.g. ";TYPE=CELL:555".
+func (p Property) rest() string {
+	return p.Params.String() + ":" + p.Value
+}
+
+// Returns the property as an unfolded content line without a line break e.g. "item1.TEL;TYPE=CELL:555".
+func (p Property) String() string {
+	return p.fullName() + p.rest()
+}
+
+var cardType = reflect.TypeFor[Card]()
+
+// Returns value of the first property with the given name. Name is case-insensitive.
+func (c *Card) Get(name string) (string, bool) {
+	name = strings.ToUpper(name)
+	for _, p := range c.props {
+		if p.Name == name {
+			return p.Value, true
+		}
+	}
+	return "", false
+}
+
+// Returns the last top-level property with the given name like [Decoder] with smart strings decodes
+// string fields e.g. "Alex" for FN:Alex and ";TYPE=CELL:555" for TEL;TYPE=CELL:555. Used by
+// codecs of [GenerateCodec], so they decode repeated properties the same way as reflection.
+func (c *Card) Field(name string) (string, bool) {
+	name = strings.ToUpper(name)
+	for _, p := range slices.Backward(topLevelProperties(c.props)) {
+		if p.Name != name {
+			continue
+		}
+		if len(p.Params) == 0 {
+			return p.Value, true
+		}
+		return p.rest(), true
+	}
+	return "", false
+}
+
+// Returns values of all properties with the given name in order of appearance.
+func (c *Card) Values(name string) []string {
+	name = strings.ToUpper(name)
+	values := []string{}
+	for _, p := range c.props {
+		if p.Name == name {
+			values = append(values, p.Value)
+		}
+	}
+	return values
+}
+
+// Appends a property with the given name and raw value.
+func (c *Card) Add(name string, value string) {
+	c.props = append(c.props, Property{Name: strings.ToUpper(name), Value: value})
+}
+
+// Replaces all properties with the given name by a single property with raw value.
+// The property keeps position of the first replaced one.
+func (c *Card) Set(name string, value string) {
+	name = strings.ToUpper(name)
+	for i, p := range c.props {
+		if p.Name == name {
+			c.props[i] = Property{Group: p.Group, Name: name, Value: value}
+			c.props = append(c.props[:i+1], deleteProps(c.props[i+1:], name)...)
+			return
+		}
+	}
+	c.Add(name, value)
+}
+
+// Removes all properties with the given name.
+func (c *Card) Del(name string) {
+	c.props = deleteProps(c.props, strings.ToUpper(name))
+}
+
+// Returns copies of all properties in order of appearance.
+func (c *Card) Properties() []Property {
+	props := make([]Property, len(c.props))
+	for i, p := range c.props {
+		p.Params = p.Params.clone()
+		props[i] = p
+	}
+	return props
+}
+
+// Returns a deep copy of the card which shares no properties, parameters or their values with c,
+// so the copy can be modified while c is used by another goroutine.
+func (c *Card) Clone() Card {
+	return Card{props: c.Properties()}
+}
+
+// Appends a property. Name is upper-cased.
+func (c *Card) AddProperty(p Property) {
+	p.Name = strings.ToUpper(p.Name)
+	c.props = append(c.props, p)
+}
+
+// Returns number of properties in the card.
+func (c *Card) Len() int {
+	return len(c.props)
+}
+
+// Returns value of VERSION property or an empty string.
+func (c *Card) Version() string {
+	v, _ := c.Get("VERSION")
+	return v
+}
+
+// Decodes every record of a document into a Card ignoring schemas.
+func parseCards(data string) ([]Card, error) {
+	d := &Decoder{}
+	cards := []Card{}
+
+	s := data
+	for strings.TrimSpace(s) != "" {
+		c, rest, err := d.decodeCardRecord(strings.TrimLeft(s, " \t\r\n"))
+		if err != nil {
+			return cards, vCardErrf("error during decoding record idx=%v: %w", len(cards), err)
+		}
+		cards = append(cards, c)
+		s = rest
+	}
+	return cards, nil
+}
+
+// Returns the most preferred property with the given name. Preference is defined by the lowest
+// PREF parameter in 4.0 or TYPE=PREF in 2.1 and 3.0. Otherwise the first property is returned.
+func (c *Card) preferred(name string) (Property, bool) {
+	best := Property{}
+	bestPref := 101
+	found := false
+
+	for _, p := range c.props {
+		if p.Name != name {
+			continue
+		}
+		pref := propertyPref(p)
+		if !found || pref < bestPref {
+			best, bestPref, found = p, pref, true
+		}
+	}
+	return best, found
+}
+
+// Returns PREF of a property in range 1..100 or 100 if property has no preference.
+// TYPE=PREF of older versions is treated as PREF=1.
+func propertyPref(p Property) int {
+	pref := 100
+	for _, v := range p.Params.Values("PREF") {
+		n, err := strconv.Atoi(v)
+		if err == nil && n >= 1 && n < pref {
+			pref = n
+		}
+	}
+	// vCard 2.1 allows parameters without a name e.g. TEL;PREF;CELL:555
+	for _, t := range p.Params.Values("TYPE") {
+		if strings.EqualFold(t, "pref") {
+			pref = 1
+		}
+	}
+	return pref
+}
+
+// Returns names of properties.
+func propertyNames(props []Property) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for _, p := range props {
+			if !yield(p.Name) {
+				return
+			}
+		}
+	}
+}
+
+// Returns number of properties with the given name.
+func (c *Card) count(name string) int {
+	n := 0
+	for _, p := range c.props {
+		if p.Name == name {
+			n++
+		}
+	}
+	return n
+}
+
+func deleteProps(props []Property, name string) []Property {
+	kept := props[:0]
+	for _, p := range props {
+		if p.Name != name {
+			kept = append(kept, p)
+		}
+	}
+	return kept
+}
+
+// Typed accessors for standard text properties. Each one returns unescaped value
+// of the first occurrence of the property.
+
+// Returns the formatted name string.
+func (c *Card) FN() (string, bool) { return c.text("FN") }
+
+// Returns a structured representation of the name of the person e.g. "Doe;John;;;".
+func (c *Card) N() (string, bool) { return c.Get("N") }
+
+// Returns descriptive/familiar names.
+func (c *Card) Nickname() (string, bool) { return c.text("NICKNAME") }
+
+// Returns the name and optionally the unit(s) of the organization e.g. "Example Company;Marketing".
+func (c *Card) Org() (string, bool) { return c.Get("ORG") }
+
+// Returns the job title, functional position or function of the individual.
+func (c *Card) Title() (string, bool) { return c.text("TITLE") }
+
+// Returns the role, occupation, or business category of the person within an organization.
+func (c *Card) Role() (string, bool) { return c.text("ROLE") }
+
+// Returns comment that is associated with the person.
+func (c *Card) Note() (string, bool) { return c.text("NOTE") }
+
+// Returns the kind of entity that this vCard represents e.g. "individual" or "group".
+func (c *Card) Kind() (string, bool) { return c.text("KIND") }
+
+// Returns the person's gender e.g. "M" or "F;boy".
+func (c *Card) Gender() (string, bool) { return c.Get("GENDER") }
+
+// Returns the persistent, globally unique identifier associated with the person.
+func (c *Card) UID() (string, bool) { return c.text("UID") }
+
+// Returns the identifier for the product that created the vCard.
+func (c *Card) ProdID() (string, bool) { return c.text("PRODID") }
+
+// Accessors for properties registered by RFC 6474, RFC 6715 and RFC 8605.
+
+// Returns the place of birth. Value is either a text or a URI e.g. a geo: URI.
+func (c *Card) BirthPlace() (string, bool) { return c.text("BIRTHPLACE") }
+
+// Returns the place of death. Value is either a text or a URI e.g. a geo: URI.
+func (c *Card) DeathPlace() (string, bool) { return c.text("DEATHPLACE") }
+
+// Returns the date of death e.g. "19960415" or a text like "circa 1800".
+func (c *Card) DeathDate() (string, bool) { return c.text("DEATHDATE") }
+
+// Returns professional subject areas the person has knowledge of.
+func (c *Card) Expertise() []string { return c.texts("EXPERTISE") }
+
+// Returns recreational activities the person actively engages in.
+func (c *Card) Hobbies() []string { return c.texts("HOBBY") }
+
+// Returns recreational activities the person is interested in.
+func (c *Card) Interests() []string { return c.texts("INTEREST") }
+
+// Returns URIs of directories of organizations the person belongs to.
+func (c *Card) OrgDirectories() []string { return c.Values("ORG-DIRECTORY") }
+
+// Returns URIs to contact the person e.g. mailto: or a web form, see https://datatracker.ietf.org/doc/html/rfc8605
+func (c *Card) ContactURIs() []string { return c.Values("CONTACT-URI") }
+
+// Returns tags describing the person from every CATEGORIES property.
+func (c *Card) Categories() []string { return c.lists("CATEGORIES") }
+
+// Returns every descriptive/familiar name from every NICKNAME property.
+func (c *Card) Nicknames() []string { return c.lists("NICKNAME") }
+
+// Typed accessors for properties with structured values. Properties which can't be decoded
+// are skipped.
+
+// Returns every telephone number in order of appearance. See [Card.PrimaryTel] for the preferred one.
+func (c *Card) Tels() []Tel { return decodedValues[Tel](c, "TEL") }
+
+// Returns every email address in order of appearance. See [Card.PrimaryEmail] for the preferred one.
+func (c *Card) Emails() []Email { return decodedValues[Email](c, "EMAIL") }
+
+// Returns the birth date of the person. The date may be partial e.g. "--0415" has Month and Day
+// only, BDAY;VALUE=text is kept in [Date.Text]. Returns false if the card has no BDAY or its
+// value is malformed.
+func (c *Card) Bday() (Date, bool) {
+	p, found := c.first("BDAY")
+	if !found {
+		return Date{}, false
+	}
+	d := Date{}
+	if err := d.UnmarshalVCardField([]byte(p.rest())); err != nil {
+		return Date{}, false
+	}
+	return d, true
+}
+
+// Returns values of properties with the given name decoded with [VCardFieldUnmarshaler] of *T.
+func decodedValues[T any, PT interface {
+	*T
+	VCardFieldUnmarshaler
+}](c *Card, name string) []T {
+	values := []T{}
+	for _, p := range c.props {
+		if p.Name != name {
+			continue
+		}
+		var v T
+		if err := PT(&v).UnmarshalVCardField([]byte(p.rest())); err == nil {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+func (c *Card) lists(name string) []string {
+	values := []string{}
+	for _, v := range c.Values(name) {
+		values = append(values, splitTextList(v)...)
+	}
+	return values
+}
+
+func (c *Card) texts(name string) []string {
+	values := c.Values(name)
+	for i, v := range values {
+		values[i] = unescapeText(v)
+	}
+	return values
+}
+
+func (c *Card) text(name string) (string, bool) {
+	v, found := c.Get(name)
+	if !found {
+		return "", false
+	}
+	return unescapeText(v), true
+}
+
+// Joins TEXT values into a comma-separated list escaping every value.
+func joinTextList(values []string) string {
+	escaped := make([]string, len(values))
+	for i, v := range values {
+		escaped[i] = escapeText(v)
+	}
+	return strings.Join(escaped, ",")
+}
+
+// Splits a comma-separated list of TEXT values at commas which are not escaped
+// and unescapes every value e.g. `a\,b,c` is ["a,b", "c"]. Empty list has no values.
+func splitTextList(s string) []string {
+	values := []string{}
+	if s == "" {
+		return values
+	}
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case ',':
+			values = append(values, unescapeText(s[start:i]))
+			start = i + 1
+		}
+	}
+	return append(values, unescapeText(s[start:]))
+}
+
+// Splits a structured value like N, ADR or ORG into components at `;` which are not escaped
+// and unescapes every component e.g. `Doe;John;;Dr.\;Prof.;` is ["Doe", "John", "", "Dr.;Prof.", ""].
+//
+// Components are not split further, so list components like "Jane,Janet" of N are returned
+// as a single string. Empty value has a single empty component.
+func SplitStructured(value string) []string {
+	components := splitComponents(value)
+	for i, c := range components {
+		components[i] = unescapeText(c)
+	}
+	return components
+}
+
+// Splits a structured value at `;` which are not escaped keeping components escaped.
+func splitComponents(value string) []string {
+	components := []string{}
+	start := 0
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			i++
+		case ';':
+			components = append(components, value[start:i])
+			start = i + 1
+		}
+	}
+	return append(components, value[start:])
+}
+
+// Joins components into a structured value like N, ADR or ORG escaping every component.
+// Reverts [SplitStructured] e.g. ["Doe", "John", "", "Dr.;Prof.", ""] is `Doe;John;;Dr.\;Prof.;`.
+func JoinStructured(components []string) string {
+	escaped := make([]string, len(components))
+	for i, c := range components {
+		escaped[i] = escapeText(c)
+	}
+	return strings.Join(escaped, ";")
+}
+
+// Reverts backslash escaping of TEXT values as per https://datatracker.ietf.org/doc/html/rfc6350#section-3.4
+func unescapeText(s string) string {
+	if !strings.Contains(s, "\\") {
+		return s
+	}
+	buf := strings.Builder{}
+	escaped := false
+	for _, r := range s {
+		if !escaped {
+			if r == '\\' {
+				escaped = true
+			} else {
+				buf.WriteRune(r)
+			}
+			continue
+		}
+		escaped = false
+		switch r {
+		case 'n', 'N':
+			buf.WriteByte('\n')
+		default:
+			buf.WriteRune(r)
+		}
+	}
+	if escaped {
+		buf.WriteByte('\\')
+	}
+	return buf.String()
+}
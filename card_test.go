@@ -0,0 +1,365 @@
+package vcard
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDecCard(t *testing.T) {
+
+	// crlfy trims leading spaces so folded line has to be written by hand
+	text := "BEGIN:VCARD\r\n" +
+		"VERSION:4.0\r\n" +
+		"FN:Alex FullName\r\n" +
+		"item1.TEL;TYPE=CELL:555\r\n" +
+		"TEL;TYPE=\"work,voice\":(555) 123-4567\r\n" +
+		"X-ABLabel:Hello\r\n" +
+		"NOTE:Long\r\n" +
+		"  note\\, folded\r\n" +
+		"END:VCARD\r\n"
+
+	c := Card{}
+	err := Unmarshal([]byte(text), &c)
+
+	assertEq(t, err, nil)
+	assertEq(t, c.Len(), 6)
+	assertEq(t, c.Version(), "4.0")
+	assertSlicesEq(t, c.Values("tel"), []string{"555", "(555) 123-4567"})
+	assertSlicesEq(t, c.Values("X-ABLABEL"), []string{"Hello"})
+
+	fn, found := c.FN()
+	assertEq(t, found, true)
+	assertEq(t, fn, "Alex FullName")
+
+	note, _ := c.Note()
+	assertEq(t, note, "Long note, folded")
+
+	_, found = c.UID()
+	assertEq(t, found, false)
+}
+
+func TestDecCardMalformedLine(t *testing.T) {
+
+	text := `BEGIN:VCARD
+VERSION:4.0
+FN
+END:VCARD
+`
+	c := Card{}
+	err := Unmarshal([]byte(text), &c)
+
+	assertErrIs(t, err, ErrParsing, "unable to decode line")
+}
+
+func TestCardSetDel(t *testing.T) {
+
+	c := Card{}
+	c.Add("FN", "Alex")
+	c.Add("TEL", "1")
+	c.Add("TEL", "2")
+	c.Add("NOTE", "Hello")
+
+	c.Set("tel", "3")
+	assertSlicesEq(t, c.Values("TEL"), []string{"3"})
+	assertEq(t, c.Len(), 3)
+
+	c.Del("FN")
+	_, found := c.FN()
+	assertEq(t, found, false)
+	assertEq(t, c.Len(), 2)
+}
+
+func TestEncCard(t *testing.T) {
+
+	c := Card{}
+	c.Add("FN", "Alex FullName")
+	c.Add("TEL", "555")
+	c.Add("X-ABLABEL", "Hello")
+
+	b, err := Marshal(c)
+
+	exp := `BEGIN:VCARD
+VERSION:4.0
+FN:Alex FullName
+TEL:555
+X-ABLABEL:Hello
+END:VCARD
+`
+	assertEq(t, err, nil)
+	assertStringsEq(t, string(b), crlfy(exp))
+}
+
+func TestCardRoundTrip(t *testing.T) {
+
+	text := `BEGIN:VCARD
+VERSION:3.0
+N:Doe;John;;;
+FN:John Doe
+item1.TEL;TYPE=WORK,VOICE:(555) 123-4567
+EMAIL;TYPE=PREF,INTERNET:john.doe@example.com
+END:VCARD
+`
+	c := Card{}
+	err := Unmarshal([]byte(crlfy(text)), &c)
+	assertEq(t, err, nil)
+
+	b, err := Marshal(c)
+
+	assertEq(t, err, nil)
+	assertStringsEq(t, string(b), crlfy(text))
+}
+
+func TestCardRFCExtensions(t *testing.T) {
+
+	text := `BEGIN:VCARD
+VERSION:4.0
+FN:Alex
+BIRTHPLACE:Babies'R'Us Hospital
+DEATHDATE:19960415
+HOBBY;LEVEL=high:reading
+HOBBY:sewing
+ORG-DIRECTORY:https://directory.example.com/
+CONTACT-URI:mailto:alex@example.com
+END:VCARD
+`
+	c := Card{}
+	err := Unmarshal([]byte(crlfy(text)), &c)
+	assertEq(t, err, nil)
+
+	place, _ := c.BirthPlace()
+	assertStringsEq(t, place, "Babies'R'Us Hospital")
+	date, _ := c.DeathDate()
+	assertStringsEq(t, date, "19960415")
+	assertSlicesEq(t, c.Hobbies(), []string{"reading", "sewing"})
+	assertSlicesEq(t, c.OrgDirectories(), []string{"https://directory.example.com/"})
+	assertSlicesEq(t, c.ContactURIs(), []string{"mailto:alex@example.com"})
+
+	s := StringSchemaV4{}
+	err = Unmarshal([]byte(crlfy(text)), &s)
+	assertEq(t, err, nil)
+	assertStringsEq(t, s.ORG_DIRECTORY, "https://directory.example.com/")
+	assertStringsEq(t, s.CONTACT_URI, "mailto:alex@example.com")
+
+	b, err := Marshal(StringSchemaV4{FN: "Alex", BIRTHPLACE: "Paris", CONTACT_URI: "https://example.com/contact"})
+	assertEq(t, err, nil)
+	assertEq(t, strings.Contains(string(b), "\r\nCONTACT-URI:https://example.com/contact\r\n"), true)
+	assertEq(t, strings.Contains(string(b), "\r\nBIRTHPLACE:Paris\r\n"), true)
+}
+
+func TestCardLists(t *testing.T) {
+
+	text := `BEGIN:VCARD
+VERSION:4.0
+FN:Alex
+CATEGORIES:work,friends
+CATEGORIES:a\,b
+NICKNAME:Al,Lex
+END:VCARD
+`
+	c := Card{}
+	err := Unmarshal([]byte(crlfy(text)), &c)
+
+	assertEq(t, err, nil)
+	assertSlicesEq(t, c.Categories(), []string{"work", "friends", "a,b"})
+	assertSlicesEq(t, c.Nicknames(), []string{"Al", "Lex"})
+}
+
+func TestCardTypedAccessors(t *testing.T) {
+
+	card, err := ParseRecord([]byte(crlfy(`BEGIN:VCARD
+VERSION:4.0
+FN:Alex
+TEL;TYPE=cell;PREF=1:+1 555 0100
+TEL;VALUE=uri;TYPE=work:tel:+1-555-0101
+EMAIL;TYPE=home:alex@example.com
+EMAIL:mailto:alex@work.example.com
+BDAY:--0415
+END:VCARD
+`)))
+	assertEq(t, err, nil)
+
+	assertDeepEq(t, card.Tels(), []Tel{
+		{Number: "+1 555 0100", Types: []string{"cell"}, Pref: 1},
+		{Number: "+1-555-0101", Types: []string{"work"}},
+	})
+	assertDeepEq(t, card.Emails(), []Email{
+		{Address: "alex@example.com", Types: []string{"home"}},
+		{Address: "alex@work.example.com"},
+	})
+	bday, found := card.Bday()
+	assertEq(t, found, true)
+	assertEq(t, bday, Date{Month: time.April, Day: 15})
+
+	card.Set("BDAY", "not a date")
+	_, found = card.Bday()
+	assertEq(t, found, false)
+
+	empty := Card{}
+	_, found = empty.Bday()
+	assertEq(t, found, false)
+	assertEq(t, len(empty.Tels()), 0)
+	assertEq(t, len(empty.Emails()), 0)
+}
+
+func TestCardFieldLastOccurrence(t *testing.T) {
+
+	card, err := ParseRecord([]byte(crlfy(`BEGIN:VCARD
+VERSION:2.1
+N:Doe;Jo;;;
+NOTE:a
+NOTE;TYPE=X:b
+AGENT:
+BEGIN:VCARD
+VERSION:2.1
+NOTE:nested
+END:VCARD
+END:VCARD
+`)))
+	assertEq(t, err, nil)
+
+	note, found := card.Field("note")
+	assertEq(t, found, true)
+	assertStringsEq(t, note, ";TYPE=X:b")
+}
+
+func TestParseProperty(t *testing.T) {
+
+	p, err := ParseProperty(`item1.tel;TYPE="work,voice";PREF=1;CELL:+1 555`)
+
+	assertEq(t, err, nil)
+	assertDeepEq(t, p, Property{
+		Group:  "item1",
+		Name:   "TEL",
+		Params: Params{{"TYPE", []string{"work", "voice"}}, {"PREF", []string{"1"}}, {Name: "CELL"}},
+		Value:  "+1 555",
+	})
+	assertSlicesEq(t, p.Params.Values("type"), []string{"work", "voice", "CELL"})
+	assertStringsEq(t, p.String(), "item1.TEL;TYPE=work,voice;PREF=1;CELL:+1 555")
+
+	p, err = ParseProperty(`ATTENDEE;CN="Doe, John":mailto:john@example.com`)
+	assertEq(t, err, nil)
+	cn, _ := p.Params.Get("CN")
+	assertStringsEq(t, cn, "Doe, John")
+	assertStringsEq(t, p.String(), `ATTENDEE;CN="Doe, John":mailto:john@example.com`)
+
+	_, err = ParseProperty("no value")
+	assertErrIs(t, err, ErrParsing, "unable to decode line")
+}
+
+func TestCardProperties(t *testing.T) {
+
+	c := Card{}
+	c.AddProperty(Property{Name: "version", Value: "4.0"})
+	c.AddProperty(Property{Group: "item1", Name: "TEL", Params: Params{{"TYPE", []string{"cell"}}}, Value: "555"})
+
+	props := c.Properties()
+	assertEq(t, len(props), 2)
+	assertStringsEq(t, props[0].Name, "VERSION")
+
+	props[1].Params[0].Name = "PREF"
+	assertStringsEq(t, c.Properties()[1].String(), "item1.TEL;TYPE=cell:555")
+}
+
+func TestCardClone(t *testing.T) {
+
+	c := Card{}
+	c.AddProperty(Property{Name: "VERSION", Value: "2.1"})
+	c.AddProperty(Property{Name: "TEL", Params: Params{{"TYPE", []string{"cell"}}, {"PREF", nil}}, Value: "555"})
+
+	clone := c.Clone()
+	assertDeepEq(t, clone.Properties(), c.Properties())
+
+	clone.props[1].Params[0].Values[0] = "work"
+	clone.props[1].Value = "556"
+	clone.Add("FN", "Alex")
+	assertStringsEq(t, c.Properties()[1].String(), "TEL;TYPE=cell;PREF:555")
+	assertEq(t, c.Len(), 2)
+	assertEq(t, clone.props[1].Params[1].Values == nil, true)
+}
+
+func TestEncWriteProperty(t *testing.T) {
+
+	buf := bytes.Buffer{}
+	enc := NewEncoder(&buf)
+
+	for _, p := range []Property{
+		{Name: "BEGIN", Value: "VCARD"},
+		{Name: "VERSION", Value: "4.0"},
+		{Name: "FN", Params: Params{{"LANGUAGE", []string{"en"}}}, Value: "Alex"},
+		{Name: "END", Value: "VCARD"},
+	} {
+		assertEq(t, enc.WriteProperty(p), nil)
+	}
+	assertStringsEq(t, buf.String(), crlfy(`BEGIN:VCARD
+VERSION:4.0
+FN;LANGUAGE=en:Alex
+END:VCARD
+`))
+
+	assertErrIs(t, enc.WriteProperty(Property{Value: "x"}), ErrVCard, "without a name")
+}
+
+func TestSplitJoinStructured(t *testing.T) {
+
+	components := SplitStructured(`Doe;John;Jane\,Janet;Dr.\;Prof.;Esq.\\`)
+	assertSlicesEq(t, components, []string{"Doe", "John", "Jane,Janet", "Dr.;Prof.", `Esq.\`})
+	assertStringsEq(t, JoinStructured(components), `Doe;John;Jane\,Janet;Dr.\;Prof.;Esq.\\`)
+
+	assertSlicesEq(t, SplitStructured(""), []string{""})
+	assertSlicesEq(t, SplitStructured(";;"), []string{"", "", ""})
+	assertStringsEq(t, JoinStructured([]string{"a\nb", ""}), `a\nb;`)
+}
+
+func TestCardLosslessRoundTrip(t *testing.T) {
+
+	text := "begin:vcard\r\n" +
+		"VERSION:2.1\r\n" +
+		"N:Doe;John\r\n" +
+		"item1.TEL;type=\"work,voice\";PREF;X-Custom=\"a:b\":+1 555\r\n" +
+		"item1.X-ABLabel:_$!<Work>!$_\r\n" +
+		"NOTE;ENCODING=QUOTED-PRINTABLE:line 1=0D=0A=\r\n" +
+		"line 2\r\n" +
+		"FN:John\r\n" +
+		"  Doe\r\n" +
+		"AGENT:\r\n" +
+		"BEGIN:VCARD\r\n" +
+		"VERSION:2.1\r\n" +
+		"N:Assistant;Jane\r\n" +
+		"END:VCARD\r\n" +
+		"X-EMPTY:\r\n" +
+		"end:vcard\r\n"
+
+	c := Card{}
+	err := Unmarshal([]byte(text), &c)
+	assertEq(t, err, nil)
+
+	b, err := Marshal(c)
+	assertEq(t, err, nil)
+	assertStringsEq(t, string(b), crlfy(`BEGIN:VCARD
+VERSION:2.1
+N:Doe;John
+item1.TEL;type=work,voice;PREF;X-Custom="a:b":+1 555
+item1.X-ABLABEL:_$!<Work>!$_
+NOTE;ENCODING=QUOTED-PRINTABLE:line 1=0D=0Aline 2
+FN:John Doe
+AGENT:
+BEGIN:VCARD
+VERSION:2.1
+N:Assistant;Jane
+END:VCARD
+X-EMPTY:
+END:VCARD
+`))
+
+	again := Card{}
+	err = Unmarshal(b, &again)
+	assertEq(t, err, nil)
+	assertDeepEq(t, again, c)
+
+	m := map[string]string{}
+	err = UnmarshalSchema([]byte(text), &m, []Schema{SchemaV2_1})
+	assertEq(t, err, nil)
+	assertStringsEq(t, m["N"], ":Doe;John")
+}
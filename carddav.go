@@ -0,0 +1,110 @@
+package vcard
+
+import (
+	"errors"
+	"slices"
+)
+
+// Validation profile matching rules common CardDAV servers (Radicale, Nextcloud, iCloud) enforce
+// on address object resources uploaded with PUT. See https://datatracker.ietf.org/doc/html/rfc6352#section-5.1
+//
+// Validating a card before sending it lets clients report a meaningful error instead of
+// a cryptic 4xx response from the server.
+type CardDAVProfile struct {
+	// Maximum size of an encoded address object resource in bytes. Zero means no limit.
+	//
+	// Servers advertise their limit in CARDDAV:max-resource-size property of an address book.
+	MaxResourceSize int
+
+	// Maximum number of properties in a single card. Zero means no limit.
+	MaxProperties int
+
+	// vCard versions accepted by the server.
+	Versions []string
+
+	// Requires exactly one UID property. CardDAV servers use UID to detect duplicates
+	// and reject cards without it.
+	RequireUID bool
+}
+
+// Profile accepted by most CardDAV servers. MaxResourceSize is deliberately conservative,
+// use the value advertised by your server if it is known.
+var DefaultCardDAVProfile = CardDAVProfile{
+	MaxResourceSize: 1 << 20,
+	MaxProperties:   1000,
+	Versions:        []string{"3.0", "4.0"},
+	RequireUID:      true,
+}
+
+// Validates an address object resource before it is uploaded to a CardDAV server.
+//
+// A resource has to contain exactly one vCard. Returns every violation found joined
+// with [errors.Join], each of them is [ErrValidation].
+func (p CardDAVProfile) Validate(data []byte) error {
+	if p.MaxResourceSize > 0 && len(data) > p.MaxResourceSize {
+		return validationErrf("resource size %v exceeds maximum of %v bytes", len(data), p.MaxResourceSize)
+	}
+
+	cards, err := parseCards(string(data))
+	if err != nil {
+		return err
+	}
+	if len(cards) != 1 {
+		return validationErrf("address object resource has to contain exactly one vCard, found %v", len(cards))
+	}
+
+	return p.validateCard(cards[0])
+}
+
+// Validates a card before it is encoded and uploaded to a CardDAV server.
+//
+// See [CardDAVProfile.Validate].
+func (p CardDAVProfile) ValidateCard(card Card) error {
+	if p.MaxResourceSize > 0 {
+		b, err := Marshal(card)
+		if err != nil {
+			return err
+		}
+		if len(b) > p.MaxResourceSize {
+			return validationErrf("resource size %v exceeds maximum of %v bytes", len(b), p.MaxResourceSize)
+		}
+	}
+	return p.validateCard(card)
+}
+
+func (p CardDAVProfile) validateCard(card Card) error {
+	errs := []error{}
+
+	switch card.count("VERSION") {
+	case 0:
+		errs = append(errs, validationErrf("card does not contain VERSION"))
+	case 1:
+		if len(p.Versions) != 0 && !slices.Contains(p.Versions, card.Version()) {
+			errs = append(errs, validationErrf("version %q is not accepted, use one of %q", card.Version(), p.Versions))
+		}
+	default:
+		errs = append(errs, validationErrf("card contains multiple VERSION properties"))
+	}
+
+	if n := card.count("FN"); n != 1 {
+		errs = append(errs, validationErrf("card has to contain exactly one FN, found %v", n))
+	}
+
+	if card.Version() == "3.0" && card.count("N") != 1 {
+		errs = append(errs, validationErrf("vCard 3.0 has to contain exactly one N, found %v", card.count("N")))
+	}
+
+	if p.RequireUID {
+		if n := card.count("UID"); n != 1 {
+			errs = append(errs, validationErrf("card has to contain exactly one UID, found %v", n))
+		} else if uid, _ := card.Get("UID"); uid == "" {
+			errs = append(errs, validationErrf("UID is empty"))
+		}
+	}
+
+	if p.MaxProperties > 0 && card.Len() > p.MaxProperties {
+		errs = append(errs, validationErrf("card has %v properties which exceeds maximum of %v", card.Len(), p.MaxProperties))
+	}
+
+	return errors.Join(errs...)
+}
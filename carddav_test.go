@@ -0,0 +1,83 @@
+package vcard
+
+import "testing"
+
+func TestCardDAVValid(t *testing.T) {
+
+	text := `BEGIN:VCARD
+VERSION:4.0
+UID:urn:uuid:4fbe8971-0bc3-424c-9c26-36c3e1eff6b1
+FN:Alex FullName
+TEL:555
+END:VCARD
+`
+	err := DefaultCardDAVProfile.Validate([]byte(crlfy(text)))
+
+	assertEq(t, err, nil)
+}
+
+func TestCardDAVMissingUIDAndMultipleFN(t *testing.T) {
+
+	text := `BEGIN:VCARD
+VERSION:4.0
+FN:Alex
+FN:Alex FullName
+END:VCARD
+`
+	err := DefaultCardDAVProfile.Validate([]byte(crlfy(text)))
+
+	assertErrIs(t, err, ErrValidation, "exactly one UID, found 0")
+	assertErrIs(t, err, ErrValidation, "exactly one FN, found 2")
+}
+
+func TestCardDAVMultipleCards(t *testing.T) {
+
+	text := `BEGIN:VCARD
+VERSION:4.0
+UID:1
+FN:Alex 1
+END:VCARD
+BEGIN:VCARD
+VERSION:4.0
+UID:2
+FN:Alex 2
+END:VCARD
+`
+	err := DefaultCardDAVProfile.Validate([]byte(crlfy(text)))
+
+	assertErrIs(t, err, ErrValidation, "exactly one vCard, found 2")
+}
+
+func TestCardDAVVersionAndSize(t *testing.T) {
+
+	text := `BEGIN:VCARD
+VERSION:2.1
+UID:1
+N:Alex;;;;
+FN:Alex
+END:VCARD
+`
+	err := DefaultCardDAVProfile.Validate([]byte(crlfy(text)))
+	assertErrIs(t, err, ErrValidation, "version \"2.1\" is not accepted")
+
+	profile := DefaultCardDAVProfile
+	profile.MaxResourceSize = 16
+
+	err = profile.Validate([]byte(crlfy(text)))
+	assertErrIs(t, err, ErrValidation, "exceeds maximum of 16 bytes")
+}
+
+func TestCardDAVValidateCard(t *testing.T) {
+
+	c := Card{}
+	c.Add("VERSION", "3.0")
+	c.Add("FN", "Alex")
+	c.Add("UID", "1")
+
+	err := DefaultCardDAVProfile.ValidateCard(c)
+	assertErrIs(t, err, ErrValidation, "vCard 3.0 has to contain exactly one N")
+
+	c.Add("N", "Alex;;;;")
+	err = DefaultCardDAVProfile.ValidateCard(c)
+	assertEq(t, err, nil)
+}
@@ -0,0 +1,742 @@
+package vcard
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// XML namespaces of WebDAV, CardDAV and getctag extension of calendarserver.org.
+const (
+	davNS       = "DAV:"
+	cardDAVNS   = "urn:ietf:params:xml:ns:carddav"
+	calServerNS = "http://calendarserver.org/ns/"
+)
+
+var davPrefixes = map[string]string{davNS: "D", cardDAVNS: "C", calServerNS: "CS"}
+
+// [http.Handler] serving cards of a [Store] as a single CardDAV address book as per
+// https://datatracker.ietf.org/doc/html/rfc6352 e.g.
+//
+//	store, _ := vcard.NewDirStore("contacts")
+//	http.Handle("/contacts/", vcard.NewCardDAVHandler(store, "/contacts/"))
+//
+// Every card is an address object resource named after its UID the same way [DirStore] names files
+// e.g. "/contacts/urn%3Auuid%3A1234.vcf". Handler supports OPTIONS, GET, HEAD, PUT, DELETE, PROPFIND
+// and REPORT with addressbook-query, addressbook-multiget and sync-collection reports.
+// Authentication, principals and discovery via /.well-known/carddav are left to the application.
+//
+// ETags are computed by [CardETag], getctag by [CardDAVHandler.CTag] and sync tokens by [CardDAVHandler.SyncToken].
+type CardDAVHandler struct {
+	store Store
+	path  string
+
+	// Serializes conditional writes since If-Match has to be checked and applied atomically
+	mu sync.Mutex
+
+	// Name of the address book shown by clients.
+	DisplayName string
+
+	// Validates cards uploaded with PUT. Invalid cards are rejected with 403 and CARDDAV:valid-address-data precondition.
+	Profile CardDAVProfile
+}
+
+// Creates new CardDAVHandler serving cards of store as an address book at path e.g. "/contacts/".
+// The handler uses [DefaultCardDAVProfile] and "Contacts" as DisplayName.
+func NewCardDAVHandler(store Store, path string) *CardDAVHandler {
+	if !strings.HasSuffix(path, "/") {
+		path += "/"
+	}
+	return &CardDAVHandler{store: store, path: path, DisplayName: "Contacts", Profile: DefaultCardDAVProfile}
+}
+
+// Returns ETag of a card served by [CardDAVHandler] e.g. `"3a7bd3e2..."`. ETag is a quoted
+// [Card.Fingerprint], so it changes only when the card does.
+func CardETag(card Card) string {
+	return `"` + card.Fingerprint() + `"`
+}
+
+// Returns getctag of the address book which changes whenever any card is created, modified or deleted.
+// It is computed from ETags of every card, so it also reflects changes made to the store directly.
+func (h *CardDAVHandler) CTag() (string, error) {
+	cards, err := h.store.List()
+	if err != nil {
+		return "", err
+	}
+	etags := make([]string, len(cards))
+	for i, c := range cards {
+		etags[i] = c.Fingerprint()
+	}
+	slices.Sort(etags)
+	sum := sha256.Sum256([]byte(strings.Join(etags, "\n")))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Returns sync token of the address book as per https://datatracker.ietf.org/doc/html/rfc6578
+// e.g. "data:,1700000000000000000". Token holds time of the latest change reported by [Store.Changes],
+// so sync-collection report returns changes reported after it. Changes made at the time of the token
+// are reported again, which clients handle as unchanged cards.
+func (h *CardDAVHandler) SyncToken() (string, error) {
+	changes, err := h.store.Changes(time.Time{})
+	if err != nil {
+		return "", err
+	}
+	return syncToken(changes, time.Time{}), nil
+}
+
+// Returns token holding time of the latest change or since if there are no changes.
+func syncToken(changes []Change, since time.Time) string {
+	for _, c := range changes {
+		if c.Time.After(since) {
+			since = c.Time
+		}
+	}
+	if since.IsZero() {
+		return "data:,0"
+	}
+	return "data:," + strconv.FormatInt(since.UnixNano(), 10)
+}
+
+func parseSyncToken(token string) (time.Time, error) {
+	n, err := strconv.ParseInt(strings.TrimPrefix(token, "data:,"), 10, 64)
+	if err != nil || !strings.HasPrefix(token, "data:,") {
+		return time.Time{}, &cardDAVError{http.StatusForbidden, "D:valid-sync-token", fmt.Errorf("invalid sync token %q", token)}
+	}
+	if n == 0 {
+		return time.Time{}, nil
+	}
+	return time.Unix(0, n), nil
+}
+
+// Handles a request to the address book or one of its resources. Requests outside of the path get 404.
+func (h *CardDAVHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	uid, isCollection, found := h.resource(r.URL.EscapedPath())
+	if !found {
+		http.NotFound(w, r)
+		return
+	}
+
+	var err error
+	switch {
+	case r.Method == http.MethodOptions:
+		w.Header().Set("DAV", "1, 3, addressbook")
+		w.Header().Set("Allow", cardDAVMethods)
+	case r.Method == "PROPFIND":
+		err = h.propfind(w, r, uid, isCollection)
+	case r.Method == "REPORT" && isCollection:
+		err = h.report(w, r)
+	case (r.Method == http.MethodGet || r.Method == http.MethodHead) && !isCollection:
+		err = h.get(w, r, uid)
+	case r.Method == http.MethodPut && !isCollection:
+		err = h.put(w, r, uid)
+	case r.Method == http.MethodDelete && !isCollection:
+		err = h.delete(w, r, uid)
+	default:
+		w.Header().Set("Allow", cardDAVMethods)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+	if err != nil {
+		writeCardDAVError(w, err)
+	}
+}
+
+const cardDAVMethods = "OPTIONS, GET, HEAD, PUT, DELETE, PROPFIND, REPORT"
+
+// Returns UID of a resource at escaped path. isCollection is true for the address book itself.
+func (h *CardDAVHandler) resource(path string) (uid string, isCollection bool, found bool) {
+	if path == h.path || path+"/" == h.path {
+		return "", true, true
+	}
+	name, found := strings.CutPrefix(path, h.path)
+	if !found || strings.Contains(name, "/") || !strings.HasSuffix(name, ".vcf") {
+		return "", false, false
+	}
+	uid, err := url.PathUnescape(strings.TrimSuffix(name, ".vcf"))
+	if err != nil || uid == "" {
+		return "", false, false
+	}
+	return uid, false, true
+}
+
+func (h *CardDAVHandler) href(uid string) string {
+	return h.path + storeFileName(uid)
+}
+
+func (h *CardDAVHandler) get(w http.ResponseWriter, r *http.Request, uid string) error {
+	card, err := h.store.Get(uid)
+	if err != nil {
+		return err
+	}
+	etag := CardETag(card)
+	w.Header().Set("ETag", etag)
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return nil
+	}
+	b, err := Marshal(card)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "text/vcard; charset=utf-8")
+	w.Header().Set("Content-Length", strconv.Itoa(len(b)))
+	if r.Method != http.MethodHead {
+		_, _ = w.Write(b)
+	}
+	return nil
+}
+
+// Stores a card. UID of the card has to match the resource name since [Store] identifies cards by UID.
+// If-Match and If-None-Match: * preconditions are honored.
+func (h *CardDAVHandler) put(w http.ResponseWriter, r *http.Request, uid string) error {
+	if contentType := r.Header.Get("Content-Type"); contentType != "" {
+		if mediaType, _, _ := mime.ParseMediaType(contentType); mediaType != "text/vcard" && mediaType != "text/x-vcard" {
+			return &cardDAVError{http.StatusUnsupportedMediaType, "C:supported-address-data", fmt.Errorf("unsupported content type %q", contentType)}
+		}
+	}
+	limit := int64(h.Profile.MaxResourceSize)
+	if limit <= 0 {
+		limit = maxCardDAVResourceSize
+	}
+	data, err := readBody(w, r, limit, "C:max-resource-size")
+	if err != nil {
+		return err
+	}
+	if err := h.Profile.Validate(data); err != nil {
+		return &cardDAVError{http.StatusForbidden, "C:valid-address-data", err}
+	}
+	cards, err := parseCards(string(data))
+	if err != nil {
+		return &cardDAVError{http.StatusForbidden, "C:valid-address-data", err}
+	}
+	card := cards[0]
+	if cardUID, _ := card.UID(); cardUID != uid {
+		return &cardDAVError{http.StatusConflict, "", fmt.Errorf("UID %q does not match resource name, expected %s", cardUID, h.href(cardUID))}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	exists, err := h.precondition(r, uid)
+	if err != nil {
+		return err
+	}
+	if err := h.store.Put(card); err != nil {
+		return err
+	}
+	w.Header().Set("ETag", CardETag(card))
+	if exists {
+		w.WriteHeader(http.StatusNoContent)
+	} else {
+		w.WriteHeader(http.StatusCreated)
+	}
+	return nil
+}
+
+func (h *CardDAVHandler) delete(w http.ResponseWriter, r *http.Request, uid string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, err := h.precondition(r, uid); err != nil {
+		return err
+	}
+	if err := h.store.Delete(uid); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// Checks If-Match and If-None-Match headers against the stored card and reports whether the card exists.
+func (h *CardDAVHandler) precondition(r *http.Request, uid string) (bool, error) {
+	current, err := h.store.Get(uid)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return false, err
+	}
+	exists := err == nil
+	etag := ""
+	if exists {
+		etag = CardETag(current)
+	}
+
+	failed := &cardDAVError{http.StatusPreconditionFailed, "", fmt.Errorf("precondition failed for %s", h.href(uid))}
+	if ifMatch := r.Header.Get("If-Match"); ifMatch != "" && (!exists || !etagMatches(ifMatch, etag)) {
+		return exists, failed
+	}
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" && exists && etagMatches(ifNoneMatch, etag) {
+		return exists, failed
+	}
+	return exists, nil
+}
+
+// Reports whether header value like `"a", W/"b"` or "*" matches etag.
+func etagMatches(header string, etag string) bool {
+	for candidate := range strings.SplitSeq(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || (candidate == etag && etag != "") {
+			return true
+		}
+	}
+	return false
+}
+
+// Body of PROPFIND and REPORT requests. XMLName tells which request it is.
+type davRequest struct {
+	XMLName   xml.Name
+	AllProp   *struct{}     `xml:"DAV: allprop"`
+	Prop      *davProp      `xml:"DAV: prop"`
+	Hrefs     []string      `xml:"DAV: href"`
+	SyncToken string        `xml:"DAV: sync-token"`
+	Filter    cardDAVFilter `xml:"urn:ietf:params:xml:ns:carddav filter"`
+}
+
+type davProp struct {
+	Names []struct{ XMLName xml.Name } `xml:",any"`
+}
+
+// Returns names of requested properties. Nil means every property.
+func (req davRequest) propNames() []xml.Name {
+	if req.Prop == nil || req.AllProp != nil {
+		return nil
+	}
+	names := []xml.Name{}
+	for _, n := range req.Prop.Names {
+		names = append(names, n.XMLName)
+	}
+	return names
+}
+
+// Maximum sizes of request bodies. Cards are limited by [CardDAVProfile.MaxResourceSize] and
+// by maxCardDAVResourceSize if the profile has no limit.
+const (
+	maxDAVRequestSize      = 1 << 20
+	maxCardDAVResourceSize = 16 << 20
+)
+
+// Reads a request body of at most limit bytes without reading the rest of a larger body.
+// Returns an error with status 413 and an optional precondition if the body is larger.
+func readBody(w http.ResponseWriter, r *http.Request, limit int64, condition string) ([]byte, error) {
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, limit))
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return nil, &cardDAVError{http.StatusRequestEntityTooLarge, condition, fmt.Errorf("request body exceeds maximum of %v bytes", limit)}
+	}
+	if err != nil {
+		return nil, vCardErrf("unable to read: %w", err)
+	}
+	return data, nil
+}
+
+func readDAVRequest(w http.ResponseWriter, r *http.Request) (davRequest, error) {
+	req := davRequest{}
+	data, err := readBody(w, r, maxDAVRequestSize, "")
+	if err != nil {
+		return req, err
+	}
+	if len(strings.TrimSpace(string(data))) == 0 {
+		return req, nil
+	}
+	if err := xml.Unmarshal(data, &req); err != nil {
+		return req, parsingErrf("malformed XML body: %w", err)
+	}
+	return req, nil
+}
+
+func (h *CardDAVHandler) propfind(w http.ResponseWriter, r *http.Request, uid string, isCollection bool) error {
+	req, err := readDAVRequest(w, r)
+	if err != nil {
+		return err
+	}
+	names := req.propNames()
+
+	if !isCollection {
+		card, err := h.store.Get(uid)
+		if err != nil {
+			return err
+		}
+		return writeMultistatus(w, "", h.cardResponse(card, names))
+	}
+
+	responses := []davResponse{propResponse(h.path, names, collectionProps, h.collectionProperty)}
+	if r.Header.Get("Depth") != "0" {
+		cards, err := h.store.List()
+		if err != nil {
+			return err
+		}
+		for _, card := range cards {
+			responses = append(responses, h.cardResponse(card, names))
+		}
+	}
+	for _, resp := range responses {
+		if resp.err != nil {
+			return resp.err
+		}
+	}
+	return writeMultistatus(w, "", responses...)
+}
+
+func (h *CardDAVHandler) report(w http.ResponseWriter, r *http.Request) error {
+	req, err := readDAVRequest(w, r)
+	if err != nil {
+		return err
+	}
+	names := req.propNames()
+
+	responses := []davResponse{}
+	token := ""
+	switch req.XMLName {
+	case xml.Name{Space: cardDAVNS, Local: "addressbook-query"}:
+		cards, err := h.store.List()
+		if err != nil {
+			return err
+		}
+		for _, card := range Filter(cards, req.Filter.predicate()) {
+			responses = append(responses, h.cardResponse(card, names))
+		}
+
+	case xml.Name{Space: cardDAVNS, Local: "addressbook-multiget"}:
+		for _, href := range req.Hrefs {
+			u, err := url.Parse(strings.TrimSpace(href))
+			if err != nil {
+				return parsingErrf("malformed href %q: %w", href, err)
+			}
+			uid, isCollection, found := h.resource(u.EscapedPath())
+			if !found || isCollection {
+				responses = append(responses, davResponse{href: href, status: http.StatusNotFound})
+				continue
+			}
+			card, err := h.store.Get(uid)
+			if errors.Is(err, ErrNotFound) {
+				responses = append(responses, davResponse{href: href, status: http.StatusNotFound})
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			responses = append(responses, h.cardResponse(card, names))
+		}
+
+	case xml.Name{Space: davNS, Local: "sync-collection"}:
+		since, err := parseSyncToken(req.SyncToken)
+		if req.SyncToken == "" {
+			since, err = time.Time{}, nil
+		}
+		if err != nil {
+			return err
+		}
+		// Changes made at the time of the token are reported again since file systems may store
+		// modification time coarsely and a change made right after the token may have the same time
+		after := since
+		if !since.IsZero() {
+			after = since.Add(-1)
+		}
+		changes, err := h.store.Changes(after)
+		if err != nil {
+			return err
+		}
+		// The latest change of a card wins
+		latest := map[string]Change{}
+		order := []string{}
+		for _, c := range changes {
+			if _, seen := latest[c.UID]; !seen {
+				order = append(order, c.UID)
+			}
+			latest[c.UID] = c
+		}
+		for _, uid := range order {
+			c := latest[uid]
+			switch {
+			case c.Deleted && since.IsZero():
+				// Initial sync does not report deleted cards
+			case c.Deleted:
+				responses = append(responses, davResponse{href: h.href(uid), status: http.StatusNotFound})
+			default:
+				responses = append(responses, h.cardResponse(c.Card, names))
+			}
+		}
+		token = syncToken(changes, since)
+
+	default:
+		return &cardDAVError{http.StatusForbidden, "D:supported-report", fmt.Errorf("unsupported report %s %s", req.XMLName.Space, req.XMLName.Local)}
+	}
+	for _, resp := range responses {
+		if resp.err != nil {
+			return resp.err
+		}
+	}
+	return writeMultistatus(w, token, responses...)
+}
+
+// Properties of the address book returned for allprop.
+var collectionProps = []xml.Name{
+	{Space: davNS, Local: "resourcetype"},
+	{Space: davNS, Local: "displayname"},
+	{Space: davNS, Local: "sync-token"},
+	{Space: davNS, Local: "supported-report-set"},
+	{Space: calServerNS, Local: "getctag"},
+	{Space: cardDAVNS, Local: "supported-address-data"},
+	{Space: cardDAVNS, Local: "max-resource-size"},
+}
+
+// Properties of address object resources returned for allprop. address-data is returned only when requested.
+var cardProps = []xml.Name{
+	{Space: davNS, Local: "resourcetype"},
+	{Space: davNS, Local: "getetag"},
+	{Space: davNS, Local: "getcontenttype"},
+}
+
+// Returns inner XML of a property of the address book.
+func (h *CardDAVHandler) collectionProperty(name xml.Name) (string, bool, error) {
+	switch name {
+	case xml.Name{Space: davNS, Local: "resourcetype"}:
+		return "<D:collection/><C:addressbook/>", true, nil
+	case xml.Name{Space: davNS, Local: "displayname"}:
+		return xmlEscape(h.DisplayName), true, nil
+	case xml.Name{Space: davNS, Local: "sync-token"}:
+		token, err := h.SyncToken()
+		return xmlEscape(token), true, err
+	case xml.Name{Space: calServerNS, Local: "getctag"}:
+		ctag, err := h.CTag()
+		return xmlEscape(ctag), true, err
+	case xml.Name{Space: davNS, Local: "supported-report-set"}:
+		b := strings.Builder{}
+		for _, report := range []string{"C:addressbook-query", "C:addressbook-multiget", "D:sync-collection"} {
+			b.WriteString("<D:supported-report><D:report><" + report + "/></D:report></D:supported-report>")
+		}
+		return b.String(), true, nil
+	case xml.Name{Space: cardDAVNS, Local: "supported-address-data"}:
+		b := strings.Builder{}
+		for _, version := range orDefault(h.Profile.Versions, []string{"3.0", "4.0"}) {
+			b.WriteString(`<C:address-data-type content-type="text/vcard" version="` + xmlEscape(version) + `"/>`)
+		}
+		return b.String(), true, nil
+	case xml.Name{Space: cardDAVNS, Local: "max-resource-size"}:
+		return strconv.Itoa(h.Profile.MaxResourceSize), h.Profile.MaxResourceSize > 0, nil
+	}
+	return "", false, nil
+}
+
+func (h *CardDAVHandler) cardResponse(card Card, names []xml.Name) davResponse {
+	uid, _ := card.UID()
+	return propResponse(h.href(uid), names, cardProps, func(name xml.Name) (string, bool, error) {
+		switch name {
+		case xml.Name{Space: davNS, Local: "resourcetype"}:
+			return "", true, nil
+		case xml.Name{Space: davNS, Local: "getetag"}:
+			return xmlEscape(CardETag(card)), true, nil
+		case xml.Name{Space: davNS, Local: "getcontenttype"}:
+			return "text/vcard; charset=utf-8", true, nil
+		case xml.Name{Space: cardDAVNS, Local: "address-data"}:
+			b, err := Marshal(card)
+			return xmlEscape(string(b)), true, err
+		}
+		return "", false, nil
+	})
+}
+
+func orDefault[T any](values []T, fallback []T) []T {
+	if len(values) == 0 {
+		return fallback
+	}
+	return values
+}
+
+// Single response of a multistatus. Either status or properties are set.
+type davResponse struct {
+	href    string
+	status  int
+	found   []string
+	missing []xml.Name
+	err     error
+}
+
+// Returns a response with values of requested properties found by prop. Nil names mean allprop ones.
+func propResponse(href string, names []xml.Name, allprop []xml.Name, prop func(xml.Name) (string, bool, error)) davResponse {
+	resp := davResponse{href: href}
+	for _, name := range orDefault(names, allprop) {
+		value, found, err := prop(name)
+		switch {
+		case err != nil:
+			resp.err = err
+			return resp
+		case found:
+			resp.found = append(resp.found, davElement(name, value))
+		case names != nil:
+			resp.missing = append(resp.missing, name)
+		}
+	}
+	return resp
+}
+
+// Returns XML of an element with inner XML. Elements of unknown namespaces declare them.
+func davElement(name xml.Name, inner string) string {
+	prefix, known := davPrefixes[name.Space]
+	attrs := ""
+	if !known {
+		prefix, attrs = "X", ` xmlns:X="`+xmlEscape(name.Space)+`"`
+	}
+	if inner == "" {
+		return "<" + prefix + ":" + name.Local + attrs + "/>"
+	}
+	return "<" + prefix + ":" + name.Local + attrs + ">" + inner + "</" + prefix + ":" + name.Local + ">"
+}
+
+func writeMultistatus(w http.ResponseWriter, syncToken string, responses ...davResponse) error {
+	b := strings.Builder{}
+	b.WriteString(`<?xml version="1.0" encoding="utf-8"?>` + "\n")
+	b.WriteString(`<D:multistatus xmlns:D="DAV:" xmlns:C="` + cardDAVNS + `" xmlns:CS="` + calServerNS + `">`)
+	for _, resp := range responses {
+		b.WriteString("<D:response><D:href>" + xmlEscape(resp.href) + "</D:href>")
+		if resp.status != 0 {
+			b.WriteString("<D:status>" + statusLine(resp.status) + "</D:status>")
+		}
+		if len(resp.found) > 0 {
+			b.WriteString("<D:propstat><D:prop>" + strings.Join(resp.found, "") + "</D:prop>")
+			b.WriteString("<D:status>" + statusLine(http.StatusOK) + "</D:status></D:propstat>")
+		}
+		if len(resp.missing) > 0 {
+			b.WriteString("<D:propstat><D:prop>")
+			for _, name := range resp.missing {
+				b.WriteString(davElement(name, ""))
+			}
+			b.WriteString("</D:prop><D:status>" + statusLine(http.StatusNotFound) + "</D:status></D:propstat>")
+		}
+		b.WriteString("</D:response>")
+	}
+	if syncToken != "" {
+		b.WriteString("<D:sync-token>" + xmlEscape(syncToken) + "</D:sync-token>")
+	}
+	b.WriteString("</D:multistatus>\n")
+
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(http.StatusMultiStatus)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func statusLine(status int) string {
+	return "HTTP/1.1 " + strconv.Itoa(status) + " " + http.StatusText(status)
+}
+
+func xmlEscape(s string) string {
+	b := strings.Builder{}
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// Error of a CardDAV request with HTTP status and an optional precondition element of
+// https://datatracker.ietf.org/doc/html/rfc4918#section-16 e.g. "C:valid-address-data".
+type cardDAVError struct {
+	status    int
+	condition string
+	err       error
+}
+
+func (e *cardDAVError) Error() string { return e.err.Error() }
+func (e *cardDAVError) Unwrap() error { return e.err }
+
+func writeCardDAVError(w http.ResponseWriter, err error) {
+	var davErr *cardDAVError
+	status := http.StatusInternalServerError
+	switch {
+	case errors.As(err, &davErr):
+		status = davErr.status
+	case errors.Is(err, ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, ErrParsing):
+		status = http.StatusBadRequest
+	}
+	if davErr == nil || davErr.condition == "" {
+		http.Error(w, err.Error(), status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(status)
+	_, _ = io.WriteString(w, `<?xml version="1.0" encoding="utf-8"?>`+"\n"+
+		`<D:error xmlns:D="DAV:" xmlns:C="`+cardDAVNS+`"><`+davErr.condition+`/><D:responsedescription>`+
+		xmlEscape(err.Error())+"</D:responsedescription></D:error>\n")
+}
+
+// CARDDAV:filter of addressbook-query report as per https://datatracker.ietf.org/doc/html/rfc6352#section-10.5
+// param-filter elements are ignored.
+type cardDAVFilter struct {
+	Test        string              `xml:"test,attr"`
+	PropFilters []cardDAVPropFilter `xml:"urn:ietf:params:xml:ns:carddav prop-filter"`
+}
+
+type cardDAVPropFilter struct {
+	Name         string             `xml:"name,attr"`
+	Test         string             `xml:"test,attr"`
+	IsNotDefined *struct{}          `xml:"urn:ietf:params:xml:ns:carddav is-not-defined"`
+	TextMatches  []cardDAVTextMatch `xml:"urn:ietf:params:xml:ns:carddav text-match"`
+}
+
+type cardDAVTextMatch struct {
+	Text            string `xml:",chardata"`
+	Collation       string `xml:"collation,attr"`
+	MatchType       string `xml:"match-type,attr"`
+	NegateCondition string `xml:"negate-condition,attr"`
+}
+
+// Returns predicate of the filter. Filter without prop-filters matches every card.
+func (f cardDAVFilter) predicate() Predicate {
+	preds := []Predicate{}
+	for _, pf := range f.PropFilters {
+		preds = append(preds, pf.predicate())
+	}
+	if f.Test == "allof" || len(preds) == 0 {
+		return And(preds...)
+	}
+	return Or(preds...)
+}
+
+func (f cardDAVPropFilter) predicate() Predicate {
+	if f.IsNotDefined != nil {
+		return Not(HasProperty(f.Name))
+	}
+	if len(f.TextMatches) == 0 {
+		return HasProperty(f.Name)
+	}
+	return propertyMatches(f.Name, func(value string) bool {
+		for _, tm := range f.TextMatches {
+			matched := tm.match(value)
+			if f.Test == "allof" && !matched {
+				return false
+			}
+			if f.Test != "allof" && matched {
+				return true
+			}
+		}
+		return f.Test == "allof"
+	})
+}
+
+// Matches value with i;unicode-casemap collation by default or i;octet which is case-sensitive.
+func (tm cardDAVTextMatch) match(value string) bool {
+	text := tm.Text
+	if tm.Collation != "i;octet" {
+		text, value = strings.ToLower(text), strings.ToLower(value)
+	}
+	matched := false
+	switch tm.MatchType {
+	case "equals":
+		matched = value == text
+	case "starts-with":
+		matched = strings.HasPrefix(value, text)
+	case "ends-with":
+		matched = strings.HasSuffix(value, text)
+	default:
+		matched = strings.Contains(value, text)
+	}
+	return matched != (tm.NegateCondition == "yes")
+}
@@ -0,0 +1,226 @@
+package vcard
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func newTestCardDAVHandler(t *testing.T) *CardDAVHandler {
+	t.Helper()
+	store, err := NewDirStore(t.TempDir())
+	assertEq(t, err, nil)
+	return NewCardDAVHandler(store, "/contacts")
+}
+
+func serveCardDAV(h http.Handler, method string, path string, body string, headers ...string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, path, strings.NewReader(body))
+	for i := 0; i+1 < len(headers); i += 2 {
+		r.Header.Set(headers[i], headers[i+1])
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+const cardDAVAlex = "BEGIN:VCARD\r\nVERSION:4.0\r\nUID:urn:uuid:1\r\nFN:Alex Smith\r\nEMAIL:alex@example.com\r\nEND:VCARD\r\n"
+
+func TestCardDAVHandlerPutGetDelete(t *testing.T) {
+	h := newTestCardDAVHandler(t)
+	const path = "/contacts/urn%3Auuid%3A1.vcf"
+
+	w := serveCardDAV(h, "PUT", path, cardDAVAlex, "Content-Type", "text/vcard; charset=utf-8", "If-None-Match", "*")
+	assertEq(t, w.Code, http.StatusCreated)
+	etag := w.Header().Get("ETag")
+	assertEq(t, regexp.MustCompile(`^"[0-9a-f]{64}"$`).MatchString(etag), true)
+
+	w = serveCardDAV(h, "PUT", path, cardDAVAlex, "If-None-Match", "*")
+	assertEq(t, w.Code, http.StatusPreconditionFailed)
+
+	w = serveCardDAV(h, "GET", path, "")
+	assertEq(t, w.Code, http.StatusOK)
+	assertEq(t, w.Header().Get("ETag"), etag)
+	assertEq(t, w.Header().Get("Content-Type"), "text/vcard; charset=utf-8")
+	assertEq(t, strings.Contains(w.Body.String(), "FN:Alex Smith"), true)
+
+	w = serveCardDAV(h, "GET", path, "", "If-None-Match", etag)
+	assertEq(t, w.Code, http.StatusNotModified)
+
+	updated := strings.Replace(cardDAVAlex, "Alex Smith", "Alex J. Smith", 1)
+	w = serveCardDAV(h, "PUT", path, updated, "If-Match", `"stale"`)
+	assertEq(t, w.Code, http.StatusPreconditionFailed)
+	w = serveCardDAV(h, "PUT", path, updated, "If-Match", etag)
+	assertEq(t, w.Code, http.StatusNoContent)
+	assertEq(t, w.Header().Get("ETag") != etag, true)
+
+	w = serveCardDAV(h, "DELETE", path, "", "If-Match", etag)
+	assertEq(t, w.Code, http.StatusPreconditionFailed)
+	w = serveCardDAV(h, "DELETE", path, "")
+	assertEq(t, w.Code, http.StatusNoContent)
+	w = serveCardDAV(h, "GET", path, "")
+	assertEq(t, w.Code, http.StatusNotFound)
+}
+
+func TestCardDAVHandlerPutInvalid(t *testing.T) {
+	h := newTestCardDAVHandler(t)
+
+	w := serveCardDAV(h, "PUT", "/contacts/urn%3Auuid%3A1.vcf", "BEGIN:VCARD\r\nVERSION:4.0\r\nUID:urn:uuid:1\r\nEND:VCARD\r\n")
+	assertEq(t, w.Code, http.StatusForbidden)
+	assertEq(t, strings.Contains(w.Body.String(), "<C:valid-address-data/>"), true)
+	assertEq(t, strings.Contains(w.Body.String(), "exactly one FN"), true)
+
+	w = serveCardDAV(h, "PUT", "/contacts/other.vcf", cardDAVAlex)
+	assertEq(t, w.Code, http.StatusConflict)
+	assertEq(t, strings.Contains(w.Body.String(), "/contacts/urn%3Auuid%3A1.vcf"), true)
+
+	w = serveCardDAV(h, "PUT", "/contacts/urn%3Auuid%3A1.vcf", cardDAVAlex, "Content-Type", "application/json")
+	assertEq(t, w.Code, http.StatusUnsupportedMediaType)
+
+	w = serveCardDAV(h, "PUT", "/contacts/", cardDAVAlex)
+	assertEq(t, w.Code, http.StatusMethodNotAllowed)
+	w = serveCardDAV(h, "GET", "/elsewhere/a.vcf", "")
+	assertEq(t, w.Code, http.StatusNotFound)
+}
+
+// Endless request body of spaces.
+type endlessBody struct{}
+
+func (endlessBody) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = ' '
+	}
+	return len(p), nil
+}
+
+func TestCardDAVHandlerBodyTooLarge(t *testing.T) {
+	h := newTestCardDAVHandler(t)
+	h.Profile.MaxResourceSize = 64
+
+	w := serveCardDAV(h, "PUT", "/contacts/urn%3Auuid%3A1.vcf", cardDAVAlex)
+	assertEq(t, w.Code, http.StatusRequestEntityTooLarge)
+	assertEq(t, strings.Contains(w.Body.String(), "<C:max-resource-size/>"), true)
+
+	// Bodies are not read beyond the limit
+	h.Profile.MaxResourceSize = 0
+	for _, method := range []string{"PUT", "PROPFIND", "REPORT"} {
+		r := httptest.NewRequest(method, "/contacts/urn%3Auuid%3A1.vcf", endlessBody{})
+		if method == "REPORT" {
+			r = httptest.NewRequest(method, "/contacts/", endlessBody{})
+		}
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		assertEq(t, w.Code, http.StatusRequestEntityTooLarge)
+	}
+}
+
+func TestCardDAVHandlerPropfind(t *testing.T) {
+	h := newTestCardDAVHandler(t)
+	serveCardDAV(h, "PUT", "/contacts/urn%3Auuid%3A1.vcf", cardDAVAlex)
+
+	w := serveCardDAV(h, "OPTIONS", "/contacts/", "")
+	assertEq(t, w.Header().Get("DAV"), "1, 3, addressbook")
+
+	body := `<?xml version="1.0"?>
+		<D:propfind xmlns:D="DAV:" xmlns:CS="http://calendarserver.org/ns/" xmlns:X="urn:x">
+			<D:prop><D:resourcetype/><D:displayname/><CS:getctag/><D:getetag/><X:unknown/></D:prop>
+		</D:propfind>`
+	w = serveCardDAV(h, "PROPFIND", "/contacts/", body, "Depth", "1")
+	assertEq(t, w.Code, http.StatusMultiStatus)
+	ctag, err := h.CTag()
+	assertEq(t, err, nil)
+	resp := w.Body.String()
+	for _, s := range []string{
+		"<D:response><D:href>/contacts/</D:href><D:propstat><D:prop><D:resourcetype><D:collection/><C:addressbook/></D:resourcetype><D:displayname>Contacts</D:displayname><CS:getctag>" + ctag + "</CS:getctag></D:prop>",
+		`<D:prop><D:getetag/><X:unknown xmlns:X="urn:x"/></D:prop><D:status>HTTP/1.1 404 Not Found</D:status>`,
+		"<D:href>/contacts/urn%3Auuid%3A1.vcf</D:href><D:propstat><D:prop><D:resourcetype/><D:getetag>&#34;",
+	} {
+		if !strings.Contains(resp, s) {
+			t.Errorf("response does not contain %s:\n%s", s, resp)
+		}
+	}
+
+	w = serveCardDAV(h, "PROPFIND", "/contacts/", "", "Depth", "0")
+	assertEq(t, strings.Contains(w.Body.String(), "<D:sync-token>data:,"), true)
+	assertEq(t, strings.Contains(w.Body.String(), "urn%3Auuid%3A1.vcf"), false)
+
+	serveCardDAV(h, "PUT", "/contacts/urn%3Auuid%3A2.vcf", strings.ReplaceAll(cardDAVAlex, "uuid:1", "uuid:2"))
+	newCTag, err := h.CTag()
+	assertEq(t, err, nil)
+	assertEq(t, newCTag != ctag, true)
+}
+
+func TestCardDAVHandlerReport(t *testing.T) {
+	h := newTestCardDAVHandler(t)
+	serveCardDAV(h, "PUT", "/contacts/urn%3Auuid%3A1.vcf", cardDAVAlex)
+	serveCardDAV(h, "PUT", "/contacts/urn%3Auuid%3A2.vcf", "BEGIN:VCARD\r\nVERSION:4.0\r\nUID:urn:uuid:2\r\nFN:Jane Doe\r\nEND:VCARD\r\n")
+
+	query := `<C:addressbook-query xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:carddav">
+			<D:prop><D:getetag/><C:address-data/></D:prop>
+			<C:filter test="anyof">
+				<C:prop-filter name="FN"><C:text-match match-type="starts-with">jane</C:text-match></C:prop-filter>
+			</C:filter>
+		</C:addressbook-query>`
+	w := serveCardDAV(h, "REPORT", "/contacts/", query)
+	assertEq(t, w.Code, http.StatusMultiStatus)
+	assertEq(t, strings.Contains(w.Body.String(), "FN:Jane Doe"), true)
+	assertEq(t, strings.Contains(w.Body.String(), "Alex"), false)
+
+	query = strings.Replace(query, `<C:text-match match-type="starts-with">jane</C:text-match>`, "<C:is-not-defined/>", 1)
+	query = strings.Replace(query, `name="FN"`, `name="EMAIL"`, 1)
+	w = serveCardDAV(h, "REPORT", "/contacts/", query)
+	assertEq(t, strings.Contains(w.Body.String(), "FN:Jane Doe"), true)
+	assertEq(t, strings.Contains(w.Body.String(), "Alex"), false)
+
+	multiget := `<C:addressbook-multiget xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:carddav">
+			<D:prop><C:address-data/></D:prop>
+			<D:href>/contacts/urn:uuid:1.vcf</D:href>
+			<D:href>/contacts/missing.vcf</D:href>
+		</C:addressbook-multiget>`
+	w = serveCardDAV(h, "REPORT", "/contacts/", multiget)
+	assertEq(t, strings.Contains(w.Body.String(), "FN:Alex Smith"), true)
+	assertEq(t, strings.Contains(w.Body.String(), "<D:href>/contacts/missing.vcf</D:href><D:status>HTTP/1.1 404 Not Found</D:status>"), true)
+
+	w = serveCardDAV(h, "REPORT", "/contacts/", `<D:expand-property xmlns:D="DAV:"/>`)
+	assertEq(t, w.Code, http.StatusForbidden)
+	assertEq(t, strings.Contains(w.Body.String(), "<D:supported-report/>"), true)
+}
+
+func TestCardDAVHandlerSyncCollection(t *testing.T) {
+	h := newTestCardDAVHandler(t)
+	serveCardDAV(h, "PUT", "/contacts/urn%3Auuid%3A1.vcf", cardDAVAlex)
+
+	sync := func(token string) string {
+		w := serveCardDAV(h, "REPORT", "/contacts/", `<D:sync-collection xmlns:D="DAV:">
+				<D:sync-token>`+token+`</D:sync-token><D:sync-level>1</D:sync-level><D:prop><D:getetag/></D:prop>
+			</D:sync-collection>`)
+		assertEq(t, w.Code, http.StatusMultiStatus)
+		body, _ := io.ReadAll(w.Body)
+		return string(body)
+	}
+	tokenOf := func(body string) string {
+		return regexp.MustCompile(`<D:sync-token>([^<]*)</D:sync-token>`).FindStringSubmatch(body)[1]
+	}
+
+	initial := sync("")
+	assertEq(t, strings.Contains(initial, "urn%3Auuid%3A1.vcf"), true)
+	token := tokenOf(initial)
+	current, err := h.SyncToken()
+	assertEq(t, err, nil)
+	assertEq(t, token, current)
+
+	assertEq(t, tokenOf(sync(token)), token)
+
+	serveCardDAV(h, "PUT", "/contacts/urn%3Auuid%3A2.vcf", strings.ReplaceAll(cardDAVAlex, "uuid:1", "uuid:2"))
+	serveCardDAV(h, "DELETE", "/contacts/urn%3Auuid%3A1.vcf", "")
+	changed := sync(token)
+	assertEq(t, strings.Contains(changed, "<D:href>/contacts/urn%3Auuid%3A1.vcf</D:href><D:status>HTTP/1.1 404 Not Found</D:status>"), true)
+	assertEq(t, strings.Contains(changed, "<D:href>/contacts/urn%3Auuid%3A2.vcf</D:href><D:propstat>"), true)
+	assertEq(t, tokenOf(changed) != token, true)
+
+	w := serveCardDAV(h, "REPORT", "/contacts/", `<D:sync-collection xmlns:D="DAV:"><D:sync-token>bogus</D:sync-token></D:sync-collection>`)
+	assertEq(t, w.Code, http.StatusForbidden)
+	assertEq(t, strings.Contains(w.Body.String(), "<D:valid-sync-token/>"), true)
+}
@@ -0,0 +1,197 @@
+package vcard
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Returns the card as a JSON-friendly map of property names to values, which can be stored in
+// document databases or passed to templates without defining structs:
+//
+//	{
+//		"VERSION": "4.0",
+//		"FN": "Alex Smith",
+//		"TEL": [
+//			{"params": {"TYPE": ["cell"], "PREF": ["1"]}, "value": "555"},
+//			{"params": {"TYPE": ["work"]}, "value": "556"}
+//		],
+//		"EMAIL": [{"group": "item1", "value": "alex@example.com"}]
+//	}
+//
+// A property which occurs once and has no group or parameters is a string. Other properties are
+// lists of map[string]any with "value" and optional "group" and "params" keys, where params map
+// names to []any of values and nameless parameters of vCard 2.1 to nil. Records nested into
+// a property e.g. AGENT of 2.1 are under "card" key of the property in the same form.
+//
+// Values are kept raw like [Property.Value]. Order of properties with different names is
+// not preserved. See [CardFromMap] for the reverse.
+func (c *Card) Map() map[string]any {
+	type entry struct {
+		p      Property
+		nested []Property
+	}
+	entries := []entry{}
+	depth := 0
+	for _, p := range c.props {
+		if p.Name == "BEGIN" {
+			depth++
+		}
+		switch {
+		case depth == 0:
+			entries = append(entries, entry{p: p})
+		case len(entries) > 0 && !(depth == 1 && (p.Name == "BEGIN" || p.Name == "END")):
+			// BEGIN and END of a nested record are not a part of it
+			entries[len(entries)-1].nested = append(entries[len(entries)-1].nested, p)
+		}
+		if depth > 0 && p.Name == "END" {
+			depth--
+		}
+	}
+
+	counts := map[string]int{}
+	for _, e := range entries {
+		counts[e.p.Name]++
+	}
+
+	m := map[string]any{}
+	for _, e := range entries {
+		if counts[e.p.Name] == 1 && e.p.Group == "" && len(e.p.Params) == 0 && e.nested == nil {
+			m[e.p.Name] = e.p.Value
+			continue
+		}
+
+		item := map[string]any{"value": e.p.Value}
+		if e.p.Group != "" {
+			item["group"] = e.p.Group
+		}
+		if len(e.p.Params) > 0 {
+			params := map[string]any{}
+			for _, param := range e.p.Params {
+				if param.Values == nil {
+					params[param.Name] = nil
+					continue
+				}
+				values, _ := params[param.Name].([]any)
+				for _, v := range param.Values {
+					values = append(values, v)
+				}
+				if values == nil {
+					values = []any{}
+				}
+				params[param.Name] = values
+			}
+			item["params"] = params
+		}
+		if e.nested != nil {
+			nested := Card{props: e.nested}
+			item["card"] = nested.Map()
+		}
+
+		list, _ := m[e.p.Name].([]any)
+		m[e.p.Name] = append(list, item)
+	}
+	return m
+}
+
+// Returns a card of a map in a form of [Card.Map]. Lists may be []any or []map[string]any and
+// parameter values may be []any, []string or a single string, so maps decoded from JSON or built
+// by hand are accepted.
+//
+// VERSION goes first and other properties are sorted by name keeping order of values of a name.
+// Returns [ErrVCard] if a value has unexpected type.
+func CardFromMap(m map[string]any) (Card, error) {
+	card := Card{}
+	names := slices.Sorted(maps.Keys(m))
+	if i := slices.IndexFunc(names, func(n string) bool { return strings.EqualFold(n, "VERSION") }); i > 0 {
+		version := names[i]
+		names = append([]string{version}, slices.Delete(names, i, i+1)...)
+	}
+
+	for _, name := range names {
+		var items []any
+		switch v := m[name].(type) {
+		case string:
+			card.AddProperty(Property{Name: name, Value: v})
+			continue
+		case []any:
+			items = v
+		case []map[string]any:
+			for _, item := range v {
+				items = append(items, item)
+			}
+		default:
+			return Card{}, vCardErrf("property %q has value of unexpected type %T", name, v)
+		}
+
+		for i, item := range items {
+			im, ok := item.(map[string]any)
+			if !ok {
+				return Card{}, vCardErrf("value %v of property %q has unexpected type %T", i, name, item)
+			}
+			props, err := propertiesFromMap(name, im)
+			if err != nil {
+				return Card{}, vCardErrf("error during decoding value %v of property %q: %w", i, name, err)
+			}
+			for _, p := range props {
+				card.AddProperty(p)
+			}
+		}
+	}
+	return card, nil
+}
+
+// Returns a property of a list item of [Card.Map] followed by its nested record if there is one.
+func propertiesFromMap(name string, item map[string]any) ([]Property, error) {
+	p := Property{Name: name}
+	var ok bool
+	if p.Value, ok = item["value"].(string); !ok && item["value"] != nil {
+		return nil, vCardErrf("value has unexpected type %T", item["value"])
+	}
+	if p.Group, ok = item["group"].(string); !ok && item["group"] != nil {
+		return nil, vCardErrf("group has unexpected type %T", item["group"])
+	}
+
+	switch params := item["params"].(type) {
+	case nil:
+	case map[string]any:
+		for _, paramName := range slices.Sorted(maps.Keys(params)) {
+			switch values := params[paramName].(type) {
+			case nil:
+				p.Params = append(p.Params, Param{Name: paramName})
+			case string:
+				p.Params = append(p.Params, Param{paramName, []string{values}})
+			case []string:
+				p.Params = append(p.Params, Param{paramName, slices.Clone(values)})
+			case []any:
+				param := Param{paramName, make([]string, len(values))}
+				for i, v := range values {
+					if param.Values[i], ok = v.(string); !ok {
+						return nil, vCardErrf("parameter %q has value of unexpected type %T", paramName, v)
+					}
+				}
+				p.Params = append(p.Params, param)
+			default:
+				return nil, vCardErrf("parameter %q has value of unexpected type %T", paramName, values)
+			}
+		}
+	default:
+		return nil, vCardErrf("params have unexpected type %T", params)
+	}
+
+	props := []Property{p}
+	switch nested := item["card"].(type) {
+	case nil:
+	case map[string]any:
+		card, err := CardFromMap(nested)
+		if err != nil {
+			return nil, err
+		}
+		props = append(props, Property{Name: "BEGIN", Value: "VCARD"})
+		props = append(props, card.props...)
+		props = append(props, Property{Name: "END", Value: "VCARD"})
+	default:
+		return nil, vCardErrf("nested card has unexpected type %T", nested)
+	}
+	return props, nil
+}
@@ -0,0 +1,78 @@
+package vcard
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestCardMap(t *testing.T) {
+
+	card, err := ParseRecord([]byte(strings.Join([]string{
+		"BEGIN:VCARD",
+		"VERSION:2.1",
+		"FN:Alex Smith",
+		"TEL;CELL;TYPE=pref:555",
+		"TEL;WORK:556",
+		"item1.EMAIL:alex@example.com",
+		"AGENT:",
+		"BEGIN:VCARD",
+		"VERSION:2.1",
+		"FN:Sam",
+		"END:VCARD",
+		"END:VCARD",
+	}, "\r\n")))
+	assertEq(t, err, nil)
+
+	b, err := json.Marshal(card.Map())
+	assertEq(t, err, nil)
+	assertStringsEq(t, string(b), `{"AGENT":[{"card":{"FN":"Sam","VERSION":"2.1"},"value":""}],`+
+		`"EMAIL":[{"group":"item1","value":"alex@example.com"}],"FN":"Alex Smith",`+
+		`"TEL":[{"params":{"CELL":null,"TYPE":["pref"]},"value":"555"},{"params":{"WORK":null},"value":"556"}],"VERSION":"2.1"}`)
+
+	m := map[string]any{}
+	assertEq(t, json.Unmarshal(b, &m), nil)
+	decoded, err := CardFromMap(m)
+	assertEq(t, err, nil)
+	assertEq(t, Equal(decoded, card), true)
+
+	lines := []string{}
+	for _, p := range decoded.Properties() {
+		lines = append(lines, p.String())
+	}
+	assertSlicesEq(t, lines, []string{
+		"VERSION:2.1",
+		"AGENT:",
+		"BEGIN:VCARD",
+		"VERSION:2.1",
+		"FN:Sam",
+		"END:VCARD",
+		"item1.EMAIL:alex@example.com",
+		"FN:Alex Smith",
+		"TEL;CELL;TYPE=pref:555",
+		"TEL;WORK:556",
+	})
+}
+
+func TestCardFromMap(t *testing.T) {
+
+	card, err := CardFromMap(map[string]any{
+		"FN":      "Alex",
+		"version": "4.0",
+		"TEL": []map[string]any{
+			{"params": map[string]any{"TYPE": []string{"cell", "voice"}, "PREF": "1"}, "value": "555"},
+		},
+	})
+	assertEq(t, err, nil)
+	lines := []string{}
+	for _, p := range card.Properties() {
+		lines = append(lines, p.String())
+	}
+	assertSlicesEq(t, lines, []string{"VERSION:4.0", "FN:Alex", "TEL;PREF=1;TYPE=cell,voice:555"})
+
+	_, err = CardFromMap(map[string]any{"FN": 1})
+	assertErrIs(t, err, ErrVCard, `property "FN" has value of unexpected type int`)
+
+	_, err = CardFromMap(map[string]any{"TEL": []any{map[string]any{"params": map[string]any{"TYPE": []any{true}}}}})
+	assertErrIs(t, err, ErrVCard, `parameter "TYPE" has value of unexpected type bool`)
+}
@@ -0,0 +1,416 @@
+// Command vcard inspects and transforms vCard documents.
+//
+// Usage:
+//
+//	vcard <command> [flags] [file...]
+//
+// Commands read records of every file in order or standard input if no files are given
+// and write results to standard output:
+//
+//	validate   report issues of every record, exit with status 1 if a record has errors
+//	fmt        normalize values and write records with folded lines
+//	convert    convert records to another version e.g. convert -to 4.0 old.vcf
+//	split      write every record to a separate file of a directory
+//	merge      concatenate records of every file into a single document
+//	dedupe     merge probable duplicates
+//	to-json    write records as a JSON array of vcard.Card.Map objects
+//	from-json  read a JSON array of vcard.Card.Map objects and write records
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ioannuwu/vcard"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// Command of the tool. Run returns errUsage if arguments are invalid.
+type command struct {
+	name  string
+	usage string
+	run   func(args []string, env env) error
+}
+
+// Standard streams of a run.
+type env struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+var errUsage = errors.New("invalid usage")
+
+// Returned by commands which report problems themselves e.g. validate.
+var errFailed = errors.New("failed")
+
+var commands = []command{
+	{"validate", "validate [file...]", runValidate},
+	{"fmt", "fmt [-region code] [file...]", runFmt},
+	{"convert", "convert -to version [file...]", runConvert},
+	{"split", "split -dir directory [file...]", runSplit},
+	{"merge", "merge [file...]", runMerge},
+	{"dedupe", "dedupe [-threshold confidence] [-prefer a|b|newer] [file...]", runDedupe},
+	{"to-json", "to-json [file...]", runToJSON},
+	{"from-json", "from-json [file...]", runFromJSON},
+}
+
+// Runs a command of args and returns exit status: 0 on success, 1 on failure and 2 on invalid usage.
+func run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return 2
+	}
+	for _, cmd := range commands {
+		if cmd.name != args[0] {
+			continue
+		}
+		err := cmd.run(args[1:], env{stdin, stdout, stderr})
+		switch {
+		case err == nil:
+			return 0
+		case errors.Is(err, errUsage):
+			fmt.Fprintln(stderr, "usage: vcard", cmd.usage)
+			return 2
+		case errors.Is(err, errFailed):
+			return 1
+		}
+		fmt.Fprintf(stderr, "vcard %s: %v\n", cmd.name, err)
+		return 1
+	}
+	fmt.Fprintf(stderr, "vcard: unknown command %q\n", args[0])
+	usage(stderr)
+	return 2
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: vcard <command> [flags] [file...]")
+	fmt.Fprintln(w, "commands:")
+	for _, cmd := range commands {
+		fmt.Fprintln(w, "  vcard", cmd.usage)
+	}
+}
+
+// Parses flags of a command. Help and invalid flags are reported by the flag set itself.
+func parseFlags(fs *flag.FlagSet, args []string, env env) error {
+	fs.SetOutput(env.stderr)
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	return nil
+}
+
+// Record of an input with its position for messages e.g. "contacts.vcf:12".
+type record struct {
+	raw  vcard.RawCard
+	name string
+}
+
+func (r record) String() string {
+	return fmt.Sprintf("%s:%d", r.name, r.raw.StartLine)
+}
+
+// Returns records of files in order or of stdin if there are no files.
+func readRecords(files []string, stdin io.Reader) ([]record, error) {
+	records := []record{}
+	err := eachInput(files, stdin, func(name string, r io.Reader) error {
+		for raw := range vcard.SplitRaw(r) {
+			records = append(records, record{raw, name})
+			if raw.Err != nil {
+				return fmt.Errorf("%s:%d: %w", name, raw.StartLine, raw.Err)
+			}
+		}
+		return nil
+	})
+	return records, err
+}
+
+// Returns cards of files in order or of stdin if there are no files.
+func readCards(files []string, stdin io.Reader) ([]vcard.Card, error) {
+	records, err := readRecords(files, stdin)
+	if err != nil {
+		return nil, err
+	}
+	cards := make([]vcard.Card, len(records))
+	for i, r := range records {
+		if cards[i], err = r.raw.Card(); err != nil {
+			return nil, fmt.Errorf("%v: %w", r, err)
+		}
+	}
+	return cards, nil
+}
+
+// Calls f with every file or with stdin named "-" if there are no files. File "-" is stdin as well.
+func eachInput(files []string, stdin io.Reader, f func(name string, r io.Reader) error) error {
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	for _, name := range files {
+		if name == "-" {
+			if err := f("<stdin>", stdin); err != nil {
+				return err
+			}
+			continue
+		}
+		file, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		err = f(name, file)
+		file.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeCards(w io.Writer, cards []vcard.Card) error {
+	enc := vcard.NewEncoder(w)
+	for _, card := range cards {
+		if err := enc.Encode(card); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runValidate(args []string, env env) error {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	if err := parseFlags(fs, args, env); err != nil {
+		return err
+	}
+	records, err := readRecords(fs.Args(), env.stdin)
+	if err != nil {
+		return err
+	}
+
+	invalid := 0
+	for _, r := range records {
+		card, err := r.raw.Card()
+		if err != nil {
+			fmt.Fprintf(env.stdout, "%v: error: %v\n", r, err)
+			invalid++
+			continue
+		}
+		report := card.Validate()
+		for _, issue := range report.Issues {
+			fmt.Fprintf(env.stdout, "%v: %v\n", r, issue)
+		}
+		if !report.Valid() {
+			invalid++
+		}
+	}
+	if invalid > 0 {
+		fmt.Fprintf(env.stderr, "vcard validate: %d of %d records are invalid\n", invalid, len(records))
+		return errFailed
+	}
+	return nil
+}
+
+func runFmt(args []string, env env) error {
+	fs := flag.NewFlagSet("fmt", flag.ContinueOnError)
+	region := fs.String("region", "", "region of national telephone numbers e.g. US")
+	if err := parseFlags(fs, args, env); err != nil {
+		return err
+	}
+	cards, err := readCards(fs.Args(), env.stdin)
+	if err != nil {
+		return err
+	}
+	for i, card := range cards {
+		cards[i] = vcard.Normalize(card, vcard.NormalizeOptions{Region: *region})
+	}
+	return writeCards(env.stdout, cards)
+}
+
+func runConvert(args []string, env env) error {
+	fs := flag.NewFlagSet("convert", flag.ContinueOnError)
+	to := fs.String("to", "", "target version: 2.1, 3.0 or 4.0")
+	if err := parseFlags(fs, args, env); err != nil {
+		return err
+	}
+	if *to == "" {
+		return errUsage
+	}
+	records, err := readRecords(fs.Args(), env.stdin)
+	if err != nil {
+		return err
+	}
+
+	cards := make([]vcard.Card, len(records))
+	for i, r := range records {
+		card, err := r.raw.Card()
+		if err != nil {
+			return fmt.Errorf("%v: %w", r, err)
+		}
+		converted, warnings, err := vcard.Convert(card, *to)
+		if err != nil {
+			return fmt.Errorf("%v: %w", r, err)
+		}
+		for _, w := range warnings {
+			fmt.Fprintf(env.stderr, "%v: warning: %v\n", r, w)
+		}
+		cards[i] = converted
+	}
+	return writeCards(env.stdout, cards)
+}
+
+// Writes records unchanged into files "0001.vcf", "0002.vcf" and so on.
+func runSplit(args []string, env env) error {
+	fs := flag.NewFlagSet("split", flag.ContinueOnError)
+	dir := fs.String("dir", "", "directory for records, created if it does not exist")
+	if err := parseFlags(fs, args, env); err != nil {
+		return err
+	}
+	if *dir == "" {
+		return errUsage
+	}
+	records, err := readRecords(fs.Args(), env.stdin)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(*dir, 0o755); err != nil {
+		return err
+	}
+
+	width := max(4, len(fmt.Sprint(len(records))))
+	for i, r := range records {
+		path := filepath.Join(*dir, fmt.Sprintf("%0*d.vcf", width, i+1))
+		if err := os.WriteFile(path, r.raw.Data, 0o644); err != nil {
+			return err
+		}
+		fmt.Fprintln(env.stdout, path)
+	}
+	return nil
+}
+
+// Writes records unchanged one after another ending every record with a line break.
+func runMerge(args []string, env env) error {
+	fs := flag.NewFlagSet("merge", flag.ContinueOnError)
+	if err := parseFlags(fs, args, env); err != nil {
+		return err
+	}
+	records, err := readRecords(fs.Args(), env.stdin)
+	if err != nil {
+		return err
+	}
+	for _, r := range records {
+		data := r.raw.Data
+		if !strings.HasSuffix(string(data), "\n") {
+			data = append(data, "\r\n"...)
+		}
+		if _, err := env.stdout.Write(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runDedupe(args []string, env env) error {
+	fs := flag.NewFlagSet("dedupe", flag.ContinueOnError)
+	threshold := fs.Float64("threshold", 0.9, "minimal confidence in range 0..1 to merge cards")
+	prefer := fs.String("prefer", "newer", "card which wins conflicts: a (first), b (last) or newer")
+	if err := parseFlags(fs, args, env); err != nil {
+		return err
+	}
+	policy := vcard.MergeUnionMultivalued
+	switch *prefer {
+	case "a":
+		policy |= vcard.MergePreferA
+	case "b":
+		policy |= vcard.MergePreferB
+	case "newer":
+		policy |= vcard.MergePreferNewer
+	default:
+		return errUsage
+	}
+	cards, err := readCards(fs.Args(), env.stdin)
+	if err != nil {
+		return err
+	}
+
+	// Merged card takes position of the first card of its group
+	merged := map[int]vcard.Card{}
+	skipped := map[int]bool{}
+	for _, d := range vcard.FindDuplicates(cards, *threshold) {
+		merged[d.Indices[0]] = d.Merge(cards, policy)
+		for _, i := range d.Indices[1:] {
+			skipped[i] = true
+		}
+		fmt.Fprintf(env.stderr, "merged records %v with confidence %.2f by %s\n",
+			d.Indices, d.Confidence, strings.Join(d.Reasons, ", "))
+	}
+
+	result := []vcard.Card{}
+	for i, card := range cards {
+		if m, ok := merged[i]; ok {
+			card = m
+		}
+		if !skipped[i] {
+			result = append(result, card)
+		}
+	}
+	return writeCards(env.stdout, result)
+}
+
+func runToJSON(args []string, env env) error {
+	fs := flag.NewFlagSet("to-json", flag.ContinueOnError)
+	if err := parseFlags(fs, args, env); err != nil {
+		return err
+	}
+	cards, err := readCards(fs.Args(), env.stdin)
+	if err != nil {
+		return err
+	}
+	maps := make([]map[string]any, len(cards))
+	for i := range cards {
+		maps[i] = cards[i].Map()
+	}
+	enc := json.NewEncoder(env.stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(maps)
+}
+
+// Reads either an array of objects or a single object of every input.
+func runFromJSON(args []string, env env) error {
+	fs := flag.NewFlagSet("from-json", flag.ContinueOnError)
+	if err := parseFlags(fs, args, env); err != nil {
+		return err
+	}
+	cards := []vcard.Card{}
+	err := eachInput(fs.Args(), env.stdin, func(name string, r io.Reader) error {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		maps := []map[string]any{}
+		if trimmed := strings.TrimSpace(string(data)); strings.HasPrefix(trimmed, "{") {
+			data = []byte("[" + trimmed + "]")
+		}
+		if err := json.Unmarshal(data, &maps); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		for i, m := range maps {
+			card, err := vcard.CardFromMap(m)
+			if err != nil {
+				return fmt.Errorf("%s: card %d: %w", name, i, err)
+			}
+			cards = append(cards, card)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return writeCards(env.stdout, cards)
+}
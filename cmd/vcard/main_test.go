@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const alex = "BEGIN:VCARD\r\nVERSION:4.0\r\nFN:Alex\r\nEMAIL:Alex@Example.com\r\nEND:VCARD\r\n"
+const alexRev = "BEGIN:VCARD\r\nVERSION:4.0\r\nFN:Alex\r\nEMAIL:alex@example.com\r\nTEL:555\r\nREV:20240101T000000Z\r\nEND:VCARD\r\n"
+const sam = "BEGIN:VCARD\r\nVERSION:3.0\r\nN:Smith;Sam;;;\r\nFN:Sam Smith\r\nEND:VCARD\r\n"
+
+func runTest(t *testing.T, stdin string, args ...string) (int, string, string) {
+	t.Helper()
+	stdout, stderr := bytes.Buffer{}, bytes.Buffer{}
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestValidate(t *testing.T) {
+	code, out, _ := runTest(t, alex+sam, "validate")
+	if code != 0 || out != "" {
+		t.Fatalf("code=%v out=%q", code, out)
+	}
+
+	code, out, stderr := runTest(t, alex+"BEGIN:VCARD\r\nVERSION:4.0\r\nN:Doe;Jo;;;\r\nEND:VCARD\r\n", "validate")
+	if code != 1 || out != "<stdin>:6: error: FN: property is required but missing\n" {
+		t.Fatalf("code=%v out=%q", code, out)
+	}
+	if stderr != "vcard validate: 1 of 2 records are invalid\n" {
+		t.Fatalf("stderr=%q", stderr)
+	}
+}
+
+func TestFmt(t *testing.T) {
+	code, out, _ := runTest(t, "BEGIN:VCARD\nVERSION:4.0\nFN:  Alex   Smith \nEMAIL:Alex@Example.com\nEND:VCARD\n", "fmt")
+	exp := "BEGIN:VCARD\r\nVERSION:4.0\r\nFN:Alex Smith\r\nEMAIL:alex@example.com\r\nEND:VCARD\r\n"
+	if code != 0 || out != exp {
+		t.Fatalf("code=%v out=%q", code, out)
+	}
+}
+
+func TestConvert(t *testing.T) {
+	code, out, _ := runTest(t, sam, "convert", "-to", "4.0")
+	if code != 0 || !strings.HasPrefix(out, "BEGIN:VCARD\r\nVERSION:4.0\r\n") {
+		t.Fatalf("code=%v out=%q", code, out)
+	}
+
+	code, _, stderr := runTest(t, sam, "convert")
+	if code != 2 || stderr != "usage: vcard convert -to version [file...]\n" {
+		t.Fatalf("code=%v stderr=%q", code, stderr)
+	}
+}
+
+func TestSplitMerge(t *testing.T) {
+	dir := t.TempDir()
+	code, out, _ := runTest(t, alex+"\r\n"+sam, "split", "-dir", dir)
+	first, second := filepath.Join(dir, "0001.vcf"), filepath.Join(dir, "0002.vcf")
+	if code != 0 || out != first+"\n"+second+"\n" {
+		t.Fatalf("code=%v out=%q", code, out)
+	}
+	data, err := os.ReadFile(second)
+	if err != nil || string(data) != sam {
+		t.Fatalf("err=%v data=%q", err, data)
+	}
+
+	code, out, _ = runTest(t, "", "merge", first, second)
+	if code != 0 || out != alex+sam {
+		t.Fatalf("code=%v out=%q", code, out)
+	}
+
+	code, _, stderr := runTest(t, "", "merge", filepath.Join(dir, "missing.vcf"))
+	if code != 1 || !strings.HasPrefix(stderr, "vcard merge: open ") {
+		t.Fatalf("code=%v stderr=%q", code, stderr)
+	}
+}
+
+func TestDedupe(t *testing.T) {
+	code, out, stderr := runTest(t, alex+sam+alexRev, "dedupe")
+	exp := "BEGIN:VCARD\r\nVERSION:4.0\r\nFN:Alex\r\nEMAIL:alex@example.com\r\nTEL:555\r\nREV:20240101T000000Z\r\nEND:VCARD\r\n" + sam
+	if code != 0 || out != exp {
+		t.Fatalf("code=%v out=%q", code, out)
+	}
+	if stderr != "merged records [0 2] with confidence 0.98 by EMAIL, NAME\n" {
+		t.Fatalf("stderr=%q", stderr)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	code, out, _ := runTest(t, alex, "to-json")
+	exp := "[\n  {\n    \"EMAIL\": \"Alex@Example.com\",\n    \"FN\": \"Alex\",\n    \"VERSION\": \"4.0\"\n  }\n]\n"
+	if code != 0 || out != exp {
+		t.Fatalf("code=%v out=%q", code, out)
+	}
+
+	// Properties except VERSION are sorted by name
+	code, out, _ = runTest(t, out, "from-json")
+	if code != 0 || out != "BEGIN:VCARD\r\nVERSION:4.0\r\nEMAIL:Alex@Example.com\r\nFN:Alex\r\nEND:VCARD\r\n" {
+		t.Fatalf("code=%v out=%q", code, out)
+	}
+
+	code, out, _ = runTest(t, `{"VERSION": "4.0", "FN": "Sam"}`, "from-json")
+	if code != 0 || out != "BEGIN:VCARD\r\nVERSION:4.0\r\nFN:Sam\r\nEND:VCARD\r\n" {
+		t.Fatalf("code=%v out=%q", code, out)
+	}
+}
+
+func TestUnknownCommand(t *testing.T) {
+	code, _, stderr := runTest(t, "", "frobnicate")
+	if code != 2 || !strings.HasPrefix(stderr, "vcard: unknown command \"frobnicate\"\n") {
+		t.Fatalf("code=%v stderr=%q", code, stderr)
+	}
+}
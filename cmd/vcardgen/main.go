@@ -0,0 +1,112 @@
+// Command vcardgen generates reflection-free MarshalVCard and UnmarshalVCard methods for a struct.
+//
+// Usage with go:generate:
+//
+//	//go:generate go run github.com/ioannuwu/vcard/cmd/vcardgen -type Contact -version 4.0
+//
+// Struct is read from the file containing the directive and methods are written to
+// <type>_vcard.go next to it. See vcard.GenerateCodec for supported tags.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/ioannuwu/vcard"
+)
+
+func main() {
+	typeName := flag.String("type", "", "name of a struct to generate codec for")
+	version := flag.String("version", "4.0", "vCard version of generated records")
+	file := flag.String("file", os.Getenv("GOFILE"), "Go source file containing the struct")
+	output := flag.String("output", "", "output file, defaults to <type>_vcard.go")
+	flag.Parse()
+
+	if *typeName == "" || *file == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *output == "" {
+		*output = filepath.Join(filepath.Dir(*file), strings.ToLower(*typeName)+"_vcard.go")
+	}
+
+	err := generate(*file, *typeName, *version, *output)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "vcardgen:", err)
+		os.Exit(1)
+	}
+}
+
+func generate(file string, typeName string, version string, output string) error {
+	f, err := parser.ParseFile(token.NewFileSet(), file, nil, parser.SkipObjectResolution)
+	if err != nil {
+		return err
+	}
+	fields, err := structFields(f, typeName)
+	if err != nil {
+		return err
+	}
+	src, err := vcard.GenerateCodec(f.Name.Name, typeName, version, fields)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(output, src, 0o644)
+}
+
+// Returns fields of a struct typeName declared in f.
+func structFields(f *ast.File, typeName string) ([]vcard.CodecField, error) {
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			typ := spec.(*ast.TypeSpec)
+			if typ.Name.Name != typeName {
+				continue
+			}
+			struc, ok := typ.Type.(*ast.StructType)
+			if !ok {
+				return nil, fmt.Errorf("type %s is not a struct", typeName)
+			}
+			return codecFields(struc)
+		}
+	}
+	return nil, fmt.Errorf("struct %s was not found", typeName)
+}
+
+func codecFields(struc *ast.StructType) ([]vcard.CodecField, error) {
+	fields := []vcard.CodecField{}
+
+	for _, field := range struc.Fields.List {
+		tag := ""
+		if field.Tag != nil {
+			var err error
+			tag, err = strconv.Unquote(field.Tag.Value)
+			if err != nil {
+				return nil, err
+			}
+		}
+		typ := ""
+		if ident, ok := field.Type.(*ast.Ident); ok {
+			typ = ident.Name
+		} else {
+			typ = fmt.Sprintf("%T", field.Type)
+		}
+		for _, name := range field.Names {
+			if !name.IsExported() {
+				continue
+			}
+			fields = append(fields, vcard.CodecField{Name: name.Name, Type: typ, Tag: reflect.StructTag(tag)})
+		}
+	}
+	return fields, nil
+}
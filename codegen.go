@@ -0,0 +1,190 @@
+package vcard
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"reflect"
+	"strings"
+)
+
+// Field of a struct passed to [GenerateCodec].
+type CodecField struct {
+	// Name of a field e.g. FullName
+	Name string
+
+	// Go type of a field as written in source code. Only string is supported.
+	Type string
+
+	// Complete struct tag without backquotes e.g. `vCard:"FN,required"`
+	Tag reflect.StructTag
+}
+
+// Generates source code of MarshalVCard and UnmarshalVCard methods of a struct typeName
+// from package pkg, so the struct implements [VCardMarshaler] and [VCardUnmarshaler] without reflection.
+//
+// Struct is treated as a schema like one passed to [SchemaFor]: every field is a property and `vCard`
+// tags may rename fields, make them required, omitempty, define defaults and aliases. Generated
+// code does not check cardinality, parameters, validators and constraints of a [Schema].
+//
+// Generated methods work with [Encoder] and [Decoder] as well. Use [MarshalCodecs] and [UnmarshalCodecs]
+// to encode and decode documents without reflection. See cmd/vcardgen to generate codecs with go:generate.
+func GenerateCodec(pkg string, typeName string, version string, fields []CodecField) ([]byte, error) {
+	var enc, dec bytes.Buffer
+	needsFmt := false
+
+	for _, field := range fields {
+		opts := parseTag(field.Tag.Get(defaultTagKey))
+		if opts.skip {
+			continue
+		}
+		if opts.rest || opts.typ != "" {
+			return nil, vCardErrf("field %q of a struct %s has tag `%s` which is not supported by generated codecs", field.Name, typeName, field.Tag)
+		}
+		if field.Type != "string" {
+			return nil, vCardErrf("field %q of a struct %s has unsupported type %s. Only string fields are supported by generated codecs", field.Name, typeName, field.Type)
+		}
+		name := field.Name
+		if opts.name != "" {
+			name = opts.name
+		}
+		if opts.cardinality != nil {
+			opts.required = opts.cardinality.Min > 0
+		}
+		needsFmt = needsFmt || (opts.required && opts.def == "")
+
+		fmt.Fprintf(&enc, "\tif v.%s != \"\" {\n", field.Name)
+		fmt.Fprintf(&enc, "\t\tb = vcard.AppendString(b, %q, v.%s)\n", aliasedName(name, opts.aliases, version), field.Name)
+		switch {
+		case opts.def != "":
+			fmt.Fprintf(&enc, "\t} else {\n\t\tb = vcard.AppendString(b, %q, %q)\n", aliasedName(name, opts.aliases, version), opts.def)
+		case opts.required:
+			fmt.Fprintf(&enc, "\t} else {\n\t\treturn nil, fmt.Errorf(\"%%w: field %%q of a struct %%s is required but empty\", vcard.ErrVCard, %q, %q)\n", field.Name, typeName)
+		case !opts.omitEmpty:
+			fmt.Fprintf(&enc, "\t} else {\n\t\tb = vcard.AppendString(b, %q, \"\")\n", aliasedName(name, opts.aliases, version))
+		}
+		enc.WriteString("\t}\n")
+
+		for i, alias := range append([]string{name}, opts.aliases...) {
+			if i > 0 {
+				dec.WriteString(" else ")
+			} else {
+				dec.WriteString("\t")
+			}
+			fmt.Fprintf(&dec, "if s, found := c.Field(%q); found {\n\t\tv.%s = s\n\t}", alias, field.Name)
+		}
+		switch {
+		case opts.def != "":
+			fmt.Fprintf(&dec, " else {\n\t\tv.%s = %q\n\t}", field.Name, opts.def)
+		case opts.required:
+			fmt.Fprintf(&dec, " else {\n\t\treturn fmt.Errorf(\"%%w: document does not contain a field %%q required by the schema\", vcard.ErrParsing, %q)\n\t}", name)
+		}
+		dec.WriteString("\n")
+	}
+
+	var b bytes.Buffer
+
+	fmt.Fprintf(&b, "// Code generated by vcardgen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	if needsFmt {
+		fmt.Fprintf(&b, "import (\n\t\"fmt\"\n\n\t\"github.com/ioannuwu/vcard\"\n)\n\n")
+	} else {
+		fmt.Fprintf(&b, "import \"github.com/ioannuwu/vcard\"\n\n")
+	}
+
+	fmt.Fprintf(&b, "// Encodes v as a vCard %s record. Implements [vcard.VCardMarshaler].\n", version)
+	fmt.Fprintf(&b, "func (v %s) MarshalVCard() ([]byte, error) {\n", typeName)
+	fmt.Fprintf(&b, "\tb := vcard.AppendRecordHeader(nil, %q)\n", version)
+	b.Write(enc.Bytes())
+	fmt.Fprintf(&b, "\treturn vcard.AppendRecordFooter(b), nil\n}\n\n")
+
+	fmt.Fprintf(&b, "// Decodes a single vCard record into v. Implements [vcard.VCardUnmarshaler].\n")
+	fmt.Fprintf(&b, "func (v *%s) UnmarshalVCard(data []byte) error {\n", typeName)
+	fmt.Fprintf(&b, "\tc, err := vcard.ParseRecord(data)\n\tif err != nil {\n\t\treturn err\n\t}\n")
+	b.Write(dec.Bytes())
+	fmt.Fprintf(&b, "\treturn nil\n}\n")
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, vCardErrf("unable to generate codec for %s: %w", typeName, err)
+	}
+	return src, nil
+}
+
+// Helpers used by code generated with [GenerateCodec]. They don't use reflection.
+
+// Appends BEGIN:VCARD and VERSION content lines to b.
+func AppendRecordHeader(b []byte, version string) []byte {
+	return codecEncoder.encodeRecordHeader(b, version)
+}
+
+// Appends END:VCARD content line to b.
+func AppendRecordFooter(b []byte) []byte {
+	return codecEncoder.encodeRecordFooter(b)
+}
+
+// Appends a content line for a string field like [Encoder] with smart strings does
+// e.g. "Alex" is written as "FN:Alex" and ";TYPE=CELL:555" as "TEL;TYPE=CELL:555".
+func AppendString(b []byte, name string, s string) []byte {
+	b = append(b, name...)
+	b = append(b, codecEncoder.stringRest(s)...)
+	return append(b, codecEncoder.newlineSequence...)
+}
+
+var codecEncoder = NewEncoder(nil)
+
+// Encodes every value into a single document using only its MarshalVCard method.
+//
+// Unlike [Marshal] of a slice it doesn't use reflection, so programs which encode and decode
+// structs with codecs of [GenerateCodec] using MarshalCodecs and [UnmarshalCodecs] only don't
+// reach reflection-based [Encoder] and [Decoder]. Such programs stay small when built for
+// targets with limited reflection support e.g. TinyGo or WASM.
+func MarshalCodecs[T VCardMarshaler](values []T) ([]byte, error) {
+	b := []byte{}
+	for i, v := range values {
+		record, err := v.MarshalVCard()
+		if err != nil {
+			return nil, vCardErrf("error during marshaling slice member idx=%v: %w", i, err)
+		}
+		b = append(b, record...)
+	}
+	return b, nil
+}
+
+// Decodes every record of a document using UnmarshalVCard method of *T without reflection.
+// See [MarshalCodecs].
+//
+//	contacts, err := vcard.UnmarshalCodecs[Contact](data)
+func UnmarshalCodecs[T any, PT interface {
+	*T
+	VCardUnmarshaler
+}](data []byte) ([]T, error) {
+	d := &Decoder{smartStrings: true}
+	values := []T{}
+
+	s := string(data)
+	for i := 0; strings.TrimSpace(s) != ""; i++ {
+		var v T
+		rest, err := d.decodeUnmarshaler(strings.TrimLeft(s, " \t\r\n"), PT(&v))
+		if err != nil {
+			return values, vCardErrf("error during unmarshaling slice member idx=%v: %w", i, err)
+		}
+		values = append(values, v)
+		s = rest
+	}
+	return values, nil
+}
+
+// Parses a single record from BEGIN:VCARD to END:VCARD.
+func ParseRecord(data []byte) (Card, error) {
+	d := &Decoder{smartStrings: true}
+
+	c, rest, err := d.decodeCardRecord(strings.TrimLeft(string(data), " \t\r\n"))
+	if err != nil {
+		return Card{}, err
+	}
+	if strings.TrimSpace(rest) != "" {
+		return Card{}, leftTokensErrf("data contains more than a single record")
+	}
+	return c, nil
+}
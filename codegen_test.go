@@ -0,0 +1,54 @@
+package vcard
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateCodecUnsupported(t *testing.T) {
+
+	_, err := GenerateCodec("p", "T", "4.0", []CodecField{{Name: "Phones", Type: "[]string"}})
+	assertErrIs(t, err, ErrVCard, `field "Phones" of a struct T has unsupported type []string`)
+
+	_, err = GenerateCodec("p", "T", "4.0", []CodecField{{Name: "Rest", Type: "string", Tag: `vCard:"rest"`}})
+	assertErrIs(t, err, ErrVCard, "not supported by generated codecs")
+
+	src, err := GenerateCodec("p", "T", "4.0", []CodecField{{Name: "FN", Type: "string"}, {Name: "Skip", Type: "int", Tag: `vCard:"-"`}})
+	assertEq(t, err, nil)
+	assertEq(t, strings.Contains(string(src), `"fmt"`), false)
+	assertEq(t, strings.Contains(string(src), `c.Field("FN")`), true)
+}
+
+func TestParseRecord(t *testing.T) {
+
+	text := `BEGIN:VCARD
+VERSION:4.0
+FN:Alex
+item1.TEL;TYPE=CELL:555
+END:VCARD
+`
+	c, err := ParseRecord([]byte(crlfy(text)))
+
+	assertEq(t, err, nil)
+
+	fn, found := c.Field("fn")
+	assertEq(t, found, true)
+	assertStringsEq(t, fn, "Alex")
+
+	tel, found := c.Field("TEL")
+	assertEq(t, found, true)
+	assertStringsEq(t, tel, ";TYPE=CELL:555")
+
+	_, found = c.Field("EMAIL")
+	assertEq(t, found, false)
+
+	_, err = ParseRecord([]byte(crlfy(text + text)))
+	assertErrIs(t, err, ErrLeftoverTokens, "more than a single record")
+
+	b := AppendRecordHeader(nil, "4.0")
+	b = AppendString(b, "FN", "Alex")
+	b = AppendString(b, "TEL", ";TYPE=CELL:555")
+	b = AppendRecordFooter(b)
+
+	assertStringsEq(t, string(b), crlfy(strings.Replace(text, "item1.", "", 1)))
+}
@@ -0,0 +1,275 @@
+package vcard
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Severity of an [Issue] found by [Card.Validate].
+type Severity int
+
+const (
+	// The card violates RFC 6350 or the specification of its version.
+	SeverityError Severity = iota
+
+	// The card is valid, but likely not what its producer meant e.g. a malformed email address.
+	SeverityWarning
+)
+
+func (s Severity) String() string {
+	if s == SeverityWarning {
+		return "warning"
+	}
+	return "error"
+}
+
+// Single problem found by [Card.Validate].
+type Issue struct {
+	Severity Severity
+	Property string // Name of a property or an empty string for problems of the whole card.
+	Value    string // Raw value of the property.
+	Message  string
+}
+
+// Returns the issue as "error: FN: message".
+func (i Issue) String() string {
+	if i.Property == "" {
+		return i.Severity.String() + ": " + i.Message
+	}
+	return i.Severity.String() + ": " + i.Property + ": " + i.Message
+}
+
+// Result of [Card.Validate].
+type ValidationReport struct {
+	Issues []Issue
+}
+
+// Reports whether the card has no issues of [SeverityError].
+func (r ValidationReport) Valid() bool {
+	return len(r.Errors()) == 0
+}
+
+// Returns issues of [SeverityError].
+func (r ValidationReport) Errors() []Issue {
+	return r.filter(SeverityError)
+}
+
+// Returns issues of [SeverityWarning].
+func (r ValidationReport) Warnings() []Issue {
+	return r.filter(SeverityWarning)
+}
+
+// Returns nil if the card is valid, otherwise an error matching [ErrValidation] which lists every error.
+func (r ValidationReport) Err() error {
+	errs := []error{}
+	for _, i := range r.Errors() {
+		errs = append(errs, errors.New(i.String()))
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return validationErrf("card is not valid: %w", errors.Join(errs...))
+}
+
+func (r ValidationReport) filter(s Severity) []Issue {
+	issues := []Issue{}
+	for _, i := range r.Issues {
+		if i.Severity == s {
+			issues = append(issues, i)
+		}
+	}
+	return issues
+}
+
+// Checks the card against RFC 6350 and specifications of older versions:
+//
+//   - VERSION is present, known and goes first.
+//   - FN is present in 3.0 and 4.0 and N in 2.1 and 3.0.
+//   - Properties like N, BDAY or UID occur at most once unless they are alternatives with the same ALTID.
+//   - Properties are defined in the version of the card e.g. no LABEL in 4.0 or GENDER in 3.0.
+//   - Values of dates, timestamps, GEO, GENDER, LANG, CLIENTPIDMAP and URIs follow their grammar.
+//   - PREF is in range 1..100, PID refers to CLIENTPIDMAP and MEMBER is used only in groups.
+//
+// Malformed email addresses, unknown KIND and properties neither standard nor X- are warnings.
+// Records nested into the card e.g. AGENT of 2.1 are not checked.
+func (c *Card) Validate() ValidationReport {
+	r := ValidationReport{}
+	report := func(s Severity, p Property, format string, args ...any) {
+		r.Issues = append(r.Issues, Issue{s, p.Name, p.Value, fmt.Sprintf(format, args...)})
+	}
+
+	props := topLevelProperties(c.props)
+	version := ""
+	switch versions := slices.DeleteFunc(slices.Clone(props), func(p Property) bool { return p.Name != "VERSION" }); {
+	case len(versions) == 0:
+		report(SeverityError, Property{}, "card does not contain VERSION")
+	case len(versions) > 1:
+		report(SeverityError, versions[1], "property occurs %v times, but it is allowed once", len(versions))
+		fallthrough
+	default:
+		version = versions[0].Value
+		if !slices.Contains([]string{"2.1", "3.0", "4.0"}, version) {
+			report(SeverityError, versions[0], "version %q is unknown", version)
+			version = ""
+		} else if props[0].Name != "VERSION" && version != "2.1" {
+			report(SeverityError, versions[0], "property has to go first")
+		}
+	}
+
+	if version != "2.1" && !slices.ContainsFunc(props, func(p Property) bool { return p.Name == "FN" }) {
+		report(SeverityError, Property{Name: "FN"}, "property is required but missing")
+	}
+	if (version == "2.1" || version == "3.0") && !slices.ContainsFunc(props, func(p Property) bool { return p.Name == "N" }) {
+		report(SeverityError, Property{Name: "N"}, "property is required in vCard %s but missing", version)
+	}
+
+	// Alternative representations with the same ALTID count as a single property
+	counts := map[string]map[string]bool{}
+	for i, p := range props {
+		if !singleValued(p.Name) || p.Name == "VERSION" || p.Name == "FN" {
+			continue
+		}
+		key := strconv.Itoa(i)
+		if altID, found := p.Params.Get("ALTID"); found {
+			key = "ALTID=" + altID
+		}
+		if counts[p.Name] == nil {
+			counts[p.Name] = map[string]bool{}
+		}
+		counts[p.Name][key] = true
+		if len(counts[p.Name]) == 2 {
+			report(SeverityError, p, "property is allowed at most once")
+		}
+	}
+
+	sources := map[int]bool{}
+	for _, m := range c.ClientPIDMaps() {
+		sources[m.Source] = true
+	}
+	kind, _ := c.Kind()
+
+	for _, p := range props {
+		if _, known := propertyVersions[p.Name]; !known && !strings.HasPrefix(p.Name, "X-") {
+			report(SeverityWarning, p, "property is neither standard nor an X- extension")
+		}
+		if version != "" && !propertyDefinedIn(p.Name, version) {
+			report(SeverityError, p, "property is not defined in vCard %s", version)
+		}
+		for _, v := range p.Params.Values("PREF") {
+			if n, err := strconv.Atoi(v); err != nil || n < 1 || n > 100 {
+				report(SeverityError, p, "PREF=%s has to be an integer in range 1..100", v)
+			}
+		}
+		for _, v := range p.Params.Values("PID") {
+			pid, err := ParsePID(v)
+			switch {
+			case err != nil:
+				report(SeverityError, p, "PID=%s is malformed", v)
+			case pid.Source != 0 && !sources[pid.Source]:
+				report(SeverityError, p, "PID=%s refers to source %v without CLIENTPIDMAP", v, pid.Source)
+			}
+		}
+		if err := validateValue(p, version, kind); err != nil {
+			severity := SeverityError
+			if errors.As(err, new(valueWarning)) {
+				severity = SeverityWarning
+			}
+			report(severity, p, "%v", err)
+		}
+	}
+	return r
+}
+
+// Marks errors of [validateValue] which are reported as warnings.
+type valueWarning struct{ error }
+
+// Checks grammar of a value of a single property.
+func validateValue(p Property, version string, kind string) error {
+	isText := strings.EqualFold(first(p.Params.Values("VALUE")), "text")
+	isInline := p.Params.Has("ENCODING")
+
+	switch p.Name {
+	case "BDAY", "ANNIVERSARY", "DEATHDATE":
+		if isText {
+			return nil
+		}
+		if _, err := ParseDate(p.Value); err != nil {
+			return fmt.Errorf("value is not a valid date: %w", err)
+		}
+	case "REV":
+		if _, ok := parseTimestamp(p.Value); !ok {
+			return errors.New("value is not a valid timestamp")
+		}
+	case "GEO":
+		if version == "4.0" {
+			if err := ValidGeoURI(p.Value); err != nil {
+				return err
+			}
+		}
+		g := Geo{}
+		if err := g.UnmarshalVCardField([]byte(p.rest())); err != nil {
+			return errors.New("value is not a valid position")
+		}
+	case "GENDER":
+		sex, _, _ := strings.Cut(p.Value, ";")
+		if !slices.Contains([]string{"", "M", "F", "O", "N", "U"}, strings.ToUpper(sex)) {
+			return fmt.Errorf("sex %q has to be one of M, F, O, N, U", sex)
+		}
+	case "LANG":
+		return ValidLanguageTag(p.Value)
+	case "CLIENTPIDMAP":
+		m := ClientPIDMap{}
+		if err := m.UnmarshalVCardField([]byte(p.rest())); err != nil {
+			return errors.New("value has to be a positive source number and a URI")
+		}
+	case "MEMBER":
+		if !strings.EqualFold(kind, "group") {
+			return errors.New("property is allowed only if KIND is group")
+		}
+	case "KIND":
+		if !slices.Contains([]string{"individual", "group", "org", "location"}, strings.ToLower(p.Value)) &&
+			!strings.HasPrefix(strings.ToLower(p.Value), "x-") {
+			return valueWarning{fmt.Errorf("kind %q is not standard", p.Value)}
+		}
+	case "EMAIL":
+		if err := validEmailAddress(p.Value); err != nil {
+			return valueWarning{err}
+		}
+	case "URL", "SOURCE", "PHOTO", "LOGO", "SOUND", "KEY", "IMPP", "CALURI", "CALADRURI", "FBURL", "CONTACT-URI", "ORG-DIRECTORY":
+		if version != "4.0" || isText || isInline || p.Name == "KEY" && !p.Params.Has("VALUE") {
+			return nil
+		}
+		scheme, _, found := strings.Cut(p.Value, ":")
+		if !found || !validScheme(scheme) {
+			return errors.New("value has to be a URI with a scheme")
+		}
+	}
+	return nil
+}
+
+// Returns properties of the card without records nested into it.
+func topLevelProperties(props []Property) []Property {
+	top := []Property{}
+	depth := 0
+	for _, p := range props {
+		if p.Name == "BEGIN" {
+			depth++
+		}
+		if depth == 0 {
+			top = append(top, p)
+		} else if p.Name == "END" {
+			depth--
+		}
+	}
+	return top
+}
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
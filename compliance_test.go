@@ -0,0 +1,78 @@
+package vcard
+
+import "testing"
+
+func TestValidate(t *testing.T) {
+
+	card := Card{}
+	err := Unmarshal([]byte(crlfy(`BEGIN:VCARD
+VERSION:4.0
+FN:Alex
+N;ALTID=1:Doe;Alex;;;
+N;ALTID=1;LANGUAGE=ja:ドウ;アレックス;;;
+BDAY:19850415
+BDAY:19850416
+LABEL:1 Main St.
+EMAIL;PREF=0:alex
+GENDER:X
+URL:example.com
+KIND:robot
+PHOTO:data:image/jpeg;base64,AQID
+TEL;PID=1.2:555
+X-CUSTOM:1
+FOO:bar
+END:VCARD
+`)), &card)
+	assertEq(t, err, nil)
+
+	r := card.Validate()
+	assertEq(t, r.Valid(), false)
+
+	errs := []string{}
+	for _, i := range r.Errors() {
+		errs = append(errs, i.String())
+	}
+	assertSlicesEq(t, errs, []string{
+		"error: BDAY: property is allowed at most once",
+		"error: LABEL: property is not defined in vCard 4.0",
+		"error: EMAIL: PREF=0 has to be an integer in range 1..100",
+		`error: GENDER: sex "X" has to be one of M, F, O, N, U`,
+		"error: URL: value has to be a URI with a scheme",
+		"error: TEL: PID=1.2 refers to source 2 without CLIENTPIDMAP",
+	})
+
+	warnings := []string{}
+	for _, i := range r.Warnings() {
+		warnings = append(warnings, i.String())
+	}
+	assertSlicesEq(t, warnings, []string{
+		"warning: EMAIL: email address has to contain '@'",
+		`warning: KIND: kind "robot" is not standard`,
+		"warning: FOO: property is neither standard nor an X- extension",
+	})
+	assertErrIs(t, r.Err(), ErrValidation, "error: LABEL: property is not defined in vCard 4.0")
+}
+
+func TestValidateRequired(t *testing.T) {
+
+	card := Card{}
+	card.Add("N", "Doe;Alex;;;")
+	card.Add("VERSION", "3.0")
+
+	issues := []string{}
+	for _, i := range card.Validate().Issues {
+		issues = append(issues, i.String())
+	}
+	assertSlicesEq(t, issues, []string{
+		"error: VERSION: property has to go first",
+		"error: FN: property is required but missing",
+	})
+
+	card = Card{}
+	card.Add("VERSION", "2.1")
+	card.Add("FN", "Alex")
+	card.Add("N", "Doe;Alex")
+	r := card.Validate()
+	assertEq(t, r.Valid(), true)
+	assertEq(t, r.Err(), nil)
+}
@@ -0,0 +1,78 @@
+package vcard
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Rule spanning multiple properties of a record e.g. "at least one of TEL or EMAIL".
+// See [Schema.WithConstraints].
+//
+// values returns raw values of every property with a given name in a record. Constraint returns
+// an error describing a violation, which [Encoder] and [Decoder] wrap with [ErrValidation].
+type Constraint func(values func(name string) []string) error
+
+// Requires at least one of properties to be present.
+func AnyOf(names ...string) Constraint {
+	return func(values func(string) []string) error {
+		for _, name := range names {
+			if len(values(name)) > 0 {
+				return nil
+			}
+		}
+		return fmt.Errorf("record has to contain at least one of %s", strings.Join(names, ", "))
+	}
+}
+
+// Requires property name unless property cond has value e.g. N is required unless KIND is "org".
+// Values are compared case-insensitively.
+func RequiredUnless(name string, cond string, value string) Constraint {
+	return func(values func(string) []string) error {
+		if len(values(name)) > 0 {
+			return nil
+		}
+		if slices.ContainsFunc(values(cond), func(v string) bool { return strings.EqualFold(v, value) }) {
+			return nil
+		}
+		return fmt.Errorf("record has to contain %s unless %s is %q", name, cond, value)
+	}
+}
+
+// Allows property name only if property cond has value e.g. MEMBER is allowed only if KIND is "group".
+// Values are compared case-insensitively.
+func AllowedOnlyIf(name string, cond string, value string) Constraint {
+	return func(values func(string) []string) error {
+		if len(values(name)) == 0 {
+			return nil
+		}
+		if slices.ContainsFunc(values(cond), func(v string) bool { return strings.EqualFold(v, value) }) {
+			return nil
+		}
+		return fmt.Errorf("record may contain %s only if %s is %q", name, cond, value)
+	}
+}
+
+// Returns a copy of the schema with additional constraints checked by [Encoder] and [Decoder]
+// after every record.
+//
+//	schema := vcard.SchemaV4.WithConstraints(
+//		vcard.AnyOf("TEL", "EMAIL"),
+//		vcard.RequiredUnless("N", "KIND", "org"),
+//	)
+func (s Schema) WithConstraints(constraints ...Constraint) Schema {
+	s = s.clone()
+	s.constraints = slices.Concat(s.constraints, constraints)
+	return s
+}
+
+// Checks constraints of the schema.
+func (s Schema) checkConstraints(values func(name string) []string) error {
+	for _, c := range s.constraints {
+		err := c(values)
+		if err != nil {
+			return validationErrf("%w", err)
+		}
+	}
+	return nil
+}
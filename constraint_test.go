@@ -0,0 +1,34 @@
+package vcard
+
+import "testing"
+
+var constrainedSchema = SchemaV4.WithConstraints(
+	AnyOf("TEL", "EMAIL"),
+	RequiredUnless("N", "KIND", "org"),
+)
+
+func TestMarshalConstraints(t *testing.T) {
+
+	_, err := MarshalSchema(map[string]string{"FN": "Alex", "N": "Alex;;;;"}, constrainedSchema)
+	assertErrIs(t, err, ErrValidation, "at least one of TEL, EMAIL")
+
+	_, err = MarshalSchema(map[string]string{"FN": "Alex", "TEL": "555"}, constrainedSchema)
+	assertErrIs(t, err, ErrValidation, `has to contain N unless KIND is "org"`)
+
+	_, err = MarshalSchema(map[string]string{"FN": "ACME", "KIND": "ORG", "EMAIL": "info@acme.com"}, constrainedSchema)
+	assertEq(t, err, nil)
+}
+
+func TestUnmarshalConstraints(t *testing.T) {
+
+	text := `BEGIN:VCARD
+VERSION:4.0
+FN:Alex
+item1.EMAIL:alex@example.com
+END:VCARD
+`
+	m := map[string]string{}
+	err := UnmarshalSchema([]byte(crlfy(text)), &m, []Schema{constrainedSchema})
+
+	assertErrIs(t, err, ErrValidation, "has to contain N")
+}
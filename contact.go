@@ -0,0 +1,383 @@
+package vcard
+
+import (
+	"io"
+	"mime/quotedprintable"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Opinionated representation of a person or an organization covering properties used by
+// typical address book applications.
+//
+// Contact is encoded differently depending on vCard version of the schema: values use format
+// of the version, PREF parameter becomes TYPE=pref in 2.1 and 3.0, LABEL parameter of ADR
+// becomes LABEL property, KIND and ANNIVERSARY become their X- equivalents and properties
+// without an equivalent e.g. IMPP in 2.1 are dropped. Every form is accepted during decoding,
+// including QUOTED-PRINTABLE values of vCard 2.1.
+//
+//	c := vcard.Contact{FormattedName: "Alex", Emails: []vcard.Email{{Address: "alex@example.com"}}}
+//	b, err := vcard.MarshalSchema(c, vcard.SchemaV3)
+type Contact struct {
+	UID UID
+
+	// Kind of entity e.g. "individual", "org". Empty means "individual".
+	Kind string
+
+	FormattedName string
+	Name          Name
+	Nicknames     []string
+
+	Emails    []Email
+	Phones    []Tel
+	Addresses []Adr
+	Impps     []Impp
+	URLs      []string
+
+	Birthday    Date
+	Anniversary Date
+	Photo       Photo
+
+	Org   Org
+	Title string
+	Role  string
+
+	Notes      []string
+	Categories []string
+
+	// Time of the last revision. Zero time is omitted.
+	Rev time.Time
+
+	// Properties not covered by other fields in order of appearance. Written as is after
+	// other fields.
+	Extra []Property
+}
+
+// Returns the contact as a [Card] of a given version.
+//
+// Returns [ErrValidation] if FormattedName is empty, because FN is required in 3.0 and 4.0,
+// or if a value can't be represented in the version e.g. IMPP without a scheme.
+func (c Contact) Card(version string) (Card, error) {
+	if c.FormattedName == "" && version != "2.1" {
+		return Card{}, validationErrf("contact has to have a formatted name, FN is required in vCard %s", version)
+	}
+
+	fields := []encodedField{}
+	text := func(name string, s string) {
+		if s != "" {
+			fields = append(fields, encodedField{name, ":" + escapeText(s)})
+		}
+	}
+	value := func(name string, m VCardFieldVersionMarshaler) error {
+		b, err := m.MarshalVCardFieldVersion(version)
+		if err != nil {
+			return vCardErrf("error during marshaling %s of a contact: %w", name, err)
+		}
+		if len(b) != 0 {
+			fields = append(fields, encodedField{name, string(b)})
+		}
+		return nil
+	}
+
+	text("KIND", c.Kind)
+	text("FN", c.FormattedName)
+	if !c.Name.isZero() || version != "4.0" {
+		_ = value("N", versionless{c.Name})
+	}
+	if len(c.Nicknames) > 0 {
+		fields = append(fields, encodedField{"NICKNAME", ":" + joinTextList(c.Nicknames)})
+	}
+	if c.Org.Name != "" || len(c.Org.Units) > 0 {
+		_ = value("ORG", versionless{c.Org})
+	}
+	text("TITLE", c.Title)
+	text("ROLE", c.Role)
+
+	var err error
+	fields, err = appendPreferred(fields, "TEL", version, c.Phones, func(t Tel) int { return t.Pref })
+	if err != nil {
+		return Card{}, err
+	}
+	fields, err = appendPreferred(fields, "EMAIL", version, c.Emails, func(e Email) int { return e.Pref })
+	if err != nil {
+		return Card{}, err
+	}
+	fields, err = appendPreferred(fields, "ADR", version, c.Addresses, func(a Adr) int { return a.Pref })
+	if err != nil {
+		return Card{}, err
+	}
+	fields, err = appendPreferred(fields, "IMPP", version, c.Impps, func(i Impp) int { return i.Pref })
+	if err != nil {
+		return Card{}, err
+	}
+	for _, u := range c.URLs {
+		fields = append(fields, encodedField{"URL", ":" + u})
+	}
+
+	for _, f := range []struct {
+		name string
+		m    VCardFieldVersionMarshaler
+	}{{"BDAY", c.Birthday}, {"ANNIVERSARY", c.Anniversary}, {"PHOTO", c.Photo}} {
+		if err := value(f.name, f.m); err != nil {
+			return Card{}, err
+		}
+	}
+	if len(c.Categories) > 0 {
+		fields = append(fields, encodedField{"CATEGORIES", ":" + joinTextList(c.Categories)})
+	}
+	for _, note := range c.Notes {
+		text("NOTE", note)
+	}
+	text("UID", string(c.UID))
+	if rest, _, err := marshalTime(c.Rev, version); err != nil {
+		return Card{}, vCardErrf("error during marshaling REV of a contact: %w", err)
+	} else if rest != "" {
+		fields = append(fields, encodedField{"REV", rest})
+	}
+
+	card := Card{props: []Property{{Name: "VERSION", Value: version}}}
+	for _, f := range translateFields(fields, version) {
+		if !propertyDefinedIn(f.name, version) {
+			continue
+		}
+		p, err := ParseProperty(f.name + f.rest)
+		if err != nil {
+			return Card{}, vCardErrf("error during marshaling %s of a contact: %w", f.name, err)
+		}
+		if version == "2.1" && !isASCII(p.Value) && !p.Params.Has("CHARSET") {
+			p.Params.Add("CHARSET", "UTF-8")
+		}
+		card.props = append(card.props, p)
+	}
+	for _, p := range c.Extra {
+		card.AddProperty(p)
+	}
+	return card, nil
+}
+
+// Appends values of a repeated property. Values are encoded as in vCard 4.0 and translated
+// later, see [translateFields]. In vCard 2.1 and 3.0 the most preferred value gets TYPE=pref
+// instead of PREF parameter.
+func appendPreferred[T VCardFieldMarshaler](fields []encodedField, name string, version string, values []T, pref func(T) int) ([]encodedField, error) {
+	best := -1
+	for i, v := range values {
+		if p := pref(v); p > 0 && (best < 0 || p < pref(values[best])) {
+			best = i
+		}
+	}
+	for i, v := range values {
+		b, err := v.MarshalVCardField()
+		if err != nil {
+			return fields, vCardErrf("error during marshaling %s of a contact: %w", name, err)
+		}
+		rest := string(b)
+		if version != "4.0" {
+			params, value, _ := splitParamsValue(rest)
+			params, _, _ = removeParam(params, "PREF")
+			if i == best {
+				params += ";TYPE=pref"
+			}
+			rest = params + ":" + value
+		}
+		fields = append(fields, encodedField{name, rest})
+	}
+	return fields, nil
+}
+
+// Adapts a value which is encoded the same way in every version to [VCardFieldVersionMarshaler].
+type versionless struct{ VCardFieldMarshaler }
+
+func (v versionless) MarshalVCardFieldVersion(string) ([]byte, error) {
+	return v.MarshalVCardField()
+}
+
+func (n Name) isZero() bool {
+	return len(n.FamilyNames)+len(n.GivenNames)+len(n.AdditionalNames)+len(n.HonorificPrefixes)+len(n.HonorificSuffixes)+len(n.SortAs) == 0
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns a contact with values of the card. Properties which are not covered by fields of [Contact],
+// repeated single-valued properties and values which can't be decoded are kept in Extra.
+func ContactOf(card Card) Contact {
+	c := Contact{}
+	labels := []string{}
+	sortString := ""
+	versionSkipped := false
+	depth := 0
+
+	for _, p := range card.props {
+		if p.Name == "VERSION" && !versionSkipped {
+			versionSkipped = true
+			continue
+		}
+		value := decodedValue(p)
+		rest := []byte(p.Params.String() + ":" + value)
+
+		// Records nested into the card e.g. AGENT of vCard 2.1 are kept as is
+		if p.Name == "BEGIN" {
+			depth++
+		}
+		if depth > 0 {
+			if p.Name == "END" {
+				depth--
+			}
+			c.Extra = append(c.Extra, p.clone())
+			continue
+		}
+
+		ok := true
+		switch p.Name {
+		case "UID":
+			ok = c.UID == ""
+			if ok {
+				c.UID = UID(unescapeText(value))
+			}
+		case "KIND", "X-ADDRESSBOOKSERVER-KIND":
+			ok = c.Kind == ""
+			if ok {
+				c.Kind = unescapeText(value)
+			}
+		case "FN":
+			ok = c.FormattedName == ""
+			if ok {
+				c.FormattedName = unescapeText(value)
+			}
+		case "N":
+			ok = c.Name.isZero() && c.Name.UnmarshalVCardField(rest) == nil
+		case "SORT-STRING":
+			sortString = unescapeText(value)
+		case "NICKNAME":
+			c.Nicknames = append(c.Nicknames, splitTextList(value)...)
+		case "EMAIL":
+			e := Email{}
+			ok = e.UnmarshalVCardField(rest) == nil
+			c.Emails = appendIf(c.Emails, e, ok)
+		case "TEL":
+			t := Tel{}
+			ok = t.UnmarshalVCardField(rest) == nil
+			c.Phones = appendIf(c.Phones, t, ok)
+		case "ADR":
+			a := Adr{}
+			ok = a.UnmarshalVCardField(rest) == nil
+			c.Addresses = appendIf(c.Addresses, a, ok)
+		case "LABEL":
+			labels = append(labels, unescapeText(value))
+		case "IMPP":
+			i := Impp{}
+			ok = i.UnmarshalVCardField(rest) == nil
+			c.Impps = appendIf(c.Impps, i, ok)
+		case "URL":
+			c.URLs = append(c.URLs, value)
+		case "BDAY":
+			ok = c.Birthday == (Date{}) && c.Birthday.UnmarshalVCardField(rest) == nil
+		case "ANNIVERSARY", "X-ANNIVERSARY":
+			ok = c.Anniversary == (Date{}) && c.Anniversary.UnmarshalVCardField(rest) == nil
+		case "PHOTO":
+			ok = c.Photo.URI == "" && c.Photo.Data == nil && c.Photo.UnmarshalVCardField(rest) == nil
+		case "ORG":
+			ok = c.Org.Name == "" && len(c.Org.Units) == 0 && c.Org.UnmarshalVCardField(rest) == nil
+		case "TITLE":
+			ok = c.Title == ""
+			if ok {
+				c.Title = unescapeText(value)
+			}
+		case "ROLE":
+			ok = c.Role == ""
+			if ok {
+				c.Role = unescapeText(value)
+			}
+		case "NOTE":
+			c.Notes = append(c.Notes, unescapeText(value))
+		case "CATEGORIES":
+			c.Categories = append(c.Categories, splitTextList(value)...)
+		case "REV":
+			t, err := parseTime(value)
+			ok = c.Rev.IsZero() && err == nil
+			if ok {
+				c.Rev = t
+			}
+		default:
+			ok = false
+		}
+		if !ok {
+			c.Extra = append(c.Extra, p.clone())
+		}
+	}
+
+	// LABEL properties of older versions are matched with addresses in order of appearance
+	for _, label := range labels {
+		i := slices.IndexFunc(c.Addresses, func(a Adr) bool { return a.Label == "" })
+		if i < 0 {
+			c.Extra = append(c.Extra, Property{Name: "LABEL", Value: escapeText(label)})
+			continue
+		}
+		c.Addresses[i].Label = label
+	}
+	if sortString != "" && c.Name.SortAs == nil {
+		c.Name.SortAs = []string{sortString}
+	}
+	return c
+}
+
+// Returns a copy of the property which does not share parameters with p.
+func (p Property) clone() Property {
+	if len(p.Params) == 0 {
+		p.Params = nil
+	} else {
+		p.Params = p.Params.clone()
+	}
+	return p
+}
+
+func appendIf[T any](values []T, v T, ok bool) []T {
+	if !ok {
+		return values
+	}
+	return append(values, v)
+}
+
+// Returns value of a property decoding QUOTED-PRINTABLE values of vCard 2.1.
+// Values which are not valid quoted-printable are returned as is.
+func decodedValue(p Property) string {
+	if !isQuotedPrintable(p.String()) {
+		return p.Value
+	}
+	b, err := io.ReadAll(quotedprintable.NewReader(strings.NewReader(p.Value)))
+	if err != nil {
+		return p.Value
+	}
+	return string(b)
+}
+
+// Encodes the contact as a vCard 4.0 record. See [Contact.MarshalVCardVersion].
+func (c Contact) MarshalVCard() ([]byte, error) {
+	return c.MarshalVCardVersion("4.0")
+}
+
+// Encodes the contact as a record of a given version. Implements [VCardVersionMarshaler].
+func (c Contact) MarshalVCardVersion(version string) ([]byte, error) {
+	card, err := c.Card(version)
+	if err != nil {
+		return nil, err
+	}
+	return codecEncoder.encodeCard(nil, card, encoderCtx{})
+}
+
+// Decodes a single record of any version into the contact. Implements [VCardUnmarshaler].
+func (c *Contact) UnmarshalVCard(data []byte) error {
+	card, err := ParseRecord(data)
+	if err != nil {
+		return err
+	}
+	*c = ContactOf(card)
+	return nil
+}
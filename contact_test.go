@@ -0,0 +1,145 @@
+package vcard
+
+import (
+	"testing"
+	"time"
+)
+
+func testContact() Contact {
+	return Contact{
+		UID:           "urn:uuid:f81d4fae-7dec-11d0-a765-00a0c91e6bf6",
+		Kind:          "individual",
+		FormattedName: "Alex Doe",
+		Name:          Name{FamilyNames: []string{"Doe"}, GivenNames: []string{"Alex"}},
+		Nicknames:     []string{"Al"},
+		Emails:        []Email{{Address: "alex@example.com", Types: []string{"work"}, Pref: 1}, {Address: "alex@home.example"}},
+		Phones:        []Tel{{Number: "+1 555 0100", Types: []string{"cell"}}},
+		Addresses:     []Adr{{Street: "1 Main St.", Locality: "Anytown", Label: "1 Main St.\nAnytown"}},
+		Impps:         []Impp{{URI: "xmpp:alex@example.com"}},
+		URLs:          []string{"https://example.com"},
+		Birthday:      Date{Year: 1985, Month: time.April, Day: 15},
+		Anniversary:   Date{Year: 2010, Month: time.June, Day: 1},
+		Org:           Org{Name: "Example, Inc.", Units: []string{"Sales"}},
+		Title:         "Manager",
+		Notes:         []string{"Likes tea; not coffee"},
+		Categories:    []string{"friends"},
+		Rev:           time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Extra:         []Property{{Name: "X-CUSTOM", Value: "42"}},
+	}
+}
+
+func TestContactV4(t *testing.T) {
+
+	text := crlfy(`BEGIN:VCARD
+VERSION:4.0
+KIND:individual
+FN:Alex Doe
+N:Doe;Alex;;;
+NICKNAME:Al
+ORG:Example\, Inc.;Sales
+TITLE:Manager
+TEL;TYPE=cell:+1 555 0100
+EMAIL;TYPE=work;PREF=1:alex@example.com
+EMAIL:alex@home.example
+ADR;LABEL="1 Main St.^nAnytown":;;1 Main St.;Anytown;;;
+IMPP:xmpp:alex@example.com
+URL:https://example.com
+BDAY:19850415
+ANNIVERSARY:20100601
+CATEGORIES:friends
+NOTE:Likes tea\; not coffee
+UID:urn:uuid:f81d4fae-7dec-11d0-a765-00a0c91e6bf6
+REV:20240102T030405Z
+X-CUSTOM:42
+END:VCARD
+`)
+	b, err := Marshal(testContact())
+	assertEq(t, err, nil)
+	assertStringLinesEq(t, string(b), text)
+
+	c := Contact{}
+	err = Unmarshal([]byte(text), &c)
+	assertEq(t, err, nil)
+	assertDeepEq(t, c, testContact())
+}
+
+func TestContactV3(t *testing.T) {
+
+	text := crlfy(`BEGIN:VCARD
+VERSION:3.0
+X-ADDRESSBOOKSERVER-KIND:individual
+FN:Alex Doe
+N:Doe;Alex;;;
+NICKNAME:Al
+ORG:Example\, Inc.;Sales
+TITLE:Manager
+TEL;TYPE=cell:+1 555 0100
+EMAIL;TYPE=work;TYPE=pref:alex@example.com
+EMAIL:alex@home.example
+ADR:;;1 Main St.;Anytown;;;
+LABEL:1 Main St.\nAnytown
+IMPP:xmpp:alex@example.com
+URL:https://example.com
+BDAY:1985-04-15
+X-ANNIVERSARY:2010-06-01
+CATEGORIES:friends
+NOTE:Likes tea\; not coffee
+UID:urn:uuid:f81d4fae-7dec-11d0-a765-00a0c91e6bf6
+REV:2024-01-02T03:04:05Z
+X-CUSTOM:42
+END:VCARD
+`)
+	b, err := MarshalSchema(testContact(), SchemaV3)
+	assertEq(t, err, nil)
+	assertStringLinesEq(t, string(b), text)
+
+	c := Contact{}
+	err = Unmarshal([]byte(text), &c)
+	assertEq(t, err, nil)
+	assertDeepEq(t, c, testContact())
+}
+
+func TestContactV21(t *testing.T) {
+
+	c := Contact{
+		FormattedName: "Zoë",
+		Impps:         []Impp{{URI: "xmpp:zoe@example.com"}},
+		Phones:        []Tel{{Number: "555", Pref: 2}, {Number: "556", Pref: 1}},
+	}
+	b, err := MarshalSchema(c, SchemaV2_1)
+	assertEq(t, err, nil)
+	assertStringLinesEq(t, string(b), crlfy(`BEGIN:VCARD
+VERSION:2.1
+FN;CHARSET=UTF-8:Zoë
+N:;;;;
+TEL:555
+TEL;TYPE=pref:556
+END:VCARD
+`))
+
+	text := crlfy(`BEGIN:VCARD
+VERSION:2.1
+N;ENCODING=QUOTED-PRINTABLE:M=C3=BCller;J=
+=C3=BCrgen
+TEL;CELL;PREF:555
+AGENT:
+BEGIN:VCARD
+VERSION:2.1
+FN:Agent
+END:VCARD
+END:VCARD
+`)
+	decoded := Contact{}
+	err = Unmarshal([]byte(text), &decoded)
+	assertEq(t, err, nil)
+	assertDeepEq(t, decoded.Name, Name{FamilyNames: []string{"Müller"}, GivenNames: []string{"Jürgen"}})
+	assertDeepEq(t, decoded.Phones, []Tel{{Number: "555", Types: []string{"CELL"}, Pref: 1}})
+	assertStringsEq(t, decoded.FormattedName, "")
+	assertEq(t, len(decoded.Extra), 5)
+}
+
+func TestContactWithoutFN(t *testing.T) {
+
+	_, err := Marshal(Contact{})
+	assertErrIs(t, err, ErrValidation, "FN is required in vCard 4.0")
+}
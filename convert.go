@@ -0,0 +1,245 @@
+package vcard
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Loss of information during [Convert] e.g. a property which is not defined in the target version.
+type Warning struct {
+	Property string // Name of a property e.g. "GENDER".
+	Message  string
+}
+
+// Returns the warning as "PROPERTY: message".
+func (w Warning) String() string {
+	return w.Property + ": " + w.Message
+}
+
+// Returns a copy of the card converted to vCard targetVersion which is "2.1", "3.0" or "4.0".
+//
+// Conversion translates representations which differ between versions:
+//
+//   - LABEL property becomes LABEL parameter of ADR in 4.0 and back in 3.0 and 2.1.
+//   - SORT-STRING becomes SORT-AS parameter of N in 4.0 and back.
+//   - PHOTO, LOGO, SOUND and KEY switch between data: URIs and ENCODING parameter.
+//   - The most preferred value of a property gets TYPE=pref in 3.0 and 2.1 and TYPE=pref becomes PREF=1 in 4.0.
+//   - Dates switch between basic format of 4.0 and extended format of older versions.
+//   - KIND, MEMBER, GENDER and ANNIVERSARY become their X- equivalents in 3.0 and 2.1 and back.
+//   - QUOTED-PRINTABLE values and nameless parameters of 2.1 are decoded e.g. TEL;CELL becomes TEL;TYPE=CELL.
+//
+// Properties which are not defined in the target version and values which can't be represented
+// in it are dropped and reported as warnings. Empty N is added in 3.0 and 2.1 where N is required.
+// Records nested into the card e.g. AGENT of 2.1 are kept as is.
+//
+// Returns [ErrVCard] if the card or the target version is not one of "2.1", "3.0" or "4.0".
+func Convert(card Card, targetVersion string) (Card, []Warning, error) {
+	versions := []string{"2.1", "3.0", "4.0"}
+	if !slices.Contains(versions, targetVersion) {
+		return Card{}, nil, vCardErrf("unable to convert a card to unknown version %q", targetVersion)
+	}
+	source := card.Version()
+	if !slices.Contains(versions, source) {
+		return Card{}, nil, vCardErrf("unable to convert a card of unknown version %q", source)
+	}
+
+	warnings := []Warning{}
+	warn := func(name string, format string, args ...any) {
+		warnings = append(warnings, Warning{name, fmt.Sprintf(format, args...)})
+	}
+
+	props := []Property{}
+	nested := map[int][]Property{} // records nested into a property at index
+	depth := 0
+	versionSkipped := false
+	for _, p := range card.props {
+		if p.Name == "BEGIN" {
+			depth++
+		}
+		if depth > 0 {
+			if p.Name == "END" {
+				depth--
+			}
+			nested[len(props)-1] = append(nested[len(props)-1], p.clone())
+			continue
+		}
+		if p.Name == "VERSION" && !versionSkipped {
+			versionSkipped = true
+			continue
+		}
+		p, err := convertProperty(p.clone(), source, targetVersion)
+		if err != nil {
+			warn(p.Name, "value can't be represented in vCard %s: %v", targetVersion, err)
+			continue
+		}
+		props = append(props, p)
+	}
+	convertPrefs(props, targetVersion)
+
+	fields := make([]encodedField, len(props))
+	for i, p := range props {
+		fields[i] = encodedField{p.fullName(), p.rest()}
+	}
+	fields = translateFields(fields, targetVersion)
+
+	converted := Card{props: []Property{{Name: "VERSION", Value: targetVersion}}}
+	for _, f := range fields {
+		if !propertyDefinedIn(f.name, targetVersion) {
+			warn(canonicalPropertyName(f.name), "property is not defined in vCard %s", targetVersion)
+			continue
+		}
+		p, err := ParseProperty(f.name + f.rest)
+		if err != nil {
+			return Card{}, nil, vCardErrf("error during converting %s: %w", f.name, err)
+		}
+		converted.props = append(converted.props, p.clone())
+
+		// Fields are translated one to one except LABEL and SORT-STRING, so nested
+		// records are found by the original property
+		if i := slices.IndexFunc(props, func(p Property) bool { return p.fullName()+p.rest() == f.name+f.rest }); i >= 0 {
+			converted.props = append(converted.props, nested[i]...)
+		}
+	}
+
+	if targetVersion != "4.0" && converted.count("N") == 0 {
+		converted.props = slices.Insert(converted.props, 1, Property{Name: "N", Value: ";;;;"})
+	}
+	if targetVersion != "2.1" && converted.count("FN") == 0 {
+		warn("FN", "property is required in vCard %s but the card does not contain it", targetVersion)
+	}
+	return converted, warnings, nil
+}
+
+// Legacy X- properties mapped to their vCard 4.0 names. See [legacyPropertyNames].
+var standardPropertyNames = map[string]string{
+	"X-ANNIVERSARY":              "ANNIVERSARY",
+	"X-GENDER":                   "GENDER",
+	"X-ADDRESSBOOKSERVER-KIND":   "KIND",
+	"X-ADDRESSBOOKSERVER-MEMBER": "MEMBER",
+}
+
+// Converts representation of a value of a single property. Returned error means the value can't
+// be represented in the target version.
+func convertProperty(p Property, source string, target string) (Property, error) {
+	if source == "2.1" && target != "2.1" {
+		p.Value = decodedValue(p)
+		p.Params = slices.DeleteFunc(p.Params, func(param Param) bool {
+			return strings.EqualFold(param.Name, "CHARSET") || strings.EqualFold(param.Name, "ENCODING") ||
+				param.Values == nil && strings.EqualFold(param.Name, "QUOTED-PRINTABLE")
+		})
+		for i, param := range p.Params {
+			if param.Values == nil {
+				p.Params[i] = Param{"TYPE", []string{param.Name}}
+			}
+		}
+	}
+	if target == "4.0" {
+		if name, found := standardPropertyNames[p.Name]; found {
+			p.Name = name
+		}
+	}
+
+	var err error
+	switch p.Name {
+	case "PHOTO", "LOGO", "SOUND", "KEY":
+		p, err = convertMedia(p, target)
+	case "BDAY", "ANNIVERSARY", "X-ANNIVERSARY", "DEATHDATE":
+		d := Date{}
+		if err = d.UnmarshalVCardField([]byte(p.rest())); err != nil {
+			return p, err
+		}
+		p, err = withRest(p, d, target)
+	case "REV":
+		t, ok := parseTimestamp(p.Value)
+		if ok {
+			p, err = withRest(p, DateTimeOf(t.UTC()), target)
+		}
+	case "TEL":
+		if target != "4.0" && strings.HasPrefix(p.Value, "tel:") {
+			p.Value = strings.TrimPrefix(p.Value, "tel:")
+			p.Params.Del("VALUE")
+		}
+	}
+	if err != nil {
+		return p, err
+	}
+
+	if target == "2.1" && !isASCII(p.Value) && !p.Params.Has("CHARSET") {
+		p.Params.Add("CHARSET", "UTF-8")
+	}
+	return p, nil
+}
+
+// Kinds of media of properties used to convert TYPE parameter to a media type.
+var mediaKinds = map[string]string{"PHOTO": "image", "LOGO": "image", "SOUND": "audio", "KEY": "application"}
+
+func convertMedia(p Property, target string) (Property, error) {
+	m, err := unmarshalMedia([]byte(p.rest()), mediaKinds[p.Name])
+	if err != nil {
+		return p, err
+	}
+	rest := m.marshal(target)
+	if len(rest) == 0 {
+		return p, nil
+	}
+	return ParseProperty(p.fullName() + string(rest))
+}
+
+// Replaces parameters and value of p with ones encoded by m for the target version.
+func withRest(p Property, m VCardFieldVersionMarshaler, target string) (Property, error) {
+	rest, err := m.MarshalVCardFieldVersion(target)
+	if err != nil {
+		return p, err
+	}
+	converted, err := ParseProperty(p.fullName() + string(rest))
+	if err != nil {
+		return p, err
+	}
+	for _, param := range p.Params {
+		if !converted.Params.Has(param.Name) && !strings.EqualFold(param.Name, "VALUE") {
+			converted.Params = append(converted.Params, param)
+		}
+	}
+	return converted, nil
+}
+
+// Converts PREF parameter of 4.0 to TYPE=pref of older versions and back. In older versions
+// the most preferred property of every name gets TYPE=pref.
+func convertPrefs(props []Property, target string) {
+	best := map[string]int{}
+	for i, p := range props {
+		pref := propertyPref(p)
+		if pref == 100 && !p.Params.Has("PREF") {
+			continue
+		}
+		if j, found := best[p.Name]; !found || pref < propertyPref(props[j]) {
+			best[p.Name] = i
+		}
+	}
+
+	for i := range props {
+		p := &props[i]
+		pref := propertyPref(*p)
+		hasPref := pref < 100 || p.Params.Has("PREF")
+		if !hasPref {
+			continue
+		}
+		p.Params.Del("PREF")
+		for j := range p.Params {
+			if strings.EqualFold(p.Params[j].Name, "TYPE") {
+				p.Params[j].Values = withoutValue(slices.Clone(p.Params[j].Values), "pref")
+			}
+		}
+		p.Params = slices.DeleteFunc(p.Params, func(param Param) bool {
+			return param.Values != nil && len(param.Values) == 0 ||
+				param.Values == nil && strings.EqualFold(param.Name, "pref")
+		})
+		switch {
+		case target == "4.0":
+			p.Params.Set("PREF", fmt.Sprint(pref))
+		case best[p.Name] == i:
+			p.Params.Add("TYPE", "pref")
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package vcard
+
+import "testing"
+
+func TestConvertV3ToV4(t *testing.T) {
+
+	card := Card{}
+	err := Unmarshal([]byte(crlfy(`BEGIN:VCARD
+VERSION:3.0
+FN:Alex
+N:Doe;Alex;;;
+SORT-STRING:Doe
+TEL;TYPE=cell,pref:555
+TEL;TYPE=work:556
+ADR;TYPE=home:;;1 Main St.;;;;
+LABEL:1 Main St.
+BDAY:1985-04-15
+PHOTO;ENCODING=b;TYPE=JPEG:AQID
+X-ADDRESSBOOKSERVER-KIND:individual
+CLASS:PUBLIC
+REV:2024-01-02T03:04:05Z
+END:VCARD
+`)), &card)
+	assertEq(t, err, nil)
+
+	converted, warnings, err := Convert(card, "4.0")
+	assertEq(t, err, nil)
+	assertSlicesEq(t, warnings, []Warning{{"CLASS", "property is not defined in vCard 4.0"}})
+
+	b, err := Marshal(converted)
+	assertEq(t, err, nil)
+	assertStringLinesEq(t, string(b), crlfy(`BEGIN:VCARD
+VERSION:4.0
+FN:Alex
+N;SORT-AS=Doe:Doe;Alex;;;
+TEL;TYPE=cell;PREF=1:555
+TEL;TYPE=work:556
+ADR;TYPE=home;LABEL=1 Main St.:;;1 Main St.;;;;
+BDAY:19850415
+PHOTO:data:image/jpeg;base64,AQID
+KIND:individual
+REV:20240102T030405Z
+END:VCARD
+`))
+
+	back, warnings, err := Convert(converted, "3.0")
+	assertEq(t, err, nil)
+	assertEq(t, len(warnings), 0)
+	b, err = Marshal(back)
+	assertEq(t, err, nil)
+	assertStringLinesEq(t, string(b), crlfy(`BEGIN:VCARD
+VERSION:3.0
+FN:Alex
+N:Doe;Alex;;;
+TEL;TYPE=cell,pref:555
+TEL;TYPE=work:556
+ADR;TYPE=home:;;1 Main St.;;;;
+LABEL:1 Main St.
+BDAY:1985-04-15
+PHOTO;ENCODING=b;TYPE=JPEG:AQID
+X-ADDRESSBOOKSERVER-KIND:individual
+REV:2024-01-02T03:04:05Z
+SORT-STRING:Doe
+END:VCARD
+`))
+}
+
+func TestConvertV21(t *testing.T) {
+
+	card := Card{}
+	err := Unmarshal([]byte(crlfy(`BEGIN:VCARD
+VERSION:2.1
+FN;CHARSET=UTF-8;ENCODING=QUOTED-PRINTABLE:J=C3=BCrgen
+TEL;CELL;PREF:555
+AGENT:
+BEGIN:VCARD
+VERSION:2.1
+FN:Agent
+END:VCARD
+END:VCARD
+`)), &card)
+	assertEq(t, err, nil)
+
+	converted, warnings, err := Convert(card, "4.0")
+	assertEq(t, err, nil)
+	assertSlicesEq(t, warnings, []Warning{{"AGENT", "property is not defined in vCard 4.0"}})
+	b, err := Marshal(converted)
+	assertEq(t, err, nil)
+	assertStringLinesEq(t, string(b), crlfy(`BEGIN:VCARD
+VERSION:4.0
+FN:Jürgen
+TEL;TYPE=CELL;PREF=1:555
+END:VCARD
+`))
+
+	converted, warnings, err = Convert(card, "3.0")
+	assertEq(t, err, nil)
+	assertEq(t, len(warnings), 0)
+	assertSlicesEq(t, converted.Values("FN"), []string{"Jürgen", "Agent"})
+	assertSlicesEq(t, converted.Values("N"), []string{";;;;"})
+
+	down, warnings, err := Convert(converted, "2.1")
+	assertEq(t, err, nil)
+	assertEq(t, len(warnings), 0)
+	p, _ := down.first("FN")
+	assertStringsEq(t, p.String(), "FN;CHARSET=UTF-8:Jürgen")
+
+	_, _, err = Convert(card, "5.0")
+	assertErrIs(t, err, ErrVCard, "unknown version")
+}
@@ -0,0 +1,287 @@
+package vcard
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Value of BDAY, ANNIVERSARY and DEATHDATE properties as per
+// https://datatracker.ietf.org/doc/html/rfc6350#section-4.3.4
+//
+// Date may be partial e.g. a birthday without a year "--0415" or a year only "1985".
+// Zero Year, Month or Day means the component is absent. Values like "circa 1800"
+// are kept in Text.
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+
+	// Reports whether Hour, Minute and Second are set.
+	HasTime bool
+	Hour    int
+	Minute  int
+	Second  int
+
+	// Time zone of the time. Nil means local time of the person.
+	Zone *time.Location
+
+	// Free form text e.g. "circa 1800" of VALUE=text. Other fields are ignored if Text is set.
+	Text string
+}
+
+// Creates a full date without time from t.
+func DateOf(t time.Time) Date {
+	return Date{Year: t.Year(), Month: t.Month(), Day: t.Day()}
+}
+
+// Creates a full date with time and zone from t.
+func DateTimeOf(t time.Time) Date {
+	return Date{
+		Year: t.Year(), Month: t.Month(), Day: t.Day(),
+		HasTime: true, Hour: t.Hour(), Minute: t.Minute(), Second: t.Second(),
+		Zone: t.Location(),
+	}
+}
+
+// Returns the date as time.Time if year, month and day are known. Dates without a zone
+// are returned in UTC.
+func (d Date) Time() (time.Time, bool) {
+	if d.Text != "" || d.Year == 0 || d.Month == 0 || d.Day == 0 {
+		return time.Time{}, false
+	}
+	loc := d.Zone
+	if loc == nil {
+		loc = time.UTC
+	}
+	return time.Date(d.Year, d.Month, d.Day, d.Hour, d.Minute, d.Second, 0, loc), true
+}
+
+// Returns the date in basic format of vCard 4.0 e.g. "19960415", "--0415" or "19961022T140000Z".
+func (d Date) String() string {
+	return d.format(false)
+}
+
+// Encodes the date in basic format of vCard 4.0.
+func (d Date) MarshalVCardField() ([]byte, error) {
+	return d.MarshalVCardFieldVersion("4.0")
+}
+
+// Encodes the date in basic format of vCard 4.0 or in extended format of vCard 2.1 and 3.0
+// e.g. "1996-04-15" and "--04-15". Zero date is omitted.
+func (d Date) MarshalVCardFieldVersion(version string) ([]byte, error) {
+	if d.Text != "" {
+		return []byte(";VALUE=text:" + escapeText(d.Text)), nil
+	}
+	if d.Year == 0 && d.Month == 0 && d.Day == 0 && !d.HasTime {
+		return nil, nil
+	}
+	if d.Year == 0 && d.Month == 0 && d.Day != 0 && version != "4.0" {
+		return nil, validationErrf("date without year and month can't be represented in vCard %s", version)
+	}
+	return []byte(":" + d.format(version != "4.0")), nil
+}
+
+// Decodes dates, times and date-times of vCard 2.1, 3.0 and 4.0. VALUE=text is decoded into Text.
+func (d *Date) UnmarshalVCardField(data []byte) error {
+	params, value, _ := splitParamsValue(string(data))
+
+	if v := paramValues(params, "VALUE"); len(v) > 0 && strings.EqualFold(v[0], "text") {
+		*d = Date{Text: unescapeText(value)}
+		return nil
+	}
+	date, err := ParseDate(value)
+	if err != nil {
+		return err
+	}
+	*d = date
+	return nil
+}
+
+// Parses DATE, TIME and DATE-TIME values of vCard 4.0 e.g. "19960415", "--0415", "1985",
+// "19961022T140000Z", "T1022" as well as extended format of older versions e.g. "1996-04-15".
+func ParseDate(s string) (Date, error) {
+	d := Date{}
+
+	datePart, timePart, hasTime := strings.Cut(strings.TrimSpace(s), "T")
+	if datePart == "" && !hasTime {
+		return d, parsingErrf("date is empty")
+	}
+	err := d.parseDate(datePart)
+	if err != nil {
+		return Date{}, parsingErrf("unable to parse date %q: %w", s, err)
+	}
+	if hasTime {
+		err = d.parseTime(timePart)
+		if err != nil {
+			return Date{}, parsingErrf("unable to parse time of %q: %w", s, err)
+		}
+	}
+	return d, nil
+}
+
+func (d *Date) parseDate(s string) error {
+	var err error
+	switch {
+	case s == "":
+		return nil
+	case strings.HasPrefix(s, "---"):
+		d.Day, err = dateComponent(s[3:], 1, 31)
+		return err
+	case strings.HasPrefix(s, "--"):
+		s = strings.ReplaceAll(s[2:], "-", "")
+		month := 0
+		month, err = dateComponent(s[:min(2, len(s))], 1, 12)
+		if err != nil {
+			return err
+		}
+		d.Month = time.Month(month)
+		if len(s) > 2 {
+			d.Day, err = dateComponent(s[2:], 1, 31)
+		}
+		return err
+	}
+
+	extended := strings.Contains(s, "-")
+	s = strings.ReplaceAll(s, "-", "")
+	if len(s) != 4 && len(s) != 6 && len(s) != 8 || len(s) == 6 && !extended {
+		return fmt.Errorf("unexpected length %v", len(s))
+	}
+	d.Year, err = dateComponent(s[:4], 0, 9999)
+	if err != nil {
+		return err
+	}
+	if len(s) > 4 {
+		month := 0
+		month, err = dateComponent(s[4:6], 1, 12)
+		if err != nil {
+			return err
+		}
+		d.Month = time.Month(month)
+	}
+	if len(s) > 6 {
+		d.Day, err = dateComponent(s[6:], 1, 31)
+	}
+	return err
+}
+
+func (d *Date) parseTime(s string) error {
+	if i := strings.IndexAny(s, "Z+-"); i >= 0 {
+		if i == 0 {
+			return fmt.Errorf("truncated time is not supported")
+		}
+		offset, ok := parseUTCOffset(strings.ToUpper(s[i:]))
+		if !ok {
+			return fmt.Errorf("invalid UTC offset %q", s[i:])
+		}
+		if s[i] == 'Z' || s[i] == 'z' {
+			d.Zone = time.UTC
+		} else {
+			d.Zone = time.FixedZone("UTC"+formatUTCOffset(offset, true), int(offset/time.Second))
+		}
+		s = s[:i]
+	}
+	s, _, _ = strings.Cut(s, ",") // fractions of seconds
+	s = strings.ReplaceAll(s, ":", "")
+	if len(s) != 2 && len(s) != 4 && len(s) != 6 {
+		return fmt.Errorf("unexpected length %v", len(s))
+	}
+
+	var err error
+	d.HasTime = true
+	d.Hour, err = dateComponent(s[:2], 0, 23)
+	if err == nil && len(s) > 2 {
+		d.Minute, err = dateComponent(s[2:4], 0, 59)
+	}
+	if err == nil && len(s) > 4 {
+		d.Second, err = dateComponent(s[4:], 0, 60)
+	}
+	return err
+}
+
+func dateComponent(s string, lo int, hi int) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < lo || n > hi || strings.ContainsAny(s, "+-") {
+		return 0, fmt.Errorf("invalid component %q", s)
+	}
+	return n, nil
+}
+
+// Fields of type time.Time or *time.Time may be used for BDAY, ANNIVERSARY, REV etc.
+var timeType = reflect.TypeFor[time.Time]()
+
+// Encodes t as DATE if its clock is exactly midnight, otherwise as DATE-TIME with its zone.
+// Format is defined by the vCard version, see [Date.MarshalVCardFieldVersion]. Zero time is omitted.
+func marshalTime(t time.Time, version string) (rest string, ok bool, err error) {
+	if t.IsZero() {
+		return "", true, nil
+	}
+	d := DateTimeOf(t)
+	if t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 && t.Nanosecond() == 0 {
+		d = DateOf(t)
+	}
+	b, err := d.MarshalVCardFieldVersion(version)
+	return string(b), true, err
+}
+
+// Decodes a full date or date-time of any vCard version e.g. "19960415", "1996-04-15",
+// "19961022T140000Z" or RFC 3339 "1996-10-22T14:00:00.5+02:00". Values without a zone are in UTC.
+func parseTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, nil
+	}
+	d, err := ParseDate(value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	t, ok := d.Time()
+	if !ok {
+		return time.Time{}, parsingErrf("date %q can't be represented as time.Time", value)
+	}
+	return t, nil
+}
+
+// Formats the date in basic or extended format.
+func (d Date) format(extended bool) string {
+	b := strings.Builder{}
+	sep := ""
+	if extended {
+		sep = "-"
+	}
+
+	switch {
+	case d.Year != 0 && d.Month == 0:
+		fmt.Fprintf(&b, "%04d", d.Year)
+	case d.Year != 0 && d.Day == 0:
+		fmt.Fprintf(&b, "%04d-%02d", d.Year, d.Month)
+	case d.Year != 0:
+		fmt.Fprintf(&b, "%04d%s%02d%s%02d", d.Year, sep, d.Month, sep, d.Day)
+	case d.Month != 0 && d.Day == 0:
+		fmt.Fprintf(&b, "--%02d", d.Month)
+	case d.Month != 0:
+		fmt.Fprintf(&b, "--%02d%s%02d", d.Month, sep, d.Day)
+	case d.Day != 0:
+		fmt.Fprintf(&b, "---%02d", d.Day)
+	}
+
+	if !d.HasTime {
+		return b.String()
+	}
+	sep = ""
+	if extended {
+		sep = ":"
+	}
+	fmt.Fprintf(&b, "T%02d%s%02d%s%02d", d.Hour, sep, d.Minute, sep, d.Second)
+
+	if d.Zone != nil {
+		_, offset := time.Date(d.Year, max(d.Month, 1), max(d.Day, 1), d.Hour, d.Minute, d.Second, 0, d.Zone).Zone()
+		if offset == 0 {
+			b.WriteByte('Z')
+		} else {
+			b.WriteString(formatUTCOffset(time.Duration(offset)*time.Second, extended))
+		}
+	}
+	return b.String()
+}
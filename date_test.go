@@ -0,0 +1,188 @@
+package vcard
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+
+	cases := map[string]Date{
+		"19960415":         {Year: 1996, Month: time.April, Day: 15},
+		"1996-04-15":       {Year: 1996, Month: time.April, Day: 15},
+		"1985":             {Year: 1985},
+		"1985-04":          {Year: 1985, Month: time.April},
+		"--0415":           {Month: time.April, Day: 15},
+		"--04-15":          {Month: time.April, Day: 15},
+		"--04":             {Month: time.April},
+		"---15":            {Day: 15},
+		"T1022":            {HasTime: true, Hour: 10, Minute: 22},
+		"19961022T140000":  {Year: 1996, Month: time.October, Day: 22, HasTime: true, Hour: 14},
+		"--1022T1400":      {Month: time.October, Day: 22, HasTime: true, Hour: 14},
+		"19961022T140000Z": {Year: 1996, Month: time.October, Day: 22, HasTime: true, Hour: 14, Zone: time.UTC},
+	}
+	for s, exp := range cases {
+		d, err := ParseDate(s)
+
+		assertEq(t, err, nil)
+		assertEq(t, d, exp)
+	}
+
+	d, err := ParseDate("1996-10-22T14:00:00-05:00")
+	assertEq(t, err, nil)
+	assertEq(t, d.String(), "19961022T140000-0500")
+
+	for _, s := range []string{"", "1996041", "19961315", "--1340", "T25", "T-2200", "19960415T14+99", "abcd"} {
+		_, err := ParseDate(s)
+		assertErrIs(t, err, ErrParsing, "")
+	}
+}
+
+func TestDateFormat(t *testing.T) {
+
+	cases := []struct {
+		date     Date
+		basic    string
+		extended string
+	}{
+		{Date{Year: 1996, Month: time.April, Day: 15}, ":19960415", ":1996-04-15"},
+		{Date{Month: time.April, Day: 15}, ":--0415", ":--04-15"},
+		{Date{Year: 1985}, ":1985", ":1985"},
+		{Date{Year: 1985, Month: time.April}, ":1985-04", ":1985-04"},
+		{DateTimeOf(time.Date(1996, time.October, 22, 14, 0, 0, 0, time.UTC)), ":19961022T140000Z", ":1996-10-22T14:00:00Z"},
+		{Date{Text: "circa 1800"}, ";VALUE=text:circa 1800", ";VALUE=text:circa 1800"},
+	}
+	for _, c := range cases {
+		b, err := c.date.MarshalVCardFieldVersion("4.0")
+		assertEq(t, err, nil)
+		assertStringsEq(t, string(b), c.basic)
+
+		b, err = c.date.MarshalVCardFieldVersion("3.0")
+		assertEq(t, err, nil)
+		assertStringsEq(t, string(b), c.extended)
+
+		d := Date{}
+		err = d.UnmarshalVCardField([]byte(c.basic))
+		assertEq(t, err, nil)
+		assertEq(t, d.String(), c.date.String())
+	}
+
+	b, err := Date{}.MarshalVCardField()
+	assertEq(t, err, nil)
+	assertEq(t, len(b), 0)
+
+	_, err = Date{Day: 15}.MarshalVCardFieldVersion("3.0")
+	assertErrIs(t, err, ErrValidation, "can't be represented in vCard 3.0")
+}
+
+func TestDateTime(t *testing.T) {
+
+	tm, ok := Date{Year: 1996, Month: time.April, Day: 15}.Time()
+	assertEq(t, ok, true)
+	assertEq(t, tm, time.Date(1996, time.April, 15, 0, 0, 0, 0, time.UTC))
+
+	_, ok = Date{Month: time.April, Day: 15}.Time()
+	assertEq(t, ok, false)
+
+	_, ok = Date{Text: "circa 1800"}.Time()
+	assertEq(t, ok, false)
+
+	assertEq(t, DateOf(tm), Date{Year: 1996, Month: time.April, Day: 15})
+}
+
+type BirthdayStruct struct {
+	FN   string
+	N    string
+	BDAY Date
+}
+
+func TestDateField(t *testing.T) {
+
+	text := `BEGIN:VCARD
+VERSION:3.0
+FN:Alex
+N:;Alex;;;
+BDAY:--04-15
+END:VCARD
+`
+	s := BirthdayStruct{}
+	err := Unmarshal([]byte(crlfy(text)), &s)
+
+	assertEq(t, err, nil)
+	assertEq(t, s.BDAY, Date{Month: time.April, Day: 15})
+
+	b, err := Marshal(s)
+
+	assertEq(t, err, nil)
+	assertStringLinesEq(t, string(b), crlfy("BEGIN:VCARD\nVERSION:4.0\nFN:Alex\nN:;Alex;;;\nBDAY:--0415\nEND:VCARD\n"))
+
+	b, err = Marshal(BirthdayStruct{FN: "Sam"})
+
+	assertEq(t, err, nil)
+	assertStringLinesEq(t, string(b), crlfy("BEGIN:VCARD\nVERSION:4.0\nFN:Sam\nN:\nEND:VCARD\n"))
+}
+
+func TestTimeField(t *testing.T) {
+
+	type Contact struct {
+		FN          string
+		N           string
+		BDAY        time.Time
+		ANNIVERSARY *time.Time
+		REV         time.Time
+	}
+	rev := time.Date(2024, 3, 5, 10, 30, 0, 0, time.UTC)
+	c := Contact{
+		FN:   "Alex",
+		N:    "Doe;Alex;;;",
+		BDAY: time.Date(1996, 4, 15, 0, 0, 0, 0, time.UTC),
+		REV:  rev,
+	}
+
+	b, err := Marshal(c)
+	assertEq(t, err, nil)
+	assertStringLinesEq(t, string(b), crlfy(`BEGIN:VCARD
+VERSION:4.0
+FN:Alex
+N:Doe;Alex;;;
+BDAY:19960415
+REV:20240305T103000Z
+END:VCARD
+`))
+
+	b, err = MarshalSchema(c, SchemaV3)
+	assertEq(t, err, nil)
+	assertStringLinesEq(t, string(b), crlfy(`BEGIN:VCARD
+VERSION:3.0
+FN:Alex
+N:Doe;Alex;;;
+BDAY:1996-04-15
+REV:2024-03-05T10:30:00Z
+END:VCARD
+`))
+
+	text := `BEGIN:VCARD
+VERSION:3.0
+FN:Alex
+N:Doe;Alex;;;
+BDAY;VALUE=date:1996-04-15
+ANNIVERSARY:20100621T140000+0200
+REV:2024-03-05T10:30:00.5Z
+END:VCARD
+`
+	decoded := Contact{}
+	err = Unmarshal([]byte(crlfy(text)), &decoded)
+	assertEq(t, err, nil)
+	assertEq(t, decoded.BDAY, c.BDAY)
+	assertEq(t, decoded.ANNIVERSARY.Equal(time.Date(2010, 6, 21, 12, 0, 0, 0, time.UTC)), true)
+	assertEq(t, decoded.REV, rev.Add(500*time.Millisecond))
+
+	text = `BEGIN:VCARD
+VERSION:4.0
+FN:Alex
+BDAY:--0415
+END:VCARD
+`
+	err = Unmarshal([]byte(crlfy(text)), &decoded)
+	assertErrIs(t, err, ErrParsing, `date "--0415" can't be represented as time.Time`)
+}
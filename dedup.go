@@ -0,0 +1,263 @@
+package vcard
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Group of cards which probably describe the same entity. See [FindDuplicates].
+type Duplicates struct {
+	// Indices of cards in the slice passed to [FindDuplicates] in ascending order.
+	Indices []int
+
+	// Confidence in range 0..1 that all cards of the group describe the same entity.
+	Confidence float64
+
+	// Signals which matched sorted alphabetically: "EMAIL", "NAME", "TEL" and "UID".
+	Reasons []string
+}
+
+// Confidence of a single matching signal. Signals of a pair of cards are combined
+// as 1 - (1-a)(1-b)..., so a matching phone and a similar name are stronger than each alone.
+const (
+	uidConfidence   = 1.0
+	emailConfidence = 0.9
+	telConfidence   = 0.75
+	nameConfidence  = 0.8 // multiplied by similarity of names
+)
+
+// Names less similar than this are not considered a match. See [nameSimilarity].
+const minNameSimilarity = 0.8
+
+// Clusters probable duplicates among cards by UID, email addresses compared case-insensitively,
+// telephone numbers compared by their last 10 digits and similarity of formatted names.
+//
+// Pairs of cards with confidence of at least threshold are linked and linked cards form a group,
+// so a group may contain cards which are linked only through other cards. Confidence of a group
+// is the confidence of its weakest link. Groups are ordered by their first index.
+//
+// Typical thresholds are 0.9 for automatic merging, which requires a matching UID, email or
+// phone with a similar name, and 0.7 for suggestions to review. See [Duplicates.Merge].
+func FindDuplicates(cards []Card, threshold float64) []Duplicates {
+	keys := make([]dedupKeys, len(cards))
+	buckets := map[string][]int{}
+	for i := range cards {
+		keys[i] = dedupKeysOf(&cards[i])
+		for _, k := range keys[i].buckets() {
+			buckets[k] = append(buckets[k], i)
+		}
+	}
+
+	type pair struct{ a, b int }
+	candidates := map[pair]bool{}
+	for _, bucket := range buckets {
+		for x := 0; x < len(bucket); x++ {
+			for y := x + 1; y < len(bucket); y++ {
+				if bucket[x] != bucket[y] {
+					candidates[pair{bucket[x], bucket[y]}] = true
+				}
+			}
+		}
+	}
+
+	// Union-find where every root keeps confidence and reasons of its group
+	parent := make([]int, len(cards))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	confidence := map[int]float64{}
+	reasons := map[int][]string{}
+
+	// Pairs are linked in a stable order, so confidence of groups does not depend on map iteration
+	sorted := slices.SortedFunc(maps.Keys(candidates), func(x, y pair) int {
+		if x.a != y.a {
+			return x.a - y.a
+		}
+		return x.b - y.b
+	})
+	for _, p := range sorted {
+		score, why := keys[p.a].match(keys[p.b])
+		if score < threshold || score == 0 {
+			continue
+		}
+		ra, rb := find(p.a), find(p.b)
+		if ra == rb {
+			reasons[ra] = mergeReasons(reasons[ra], why)
+			continue
+		}
+		c := score
+		if v, found := confidence[ra]; found {
+			c = min(c, v)
+		}
+		if v, found := confidence[rb]; found {
+			c = min(c, v)
+		}
+		root, child := min(ra, rb), max(ra, rb)
+		parent[child] = root
+		confidence[root] = c
+		reasons[root] = mergeReasons(mergeReasons(reasons[root], reasons[child]), why)
+		delete(confidence, child)
+		delete(reasons, child)
+	}
+
+	groups := []Duplicates{}
+	index := map[int]int{}
+	for i := range cards {
+		root := find(i)
+		if _, found := confidence[root]; !found {
+			continue
+		}
+		g, found := index[root]
+		if !found {
+			g = len(groups)
+			index[root] = g
+			groups = append(groups, Duplicates{Confidence: confidence[root], Reasons: reasons[root]})
+		}
+		groups[g].Indices = append(groups[g].Indices, i)
+	}
+	return groups
+}
+
+// Merges cards of the group one by one in order of indices. See [Merge].
+func (d Duplicates) Merge(cards []Card, policy MergePolicy) Card {
+	if len(d.Indices) == 0 {
+		return Card{}
+	}
+	merged := cards[d.Indices[0]]
+	for _, i := range d.Indices[1:] {
+		merged = Merge(merged, cards[i], policy)
+	}
+	return merged
+}
+
+func mergeReasons(a []string, b []string) []string {
+	reasons := slices.Concat(a, b)
+	slices.Sort(reasons)
+	return slices.Compact(reasons)
+}
+
+// Normalized values of a card compared by [FindDuplicates].
+type dedupKeys struct {
+	uid    string
+	emails []string
+	tels   []string
+	name   string
+}
+
+func dedupKeysOf(c *Card) dedupKeys {
+	k := dedupKeys{}
+	if uid, found := c.UID(); found {
+		k.uid = UID(uid).Key()
+	}
+	for _, v := range c.Values("EMAIL") {
+		if v = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(v, "mailto:"))); v != "" {
+			k.emails = append(k.emails, v)
+		}
+	}
+	for _, v := range c.Values("TEL") {
+		if d := telKey(v); len(d) >= 5 {
+			k.tels = append(k.tels, d)
+		}
+	}
+	name, _ := c.FN()
+	if strings.TrimSpace(name) == "" {
+		if n, found := c.Name(); found {
+			name = strings.Join(append(n.GivenNames, n.FamilyNames...), " ")
+		}
+	}
+	k.name = normalizeName(name)
+	return k
+}
+
+// Returns keys of buckets of cards which are compared to each other. Names are bucketed by their
+// words, so names with a typo in one word are still compared.
+func (k dedupKeys) buckets() []string {
+	buckets := []string{}
+	if k.uid != "" {
+		buckets = append(buckets, "UID:"+k.uid)
+	}
+	for _, e := range k.emails {
+		buckets = append(buckets, "EMAIL:"+e)
+	}
+	for _, t := range k.tels {
+		buckets = append(buckets, "TEL:"+t)
+	}
+	for _, word := range strings.Fields(k.name) {
+		buckets = append(buckets, "NAME:"+word)
+	}
+	return buckets
+}
+
+// Returns combined confidence of matching signals and their names.
+func (k dedupKeys) match(other dedupKeys) (float64, []string) {
+	miss := 1.0
+	reasons := []string{}
+	if k.uid != "" && k.uid == other.uid {
+		miss *= 1 - uidConfidence
+		reasons = append(reasons, "UID")
+	}
+	if slices.ContainsFunc(k.emails, func(e string) bool { return slices.Contains(other.emails, e) }) {
+		miss *= 1 - emailConfidence
+		reasons = append(reasons, "EMAIL")
+	}
+	if k.name != "" && other.name != "" {
+		if s := nameSimilarity(k.name, other.name); s >= minNameSimilarity {
+			miss *= 1 - nameConfidence*s
+			reasons = append(reasons, "NAME")
+		}
+	}
+	if slices.ContainsFunc(k.tels, func(t string) bool { return slices.Contains(other.tels, t) }) {
+		miss *= 1 - telConfidence
+		reasons = append(reasons, "TEL")
+	}
+	slices.Sort(reasons)
+	return 1 - miss, reasons
+}
+
+// Lower-cases a name, drops punctuation and sorts its words, so "Doe, John" equals "John Doe".
+func normalizeName(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	slices.Sort(words)
+	return strings.Join(words, " ")
+}
+
+// Returns similarity of normalized names in range 0..1 based on Levenshtein distance.
+func nameSimilarity(a string, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+func levenshtein(a []rune, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,53 @@
+package vcard
+
+import "testing"
+
+func TestFindDuplicates(t *testing.T) {
+
+	card := func(props ...string) Card {
+		c := Card{}
+		for i := 0; i < len(props); i += 2 {
+			c.Add(props[i], props[i+1])
+		}
+		return c
+	}
+	cards := []Card{
+		card("FN", "John Doe", "EMAIL", "john@example.com"),
+		card("FN", "Jane Roe", "TEL", "+1 555 010 0200"),
+		card("FN", "Doe, John", "EMAIL", "JOHN@example.com"),
+		card("FN", "Jane Roe", "TEL", "(555) 010-0200"),
+		card("FN", "Jon Doe", "UID", "urn:uuid:F81D4FAE-7DEC-11D0-A765-00A0C91E6BF6"),
+		card("FN", "Someone Else", "UID", "f81d4fae-7dec-11d0-a765-00a0c91e6bf6"),
+		card("FN", "Unrelated"),
+	}
+
+	groups := FindDuplicates(cards, 0.9)
+	assertEq(t, len(groups), 3)
+
+	assertSlicesEq(t, groups[0].Indices, []int{0, 2})
+	assertSlicesEq(t, groups[0].Reasons, []string{"EMAIL", "NAME"})
+	assertEq(t, groups[0].Confidence > 0.9, true)
+
+	assertSlicesEq(t, groups[1].Indices, []int{1, 3})
+	assertSlicesEq(t, groups[1].Reasons, []string{"NAME", "TEL"})
+
+	assertSlicesEq(t, groups[2].Indices, []int{4, 5})
+	assertSlicesEq(t, groups[2].Reasons, []string{"UID"})
+	assertEq(t, groups[2].Confidence, 1.0)
+
+	// "Jon Doe" is similar to "John Doe" but a name alone is not enough for 0.9
+	groups = FindDuplicates(cards, 0.5)
+	assertSlicesEq(t, groups[0].Indices, []int{0, 2, 4, 5})
+	assertEq(t, groups[0].Confidence < 0.9, true)
+
+	merged := groups[1].Merge(cards, MergePreferA)
+	assertSlicesEq(t, merged.Values("TEL"), []string{"+1 555 010 0200"})
+}
+
+func TestNameSimilarity(t *testing.T) {
+
+	assertEq(t, normalizeName("Doe, John"), "doe john")
+	assertEq(t, nameSimilarity("doe john", "doe john"), 1.0)
+	assertEq(t, nameSimilarity("doe jon", "doe john") > 0.8, true)
+	assertEq(t, nameSimilarity("doe john", "roe jane") < 0.8, true)
+}
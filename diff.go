@@ -0,0 +1,129 @@
+package vcard
+
+import (
+	"slices"
+	"strings"
+)
+
+// Kind of a [PropertyChange].
+type ChangeOp string
+
+const (
+	ChangeAdd    ChangeOp = "add"
+	ChangeRemove ChangeOp = "remove"
+)
+
+// Single property added to or removed from a card. Modified property is a removal of the old
+// property followed by an addition of the new one. See [Diff] and [Apply].
+//
+// Not to be confused with [Change] of a [Store] which is a change of an entire card.
+//
+// Changes are serialized either as JSON with exported fields or as text lines of
+// [PropertyChange.String] e.g. "+TEL;TYPE=cell:555" and "-EMAIL:old@example.com" parsed by [ParseChange].
+type PropertyChange struct {
+	Op       ChangeOp
+	Property Property
+}
+
+// Returns the change as a content line prefixed with "+" for additions and "-" for removals.
+func (c PropertyChange) String() string {
+	if c.Op == ChangeRemove {
+		return "-" + c.Property.String()
+	}
+	return "+" + c.Property.String()
+}
+
+// Parses a change from a line produced by [PropertyChange.String]. Returns [ErrParsing]
+// if the line does not start with "+" or "-" or is not a valid content line.
+func ParseChange(line string) (PropertyChange, error) {
+	line = strings.TrimRight(line, "\r\n")
+	if line == "" {
+		return PropertyChange{}, parsingErrf("change is empty")
+	}
+	c := PropertyChange{}
+	switch line[0] {
+	case '+':
+		c.Op = ChangeAdd
+	case '-':
+		c.Op = ChangeRemove
+	default:
+		return PropertyChange{}, parsingErrf("change %q has to start with '+' or '-'", line)
+	}
+	p, err := ParseProperty(line[1:])
+	if err != nil {
+		return PropertyChange{}, err
+	}
+	c.Property = p.clone()
+	return c, nil
+}
+
+// Returns minimal changes turning old into new. Properties are compared like [Equal] does,
+// so reordering properties, parameters or folding lines produces no changes. Removals go first.
+func Diff(old, new Card) []PropertyChange {
+	oldProps, newProps := canonicalIndex(old), canonicalIndex(new)
+
+	changes := []PropertyChange{}
+	for _, p := range old.props {
+		if !takeCanonical(newProps, p) {
+			changes = append(changes, PropertyChange{ChangeRemove, p.clone()})
+		}
+	}
+	for _, p := range new.props {
+		if !takeCanonical(oldProps, p) {
+			changes = append(changes, PropertyChange{ChangeAdd, p.clone()})
+		}
+	}
+	return changes
+}
+
+// Returns a copy of the card with changes applied in order. Removal deletes the first property
+// equal to the removed one as defined by [Equal] and is ignored if there is no such property.
+// A property added right after a removal of a property with the same name takes its position,
+// so modified properties keep their place. Other additions are appended.
+func Apply(card Card, changes []PropertyChange) Card {
+	props := card.Properties()
+	removedAt := -1
+	removedName := ""
+
+	for _, c := range changes {
+		switch c.Op {
+		case ChangeRemove:
+			removed := canonicalProperty(c.Property)
+			i := slices.IndexFunc(props, func(p Property) bool { return canonicalProperty(p) == removed })
+			removedAt, removedName = -1, ""
+			if i >= 0 {
+				props = slices.Delete(props, i, i+1)
+				removedAt, removedName = i, strings.ToUpper(c.Property.Name)
+			}
+		case ChangeAdd:
+			p := c.Property.clone()
+			p.Name = strings.ToUpper(p.Name)
+			if removedAt >= 0 && p.Name == removedName {
+				props = slices.Insert(props, removedAt, p)
+			} else {
+				props = append(props, p)
+			}
+			removedAt, removedName = -1, ""
+		}
+	}
+	return Card{props: props}
+}
+
+// Returns number of occurrences of canonical forms of properties of the card.
+func canonicalIndex(c Card) map[string]int {
+	index := map[string]int{}
+	for _, p := range c.props {
+		index[canonicalProperty(p)]++
+	}
+	return index
+}
+
+// Removes a single occurrence of p from index and reports whether it was there.
+func takeCanonical(index map[string]int, p Property) bool {
+	key := canonicalProperty(p)
+	if index[key] == 0 {
+		return false
+	}
+	index[key]--
+	return true
+}
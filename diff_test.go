@@ -0,0 +1,52 @@
+package vcard
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDiffApply(t *testing.T) {
+
+	old := Card{}
+	old.Add("VERSION", "4.0")
+	old.Add("FN", "Alex")
+	old.AddProperty(Property{Name: "TEL", Params: Params{{"TYPE", []string{"cell"}}}, Value: "555"})
+	old.Add("EMAIL", "alex@example.com")
+	old.Add("NOTE", "old")
+
+	new := Card{}
+	new.Add("VERSION", "4.0")
+	new.AddProperty(Property{Name: "TEL", Params: Params{{"type", []string{"CELL"}}}, Value: "555"})
+	new.Add("FN", "Alex")
+	new.Add("EMAIL", "alex@example.org")
+	new.Add("NOTE", "old")
+	new.Add("URL", "https://example.com")
+
+	changes := Diff(old, new)
+	lines := []string{}
+	for _, c := range changes {
+		lines = append(lines, c.String())
+	}
+	assertSlicesEq(t, lines, []string{"-EMAIL:alex@example.com", "+EMAIL:alex@example.org", "+URL:https://example.com"})
+
+	patched := Apply(old, changes)
+	assertEq(t, Equal(patched, new), true)
+	assertSlicesEq(t, patched.Values("EMAIL"), []string{"alex@example.org"})
+	assertEq(t, patched.props[3].Name, "EMAIL")
+	assertEq(t, len(Diff(new, new)), 0)
+
+	b, err := json.Marshal(changes)
+	assertEq(t, err, nil)
+	decoded := []PropertyChange{}
+	err = json.Unmarshal(b, &decoded)
+	assertEq(t, err, nil)
+	assertEq(t, Equal(Apply(old, decoded), new), true)
+
+	for i, line := range lines {
+		c, err := ParseChange(line)
+		assertEq(t, err, nil)
+		assertDeepEq(t, c, changes[i])
+	}
+	_, err = ParseChange("EMAIL:x")
+	assertErrIs(t, err, ErrParsing, "has to start with '+' or '-'")
+}
@@ -0,0 +1,84 @@
+package vcard
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+// Value of EMAIL property as per https://datatracker.ietf.org/doc/html/rfc6350#section-6.4.2
+//
+// Email implements [VCardFieldMarshaler] and [VCardFieldUnmarshaler], so it can be used as a type
+// of struct fields instead of string. See [Tel].
+type Email struct {
+	// Address e.g. "alex@example.com". "mailto:" prefix is removed during decoding.
+	Address string
+
+	// Values of TYPE parameter e.g. "work", "home" or "internet" of vCard 3.0.
+	Types []string
+
+	// Preference in range 1..100 where 1 is the most preferred. 0 means no preference.
+	Pref int
+}
+
+// Reports whether TYPE parameter of the address contains typ compared case-insensitively.
+func (e Email) HasType(typ string) bool {
+	return slices.ContainsFunc(e.Types, func(s string) bool { return strings.EqualFold(s, typ) })
+}
+
+// Performs basic syntactic validation of the address: it has to contain a single '@'
+// separating non-empty local part and domain, and no whitespace. Returns [*ValidationError].
+func (e Email) Validate() error {
+	err := validEmailAddress(e.Address)
+	if err != nil {
+		return &ValidationError{"EMAIL", e.Address, err}
+	}
+	return nil
+}
+
+func validEmailAddress(address string) error {
+	local, domain, found := strings.Cut(address, "@")
+	switch {
+	case !found:
+		return errors.New("email address has to contain '@'")
+	case local == "" || domain == "":
+		return errors.New("email address has to contain local part and domain")
+	case strings.Contains(domain, "@"):
+		return errors.New("email address has to contain a single '@'")
+	case strings.ContainsAny(address, " \t\r\n"):
+		return errors.New("email address can't contain whitespace")
+	case strings.HasPrefix(domain, ".") || strings.HasSuffix(domain, ".") || strings.Contains(domain, ".."):
+		return errors.New("domain of email address is malformed")
+	}
+	return nil
+}
+
+// Encodes the address e.g. ";TYPE=work;PREF=1:alex@example.com". Returns [*ValidationError]
+// if the address is not valid, see [Email.Validate].
+func (e Email) MarshalVCardField() ([]byte, error) {
+	err := e.Validate()
+	if err != nil {
+		return nil, err
+	}
+	b := appendParam([]byte{}, "TYPE", e.Types...)
+	b = appendPref(b, e.Pref)
+	b = append(b, ':')
+	return append(b, e.Address...), nil
+}
+
+// Decodes the address from ";TYPE=work:alex@example.com". The address is not validated,
+// so documents of producers with sloppy data can still be decoded. See [Email.Validate].
+func (e *Email) UnmarshalVCardField(data []byte) error {
+	params, value, _ := splitParamsValue(string(data))
+
+	if len(value) >= 7 && strings.EqualFold(value[:7], "mailto:") {
+		value = value[7:]
+	}
+	e.Address = value
+	e.Types = withoutValue(paramValues(params, "TYPE"), "pref")
+	if len(e.Types) == 0 {
+		e.Types = nil
+	}
+	e.Pref = paramPref(params)
+	return nil
+}
@@ -0,0 +1,57 @@
+package vcard
+
+import "testing"
+
+func TestEmailUnmarshal(t *testing.T) {
+
+	e := Email{}
+	err := e.UnmarshalVCardField([]byte(";TYPE=work,internet;PREF=1:alex@example.com"))
+
+	assertEq(t, err, nil)
+	assertEq(t, e.Address, "alex@example.com")
+	assertSlicesEq(t, e.Types, []string{"work", "internet"})
+	assertEq(t, e.Pref, 1)
+	assertEq(t, e.HasType("WORK"), true)
+
+	err = e.UnmarshalVCardField([]byte(";TYPE=INTERNET,PREF:MAILTO:alex@example.com"))
+
+	assertEq(t, err, nil)
+	assertEq(t, e.Address, "alex@example.com")
+	assertSlicesEq(t, e.Types, []string{"INTERNET"})
+	assertEq(t, e.Pref, 1)
+
+	// Invalid addresses are decoded as is
+	err = e.UnmarshalVCardField([]byte(":not an email"))
+
+	assertEq(t, err, nil)
+	assertEq(t, e.Address, "not an email")
+	assertErrIs(t, e.Validate(), ErrValidation, "has to contain '@'")
+}
+
+func TestEmailMarshal(t *testing.T) {
+
+	b, err := Email{Address: "alex@example.com", Types: []string{"home"}, Pref: 1}.MarshalVCardField()
+
+	assertEq(t, err, nil)
+	assertStringsEq(t, string(b), ";TYPE=home;PREF=1:alex@example.com")
+
+	_, err = Marshal(struct {
+		FN    string
+		EMAIL Email
+	}{"Alex", Email{Address: "alex@@example.com"}})
+
+	assertErrIs(t, err, ErrValidation, "single '@'")
+}
+
+func TestEmailValidate(t *testing.T) {
+
+	assertEq(t, Email{Address: "alex@example.com"}.Validate(), nil)
+	assertEq(t, Email{Address: "alex+tag@mail.example.com"}.Validate(), nil)
+
+	for _, address := range []string{"", "alex", "@example.com", "alex@", "alex @example.com", "alex@example..com", "alex@.example.com"} {
+		err := Email{Address: address}.Validate()
+		if err == nil {
+			t.Errorf("address %q is expected to be invalid", address)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package vcard
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Reports whether cards contain the same properties regardless of their order.
+//
+// Properties are compared by group and name case-insensitively, parameters regardless of their
+// order and case of names. Values of TYPE, VALUE, ENCODING and CHARSET parameters are compared
+// case-insensitively and values of TYPE regardless of their order, so TEL;CELL;PREF of vCard 2.1
+// equals TEL;TYPE=pref,cell. Values of properties are compared as is.
+func Equal(a, b Card) bool {
+	if len(a.props) != len(b.props) {
+		return false
+	}
+	return slices.Equal(canonicalProperties(a), canonicalProperties(b))
+}
+
+// Returns a hex-encoded SHA-256 hash of canonical forms of properties of the card, so cards
+// which are [Equal] have the same fingerprint regardless of the order of their properties.
+// Used as ETag by [CardDAVHandler].
+func (c *Card) Fingerprint() string {
+	h := sha256.New()
+	for _, p := range canonicalProperties(*c) {
+		h.Write([]byte(p))
+		h.Write([]byte{'\n'})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Returns sorted canonical forms of properties of the card. See [Equal].
+func canonicalProperties(c Card) []string {
+	props := make([]string, len(c.props))
+	for i, p := range c.props {
+		props[i] = canonicalProperty(p)
+	}
+	slices.Sort(props)
+	return props
+}
+
+// Parameters which values are case-insensitive.
+var caseInsensitiveParams = []string{"TYPE", "VALUE", "ENCODING", "CHARSET"}
+
+// Returns the property as a content line with a lower-cased group and parameters sorted by name.
+// Values of TYPE parameter are merged into a single sorted list.
+func canonicalProperty(p Property) string {
+	params := map[string][]string{}
+	for _, param := range p.Params {
+		name := strings.ToUpper(param.Name)
+		values := param.Values
+		if param.Values == nil {
+			name, values = "TYPE", []string{param.Name}
+		}
+		if slices.Contains(caseInsensitiveParams, name) {
+			values = slices.Clone(values)
+			for i, v := range values {
+				values[i] = strings.ToLower(v)
+			}
+		}
+		params[name] = append(params[name], values...)
+	}
+	slices.Sort(params["TYPE"])
+
+	canonical := Property{Group: strings.ToLower(p.Group), Name: strings.ToUpper(p.Name), Value: p.Value}
+	for _, name := range slices.Sorted(maps.Keys(params)) {
+		canonical.Params = append(canonical.Params, Param{name, params[name]})
+	}
+	return canonical.String()
+}
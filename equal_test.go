@@ -0,0 +1,53 @@
+package vcard
+
+import "testing"
+
+func TestEqual(t *testing.T) {
+
+	a := Card{}
+	err := Unmarshal([]byte(crlfy(`BEGIN:VCARD
+VERSION:2.1
+FN:Alex
+ITEM1.TEL;CELL;PREF:555
+EMAIL;TYPE=INTERNET;X-CUSTOM=A:alex@example.com
+END:VCARD
+`)), &a)
+	assertEq(t, err, nil)
+
+	b := Card{}
+	err = Unmarshal([]byte("BEGIN:VCARD\r\nVERSION:2.1\r\n"+
+		"email;x-custom=A;type=internet:alex@exam\r\n ple.com\r\n"+
+		"item1.tel;type=pref,cell:555\r\nFN:Alex\r\nEND:VCARD\r\n"), &b)
+	assertEq(t, err, nil)
+	assertEq(t, Equal(a, b), true)
+
+	c := Card{props: a.Properties()}
+	c.Set("EMAIL", "Alex@example.com")
+	assertEq(t, Equal(a, c), false)
+
+	c = Card{props: a.Properties()}
+	c.props[2].Params.Set("X-CUSTOM", "a")
+	assertEq(t, Equal(a, c), false)
+
+	c = Card{props: a.Properties()}
+	c.Add("NOTE", "")
+	assertEq(t, Equal(a, c), false)
+}
+
+func TestCardFingerprint(t *testing.T) {
+
+	a := Card{}
+	a.Add("VERSION", "4.0")
+	a.Add("FN", "Alex")
+	a.AddProperty(Property{Name: "TEL", Params: Params{{"TYPE", []string{"cell", "pref"}}}, Value: "555"})
+
+	b := Card{}
+	b.AddProperty(Property{Name: "tel", Params: Params{{"type", []string{"PREF"}}, {"TYPE", []string{"CELL"}}}, Value: "555"})
+	b.Add("FN", "Alex")
+	b.Add("VERSION", "4.0")
+	assertEq(t, a.Fingerprint(), b.Fingerprint())
+	assertEq(t, len(a.Fingerprint()), 64)
+
+	b.Set("FN", "Alex Smith")
+	assertEq(t, a.Fingerprint() == b.Fingerprint(), false)
+}
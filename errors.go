@@ -16,6 +16,10 @@ var ErrParsing = fmt.Errorf("%w: parsing error in Decoder", ErrVCard)
 // This could be the case when trying to decode a document of multiple vCards into a single struct or a map.
 var ErrLeftoverTokens = fmt.Errorf("%w: leftover tokens", ErrParsing)
 
+// Signifies a vCard document or a value is well-formed but breaks validation rules
+// e.g. [CardDAVProfile] or [ControlCharsReject].
+var ErrValidation = fmt.Errorf("%w: validation error", ErrVCard)
+
 func vCardErrf(format string, v ...any) error {
 	return fmt.Errorf("%w: %w", ErrVCard, fmt.Errorf(format, v...))
 }
@@ -27,3 +31,14 @@ func parsingErrf(format string, v ...any) error {
 func leftTokensErrf(format string, v ...any) error {
 	return fmt.Errorf("%w: %w", ErrLeftoverTokens, fmt.Errorf(format, v...))
 }
+
+func validationErrf(format string, v ...any) error {
+	return fmt.Errorf("%w: %w", ErrValidation, fmt.Errorf(format, v...))
+}
+
+// Signifies a card with requested UID does not exist in a [Store].
+var ErrNotFound = fmt.Errorf("%w: not found", ErrVCard)
+
+func notFoundErrf(format string, v ...any) error {
+	return fmt.Errorf("%w: %w", ErrNotFound, fmt.Errorf(format, v...))
+}
@@ -0,0 +1,92 @@
+package vcard
+
+// Names of widely used vendor extension properties. See [KnownExtensions].
+const (
+	XABLabel           = "X-ABLABEL"
+	XABDate            = "X-ABDATE"
+	XABRelatedNames    = "X-ABRELATEDNAMES"
+	XABUID             = "X-ABUID"
+	XAndroidCustom     = "X-ANDROID-CUSTOM"
+	XSocialProfile     = "X-SOCIALPROFILE"
+	XPhoneticFirst     = "X-PHONETIC-FIRST-NAME"
+	XPhoneticLast      = "X-PHONETIC-LAST-NAME"
+	XGender            = "X-GENDER"
+	XAnniversary       = "X-ANNIVERSARY"
+	XAddressBookKind   = "X-ADDRESSBOOKSERVER-KIND"
+	XAddressBookMember = "X-ADDRESSBOOKSERVER-MEMBER"
+	XAIM               = "X-AIM"
+	XICQ               = "X-ICQ"
+	XJabber            = "X-JABBER"
+	XMSN               = "X-MSN"
+	XSkype             = "X-SKYPE"
+	XTwitter           = "X-TWITTER"
+)
+
+// Vendor extension property which is not a part of vCard specification but is commonly
+// produced by popular applications.
+type Extension struct {
+	Name        string
+	Vendor      string
+	Description string
+}
+
+// Registry of well-known vendor extensions. See [Schema.WithExtensions] and [Decoder.SetExtensions].
+var KnownExtensions = []Extension{
+	{XABLabel, "Apple", "Custom label of a grouped property e.g. item1.X-ABLabel for item1.TEL."},
+	{XABDate, "Apple", "Custom date e.g. an anniversary labeled with X-ABLabel."},
+	{XABRelatedNames, "Apple", "Name of a related person e.g. a spouse labeled with X-ABLabel."},
+	{XABUID, "Apple", "Identifier of the vCard in Apple address book."},
+	{XAndroidCustom, "Google", "Android contact data rows without vCard equivalent e.g. nickname or relation."},
+	{XSocialProfile, "Apple", "Profile on a social network with TYPE parameter naming the network."},
+	{XPhoneticFirst, "Apple", "Pronunciation of the given name."},
+	{XPhoneticLast, "Apple", "Pronunciation of the family name."},
+	{XGender, "Various", "Gender in vCard 2.1 and 3.0, replaced by GENDER in 4.0."},
+	{XAnniversary, "Various", "Anniversary in vCard 2.1 and 3.0, replaced by ANNIVERSARY in 4.0."},
+	{XAddressBookKind, "Apple", "KIND in vCard 3.0 used for contact groups."},
+	{XAddressBookMember, "Apple", "MEMBER in vCard 3.0 used for contact groups."},
+	{XAIM, "Various", "AOL Instant Messenger handle, replaced by IMPP."},
+	{XICQ, "Various", "ICQ handle, replaced by IMPP."},
+	{XJabber, "Various", "Jabber (XMPP) handle, replaced by IMPP."},
+	{XMSN, "Various", "MSN Messenger handle, replaced by IMPP."},
+	{XSkype, "Various", "Skype handle, replaced by IMPP."},
+	{XTwitter, "Various", "Twitter handle."},
+}
+
+// Returns a copy of the schema with every property from [KnownExtensions] added as optional field.
+func (s Schema) WithExtensions() Schema {
+	// Extended schema is kept with the schema, so prepared schemas for it are cached as well
+	if s.derived == nil {
+		return s.extend()
+	}
+	d := s.derived
+	d.extendedOnce.Do(func() {
+		d.extended = s.extend()
+	})
+	return d.extended
+}
+
+func (s Schema) extend() Schema {
+	ext := s.clone()
+	for _, e := range KnownExtensions {
+		ext.fields[e.Name] = struct{}{}
+	}
+	return ext
+}
+
+// Toggles recognition of well-known vendor extensions from [KnownExtensions]. Disabled by default.
+//
+// When enabled, every schema of the Decoder accepts these extensions, so they are decoded
+// into maps and struct fields without listing them in custom schemas.
+func (d *Decoder) SetExtensions(enabled bool) *Decoder {
+	d.extensions = enabled
+	return d
+}
+
+// Returns schema used to decode a record of a version.
+func (d *Decoder) schemaFor(version string) (Schema, bool) {
+	s, found := d.schemas[version]
+	if found && d.extensions {
+		s = s.WithExtensions()
+	}
+	return s, found
+}
@@ -0,0 +1,45 @@
+package vcard
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestWithExtensions(t *testing.T) {
+
+	schema := SchemaV3.WithExtensions()
+
+	_, found := schema.fields[XABLabel]
+	assertEq(t, found, true)
+	_, found = SchemaV3.fields[XABLabel]
+	assertEq(t, found, false)
+
+	assertSlicesEq(t, schema.Required(), SchemaV3.Required())
+
+	// Extended schema is cached
+	assertEq(t, reflect.ValueOf(SchemaV3.WithExtensions().fields).Pointer(), reflect.ValueOf(schema.fields).Pointer())
+
+	// Schemas of the same version are extended separately
+	custom := SchemaV3.WithDefault("KIND", "individual").WithExtensions()
+	assertEq(t, custom.defaults["KIND"], "individual")
+	_, found = SchemaV3.WithExtensions().defaults["KIND"]
+	assertEq(t, found, false)
+}
+
+func TestDecoderExtensions(t *testing.T) {
+
+	text := `BEGIN:VCARD
+VERSION:3.0
+N:;Alex;;;
+FN:Alex
+X-SKYPE:alex.s
+X-UNKNOWN:skipped
+END:VCARD
+`
+	m := map[string]string{}
+	err := NewDecoder(bytes.NewReader([]byte(crlfy(text))), DefaultSchemas).SetExtensions(true).Decode(&m)
+
+	assertEq(t, err, nil)
+	assertMapsEq(t, m, map[string]string{"VERSION": ":3.0", "N": ":;Alex;;;", "FN": ":Alex", "X-SKYPE": ":alex.s"})
+}
@@ -0,0 +1,108 @@
+package vcard
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Condition on a card used by [Filter].
+type Predicate func(c *Card) bool
+
+// Returns cards matching every predicate in order of appearance. Cards are not copied.
+//
+//	withWork := vcard.Filter(cards, vcard.HasProperty("EMAIL"), vcard.PropertyContains("ORG", "Acme"))
+func Filter(cards []Card, preds ...Predicate) []Card {
+	matched := []Card{}
+	for i := range cards {
+		if And(preds...)(&cards[i]) {
+			matched = append(matched, cards[i])
+		}
+	}
+	return matched
+}
+
+// Matches cards matching every predicate. And without predicates matches every card.
+func And(preds ...Predicate) Predicate {
+	return func(c *Card) bool {
+		for _, p := range preds {
+			if !p(c) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Matches cards matching at least one predicate.
+func Or(preds ...Predicate) Predicate {
+	return func(c *Card) bool {
+		for _, p := range preds {
+			if p(c) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Matches cards not matching pred.
+func Not(pred Predicate) Predicate {
+	return func(c *Card) bool { return !pred(c) }
+}
+
+// Matches cards containing a property with the given name.
+func HasProperty(name string) Predicate {
+	name = strings.ToUpper(name)
+	return func(c *Card) bool { return c.count(name) > 0 }
+}
+
+// Matches cards with a property which unescaped value equals value compared case-insensitively.
+func PropertyEquals(name string, value string) Predicate {
+	return propertyMatches(name, func(v string) bool { return strings.EqualFold(v, value) })
+}
+
+// Matches cards with a property which unescaped value contains substr compared case-insensitively
+// e.g. PropertyContains("ORG", "acme") matches ORG:Acme Inc.;Sales.
+func PropertyContains(name string, substr string) Predicate {
+	substr = strings.ToLower(substr)
+	return propertyMatches(name, func(v string) bool { return strings.Contains(strings.ToLower(v), substr) })
+}
+
+// Matches cards with a property which unescaped value matches re.
+func PropertyMatches(name string, re *regexp.Regexp) Predicate {
+	return propertyMatches(name, re.MatchString)
+}
+
+// Matches cards with a property which TYPE parameter contains typ compared case-insensitively
+// e.g. PropertyHasType("TEL", "cell"). Nameless parameters of vCard 2.1 are types too.
+func PropertyHasType(name string, typ string) Predicate {
+	name = strings.ToUpper(name)
+	return func(c *Card) bool {
+		return slices.ContainsFunc(c.props, func(p Property) bool {
+			return p.Name == name && slices.ContainsFunc(p.Params.Values("TYPE"), func(t string) bool {
+				return strings.EqualFold(t, typ)
+			})
+		})
+	}
+}
+
+// Matches cards of the given KIND e.g. "group". Cards without KIND are individuals.
+func KindIs(kind string) Predicate {
+	return func(c *Card) bool {
+		k, found := c.Kind()
+		if !found {
+			k = "individual"
+		}
+		return strings.EqualFold(k, kind)
+	}
+}
+
+func propertyMatches(name string, match func(value string) bool) Predicate {
+	name = strings.ToUpper(name)
+	return func(c *Card) bool {
+		return slices.ContainsFunc(c.props, func(p Property) bool {
+			return p.Name == name && match(unescapeText(p.Value))
+		})
+	}
+}
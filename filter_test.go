@@ -0,0 +1,48 @@
+package vcard
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestFilter(t *testing.T) {
+
+	cards := []Card{}
+	err := Unmarshal([]byte(crlfy(`BEGIN:VCARD
+VERSION:4.0
+FN:Alex
+ORG:Acme\, Inc.;Sales
+EMAIL:alex@acme.example
+TEL;TYPE=cell:555
+END:VCARD
+BEGIN:VCARD
+VERSION:4.0
+FN:Bob
+ORG:Globex
+EMAIL:bob@globex.example
+END:VCARD
+BEGIN:VCARD
+VERSION:4.0
+KIND:group
+FN:Team
+END:VCARD
+`)), &cards)
+	assertEq(t, err, nil)
+
+	fns := func(cards []Card) []string {
+		names := []string{}
+		for _, c := range cards {
+			fn, _ := c.FN()
+			names = append(names, fn)
+		}
+		return names
+	}
+
+	assertSlicesEq(t, fns(Filter(cards, HasProperty("email"), PropertyContains("ORG", "acme,"))), []string{"Alex"})
+	assertSlicesEq(t, fns(Filter(cards, PropertyEquals("org", "GLOBEX"))), []string{"Bob"})
+	assertSlicesEq(t, fns(Filter(cards, PropertyHasType("TEL", "CELL"))), []string{"Alex"})
+	assertSlicesEq(t, fns(Filter(cards, PropertyMatches("EMAIL", regexp.MustCompile(`@globex\.`)))), []string{"Bob"})
+	assertSlicesEq(t, fns(Filter(cards, Or(KindIs("group"), PropertyEquals("FN", "Bob")))), []string{"Bob", "Team"})
+	assertSlicesEq(t, fns(Filter(cards, Not(HasProperty("ORG")))), []string{"Team"})
+	assertSlicesEq(t, fns(Filter(cards)), []string{"Alex", "Bob", "Team"})
+}
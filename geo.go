@@ -0,0 +1,68 @@
+package vcard
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Value of GEO property as per https://datatracker.ietf.org/doc/html/rfc6350#section-6.5.2
+//
+// Geo is encoded as a geo: URI e.g. "geo:37.386013,-122.082932" in vCard 4.0, "37.386013;-122.082932"
+// in vCard 3.0 and "37.386013,-122.082932" in vCard 2.1. All forms are accepted during decoding.
+type Geo struct {
+	Lat float64
+	Lon float64
+}
+
+// Encodes the position as a geo: URI of vCard 4.0.
+func (g Geo) MarshalVCardField() ([]byte, error) {
+	return g.MarshalVCardFieldVersion("4.0")
+}
+
+// Encodes the position in a form defined by the vCard version.
+func (g Geo) MarshalVCardFieldVersion(version string) ([]byte, error) {
+	if g.Lat < -90 || g.Lat > 90 || g.Lon < -180 || g.Lon > 180 {
+		return nil, validationErrf("position %v,%v is out of range", g.Lat, g.Lon)
+	}
+	lat := strconv.FormatFloat(g.Lat, 'f', -1, 64)
+	lon := strconv.FormatFloat(g.Lon, 'f', -1, 64)
+
+	switch version {
+	case "2.1":
+		return []byte(":" + lat + "," + lon), nil
+	case "3.0":
+		return []byte(":" + lat + ";" + lon), nil
+	}
+	return []byte(":geo:" + lat + "," + lon), nil
+}
+
+// Decodes the position from "geo:lat,lon", "lat;lon" or "lat,lon". Parameters of a geo: URI
+// like ";u=35" are ignored.
+func (g *Geo) UnmarshalVCardField(data []byte) error {
+	_, value, _ := splitParamsValue(string(data))
+
+	s := value
+	if len(s) >= 4 && strings.EqualFold(s[:4], "geo:") {
+		s, _, _ = strings.Cut(s[4:], ";")
+	}
+	lat, lon, found := strings.Cut(s, ",")
+	if !found {
+		lat, lon, found = strings.Cut(s, ";")
+	}
+	if !found {
+		return parsingErrf("unable to parse GEO %q", value)
+	}
+	// geo: URIs may contain altitude e.g. geo:37.786971,-122.399677,120
+	lon, _, _ = strings.Cut(lon, ",")
+
+	var err error
+	g.Lat, err = strconv.ParseFloat(strings.TrimSpace(lat), 64)
+	if err != nil {
+		return parsingErrf("unable to parse latitude of GEO %q: %w", value, err)
+	}
+	g.Lon, err = strconv.ParseFloat(strings.TrimSpace(lon), 64)
+	if err != nil {
+		return parsingErrf("unable to parse longitude of GEO %q: %w", value, err)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package vcard
+
+import "testing"
+
+func TestGeoUnmarshal(t *testing.T) {
+
+	cases := map[string]Geo{
+		":geo:37.386013,-122.082932":       {37.386013, -122.082932},
+		";VALUE=uri:geo:37.78,-122.39,120": {37.78, -122.39},
+		":geo:37.78,-122.39;u=35":          {37.78, -122.39},
+		":37.386013;-122.082932":           {37.386013, -122.082932},
+		":37.24,-17.87":                    {37.24, -17.87},
+	}
+	for data, exp := range cases {
+		g := Geo{}
+		err := g.UnmarshalVCardField([]byte(data))
+
+		assertEq(t, err, nil)
+		assertEq(t, g, exp)
+	}
+
+	g := Geo{}
+	assertErrIs(t, g.UnmarshalVCardField([]byte(":somewhere")), ErrParsing, `unable to parse GEO "somewhere"`)
+	assertErrIs(t, g.UnmarshalVCardField([]byte(":geo:north,1")), ErrParsing, "unable to parse latitude")
+}
+
+func TestGeoMarshal(t *testing.T) {
+
+	g := Geo{37.386013, -122.082932}
+
+	b, err := g.MarshalVCardField()
+	assertEq(t, err, nil)
+	assertStringsEq(t, string(b), ":geo:37.386013,-122.082932")
+
+	b, err = g.MarshalVCardFieldVersion("3.0")
+	assertEq(t, err, nil)
+	assertStringsEq(t, string(b), ":37.386013;-122.082932")
+
+	b, err = g.MarshalVCardFieldVersion("2.1")
+	assertEq(t, err, nil)
+	assertStringsEq(t, string(b), ":37.386013,-122.082932")
+
+	_, err = Geo{91, 0}.MarshalVCardField()
+	assertErrIs(t, err, ErrValidation, "out of range")
+}
+
+type GeoStruct struct {
+	FN  string
+	N   string
+	GEO *Geo
+}
+
+func TestGeoField(t *testing.T) {
+
+	v := GeoStruct{FN: "Alex", GEO: &Geo{1.5, -2}}
+
+	b, err := MarshalSchema(v, SchemaV3)
+
+	exp := `BEGIN:VCARD
+VERSION:3.0
+FN:Alex
+N:
+GEO:1.5;-2
+END:VCARD
+`
+	assertEq(t, err, nil)
+	assertStringsEq(t, string(b), crlfy(exp))
+
+	s := GeoStruct{}
+	err = Unmarshal(b, &s)
+
+	assertEq(t, err, nil)
+	assertEq(t, *s.GEO, Geo{1.5, -2})
+
+	b, err = Marshal(v)
+
+	assertEq(t, err, nil)
+	assertStringLinesEq(t, string(b), crlfy("BEGIN:VCARD\nVERSION:4.0\nFN:Alex\nN:\nGEO:geo:1.5,-2\nEND:VCARD\n"))
+}
@@ -0,0 +1,347 @@
+package vcard
+
+import (
+	"cmp"
+	"encoding/csv"
+	"errors"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Reads contacts exported from Google Contacts as "Google CSV". Both the current columns
+// ("First Name", "E-mail 1 - Label") and the legacy ones ("Given Name", "E-mail 1 - Type") are accepted.
+//
+//   - Values of a cell separated by " ::: " become several values with the same label e.g. two emails.
+//   - Labels of emails, phones and addresses become types e.g. "Mobile" becomes "cell" and
+//     "Work Fax" becomes "work" and "fax". A label starting with "* " marks the primary value.
+//   - "Labels" or "Group Membership" become Categories except system groups like "* myContacts".
+//   - "File As" becomes SORT-AS of the name and phonetic names become X-PHONETIC-* properties of Extra.
+//   - FormattedName is "Name" of the legacy format or is made of the name or the organization.
+//
+// Use [Contact.Card] to get cards of a version. Unknown columns are ignored. Returns [ErrParsing]
+// if the CSV is malformed.
+func ReadGoogleCSV(r io.Reader) ([]Contact, error) {
+	t, err := readCSVTable(r)
+	if err != nil {
+		return nil, err
+	}
+
+	contacts := []Contact{}
+	for _, row := range t.rows {
+		get := func(names ...string) string { return t.get(row, names...) }
+		c := Contact{
+			Name: Name{
+				GivenNames:        nonEmpty(get("First Name", "Given Name")),
+				AdditionalNames:   nonEmpty(get("Middle Name", "Additional Name")),
+				FamilyNames:       nonEmpty(get("Last Name", "Family Name")),
+				HonorificPrefixes: nonEmpty(get("Name Prefix")),
+				HonorificSuffixes: nonEmpty(get("Name Suffix")),
+				SortAs:            nonEmpty(get("File As")),
+			},
+			Nicknames: splitGoogleValues(get("Nickname")),
+			Org: Org{
+				Name:  get("Organization Name", "Organization 1 - Name"),
+				Units: nonEmpty(get("Organization Department", "Organization 1 - Department")),
+			},
+			Title: get("Organization Title", "Organization 1 - Title"),
+			Notes: nonEmpty(get("Notes")),
+			Photo: Photo{URI: get("Photo")},
+		}
+		if d, err := ParseDate(get("Birthday")); err == nil {
+			c.Birthday = d
+		}
+		for _, label := range splitGoogleValues(get("Labels", "Group Membership")) {
+			if !strings.HasPrefix(label, "* ") {
+				c.Categories = append(c.Categories, label)
+			}
+		}
+		for _, phonetic := range [][3]string{
+			{"X-PHONETIC-FIRST-NAME", "Phonetic First Name", "Given Name Yomi"},
+			{"X-PHONETIC-MIDDLE-NAME", "Phonetic Middle Name", "Additional Name Yomi"},
+			{"X-PHONETIC-LAST-NAME", "Phonetic Last Name", "Family Name Yomi"},
+		} {
+			if v := get(phonetic[1], phonetic[2]); v != "" {
+				c.Extra = append(c.Extra, Property{Name: phonetic[0], Value: escapeText(v)})
+			}
+		}
+
+		for i := 1; i <= t.maxIndex; i++ {
+			prefix := func(kind string) string { return kind + " " + strconv.Itoa(i) + " - " }
+			label := func(kind string) string { return get(prefix(kind)+"Label", prefix(kind)+"Type") }
+
+			types, pref := googleLabelTypes(label("E-mail"))
+			for _, v := range splitGoogleValues(get(prefix("E-mail") + "Value")) {
+				c.Emails = append(c.Emails, Email{Address: v, Types: types, Pref: pref})
+			}
+			types, pref = googleLabelTypes(label("Phone"))
+			for _, v := range splitGoogleValues(get(prefix("Phone") + "Value")) {
+				c.Phones = append(c.Phones, Tel{Number: v, Types: types, Pref: pref})
+			}
+			for _, v := range splitGoogleValues(get(prefix("Website") + "Value")) {
+				c.URLs = append(c.URLs, v)
+			}
+
+			a := Adr{
+				POBox:      get(prefix("Address") + "PO Box"),
+				Extended:   get(prefix("Address") + "Extended Address"),
+				Street:     get(prefix("Address") + "Street"),
+				Locality:   get(prefix("Address") + "City"),
+				Region:     get(prefix("Address") + "Region"),
+				PostalCode: get(prefix("Address") + "Postal Code"),
+				Country:    get(prefix("Address") + "Country"),
+				Label:      get(prefix("Address") + "Formatted"),
+			}
+			if a.String() != "" || a.Label != "" {
+				if a.Label == a.String() {
+					a.Label = ""
+				}
+				a.Types, a.Pref = googleLabelTypes(label("Address"))
+				c.Addresses = append(c.Addresses, a)
+			}
+		}
+
+		c.FormattedName = get("Name")
+		if c.FormattedName == "" {
+			c.FormattedName = displayName(c.Name, c.Org)
+		}
+		contacts = append(contacts, c)
+	}
+	return contacts, nil
+}
+
+// Writes contacts in the current format of Google Contacts. See [ReadGoogleCSV] for the mapping.
+// FormattedName of a contact without a name becomes "First Name" unless it is the organization.
+// Use [ContactOf] to write cards.
+//
+// Every contact has as many "E-mail N", "Phone N", "Address N" and "Website N" columns as the contact
+// with the most values needs. Contacts are added to "* myContacts" as Google Contacts does.
+func WriteGoogleCSV(w io.Writer, contacts []Contact) error {
+	emails, phones, addresses, urls := 0, 0, 0, 0
+	for _, c := range contacts {
+		emails, phones = max(emails, len(c.Emails)), max(phones, len(c.Phones))
+		addresses, urls = max(addresses, len(c.Addresses)), max(urls, len(c.URLs))
+	}
+
+	header := []string{
+		"First Name", "Middle Name", "Last Name", "Phonetic First Name", "Phonetic Middle Name", "Phonetic Last Name",
+		"Name Prefix", "Name Suffix", "Nickname", "File As", "Organization Name", "Organization Title",
+		"Organization Department", "Birthday", "Notes", "Photo", "Labels",
+	}
+	indexed := func(kind string, n int, columns ...string) {
+		for i := 1; i <= n; i++ {
+			for _, col := range columns {
+				header = append(header, kind+" "+strconv.Itoa(i)+" - "+col)
+			}
+		}
+	}
+	indexed("E-mail", emails, "Label", "Value")
+	indexed("Phone", phones, "Label", "Value")
+	indexed("Address", addresses, "Label", "Formatted", "Street", "City", "PO Box", "Region", "Postal Code", "Country", "Extended Address")
+	indexed("Website", urls, "Label", "Value")
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return vCardErrf("unable to write: %w", err)
+	}
+	for _, c := range contacts {
+		extra := func(name string) string {
+			i := slices.IndexFunc(c.Extra, func(p Property) bool { return p.Name == name })
+			if i < 0 {
+				return ""
+			}
+			return unescapeText(c.Extra[i].Value)
+		}
+		birthday := ""
+		if c.Birthday != (Date{}) {
+			birthday = c.Birthday.Text
+			if birthday == "" {
+				birthday = c.Birthday.format(true)
+			}
+		}
+
+		// Google Contacts shows the organization of contacts without a name, other contacts
+		// without a name keep FormattedName as the first name
+		firstName := strings.Join(c.Name.GivenNames, " ")
+		if displayName(c.Name, Org{}) == "" && displayName(c.Name, c.Org) != strings.TrimSpace(c.FormattedName) {
+			firstName = c.FormattedName
+		}
+
+		row := []string{
+			firstName, strings.Join(c.Name.AdditionalNames, " "), strings.Join(c.Name.FamilyNames, " "),
+			extra("X-PHONETIC-FIRST-NAME"), extra("X-PHONETIC-MIDDLE-NAME"), extra("X-PHONETIC-LAST-NAME"),
+			strings.Join(c.Name.HonorificPrefixes, " "), strings.Join(c.Name.HonorificSuffixes, " "),
+			strings.Join(c.Nicknames, googleSeparator), strings.Join(c.Name.SortAs, " "),
+			c.Org.Name, c.Title, strings.Join(c.Org.Units, " "), birthday,
+			strings.Join(c.Notes, "\n"), c.Photo.URI, strings.Join(append([]string{"* myContacts"}, c.Categories...), googleSeparator),
+		}
+		for i := range emails {
+			if i < len(c.Emails) {
+				row = append(row, googleLabel(c.Emails[i].Types, c.Emails[i].Pref), c.Emails[i].Address)
+			} else {
+				row = append(row, "", "")
+			}
+		}
+		for i := range phones {
+			if i < len(c.Phones) {
+				row = append(row, googleLabel(c.Phones[i].Types, c.Phones[i].Pref), c.Phones[i].Number)
+			} else {
+				row = append(row, "", "")
+			}
+		}
+		for i := range addresses {
+			if i < len(c.Addresses) {
+				a := c.Addresses[i]
+				formatted := a.Label
+				if formatted == "" {
+					formatted = a.String()
+				}
+				row = append(row, googleLabel(a.Types, a.Pref), formatted, a.Street, a.Locality, a.POBox, a.Region, a.PostalCode, a.Country, a.Extended)
+			} else {
+				row = append(row, make([]string, 9)...)
+			}
+		}
+		for i := range urls {
+			if i < len(c.URLs) {
+				row = append(row, "", c.URLs[i])
+			} else {
+				row = append(row, "", "")
+			}
+		}
+		if err := cw.Write(row); err != nil {
+			return vCardErrf("unable to write: %w", err)
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return vCardErrf("unable to write: %w", err)
+	}
+	return nil
+}
+
+// Separator of several values in a single cell of Google CSV.
+const googleSeparator = " ::: "
+
+func splitGoogleValues(s string) []string {
+	values := []string{}
+	for _, v := range strings.Split(s, ":::") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// Labels of Google Contacts which don't match a type of vCard.
+var googleLabels = map[string][]string{
+	"mobile":   {"cell"},
+	"work fax": {"work", "fax"},
+	"home fax": {"home", "fax"},
+	"other":    nil,
+}
+
+// Converts a label e.g. "* Work Fax" to types e.g. ["work", "fax"] and preference.
+func googleLabelTypes(label string) (types []string, pref int) {
+	label, primary := strings.CutPrefix(strings.TrimSpace(label), "* ")
+	if primary {
+		pref = 1
+	}
+	label = strings.ToLower(strings.TrimSpace(label))
+	if label == "" {
+		return nil, pref
+	}
+	if types, found := googleLabels[label]; found {
+		return types, pref
+	}
+	return []string{label}, pref
+}
+
+// Converts types to a label of Google Contacts e.g. ["work", "fax"] to "Work Fax".
+func googleLabel(types []string, pref int) string {
+	lower := make([]string, 0, len(types))
+	for _, t := range types {
+		if t = strings.ToLower(t); t != "pref" && t != "internet" && t != "voice" {
+			lower = append(lower, t)
+		}
+	}
+	label := ""
+	for l, ts := range googleLabels {
+		if ts != nil && slices.Equal(slices.Sorted(slices.Values(ts)), slices.Sorted(slices.Values(lower))) {
+			label = l
+		}
+	}
+	if label == "" {
+		label = strings.Join(lower, " ")
+	}
+	words := strings.Fields(label)
+	for i, w := range words {
+		words[i] = strings.ToUpper(w[:1]) + w[1:]
+	}
+	label = strings.Join(words, " ")
+	if pref == 1 {
+		return "* " + cmp.Or(label, "Other")
+	}
+	return label
+}
+
+// Rows of a CSV file with a header.
+type csvTable struct {
+	columns map[string]int
+	rows    [][]string
+
+	// The greatest N of columns like "E-mail N - Value".
+	maxIndex int
+}
+
+func readCSVTable(r io.Reader) (csvTable, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = true
+	records, err := cr.ReadAll()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return csvTable{}, parsingErrf("malformed CSV: %w", err)
+		}
+		return csvTable{}, vCardErrf("unable to read: %w", err)
+	}
+	if len(records) == 0 {
+		return csvTable{}, parsingErrf("CSV does not contain a header")
+	}
+
+	t := csvTable{columns: map[string]int{}, rows: records[1:]}
+	for i, name := range records[0] {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if _, dup := t.columns[name]; !dup {
+			t.columns[name] = i
+		}
+		if left, _, found := strings.Cut(name, " - "); found {
+			if n, err := strconv.Atoi(left[strings.LastIndexByte(left, ' ')+1:]); err == nil {
+				t.maxIndex = max(t.maxIndex, n)
+			}
+		}
+	}
+	return t, nil
+}
+
+// Returns trimmed value of the first of columns present in the header.
+func (t csvTable) get(row []string, columns ...string) string {
+	for _, col := range columns {
+		if i, found := t.columns[strings.ToLower(col)]; found {
+			if i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+	}
+	return ""
+}
+
+// Returns a slice with s or nil if s is empty.
+func nonEmpty(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return []string{s}
+}
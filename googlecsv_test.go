@@ -0,0 +1,122 @@
+package vcard
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestReadGoogleCSV(t *testing.T) {
+
+	doc := "\ufeffFirst Name,Middle Name,Last Name,Phonetic First Name,Name Prefix,Nickname,File As,Organization Name,Organization Title,Organization Department,Birthday,Notes,Labels,E-mail 1 - Label,E-mail 1 - Value,E-mail 2 - Label,E-mail 2 - Value,Phone 1 - Label,Phone 1 - Value,Phone 2 - Label,Phone 2 - Value,Address 1 - Label,Address 1 - Formatted,Address 1 - Street,Address 1 - City,Address 1 - Country,Website 1 - Label,Website 1 - Value\n" +
+		`Alex,Q,Smith,Aleks,Dr.,Al,Smith Alex,Acme,Engineer,R&D,1990-01-02,"Line 1` + "\n" + `Line 2",* myContacts ::: Friends ::: * starred,* Work,alex@acme.example ::: a@acme.example,Home,alex@home.example,Mobile,+1 555 0100,Work Fax,+1 555 0101,Home,"1 Main St` + "\n" + `Town",1 Main St,Town,USA,,https://alex.example` + "\n" +
+		`,,,,,,,Acme,,,--04-15,,,,,,,Main,555,,,,,,,,,,` + "\n"
+
+	contacts, err := ReadGoogleCSV(strings.NewReader(doc))
+	assertEq(t, err, nil)
+	assertEq(t, len(contacts), 2)
+
+	c := contacts[0]
+	assertEq(t, c.FormattedName, "Dr. Alex Q Smith")
+	assertDeepEq(t, c.Name, Name{FamilyNames: []string{"Smith"}, GivenNames: []string{"Alex"}, AdditionalNames: []string{"Q"}, HonorificPrefixes: []string{"Dr."}, SortAs: []string{"Smith Alex"}})
+	assertSlicesEq(t, c.Nicknames, []string{"Al"})
+	assertDeepEq(t, c.Org, Org{Name: "Acme", Units: []string{"R&D"}})
+	assertEq(t, c.Title, "Engineer")
+	assertEq(t, c.Birthday, Date{Year: 1990, Month: 1, Day: 2})
+	assertSlicesEq(t, c.Notes, []string{"Line 1\nLine 2"})
+	assertSlicesEq(t, c.Categories, []string{"Friends"})
+	assertDeepEq(t, c.Emails, []Email{
+		{Address: "alex@acme.example", Types: []string{"work"}, Pref: 1},
+		{Address: "a@acme.example", Types: []string{"work"}, Pref: 1},
+		{Address: "alex@home.example", Types: []string{"home"}},
+	})
+	assertDeepEq(t, c.Phones, []Tel{{Number: "+1 555 0100", Types: []string{"cell"}}, {Number: "+1 555 0101", Types: []string{"work", "fax"}}})
+	assertDeepEq(t, c.Addresses, []Adr{{Street: "1 Main St", Locality: "Town", Country: "USA", Types: []string{"home"}, Label: "1 Main St\nTown"}})
+	assertSlicesEq(t, c.URLs, []string{"https://alex.example"})
+	assertDeepEq(t, c.Extra, []Property{{Name: "X-PHONETIC-FIRST-NAME", Value: "Aleks"}})
+
+	c = contacts[1]
+	assertEq(t, c.FormattedName, "Acme")
+	assertEq(t, c.Birthday, Date{Month: 4, Day: 15})
+	assertDeepEq(t, c.Phones, []Tel{{Number: "555", Types: []string{"main"}}})
+
+	card, err := contacts[0].Card("4.0")
+	assertEq(t, err, nil)
+	fn, _ := card.FN()
+	assertEq(t, fn, "Dr. Alex Q Smith")
+}
+
+func TestReadGoogleCSVLegacy(t *testing.T) {
+
+	doc := "Name,Given Name,Family Name,Group Membership,E-mail 1 - Type,E-mail 1 - Value,Organization 1 - Name\n" +
+		"John Doe,John,Doe,* myContacts,* Other,john@example.com,Acme\n"
+
+	contacts, err := ReadGoogleCSV(strings.NewReader(doc))
+	assertEq(t, err, nil)
+	assertEq(t, len(contacts), 1)
+	assertEq(t, contacts[0].FormattedName, "John Doe")
+	assertEq(t, contacts[0].Org.Name, "Acme")
+	assertDeepEq(t, contacts[0].Emails, []Email{{Address: "john@example.com", Pref: 1}})
+	assertEq(t, len(contacts[0].Categories), 0)
+
+	_, err = ReadGoogleCSV(strings.NewReader(""))
+	assertErrIs(t, err, ErrParsing, "CSV does not contain a header")
+}
+
+func TestWriteGoogleCSV(t *testing.T) {
+
+	contacts := []Contact{
+		{
+			FormattedName: "Alex Smith",
+			Name:          Name{FamilyNames: []string{"Smith"}, GivenNames: []string{"Alex"}},
+			Emails:        []Email{{Address: "alex@example.com", Types: []string{"work", "internet"}, Pref: 1}},
+			Phones:        []Tel{{Number: "555", Types: []string{"cell"}}, {Number: "556", Types: []string{"fax", "home"}}},
+			Addresses:     []Adr{{Street: "1 Main St", Locality: "Town"}},
+			Birthday:      Date{Year: 1990, Month: 1, Day: 2},
+			Categories:    []string{"Friends"},
+			Extra:         []Property{{Name: "X-PHONETIC-LAST-NAME", Value: "Smit"}},
+		},
+		{FormattedName: "Acme", Org: Org{Name: "Acme"}, URLs: []string{"https://acme.example"}},
+	}
+
+	b := bytes.Buffer{}
+	err := WriteGoogleCSV(&b, contacts)
+	assertEq(t, err, nil)
+	assertStringLinesEq(t, b.String(), strings.Join([]string{
+		"First Name,Middle Name,Last Name,Phonetic First Name,Phonetic Middle Name,Phonetic Last Name,Name Prefix,Name Suffix,Nickname,File As,Organization Name,Organization Title,Organization Department,Birthday,Notes,Photo,Labels," +
+			"E-mail 1 - Label,E-mail 1 - Value,Phone 1 - Label,Phone 1 - Value,Phone 2 - Label,Phone 2 - Value," +
+			"Address 1 - Label,Address 1 - Formatted,Address 1 - Street,Address 1 - City,Address 1 - PO Box,Address 1 - Region,Address 1 - Postal Code,Address 1 - Country,Address 1 - Extended Address," +
+			"Website 1 - Label,Website 1 - Value",
+		`Alex,,Smith,,,Smit,,,,,,,,1990-01-02,,,* myContacts ::: Friends,* Work,alex@example.com,Mobile,555,Home Fax,556,,"1 Main St, Town",1 Main St,Town,,,,,,,`,
+		`,,,,,,,,,,Acme,,,,,,* myContacts,,,,,,,,,,,,,,,,,https://acme.example`,
+		"",
+	}, "\n"))
+
+	again, err := ReadGoogleCSV(&b)
+	assertEq(t, err, nil)
+	assertEq(t, len(again), 2)
+	assertDeepEq(t, again[0].Phones, []Tel{{Number: "555", Types: []string{"cell"}}, {Number: "556", Types: []string{"home", "fax"}}})
+	assertDeepEq(t, again[0].Addresses, contacts[0].Addresses)
+	assertDeepEq(t, again[0].Emails, []Email{{Address: "alex@example.com", Types: []string{"work"}, Pref: 1}})
+	assertEq(t, again[1].FormattedName, "Acme")
+}
+
+func TestWriteGoogleCSVFormattedNameOnly(t *testing.T) {
+
+	card := Card{}
+	err := Unmarshal([]byte(crlfy("BEGIN:VCARD\nVERSION:4.0\nFN:Alex Smith\nN:;;;;\nORG:Acme\nEND:VCARD\n")), &card)
+	assertEq(t, err, nil)
+
+	b := bytes.Buffer{}
+	err = WriteGoogleCSV(&b, []Contact{ContactOf(card)})
+	assertEq(t, err, nil)
+
+	again, err := ReadGoogleCSV(&b)
+	assertEq(t, err, nil)
+	assertEq(t, len(again), 1)
+	assertEq(t, again[0].FormattedName, "Alex Smith")
+	assertEq(t, again[0].Org.Name, "Acme")
+
+	_, err = again[0].Card("4.0")
+	assertEq(t, err, nil)
+}
@@ -0,0 +1,184 @@
+package vcard
+
+import "slices"
+
+// Directed graph of cards connected by RELATED properties e.g. an org chart or a household:
+//
+//	g := vcard.NewRelationGraph(cards)
+//	for _, r := range g.Relations(&cards[0]) {
+//		if r.To != nil && r.Related.HasType(vcard.RelatedSpouse) { ... }
+//	}
+//	household := g.Components(vcard.RelatedSpouse, vcard.RelatedChild, vcard.RelatedCoResident)
+//
+// Nodes are pointers to the cards the graph was built from and edges are matched by UID like
+// [Group.Resolve] does.
+type RelationGraph struct {
+	cards []Card
+	nodes map[*Card]int
+	out   [][]Relation
+	in    [][]Relation
+}
+
+// Edge of [RelationGraph] created from a RELATED property of From.
+type Relation struct {
+	From *Card
+
+	// Card referenced by the property or nil if it is not among the cards of the graph or
+	// the relation is a free form text.
+	To *Card
+
+	Related Related
+}
+
+// Builds a graph of relations between cards. Invalid RELATED properties are skipped.
+func NewRelationGraph(cards []Card) *RelationGraph {
+	g := &RelationGraph{
+		cards: cards,
+		nodes: make(map[*Card]int, len(cards)),
+		out:   make([][]Relation, len(cards)),
+		in:    make([][]Relation, len(cards)),
+	}
+	byUID := make(map[string]int, len(cards))
+	for i := range cards {
+		g.nodes[&cards[i]] = i
+		if uid, found := cards[i].UID(); found {
+			if _, dup := byUID[normalizeUID(uid)]; !dup {
+				byUID[normalizeUID(uid)] = i
+			}
+		}
+	}
+
+	for i := range cards {
+		for _, p := range topLevelProperties(cards[i].props) {
+			if p.Name != "RELATED" {
+				continue
+			}
+			r := Relation{From: &cards[i]}
+			if err := r.Related.UnmarshalVCardField([]byte(p.rest())); err != nil {
+				continue
+			}
+			j, found := byUID[normalizeUID(r.Related.URI)]
+			if r.Related.URI != "" && found {
+				r.To = &cards[j]
+				g.in[j] = append(g.in[j], r)
+			}
+			g.out[i] = append(g.out[i], r)
+		}
+	}
+	return g
+}
+
+// Returns a card with the given UID. See [Group.Resolve] for how UIDs are compared.
+func (g *RelationGraph) Node(uid string) (*Card, bool) {
+	for i := range g.cards {
+		if u, found := g.cards[i].UID(); found && normalizeUID(u) == normalizeUID(uid) {
+			return &g.cards[i], true
+		}
+	}
+	return nil, false
+}
+
+// Returns relations of the card in order of its RELATED properties, including ones which refer
+// to unknown cards. Returns nil if the card is not a node of the graph.
+func (g *RelationGraph) Relations(c *Card) []Relation {
+	i, found := g.nodes[c]
+	if !found {
+		return nil
+	}
+	return g.out[i]
+}
+
+// Returns relations of other cards referring to the card.
+func (g *RelationGraph) RelatedTo(c *Card) []Relation {
+	i, found := g.nodes[c]
+	if !found {
+		return nil
+	}
+	return g.in[i]
+}
+
+// Returns cards the card refers to with a relation of any of types e.g. Related(c, RelatedChild).
+// Without types all relations are followed.
+func (g *RelationGraph) Related(c *Card, types ...string) []*Card {
+	related := []*Card{}
+	for _, r := range g.Relations(c) {
+		if r.To != nil && hasAnyType(r.Related, types) && !slices.Contains(related, r.To) {
+			related = append(related, r.To)
+		}
+	}
+	return related
+}
+
+// Returns cards reachable from start by following relations of any of types in breadth-first
+// order, so direct relations go first. The start card is not included.
+//
+// E.g. Reachable(ceo, RelatedAgent) returns assistants of the CEO, their assistants and so on.
+func (g *RelationGraph) Reachable(start *Card, types ...string) []*Card {
+	reached := []*Card{}
+	visited := map[*Card]bool{start: true}
+	queue := []*Card{start}
+	for len(queue) > 0 {
+		c := queue[0]
+		queue = queue[1:]
+		for _, next := range g.Related(c, types...) {
+			if !visited[next] {
+				visited[next] = true
+				reached = append(reached, next)
+				queue = append(queue, next)
+			}
+		}
+	}
+	return reached
+}
+
+// Returns groups of cards connected by relations of any of types regardless of their direction
+// e.g. households connected by spouse, child and co-resident relations. Cards without such
+// relations are omitted. Groups and cards within them are in order of the cards of the graph.
+func (g *RelationGraph) Components(types ...string) [][]*Card {
+	neighbors := make([][]int, len(g.cards))
+	for i := range g.cards {
+		for _, r := range g.out[i] {
+			if r.To != nil && hasAnyType(r.Related, types) {
+				j := g.nodes[r.To]
+				neighbors[i] = append(neighbors[i], j)
+				neighbors[j] = append(neighbors[j], i)
+			}
+		}
+	}
+
+	components := [][]*Card{}
+	component := make([]int, len(g.cards))
+	for i := range component {
+		component[i] = -1
+	}
+	for i := range g.cards {
+		if component[i] >= 0 || len(neighbors[i]) == 0 {
+			continue
+		}
+		members := []int{}
+		stack := []int{i}
+		component[i] = len(components)
+		for len(stack) > 0 {
+			n := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			members = append(members, n)
+			for _, m := range neighbors[n] {
+				if component[m] < 0 {
+					component[m] = len(components)
+					stack = append(stack, m)
+				}
+			}
+		}
+		slices.Sort(members)
+		cards := make([]*Card, len(members))
+		for k, m := range members {
+			cards[k] = &g.cards[m]
+		}
+		components = append(components, cards)
+	}
+	return components
+}
+
+func hasAnyType(r Related, types []string) bool {
+	return len(types) == 0 || slices.ContainsFunc(types, r.HasType)
+}
@@ -0,0 +1,88 @@
+package vcard
+
+import "testing"
+
+func TestRelationGraph(t *testing.T) {
+
+	text := `BEGIN:VCARD
+VERSION:4.0
+FN:Ceo
+UID:urn:uuid:ceo
+RELATED;TYPE=agent:urn:uuid:assistant
+RELATED;TYPE=spouse:urn:uuid:partner
+RELATED;VALUE=text;TYPE=emergency:Call the front desk
+END:VCARD
+BEGIN:VCARD
+VERSION:4.0
+FN:Assistant
+UID:assistant
+RELATED;TYPE=agent:urn:uuid:intern
+RELATED;TYPE=co-worker:urn:uuid:ceo
+END:VCARD
+BEGIN:VCARD
+VERSION:4.0
+FN:Intern
+UID:intern
+RELATED;TYPE=friend:urn:uuid:unknown
+END:VCARD
+BEGIN:VCARD
+VERSION:4.0
+FN:Partner
+UID:partner
+RELATED;TYPE=child,co-resident:urn:uuid:kid
+END:VCARD
+BEGIN:VCARD
+VERSION:4.0
+FN:Kid
+UID:kid
+END:VCARD
+BEGIN:VCARD
+VERSION:4.0
+FN:Stranger
+END:VCARD
+`
+	cards := []Card{}
+	err := Unmarshal([]byte(crlfy(text)), &cards)
+	assertEq(t, err, nil)
+
+	fns := func(cards []*Card) []string {
+		names := []string{}
+		for _, c := range cards {
+			fn, _ := c.FN()
+			names = append(names, fn)
+		}
+		return names
+	}
+
+	g := NewRelationGraph(cards)
+
+	ceo, found := g.Node("CEO")
+	assertEq(t, found, true)
+	assertEq(t, ceo, &cards[0])
+	_, found = g.Node("nobody")
+	assertEq(t, found, false)
+
+	rels := g.Relations(ceo)
+	assertEq(t, len(rels), 3)
+	assertEq(t, rels[0].To, &cards[1])
+	assertEq(t, rels[2].To, (*Card)(nil))
+	assertEq(t, rels[2].Related.Text, "Call the front desk")
+
+	assertEq(t, len(g.RelatedTo(ceo)), 1)
+	assertEq(t, g.RelatedTo(ceo)[0].From, &cards[1])
+	assertEq(t, len(g.Relations(&cards[2])), 1)
+	assertEq(t, g.Relations(&Card{}) == nil, true)
+
+	assertSlicesEq(t, fns(g.Related(ceo)), []string{"Assistant", "Partner"})
+	assertSlicesEq(t, fns(g.Related(ceo, RelatedSpouse)), []string{"Partner"})
+	assertSlicesEq(t, fns(g.Reachable(ceo, RelatedAgent)), []string{"Assistant", "Intern"})
+	assertSlicesEq(t, fns(g.Reachable(ceo)), []string{"Assistant", "Partner", "Intern", "Kid"})
+
+	households := g.Components(RelatedSpouse, RelatedChild, RelatedCoResident)
+	assertEq(t, len(households), 1)
+	assertSlicesEq(t, fns(households[0]), []string{"Ceo", "Partner", "Kid"})
+
+	all := g.Components()
+	assertEq(t, len(all), 1)
+	assertSlicesEq(t, fns(all[0]), []string{"Ceo", "Assistant", "Intern", "Partner", "Kid"})
+}
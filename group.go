@@ -0,0 +1,154 @@
+package vcard
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+// Group card of vCard 4.0 which has KIND:group and lists its members with MEMBER properties
+// as per https://datatracker.ietf.org/doc/html/rfc6350#section-6.6.5
+//
+// Use [AllowedOnlyIf]("MEMBER", "KIND", "group") constraint to reject MEMBER in other cards.
+type Group struct {
+	UID string
+	FN  string
+
+	// URIs of members, usually UIDs of other cards e.g. "urn:uuid:03a0e51f-d1aa-4385-8a53-e29025acd8af".
+	Members []string
+}
+
+// Returns a group represented by the card. ok is false if the card is not a group.
+func (c *Card) Group() (g Group, ok bool) {
+	kind, _ := c.Kind()
+	if !strings.EqualFold(kind, "group") {
+		return Group{}, false
+	}
+	g.UID, _ = c.UID()
+	g.FN, _ = c.FN()
+	g.Members = c.Values("MEMBER")
+	return g, true
+}
+
+// Returns a vCard 4.0 card of the group.
+func (g Group) Card() Card {
+	c := Card{}
+	c.Add("VERSION", "4.0")
+	c.Add("KIND", "group")
+	if g.FN != "" {
+		c.Add("FN", escapeText(g.FN))
+	}
+	if g.UID != "" {
+		c.Add("UID", g.UID)
+	}
+	for _, m := range g.Members {
+		c.Add("MEMBER", m)
+	}
+	return c
+}
+
+// Finds cards of members among cards by UID. Returns found cards in order of members
+// and URIs of members which were not found.
+//
+// UIDs are compared case-insensitively and "urn:uuid:" prefix is ignored, so MEMBER:urn:uuid:abc
+// matches UID:abc.
+func (g Group) Resolve(cards []Card) (members []Card, missing []string) {
+	byUID := make(map[string]int, len(cards))
+	for i := range cards {
+		if uid, found := cards[i].UID(); found {
+			byUID[normalizeUID(uid)] = i
+		}
+	}
+	for _, m := range g.Members {
+		if i, found := byUID[normalizeUID(m)]; found {
+			members = append(members, cards[i])
+		} else {
+			missing = append(missing, m)
+		}
+	}
+	return members, missing
+}
+
+// Members of a group found by [Group.ResolveAll].
+type Membership struct {
+	// Cards of members which are not groups in order of first reference. Nested groups are expanded
+	// and every card occurs once. Pointers refer to the cards passed to [Group.ResolveAll].
+	Members []*Card
+
+	// Cards of nested groups which were expanded.
+	Groups []*Card
+
+	// URIs of members which match no card, including members of nested groups.
+	Dangling []string
+
+	// UIDs of groups which refer to themselves through their members e.g. ["a", "b", "a"] if group
+	// a contains group b which contains group a. Members of a cycle are resolved once.
+	Cycles [][]string
+}
+
+// Returns nil if all members were found without cycles, otherwise an error matching [ErrNotFound]
+// for dangling members and [ErrValidation] for cycles.
+func (m Membership) Err() error {
+	errs := []error{}
+	if len(m.Dangling) > 0 {
+		errs = append(errs, notFoundErrf("group members not found: %s", strings.Join(m.Dangling, ", ")))
+	}
+	for _, c := range m.Cycles {
+		errs = append(errs, validationErrf("groups form a cycle: %s", strings.Join(c, " -> ")))
+	}
+	return errors.Join(errs...)
+}
+
+// Finds cards of members among cards like [Group.Resolve], but expands members which are
+// groups themselves, so the result contains only individual cards e.g. people and organizations.
+// Missing members and cycles of groups are reported instead of failing, see [Membership.Err].
+func (g Group) ResolveAll(cards []Card) Membership {
+	byUID := make(map[string]int, len(cards))
+	for i := range cards {
+		if uid, found := cards[i].UID(); found {
+			if _, dup := byUID[normalizeUID(uid)]; !dup {
+				byUID[normalizeUID(uid)] = i
+			}
+		}
+	}
+
+	m := Membership{}
+	seen := map[int]bool{}
+	path := []string{g.UID}
+	var expand func(members []string)
+	expand = func(members []string) {
+		for _, uri := range members {
+			i, found := byUID[normalizeUID(uri)]
+			if !found {
+				if !slices.Contains(m.Dangling, uri) {
+					m.Dangling = append(m.Dangling, uri)
+				}
+				continue
+			}
+			nested, isGroup := cards[i].Group()
+			if start := slices.IndexFunc(path, func(uid string) bool { return normalizeUID(uid) == normalizeUID(uri) }); isGroup && start >= 0 {
+				m.Cycles = append(m.Cycles, append(slices.Clone(path[start:]), nested.UID))
+				continue
+			}
+			if seen[i] {
+				continue
+			}
+			seen[i] = true
+			if !isGroup {
+				m.Members = append(m.Members, &cards[i])
+				continue
+			}
+			m.Groups = append(m.Groups, &cards[i])
+			path = append(path, nested.UID)
+			expand(nested.Members)
+			path = path[:len(path)-1]
+		}
+	}
+	expand(g.Members)
+	return m
+}
+
+func normalizeUID(uid string) string {
+	uid = strings.ToLower(strings.TrimSpace(uid))
+	return strings.TrimPrefix(uid, "urn:uuid:")
+}
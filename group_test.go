@@ -0,0 +1,125 @@
+package vcard
+
+import "testing"
+
+func TestGroup(t *testing.T) {
+
+	text := `BEGIN:VCARD
+VERSION:4.0
+KIND:group
+FN:The Doe family
+UID:urn:uuid:family
+MEMBER:urn:uuid:03A0E51F
+MEMBER:urn:uuid:b8767877
+MEMBER:mailto:kim@example.com
+END:VCARD
+BEGIN:VCARD
+VERSION:4.0
+FN:John Doe
+UID:03a0e51f
+END:VCARD
+BEGIN:VCARD
+VERSION:4.0
+FN:Jane Doe
+UID:urn:uuid:b8767877
+END:VCARD
+`
+	cards := []Card{}
+	err := Unmarshal([]byte(crlfy(text)), &cards)
+	assertEq(t, err, nil)
+
+	_, ok := cards[1].Group()
+	assertEq(t, ok, false)
+
+	g, ok := cards[0].Group()
+	assertEq(t, ok, true)
+	assertEq(t, g.FN, "The Doe family")
+	assertEq(t, g.UID, "urn:uuid:family")
+	assertSlicesEq(t, g.Members, []string{"urn:uuid:03A0E51F", "urn:uuid:b8767877", "mailto:kim@example.com"})
+
+	members, missing := g.Resolve(cards)
+	assertEq(t, len(members), 2)
+	assertStringsEq(t, members[0].Values("FN")[0], "John Doe")
+	assertStringsEq(t, members[1].Values("FN")[0], "Jane Doe")
+	assertSlicesEq(t, missing, []string{"mailto:kim@example.com"})
+
+	c := g.Card()
+	b, err := Marshal(c)
+	assertEq(t, err, nil)
+
+	decoded := Card{}
+	err = Unmarshal(b, &decoded)
+	assertEq(t, err, nil)
+
+	again, ok := decoded.Group()
+	assertEq(t, ok, true)
+	assertDeepEq(t, again, g)
+}
+
+func TestMemberOnlyInGroups(t *testing.T) {
+
+	schema := SchemaV4.WithConstraints(AllowedOnlyIf("MEMBER", "KIND", "group"))
+
+	_, err := MarshalSchema(map[string]string{"FN": "Alex", "MEMBER": "urn:uuid:1"}, schema)
+	assertErrIs(t, err, ErrValidation, `may contain MEMBER only if KIND is "group"`)
+
+	_, err = MarshalSchema(map[string]string{"FN": "Team", "KIND": "Group", "MEMBER": "urn:uuid:1"}, schema)
+	assertEq(t, err, nil)
+}
+
+func TestGroupResolveAll(t *testing.T) {
+
+	text := `BEGIN:VCARD
+VERSION:4.0
+KIND:group
+FN:Company
+UID:company
+MEMBER:urn:uuid:sales
+MEMBER:urn:uuid:ceo
+MEMBER:urn:uuid:gone
+END:VCARD
+BEGIN:VCARD
+VERSION:4.0
+KIND:group
+FN:Sales
+UID:sales
+MEMBER:urn:uuid:alex
+MEMBER:urn:uuid:ceo
+MEMBER:urn:uuid:company
+MEMBER:urn:uuid:lost
+END:VCARD
+BEGIN:VCARD
+VERSION:4.0
+FN:Alex
+UID:alex
+END:VCARD
+BEGIN:VCARD
+VERSION:4.0
+FN:Kim
+UID:CEO
+END:VCARD
+`
+	cards := []Card{}
+	err := Unmarshal([]byte(crlfy(text)), &cards)
+	assertEq(t, err, nil)
+
+	g, ok := cards[0].Group()
+	assertEq(t, ok, true)
+
+	m := g.ResolveAll(cards)
+	assertEq(t, len(m.Members), 2)
+	assertEq(t, m.Members[0], &cards[2])
+	assertEq(t, m.Members[1], &cards[3])
+	assertEq(t, len(m.Groups), 1)
+	assertEq(t, m.Groups[0], &cards[1])
+	assertSlicesEq(t, m.Dangling, []string{"urn:uuid:lost", "urn:uuid:gone"})
+	assertDeepEq(t, m.Cycles, [][]string{{"company", "sales", "company"}})
+
+	err = m.Err()
+	assertErrIs(t, err, ErrNotFound, "group members not found: urn:uuid:lost, urn:uuid:gone")
+	assertErrIs(t, err, ErrValidation, "groups form a cycle: company -> sales -> company")
+
+	g, _ = cards[1].Group()
+	g.Members = g.Members[:2]
+	assertEq(t, g.ResolveAll(cards).Err(), nil)
+}
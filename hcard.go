@@ -0,0 +1,568 @@
+package vcard
+
+import (
+	"html"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Renders the card as hCard microformat as per https://microformats.org/wiki/hCard e.g.
+//
+//	<div class="vcard">
+//	  <span class="fn">Alex Smith</span>
+//	  <a class="email" href="mailto:alex@example.com">alex@example.com</a>
+//	</div>
+//
+// FN, N, NICKNAME, ORG, TITLE, ROLE, EMAIL, TEL, ADR, URL, PHOTO, LOGO, BDAY, NOTE, CATEGORIES,
+// GEO and UID are rendered in order of the card. TYPE parameters of EMAIL, TEL and ADR are rendered as
+// "type" subproperties. Embedded photos are rendered as data: URIs. URLs other than http, https and
+// mailto e.g. javascript: of untrusted cards are rendered as text instead of links. Other properties
+// are omitted.
+func MarshalHCard(card Card) ([]byte, error) {
+	b := []byte("<div class=\"vcard\">\n")
+	for _, p := range topLevelProperties(card.props) {
+		b = appendHCardProperty(b, p)
+	}
+	return append(b, "</div>\n"...), nil
+}
+
+func appendHCardProperty(b []byte, p Property) []byte {
+	span := func(class string, text string) {
+		b = append(b, `<span class="`+class+`">`+html.EscapeString(text)+"</span>"...)
+	}
+	line := func(f func()) {
+		b = append(b, "  "...)
+		f()
+		b = append(b, '\n')
+	}
+	types := func() {
+		types := withoutValue(p.Params.Values("TYPE"), "pref")
+		if propertyPref(p) == 1 {
+			types = append(types, "pref")
+		}
+		for _, t := range types {
+			span("type", strings.ToLower(t))
+			b = append(b, ' ')
+		}
+	}
+	components := func(names []string) {
+		sep := ""
+		for i, c := range splitComponents(p.Value) {
+			if i >= len(names) {
+				break
+			}
+			for _, v := range splitTextList(c) {
+				if v != "" {
+					b = append(b, sep...)
+					span(names[i], v)
+					sep = " "
+				}
+			}
+		}
+	}
+	div := func(class string, f func()) {
+		b = append(b, `<div class="`+class+`">`...)
+		f()
+		b = append(b, "</div>"...)
+	}
+	text := unescapeText(p.Value)
+
+	switch p.Name {
+	case "FN", "NICKNAME", "TITLE", "ROLE", "NOTE", "UID":
+		line(func() { span(strings.ToLower(p.Name), text) })
+	case "CATEGORIES":
+		for _, c := range splitTextList(p.Value) {
+			line(func() { span("category", c) })
+		}
+	case "N":
+		line(func() { div("n", func() { components(hCardNameClasses) }) })
+	case "ADR":
+		line(func() {
+			div("adr", func() {
+				types()
+				components(hCardAdrClasses)
+			})
+		})
+	case "ORG":
+		line(func() { div("org", func() { components([]string{"organization-name", "organization-unit"}) }) })
+	case "EMAIL":
+		address := strings.TrimPrefix(p.Value, "mailto:")
+		line(func() {
+			b = append(b, `<span class="email">`...)
+			types()
+			b = append(b, `<a class="value" href="mailto:`+html.EscapeString(address)+`">`+html.EscapeString(address)+"</a></span>"...)
+		})
+	case "TEL":
+		number := strings.TrimPrefix(p.Value, "tel:")
+		line(func() {
+			b = append(b, `<span class="tel">`...)
+			types()
+			span("value", number)
+			b = append(b, "</span>"...)
+		})
+	case "URL":
+		line(func() {
+			if !isSafeLink(p.Value) {
+				span("url", p.Value)
+				return
+			}
+			b = append(b, `<a class="url" href="`+html.EscapeString(p.Value)+`">`+html.EscapeString(p.Value)+"</a>"...)
+		})
+	case "PHOTO", "LOGO":
+		m, err := unmarshalMedia([]byte(p.rest()), "image")
+		if err != nil {
+			return b
+		}
+		line(func() {
+			b = append(b, `<img class="`+strings.ToLower(p.Name)+`" src="`+html.EscapeString(m.dataURI())+`" alt="`+strings.ToLower(p.Name)+`"/>`...)
+		})
+	case "BDAY":
+		d := Date{}
+		if err := d.UnmarshalVCardField([]byte(p.rest())); err != nil {
+			return b
+		}
+		value := d.Text
+		if value == "" {
+			value = d.format(true)
+		}
+		line(func() {
+			b = append(b, `<abbr class="bday" title="`+html.EscapeString(value)+`">`+html.EscapeString(value)+"</abbr>"...)
+		})
+	case "GEO":
+		g := Geo{}
+		if err := g.UnmarshalVCardField([]byte(p.rest())); err != nil {
+			return b
+		}
+		line(func() {
+			b = append(b, `<span class="geo">`...)
+			span("latitude", strconv.FormatFloat(g.Lat, 'f', -1, 64))
+			b = append(b, ' ')
+			span("longitude", strconv.FormatFloat(g.Lon, 'f', -1, 64))
+			b = append(b, "</span>"...)
+		})
+	}
+	return b
+}
+
+// Classes of components of N and ADR in order of their components.
+var (
+	hCardNameClasses = []string{"family-name", "given-name", "additional-name", "honorific-prefix", "honorific-suffix"}
+	hCardAdrClasses  = []string{"post-office-box", "extended-address", "street-address", "locality", "region", "postal-code", "country-name"}
+)
+
+// Extracts cards of vCard 4.0 from hCard markup. Every element with class "vcard" of hCard or
+// "h-card" of microformats2 becomes a card, including cards nested into other cards.
+//
+// Values are taken from "value" subproperties, href of links, src of images, title of abbr and
+// datetime of time elements, or text of elements otherwise. "type" subproperties become TYPE parameters.
+// FN is derived from N or ORG if the markup has no "fn". Returns [ErrParsing] if the markup contains
+// no cards.
+func UnmarshalHCard(data []byte) ([]Card, error) {
+	root := parseHTML(string(data))
+	cards := []Card{}
+	root.walk(func(n *htmlNode) bool {
+		if n.hasClass("vcard") {
+			cards = append(cards, hCardOf(n))
+		}
+		return true
+	})
+	if len(cards) == 0 {
+		return nil, parsingErrf("markup does not contain hCard")
+	}
+	return cards, nil
+}
+
+func hCardOf(root *htmlNode) Card {
+	c := Card{}
+	c.Add("VERSION", "4.0")
+	add := func(name string, params Params, value string) {
+		c.AddProperty(Property{Name: name, Params: params, Value: value})
+	}
+
+	root.properties(func(n *htmlNode) {
+		for _, class := range n.classes() {
+			switch class {
+			case "fn", "nickname", "title", "role", "note":
+				if v := n.value(); v != "" {
+					add(strings.ToUpper(class), nil, escapeText(v))
+				}
+			case "category":
+				if v := n.value(); v != "" {
+					add("CATEGORIES", nil, escapeText(v))
+				}
+			case "n":
+				add("N", nil, joinComponentLists(n.components(hCardNameClasses)...))
+			case "adr":
+				add("ADR", n.typeParams(), joinComponentLists(n.components(hCardAdrClasses)...))
+			case "org":
+				lists := n.components([]string{"organization-name", "organization-unit"})
+				if len(lists[0]) == 0 && len(lists[1]) == 0 {
+					lists[0] = []string{n.value()}
+				}
+				add("ORG", nil, JoinStructured(append(slices.Clone(lists[0][:min(len(lists[0]), 1)]), lists[1]...)))
+			case "email":
+				address, _, _ := strings.Cut(strings.TrimPrefix(n.uriValue(), "mailto:"), "?")
+				if address != "" {
+					add("EMAIL", n.typeParams(), address)
+				}
+			case "tel":
+				if number := strings.TrimPrefix(n.uriValue(), "tel:"); number != "" {
+					add("TEL", n.typeParams(), number)
+				}
+			case "url", "photo", "logo", "uid":
+				if v := n.uriValue(); v != "" {
+					add(strings.ToUpper(class), nil, v)
+				}
+			case "bday":
+				v := n.value()
+				if d, err := ParseDate(v); err == nil {
+					add("BDAY", nil, d.String())
+				} else if v != "" {
+					add("BDAY", Params{{"VALUE", []string{"text"}}}, escapeText(v))
+				}
+			case "geo":
+				lat, lon := n.find("latitude"), n.find("longitude")
+				if lat != nil && lon != nil {
+					add("GEO", nil, "geo:"+lat.value()+","+lon.value())
+				} else if v := strings.TrimPrefix(n.value(), "geo:"); v != "" {
+					lat, lon, _ := strings.Cut(strings.ReplaceAll(v, ";", ","), ",")
+					add("GEO", nil, "geo:"+strings.TrimSpace(lat)+","+strings.TrimSpace(lon))
+				}
+			}
+		}
+	})
+
+	if c.count("FN") == 0 {
+		n, _ := c.Name()
+		o, _ := c.Organization()
+		if fn := displayName(n, o); fn != "" {
+			c.props = slices.Insert(c.props, 1, Property{Name: "FN", Value: escapeText(fn)})
+		}
+	}
+	return c
+}
+
+// Properties of hCard which contain subproperties, so their descendants are not properties of a card.
+var hCardCompound = []string{"n", "adr", "org", "email", "tel", "geo"}
+
+// Calls fn for every descendant which has a class of a property of the card. Descendants of
+// compound properties and nested cards are not visited.
+func (n *htmlNode) properties(fn func(n *htmlNode)) {
+	for _, child := range n.children {
+		if child.tag == "" {
+			continue
+		}
+		if child.hasClass("vcard") {
+			continue
+		}
+		fn(child)
+		if !slices.ContainsFunc(child.classes(), func(c string) bool { return slices.Contains(hCardCompound, c) }) {
+			child.properties(fn)
+		}
+	}
+}
+
+// Returns values of descendants with the given classes.
+func (n *htmlNode) components(classes []string) [][]string {
+	lists := make([][]string, len(classes))
+	n.walk(func(d *htmlNode) bool {
+		if d == n {
+			return true
+		}
+		for i, class := range classes {
+			if d.hasClass(class) {
+				if v := d.value(); v != "" {
+					lists[i] = append(lists[i], v)
+				}
+				return false
+			}
+		}
+		return !d.hasClass("vcard")
+	})
+	return lists
+}
+
+// Returns TYPE and PREF parameters of "type" subproperties.
+func (n *htmlNode) typeParams() Params {
+	types := []string{}
+	n.walk(func(d *htmlNode) bool {
+		if d != n && d.hasClass("type") {
+			types = append(types, strings.ToLower(d.value()))
+			return false
+		}
+		return true
+	})
+	params := Params{}
+	if t := withoutValue(types, "pref"); len(t) > 0 {
+		params.Add("TYPE", t...)
+	}
+	if slices.Contains(types, "pref") {
+		params.Add("PREF", "1")
+	}
+	return params
+}
+
+// Returns the first descendant with the given class.
+func (n *htmlNode) find(class string) *htmlNode {
+	var found *htmlNode
+	n.walk(func(d *htmlNode) bool {
+		if found == nil && d != n && d.hasClass(class) {
+			found = d
+		}
+		return found == nil
+	})
+	return found
+}
+
+// Returns value of a property as defined by hCard parsing rules.
+func (n *htmlNode) value() string {
+	values := []string{}
+	n.walk(func(d *htmlNode) bool {
+		if d != n && d.hasClass("value") {
+			values = append(values, d.value())
+			return false
+		}
+		return true
+	})
+	if len(values) > 0 {
+		return strings.Join(values, "")
+	}
+
+	for _, attr := range [][2]string{{"abbr", "title"}, {"time", "datetime"}, {"img", "alt"}} {
+		if v, found := n.attrs[attr[1]]; n.tag == attr[0] && found {
+			return strings.TrimSpace(v)
+		}
+	}
+
+	// Text of the element without "type" subproperties
+	b := strings.Builder{}
+	n.walk(func(d *htmlNode) bool {
+		if d != n && d.hasClass("type") {
+			return false
+		}
+		if d.tag == "br" {
+			b.WriteByte('\n')
+		}
+		b.WriteString(strings.Map(func(r rune) rune {
+			if r == '\n' || r == '\r' {
+				return ' '
+			}
+			return r
+		}, d.text))
+		return true
+	})
+	lines := strings.Split(b.String(), "\n")
+	for i, l := range lines {
+		lines[i] = strings.Join(strings.Fields(l), " ")
+	}
+	return strings.TrimSpace(strings.Join(slices.DeleteFunc(lines, func(l string) bool { return l == "" }), "\n"))
+}
+
+// Returns value of a property which is a URI e.g. href of a link or src of an image.
+func (n *htmlNode) uriValue() string {
+	for _, attr := range [][2]string{{"a", "href"}, {"area", "href"}, {"img", "src"}, {"object", "data"}} {
+		if v, found := n.attrs[attr[1]]; n.tag == attr[0] && found && n.find("value") == nil {
+			return strings.TrimSpace(v)
+		}
+	}
+	return n.value()
+}
+
+// Returns classes of the element mapped to names of hCard e.g. "h-card" to "vcard" and
+// "p-street-address" to "street-address".
+func (n *htmlNode) classes() []string {
+	classes := []string{}
+	for _, c := range strings.Fields(strings.ToLower(n.attrs["class"])) {
+		switch c {
+		case "h-card":
+			c = "vcard"
+		case "p-name":
+			c = "fn"
+		case "p-job-title":
+			c = "title"
+		default:
+			for _, prefix := range []string{"p-", "u-", "dt-", "e-", "h-"} {
+				if trimmed, found := strings.CutPrefix(c, prefix); found {
+					c = trimmed
+					break
+				}
+			}
+		}
+		if !slices.Contains(classes, c) {
+			classes = append(classes, c)
+		}
+	}
+	return classes
+}
+
+func (n *htmlNode) hasClass(class string) bool {
+	return n.tag != "" && slices.Contains(n.classes(), class)
+}
+
+// Element or text of an HTML document. Text nodes have an empty tag.
+type htmlNode struct {
+	tag      string
+	attrs    map[string]string
+	children []*htmlNode
+	text     string
+}
+
+// Calls fn for the node and its descendants in document order. Descendants of a node are
+// skipped if fn returns false.
+func (n *htmlNode) walk(fn func(n *htmlNode) bool) {
+	if !fn(n) {
+		return
+	}
+	for _, c := range n.children {
+		c.walk(fn)
+	}
+}
+
+// Elements which have no content and no end tag.
+var htmlVoidElements = []string{"area", "base", "br", "col", "embed", "hr", "img", "input", "link", "meta", "source", "track", "wbr"}
+
+// Parses an HTML document into a tree leniently: unknown end tags are ignored and unclosed
+// elements are closed by end tags of their ancestors. Comments, doctypes, scripts and styles are dropped.
+func parseHTML(s string) *htmlNode {
+	root := &htmlNode{tag: "#document"}
+	stack := []*htmlNode{root}
+	top := func() *htmlNode { return stack[len(stack)-1] }
+
+	for len(s) > 0 {
+		lt := strings.IndexByte(s, '<')
+		if lt < 0 {
+			lt = len(s)
+		}
+		if lt > 0 {
+			top().children = append(top().children, &htmlNode{text: html.UnescapeString(s[:lt])})
+			s = s[lt:]
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(s, "<!--"):
+			end := strings.Index(s, "-->")
+			if end < 0 {
+				return root
+			}
+			s = s[end+3:]
+			continue
+		case strings.HasPrefix(s, "<!") || strings.HasPrefix(s, "<?"):
+			end := strings.IndexByte(s, '>')
+			if end < 0 {
+				return root
+			}
+			s = s[end+1:]
+			continue
+		}
+
+		tag, rest, ok := parseHTMLTag(s)
+		if !ok {
+			top().children = append(top().children, &htmlNode{text: "<"})
+			s = s[1:]
+			continue
+		}
+		s = rest
+
+		if strings.HasPrefix(tag.tag, "/") {
+			name := tag.tag[1:]
+			for i := len(stack) - 1; i > 0; i-- {
+				if stack[i].tag == name {
+					stack = stack[:i]
+					break
+				}
+			}
+			continue
+		}
+
+		top().children = append(top().children, tag)
+		if tag.tag == "script" || tag.tag == "style" {
+			end := strings.Index(strings.ToLower(s), "</"+tag.tag)
+			if end < 0 {
+				return root
+			}
+			s = s[end:]
+			continue
+		}
+		if !slices.Contains(htmlVoidElements, tag.tag) && tag.text != "/" {
+			stack = append(stack, tag)
+		}
+	}
+	return root
+}
+
+// Parses a start or an end tag at the beginning of s and returns the rest of s. Self-closing
+// tags are marked with "/" text.
+func parseHTMLTag(s string) (*htmlNode, string, bool) {
+	i := 1
+	if i < len(s) && s[i] == '/' {
+		i++
+	}
+	start := i
+	for i < len(s) && (isAlphaNum(s[i]) || s[i] == '-') {
+		i++
+	}
+	if i == start {
+		return nil, s, false
+	}
+	n := &htmlNode{tag: strings.ToLower(s[1:i]), attrs: map[string]string{}}
+
+	for i < len(s) {
+		for i < len(s) && strings.IndexByte(" \t\r\n\f", s[i]) >= 0 {
+			i++
+		}
+		switch {
+		case i >= len(s):
+			return nil, s, false
+		case s[i] == '>':
+			return n, s[i+1:], true
+		case strings.HasPrefix(s[i:], "/>"):
+			n.text = "/"
+			return n, s[i+2:], true
+		}
+
+		start := i
+		for i < len(s) && strings.IndexByte(" \t\r\n\f=>", s[i]) < 0 && !strings.HasPrefix(s[i:], "/>") {
+			i++
+		}
+		name := strings.ToLower(s[start:i])
+		value := ""
+		if i < len(s) && s[i] == '=' {
+			i++
+			if i < len(s) && (s[i] == '"' || s[i] == '\'') {
+				end := strings.IndexByte(s[i+1:], s[i])
+				if end < 0 {
+					return nil, s, false
+				}
+				value = s[i+1 : i+1+end]
+				i += end + 2
+			} else {
+				start := i
+				for i < len(s) && strings.IndexByte(" \t\r\n\f>", s[i]) < 0 {
+					i++
+				}
+				value = s[start:i]
+			}
+		}
+		if name == "" {
+			i++
+			continue
+		}
+		if _, dup := n.attrs[name]; !dup {
+			n.attrs[name] = html.UnescapeString(value)
+		}
+	}
+	return nil, s, false
+}
+
+func isAlphaNum(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// Reports whether the URI is safe to render as a link i.e. it is an http, https or mailto URI.
+func isSafeLink(uri string) bool {
+	u, err := url.Parse(uri)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https" || u.Scheme == "mailto")
+}
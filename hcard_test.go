@@ -0,0 +1,144 @@
+package vcard
+
+import "testing"
+
+func TestHCardRoundTrip(t *testing.T) {
+
+	card := Card{}
+	err := Unmarshal([]byte(crlfy(`BEGIN:VCARD
+VERSION:4.0
+FN:Alex Smith & Co
+N:Smith;Alex;;Dr.;
+ORG:Acme\, Inc.;Sales
+TITLE:Engineer
+EMAIL;TYPE=work;PREF=1:alex@example.com
+TEL;TYPE=cell:+1 555 0100
+ADR;TYPE=home:;;123 Main St.;Anytown;CA;91921;USA
+URL:https://example.com/?a=1&b=2
+BDAY:19900102
+GEO:geo:37.386013,-122.082932
+CATEGORIES:friends,work
+X-SKYPE:alex
+END:VCARD
+`)), &card)
+	assertEq(t, err, nil)
+
+	b, err := MarshalHCard(card)
+	assertEq(t, err, nil)
+	assertStringLinesEq(t, string(b), `<div class="vcard">
+  <span class="fn">Alex Smith &amp; Co</span>
+  <div class="n"><span class="family-name">Smith</span> <span class="given-name">Alex</span> <span class="honorific-prefix">Dr.</span></div>
+  <div class="org"><span class="organization-name">Acme, Inc.</span> <span class="organization-unit">Sales</span></div>
+  <span class="title">Engineer</span>
+  <span class="email"><span class="type">work</span> <span class="type">pref</span> <a class="value" href="mailto:alex@example.com">alex@example.com</a></span>
+  <span class="tel"><span class="type">cell</span> <span class="value">+1 555 0100</span></span>
+  <div class="adr"><span class="type">home</span> <span class="street-address">123 Main St.</span> <span class="locality">Anytown</span> <span class="region">CA</span> <span class="postal-code">91921</span> <span class="country-name">USA</span></div>
+  <a class="url" href="https://example.com/?a=1&amp;b=2">https://example.com/?a=1&amp;b=2</a>
+  <abbr class="bday" title="1990-01-02">1990-01-02</abbr>
+  <span class="geo"><span class="latitude">37.386013</span> <span class="longitude">-122.082932</span></span>
+  <span class="category">friends</span>
+  <span class="category">work</span>
+</div>
+`)
+
+	cards, err := UnmarshalHCard(b)
+	assertEq(t, err, nil)
+	assertEq(t, len(cards), 1)
+
+	b, err = Marshal(cards[0])
+	assertEq(t, err, nil)
+	assertStringLinesEq(t, string(b), crlfy(`BEGIN:VCARD
+VERSION:4.0
+FN:Alex Smith & Co
+N:Smith;Alex;;Dr.;
+ORG:Acme\, Inc.;Sales
+TITLE:Engineer
+EMAIL;TYPE=work;PREF=1:alex@example.com
+TEL;TYPE=cell:+1 555 0100
+ADR;TYPE=home:;;123 Main St.;Anytown;CA;91921;USA
+URL:https://example.com/?a=1&b=2
+BDAY:19900102
+GEO:geo:37.386013,-122.082932
+CATEGORIES:friends
+CATEGORIES:work
+END:VCARD
+`))
+}
+
+func TestUnmarshalHCard(t *testing.T) {
+
+	markup := `<!DOCTYPE html>
+<html><body>
+<!-- <div class="vcard">commented out</div> -->
+<script>var s = "<div class='vcard'>";</script>
+<ul>
+  <li class="vcard">
+    <a class="url fn" href="http://tantek.com/">Tantek
+      Çelik</a>
+    <div class="org">Technorati</div>
+    <a class="email" href="mailto:tantek@example.com?subject=hi">mail me</a>
+    <div class="tel"><span class="type">Work</span> +1-415-555-0100</div>
+    <img class="photo" src="http://example.com/t.jpg" alt="Tantek">
+    <div class="agent vcard"><span class="fn">Assistant</span></div>
+  <li class="h-card">
+    <p class="p-name">Jane<br>Doe</p>
+    <p class="h-adr p-adr"><span class="p-street-address">1 Main St</span><span class="p-locality">Town</span></p>
+    <time class="dt-bday" datetime="1985-04-12">April 12</time>
+    <a class="u-email" href="mailto:jane@example.com">jane</a>
+</ul>
+<div class="vcard"><span class="n"><span class="given-name">Only</span> <span class="family-name">Name</span></span></div>
+</body></html>`
+
+	cards, err := UnmarshalHCard([]byte(markup))
+	assertEq(t, err, nil)
+	assertEq(t, len(cards), 4)
+
+	lines := func(c Card) []string {
+		props := []string{}
+		for _, p := range c.Properties() {
+			props = append(props, p.String())
+		}
+		return props
+	}
+	assertSlicesEq(t, lines(cards[0]), []string{
+		"VERSION:4.0",
+		"URL:http://tantek.com/",
+		"FN:Tantek Çelik",
+		"ORG:Technorati",
+		"EMAIL:tantek@example.com",
+		"TEL;TYPE=work:+1-415-555-0100",
+		"PHOTO:http://example.com/t.jpg",
+	})
+	assertSlicesEq(t, lines(cards[1]), []string{"VERSION:4.0", "FN:Assistant"})
+	assertSlicesEq(t, lines(cards[2]), []string{
+		"VERSION:4.0",
+		`FN:Jane\nDoe`,
+		"ADR:;;1 Main St;Town;;;",
+		"BDAY:19850412",
+		"EMAIL:jane@example.com",
+	})
+	assertSlicesEq(t, lines(cards[3]), []string{"VERSION:4.0", "FN:Only Name", "N:Name;Only;;;"})
+
+	_, err = UnmarshalHCard([]byte(`<div class="vcardish">no</div>`))
+	assertErrIs(t, err, ErrParsing, "markup does not contain hCard")
+}
+
+func TestHCardUnsafeURL(t *testing.T) {
+
+	card := Card{}
+	card.Add("VERSION", "4.0")
+	card.Add("FN", "Alex")
+	card.Add("URL", "javascript:alert(1)")
+	card.Add("URL", "JavaScript:alert(2)")
+	card.Add("URL", "mailto:alex@example.com")
+
+	b, err := MarshalHCard(card)
+	assertEq(t, err, nil)
+	assertStringLinesEq(t, string(b), `<div class="vcard">
+  <span class="fn">Alex</span>
+  <span class="url">javascript:alert(1)</span>
+  <span class="url">JavaScript:alert(2)</span>
+  <a class="url" href="mailto:alex@example.com">mailto:alex@example.com</a>
+</div>
+`)
+}
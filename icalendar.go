@@ -0,0 +1,114 @@
+package vcard
+
+import "strings"
+
+// Returns value of iCalendar ATTENDEE property for the person described by the card e.g.
+//
+//	ATTENDEE;CN="Alex FullName":mailto:alex@example.com
+//
+// CN is taken from FN and the address from the most preferred EMAIL.
+// See https://datatracker.ietf.org/doc/html/rfc5545#section-3.8.4.1
+func (c *Card) ICalAttendee() (string, error) {
+	return c.icalCalAddress("ATTENDEE")
+}
+
+// Returns value of iCalendar ORGANIZER property for the person described by the card.
+//
+// See [Card.ICalAttendee] and https://datatracker.ietf.org/doc/html/rfc5545#section-3.8.4.3
+func (c *Card) ICalOrganizer() (string, error) {
+	return c.icalCalAddress("ORGANIZER")
+}
+
+func (c *Card) icalCalAddress(name string) (string, error) {
+	email, found := c.preferred("EMAIL")
+	if !found || email.Value == "" {
+		return "", vCardErrf("card does not contain EMAIL required by %s", name)
+	}
+
+	buf := strings.Builder{}
+	buf.WriteString(name)
+
+	if fn, found := c.FN(); found && fn != "" {
+		buf.WriteString(";CN=")
+		buf.WriteString(icalParamValue(fn))
+	}
+	if kind, found := c.Kind(); found {
+		if cutype, found := kindToCUType[strings.ToLower(kind)]; found {
+			buf.WriteString(";CUTYPE=")
+			buf.WriteString(cutype)
+		}
+	}
+
+	buf.WriteString(":mailto:")
+	buf.WriteString(strings.TrimPrefix(email.Value, "mailto:"))
+
+	return buf.String(), nil
+}
+
+// Creates a vCard 4.0 stub from iCalendar ATTENDEE or ORGANIZER content line e.g.
+//
+//	ATTENDEE;CUTYPE=INDIVIDUAL;CN=Alex FullName:mailto:alex@example.com
+//
+// FN is taken from CN parameter or from the address if there is no CN,
+// KIND is taken from CUTYPE parameter.
+func CardFromICalAttendee(line string) (Card, error) {
+	// Folded lines are allowed in iCalendar just like in vCard
+	line = strings.NewReplacer("\r\n ", "", "\r\n\t", "", "\n ", "", "\n\t", "").Replace(strings.TrimSpace(line))
+
+	p, err := ParseProperty(line)
+	if err != nil {
+		return Card{}, err
+	}
+	if p.Name != "ATTENDEE" && p.Name != "ORGANIZER" {
+		return Card{}, parsingErrf("expected ATTENDEE or ORGANIZER but found %q", p.Name)
+	}
+
+	address := p.Value
+	if len(address) >= len("mailto:") && strings.EqualFold(address[:len("mailto:")], "mailto:") {
+		address = address[len("mailto:"):]
+	}
+	if address == "" {
+		return Card{}, parsingErrf("%s does not contain an address", p.Name)
+	}
+
+	fn := address
+	kind := ""
+	if v, found := p.Params.Get("CN"); found {
+		fn = v
+	}
+	if v, found := p.Params.Get("CUTYPE"); found {
+		kind = cuTypeToKind[strings.ToUpper(v)]
+	}
+
+	c := Card{}
+	c.Add("VERSION", "4.0")
+	if kind != "" {
+		c.Add("KIND", kind)
+	}
+	c.Add("FN", escapeText(fn))
+	c.Add("EMAIL", address)
+
+	return c, nil
+}
+
+// Maps KIND of vCard 4.0 to CUTYPE of iCalendar
+var kindToCUType = map[string]string{
+	"individual": "INDIVIDUAL",
+	"group":      "GROUP",
+	"location":   "ROOM",
+}
+
+var cuTypeToKind = map[string]string{
+	"INDIVIDUAL": "individual",
+	"GROUP":      "group",
+	"ROOM":       "location",
+}
+
+// Quotes iCalendar parameter value if it contains `:`, `;` or `,`
+// as per https://datatracker.ietf.org/doc/html/rfc5545#section-3.2
+func icalParamValue(s string) string {
+	if strings.ContainsAny(s, ":;,\"^\n") {
+		return quoteParamValue(s)
+	}
+	return s
+}
@@ -0,0 +1,89 @@
+package vcard
+
+import "testing"
+
+func TestICalAttendee(t *testing.T) {
+
+	text := `BEGIN:VCARD
+VERSION:4.0
+FN:Doe\, John
+EMAIL;TYPE=work:john@work.example.com
+EMAIL;PREF=1:john@example.com
+END:VCARD
+`
+	c := Card{}
+	_ = Unmarshal([]byte(crlfy(text)), &c)
+
+	attendee, err := c.ICalAttendee()
+	assertEq(t, err, nil)
+	assertStringsEq(t, attendee, `ATTENDEE;CN="Doe, John":mailto:john@example.com`)
+
+	organizer, err := c.ICalOrganizer()
+	assertEq(t, err, nil)
+	assertStringsEq(t, organizer, `ORGANIZER;CN="Doe, John":mailto:john@example.com`)
+}
+
+func TestICalAttendeeLegacyPref(t *testing.T) {
+
+	c := Card{}
+	c.Add("VERSION", "3.0")
+	c.Add("FN", "Alex")
+	c.Add("KIND", "group")
+	c.Add("EMAIL", "first@example.com")
+	c.props = append(c.props, Property{Name: "EMAIL", Params: parseParams(";TYPE=INTERNET,PREF"), Value: "pref@example.com"})
+
+	attendee, err := c.ICalAttendee()
+
+	assertEq(t, err, nil)
+	assertStringsEq(t, attendee, `ATTENDEE;CN=Alex;CUTYPE=GROUP:mailto:pref@example.com`)
+}
+
+func TestICalAttendeeWithoutEmail(t *testing.T) {
+
+	c := Card{}
+	c.Add("FN", "Alex")
+
+	_, err := c.ICalAttendee()
+
+	assertErrIs(t, err, ErrVCard, "does not contain EMAIL")
+}
+
+func TestCardFromICalAttendee(t *testing.T) {
+
+	line := "ATTENDEE;CUTYPE=INDIVIDUAL;ROLE=REQ-PARTICIPANT;CN=\"Doe, John\":\r\n MAILTO:john@example.com"
+
+	c, err := CardFromICalAttendee(line)
+
+	assertEq(t, err, nil)
+	assertEq(t, c.Version(), "4.0")
+
+	fn, _ := c.FN()
+	assertEq(t, fn, "Doe, John")
+
+	kind, _ := c.Kind()
+	assertEq(t, kind, "individual")
+	assertSlicesEq(t, c.Values("EMAIL"), []string{"john@example.com"})
+
+	b, err := Marshal(c)
+	exp := `BEGIN:VCARD
+VERSION:4.0
+KIND:individual
+FN:Doe\, John
+EMAIL:john@example.com
+END:VCARD
+`
+	assertEq(t, err, nil)
+	assertStringsEq(t, string(b), crlfy(exp))
+}
+
+func TestCardFromICalAttendeeWithoutCN(t *testing.T) {
+
+	c, err := CardFromICalAttendee("ORGANIZER:mailto:boss@example.com")
+
+	assertEq(t, err, nil)
+	fn, _ := c.FN()
+	assertEq(t, fn, "boss@example.com")
+
+	_, err = CardFromICalAttendee("SUMMARY:Meeting")
+	assertErrIs(t, err, ErrParsing, "expected ATTENDEE or ORGANIZER")
+}
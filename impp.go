@@ -0,0 +1,90 @@
+package vcard
+
+import (
+	"slices"
+	"strings"
+)
+
+// Value of IMPP property as per https://datatracker.ietf.org/doc/html/rfc6350#section-6.4.3
+// e.g. "xmpp:alice@example.com", "sip:alice@example.com" or "skype:alice.example".
+//
+// Impp implements [VCardFieldMarshaler] and [VCardFieldUnmarshaler], so it can be used as a type
+// of struct fields instead of string. See [Tel].
+type Impp struct {
+	// URI of the messenger handle.
+	URI string
+
+	// Values of TYPE parameter e.g. "work", "home".
+	Types []string
+
+	// Preference in range 1..100 where 1 is the most preferred. 0 means no preference.
+	Pref int
+}
+
+// Reports whether TYPE parameter of the handle contains typ compared case-insensitively.
+func (i Impp) HasType(typ string) bool {
+	return slices.ContainsFunc(i.Types, func(s string) bool { return strings.EqualFold(s, typ) })
+}
+
+// Returns lower-cased scheme of the URI e.g. "xmpp" or an empty string if the URI has no scheme.
+func (i Impp) Scheme() string {
+	scheme, _, found := strings.Cut(i.URI, ":")
+	if !found || !validScheme(scheme) {
+		return ""
+	}
+	return strings.ToLower(scheme)
+}
+
+// Returns the handle without scheme, leading slashes and query e.g. "alice@example.com"
+// of "xmpp:alice@example.com?message" or "alice" of "skype:alice?call".
+func (i Impp) Handle() string {
+	handle := i.URI
+	if scheme, rest, found := strings.Cut(i.URI, ":"); found && validScheme(scheme) {
+		handle = strings.TrimPrefix(rest, "//")
+	}
+	handle, _, _ = strings.Cut(handle, "?")
+	return handle
+}
+
+// Encodes the handle e.g. ";TYPE=work;PREF=1:xmpp:alice@example.com". Returns [ErrValidation]
+// if the URI has no scheme.
+func (i Impp) MarshalVCardField() ([]byte, error) {
+	if i.Scheme() == "" {
+		return nil, validationErrf("IMPP value %q has to be a URI with a scheme", i.URI)
+	}
+	b := appendParam([]byte{}, "TYPE", i.Types...)
+	b = appendPref(b, i.Pref)
+	b = append(b, ':')
+	return append(b, i.URI...), nil
+}
+
+// Decodes the handle from ";TYPE=work:xmpp:alice@example.com". Values without a scheme are
+// kept as is, see [Impp.Scheme].
+func (i *Impp) UnmarshalVCardField(data []byte) error {
+	params, value, _ := splitParamsValue(string(data))
+
+	i.URI = value
+	i.Types = withoutValue(paramValues(params, "TYPE"), "pref")
+	if len(i.Types) == 0 {
+		i.Types = nil
+	}
+	i.Pref = paramPref(params)
+	return nil
+}
+
+// Reports whether s is a URI scheme as per https://datatracker.ietf.org/doc/html/rfc3986#section-3.1
+func validScheme(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+		case i > 0 && (c >= '0' && c <= '9' || c == '+' || c == '-' || c == '.'):
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,53 @@
+package vcard
+
+import "testing"
+
+func TestImpp(t *testing.T) {
+
+	i := Impp{}
+	err := i.UnmarshalVCardField([]byte(";TYPE=work,pref:xmpp:alice@example.com?message"))
+
+	assertEq(t, err, nil)
+	assertSlicesEq(t, i.Types, []string{"work"})
+	assertEq(t, i.Pref, 1)
+	assertEq(t, i.HasType("WORK"), true)
+	assertStringsEq(t, i.Scheme(), "xmpp")
+	assertStringsEq(t, i.Handle(), "alice@example.com")
+
+	b, err := i.MarshalVCardField()
+	assertEq(t, err, nil)
+	assertStringsEq(t, string(b), ";TYPE=work;PREF=1:xmpp:alice@example.com?message")
+
+	assertStringsEq(t, Impp{URI: "SKYPE:alice.example?call"}.Scheme(), "skype")
+	assertStringsEq(t, Impp{URI: "skype:alice.example?call"}.Handle(), "alice.example")
+	assertStringsEq(t, Impp{URI: "sip:alice@example.com"}.Handle(), "alice@example.com")
+	assertStringsEq(t, Impp{URI: "alice"}.Scheme(), "")
+	assertStringsEq(t, Impp{URI: "alice"}.Handle(), "alice")
+
+	_, err = Impp{URI: "alice"}.MarshalVCardField()
+	assertErrIs(t, err, ErrValidation, "has to be a URI with a scheme")
+}
+
+func TestImppField(t *testing.T) {
+
+	type Contact struct {
+		FN   string `vCard:"required"`
+		IMPP Impp
+	}
+
+	text := crlfy(`BEGIN:VCARD
+VERSION:4.0
+FN:Alice
+IMPP;PREF=1:xmpp:alice@example.com
+END:VCARD
+`)
+	c := Contact{}
+	err := Unmarshal([]byte(text), &c)
+
+	assertEq(t, err, nil)
+	assertDeepEq(t, c.IMPP, Impp{URI: "xmpp:alice@example.com", Pref: 1})
+
+	b, err := Marshal(c)
+	assertEq(t, err, nil)
+	assertStringLinesEq(t, string(b), text)
+}
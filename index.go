@@ -0,0 +1,172 @@
+package vcard
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Read-only index of cards for lookups by UID, email address, telephone number and name:
+//
+//	index := vcard.NewIndex(cards)
+//	card, found := index.ByEmail("Alex@Example.com")
+//	matches := index.SearchName("ale")
+//
+// Email addresses are compared case-insensitively without "mailto:" and telephone numbers
+// by their last 10 digits, so "+1 (555) 010-0100" and "tel:555-010-0100" are the same number.
+// The index refers to cards it was built from, so they must not be modified while it is in use.
+type Index struct {
+	cards  []Card
+	uids   map[string]int
+	emails map[string][]int
+	tels   map[string][]int
+	names  []indexedName // sorted by word
+}
+
+type indexedName struct {
+	word string
+	card int
+}
+
+// Builds an index of cards. UIDs are expected to be unique; if they are not, the first card wins.
+func NewIndex(cards []Card) *Index {
+	index := &Index{
+		cards:  cards,
+		uids:   map[string]int{},
+		emails: map[string][]int{},
+		tels:   map[string][]int{},
+	}
+	for i := range cards {
+		k := dedupKeysOf(&cards[i])
+		if _, found := index.uids[k.uid]; k.uid != "" && !found {
+			index.uids[k.uid] = i
+		}
+		for _, e := range k.emails {
+			index.emails[e] = appendIndex(index.emails[e], i)
+		}
+		for _, t := range k.tels {
+			index.tels[t] = appendIndex(index.tels[t], i)
+		}
+		for _, w := range nameWords(&cards[i]) {
+			index.names = append(index.names, indexedName{w, i})
+		}
+	}
+	slices.SortFunc(index.names, func(a, b indexedName) int { return strings.Compare(a.word, b.word) })
+	return index
+}
+
+// Returns the number of indexed cards.
+func (x *Index) Len() int {
+	return len(x.cards)
+}
+
+// Returns a card with the given UID. UUIDs are compared regardless of "urn:uuid:" and case.
+func (x *Index) ByUID(uid string) (Card, bool) {
+	i, found := x.uids[UID(uid).Key()]
+	if !found {
+		return Card{}, false
+	}
+	return x.cards[i], true
+}
+
+// Returns the first card with the given email address.
+func (x *Index) ByEmail(address string) (Card, bool) {
+	return x.firstOf(x.emails[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(address, "mailto:")))])
+}
+
+// Returns the first card with the given telephone number.
+func (x *Index) ByTel(number string) (Card, bool) {
+	return x.firstOf(x.tels[telKey(number)])
+}
+
+// Returns cards which have the given email address in order of the index.
+func (x *Index) AllByEmail(address string) []Card {
+	return x.cardsOf(x.emails[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(address, "mailto:")))])
+}
+
+// Returns cards which have the given telephone number in order of the index.
+func (x *Index) AllByTel(number string) []Card {
+	return x.cardsOf(x.tels[telKey(number)])
+}
+
+// Returns cards with a word of FN or N starting with prefix in order of the index. Words are
+// compared case-insensitively and without diacritics, so "ale" finds "Álex Smith" and "smi" finds
+// it too. Prefix of several words e.g. "alex sm" matches cards having all of them.
+func (x *Index) SearchName(prefix string) []Card {
+	words := strings.FieldsFunc(foldKey(prefix), isNameSeparator)
+	if len(words) == 0 {
+		return []Card{}
+	}
+
+	var matched []int
+	for n, w := range words {
+		found := []int{}
+		start, _ := slices.BinarySearchFunc(x.names, w, func(e indexedName, w string) int { return strings.Compare(e.word, w) })
+		for _, e := range x.names[start:] {
+			if !strings.HasPrefix(e.word, w) {
+				break
+			}
+			found = append(found, e.card)
+		}
+		slices.Sort(found)
+		found = slices.Compact(found)
+		if n == 0 {
+			matched = found
+			continue
+		}
+		matched = slices.DeleteFunc(matched, func(i int) bool { return !slices.Contains(found, i) })
+	}
+	return x.cardsOf(matched)
+}
+
+func (x *Index) firstOf(indices []int) (Card, bool) {
+	if len(indices) == 0 {
+		return Card{}, false
+	}
+	return x.cards[indices[0]], true
+}
+
+func (x *Index) cardsOf(indices []int) []Card {
+	cards := make([]Card, len(indices))
+	for i, j := range indices {
+		cards[i] = x.cards[j]
+	}
+	return cards
+}
+
+// Appends i unless it is the last index already e.g. a card with the same email twice.
+func appendIndex(indices []int, i int) []int {
+	if len(indices) > 0 && indices[len(indices)-1] == i {
+		return indices
+	}
+	return append(indices, i)
+}
+
+// Returns the last 10 digits of a telephone number, so numbers with and without a country code match.
+func telKey(number string) string {
+	d := strings.TrimPrefix(digits(strings.TrimPrefix(number, "tel:")), "+")
+	if len(d) > 10 {
+		d = d[len(d)-10:]
+	}
+	return d
+}
+
+// Returns distinct folded words of FN and all components of N.
+func nameWords(c *Card) []string {
+	names := c.Values("FN")
+	if n, found := c.Name(); found {
+		for _, parts := range [][]string{n.FamilyNames, n.GivenNames, n.AdditionalNames} {
+			names = append(names, parts...)
+		}
+	}
+	words := []string{}
+	for _, name := range names {
+		words = append(words, strings.FieldsFunc(foldKey(name), isNameSeparator)...)
+	}
+	slices.Sort(words)
+	return slices.Compact(words)
+}
+
+func isNameSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
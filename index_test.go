@@ -0,0 +1,62 @@
+package vcard
+
+import "testing"
+
+func TestIndex(t *testing.T) {
+
+	cards := []Card{}
+	err := Unmarshal([]byte(crlfy(`BEGIN:VCARD
+VERSION:4.0
+UID:urn:uuid:4FBE8971-0BC3-424C-9C26-36C3E1EFF6B1
+FN:Álex Smith
+N:Smith;Álex;;;
+EMAIL:Alex@Example.com
+TEL;VALUE=uri:tel:+1-555-010-0100
+END:VCARD
+BEGIN:VCARD
+VERSION:4.0
+UID:bob
+FN:Bob Smithers
+EMAIL:mailto:bob@example.com
+EMAIL:alex@example.com
+END:VCARD
+`)), &cards)
+	assertEq(t, err, nil)
+
+	index := NewIndex(cards)
+	assertEq(t, index.Len(), 2)
+
+	fn := func(c Card, found bool) string {
+		if !found {
+			return "<none>"
+		}
+		name, _ := c.FN()
+		return name
+	}
+	fns := func(cards []Card) []string {
+		names := []string{}
+		for _, c := range cards {
+			name, _ := c.FN()
+			names = append(names, name)
+		}
+		return names
+	}
+
+	assertEq(t, fn(index.ByUID("4fbe8971-0bc3-424c-9c26-36c3e1eff6b1")), "Álex Smith")
+	assertEq(t, fn(index.ByUID("bob")), "Bob Smithers")
+	assertEq(t, fn(index.ByUID("alex")), "<none>")
+
+	assertEq(t, fn(index.ByEmail(" ALEX@example.com")), "Álex Smith")
+	assertEq(t, fn(index.ByEmail("mailto:Bob@Example.com")), "Bob Smithers")
+	assertSlicesEq(t, fns(index.AllByEmail("alex@example.com")), []string{"Álex Smith", "Bob Smithers"})
+
+	assertEq(t, fn(index.ByTel("(555) 010 0100")), "Álex Smith")
+	assertEq(t, fn(index.ByTel("+15550100")), "<none>")
+	assertSlicesEq(t, fns(index.AllByTel("+1 555 010 0100")), []string{"Álex Smith"})
+
+	assertSlicesEq(t, fns(index.SearchName("smi")), []string{"Álex Smith", "Bob Smithers"})
+	assertSlicesEq(t, fns(index.SearchName("ale")), []string{"Álex Smith"})
+	assertSlicesEq(t, fns(index.SearchName("b SMITH")), []string{"Bob Smithers"})
+	assertSlicesEq(t, fns(index.SearchName("smithy")), []string{})
+	assertSlicesEq(t, fns(index.SearchName(" ")), []string{})
+}
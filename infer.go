@@ -0,0 +1,117 @@
+package vcard
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Scans a .vcf corpus and creates a schema for every vCard version found in it.
+//
+// Schema contains every property seen in cards of the version, including X- extensions.
+// Properties present in every card of the version are required. Schemas are ordered by version.
+//
+// Useful to bootstrap integration with an unknown producer, see [GenerateStruct].
+func InferSchemas(r io.Reader) ([]Schema, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, vCardErrf("unable to read: %w", err)
+	}
+	cards, err := parseCards(string(b))
+	if err != nil {
+		return nil, err
+	}
+
+	type stats struct {
+		cards  int
+		counts map[string]int
+	}
+	versions := map[string]*stats{}
+
+	for _, c := range cards {
+		v := c.Version()
+		st, found := versions[v]
+		if !found {
+			st = &stats{counts: map[string]int{}}
+			versions[v] = st
+		}
+		st.cards++
+
+		seen := map[string]struct{}{}
+		for _, p := range c.props {
+			name := strings.ToUpper(p.Name)
+			if name == "VERSION" {
+				continue
+			}
+			if _, found := seen[name]; found {
+				continue
+			}
+			seen[name] = struct{}{}
+			st.counts[name]++
+		}
+	}
+
+	schemas := make([]Schema, 0, len(versions))
+	for _, v := range slices.Sorted(maps.Keys(versions)) {
+		st := versions[v]
+
+		fields := slices.Sorted(maps.Keys(st.counts))
+		required := []string{}
+		for _, name := range fields {
+			if st.counts[name] == st.cards {
+				required = append(required, name)
+			}
+		}
+		schemas = append(schemas, NewSchema(v, fields, required))
+	}
+	return schemas, nil
+}
+
+// Generates source code of a Go struct named typeName for schema e.g. one created by [InferSchemas].
+//
+// Every field of the struct is a string. Property names which are not valid Go identifiers
+// like X-ABLabel are mapped to fields like X_ABLABEL tagged `vCard:"X-ABLABEL"`.
+func GenerateStruct(schema Schema, typeName string) ([]byte, error) {
+	var b bytes.Buffer
+
+	fmt.Fprintf(&b, "// %s is a vCard %s schema.\n", typeName, schema.version)
+	fmt.Fprintf(&b, "type %s struct {\n", typeName)
+
+	for _, name := range slices.Sorted(maps.Keys(schema.fields)) {
+		ident := strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+
+		opts := []string{}
+		if ident != name {
+			opts = append(opts, name)
+		}
+		c := schema.cardinalityOf(name)
+		switch c {
+		case OneOrMore:
+			// Single "required" keeps the name of a field
+			opts = append(opts, "required")
+		case ZeroOrMore:
+		default:
+			if len(opts) == 0 {
+				opts = append(opts, "")
+			}
+			opts = append(opts, "cardinality="+c.String())
+		}
+
+		if len(opts) == 0 {
+			fmt.Fprintf(&b, "\t%s string\n", ident)
+		} else {
+			fmt.Fprintf(&b, "\t%s string `vCard:%q`\n", ident, strings.Join(opts, ","))
+		}
+	}
+	b.WriteString("}\n")
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, vCardErrf("unable to generate struct %s: %w", typeName, err)
+	}
+	return src, nil
+}
@@ -0,0 +1,61 @@
+package vcard
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInferSchemas(t *testing.T) {
+
+	text := `BEGIN:VCARD
+VERSION:3.0
+FN:Alex
+N:;Alex;;;
+item1.X-ABLabel:Work
+END:VCARD
+BEGIN:VCARD
+VERSION:3.0
+FN:Sam
+TEL:555
+TEL:777
+END:VCARD
+BEGIN:VCARD
+VERSION:4.0
+FN:Kim
+END:VCARD
+`
+	schemas, err := InferSchemas(strings.NewReader(crlfy(text)))
+
+	assertEq(t, err, nil)
+	assertEq(t, len(schemas), 2)
+
+	assertEq(t, schemas[0].version, "3.0")
+	assertMapsEq(t, schemas[0].fields, map[string]struct{}{"FN": {}, "N": {}, "X-ABLABEL": {}, "TEL": {}})
+	assertSlicesEq(t, schemas[0].Required(), []string{"FN"})
+
+	assertEq(t, schemas[1].version, "4.0")
+	assertMapsEq(t, schemas[1].fields, map[string]struct{}{"FN": {}})
+}
+
+func TestGenerateStruct(t *testing.T) {
+
+	schema := NewSchemaBuilder("3.0").
+		Field("FN", Required).
+		Field("X-ABLABEL", Optional).
+		Field("X-DEPT", Required).
+		Field("KIND", ZeroOrOne).
+		Build()
+
+	src, err := GenerateStruct(schema, "Contact")
+
+	exp := "// Contact is a vCard 3.0 schema.\n" +
+		"type Contact struct {\n" +
+		"\tFN        string `vCard:\"required\"`\n" +
+		"\tKIND      string `vCard:\",cardinality=*1\"`\n" +
+		"\tX_ABLABEL string `vCard:\"X-ABLABEL\"`\n" +
+		"\tX_DEPT    string `vCard:\"X-DEPT,required\"`\n" +
+		"}\n"
+
+	assertEq(t, err, nil)
+	assertStringsEq(t, string(src), exp)
+}
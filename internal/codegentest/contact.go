@@ -0,0 +1,14 @@
+// Package codegentest checks code generated by cmd/vcardgen against reflection based codec.
+package codegentest
+
+//go:generate go run github.com/ioannuwu/vcard/cmd/vcardgen -type Contact -version 4.0
+
+type Contact struct {
+	FN     string `vCard:"required"`
+	Phone  string `vCard:"TEL,omitempty"`
+	Email  string `vCard:"EMAIL,omitempty"`
+	Kind   string `vCard:"KIND,default=individual"`
+	Gender string `vCard:"GENDER,omitempty,alias=X-GENDER"`
+	Note   string `vCard:"NOTE"`
+	Secret string `vCard:"-"`
+}
@@ -0,0 +1,156 @@
+package codegentest
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/ioannuwu/vcard"
+)
+
+// Same struct without generated methods, so it is encoded using reflection
+type reflectContact Contact
+
+var contactSchema = vcard.SchemaFor[reflectContact]("4.0")
+
+func TestGeneratedMatchesReflection(t *testing.T) {
+
+	contacts := []Contact{
+		{FN: "Alex", Phone: ";TYPE=CELL:555", Email: "alex@example.com", Gender: "M", Note: "Friend", Secret: "x"},
+		{FN: "Sam", Kind: "org"},
+	}
+	for _, c := range contacts {
+		generated, err := c.MarshalVCard()
+		if err != nil {
+			t.Fatal(err)
+		}
+		reflected, err := vcard.MarshalSchema(reflectContact(c), contactSchema)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(generated, reflected) {
+			t.Fatalf("generated:\n%s\nreflection:\n%s", generated, reflected)
+		}
+
+		decoded := Contact{}
+		err = decoded.UnmarshalVCard(generated)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.Secret = ""
+		if c.Kind == "" {
+			c.Kind = "individual"
+		}
+		if decoded != c {
+			t.Fatalf("decoded %+v, expected %+v", decoded, c)
+		}
+	}
+}
+
+func TestGeneratedRepeatedProperty(t *testing.T) {
+
+	data := []byte("BEGIN:VCARD\r\nVERSION:4.0\r\nFN:Alex\r\nNOTE:a\r\nNOTE:b\r\nEND:VCARD\r\n")
+
+	generated := Contact{}
+	if err := generated.UnmarshalVCard(data); err != nil {
+		t.Fatal(err)
+	}
+	reflected := reflectContact{}
+	if err := vcard.UnmarshalSchema(data, &reflected, []vcard.Schema{contactSchema}); err != nil {
+		t.Fatal(err)
+	}
+	if generated.Note != "b" || generated != Contact(reflected) {
+		t.Fatalf("generated %+v, reflection %+v", generated, reflected)
+	}
+}
+
+func TestGeneratedWithEncoderAndDecoder(t *testing.T) {
+
+	b, err := vcard.Marshal([]Contact{{FN: "Alex"}, {FN: "Sam"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := []Contact{}
+	err = vcard.Unmarshal(b, &decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != 2 || decoded[1].FN != "Sam" {
+		t.Fatalf("unexpected %+v", decoded)
+	}
+}
+
+func TestGeneratedWithCodecs(t *testing.T) {
+
+	contacts := []Contact{{FN: "Alex", Phone: ";TYPE=CELL:555"}, {FN: "Sam"}}
+	b, err := vcard.MarshalCodecs(contacts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reflected, err := vcard.Marshal(contacts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b, reflected) {
+		t.Fatalf("codecs:\n%s\nreflection:\n%s", b, reflected)
+	}
+
+	decoded, err := vcard.UnmarshalCodecs[Contact](append([]byte("\r\n"), b...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != 2 || decoded[0].Phone != ";TYPE=CELL:555" || decoded[1].FN != "Sam" {
+		t.Fatalf("unexpected %+v", decoded)
+	}
+
+	_, err = vcard.MarshalCodecs([]Contact{{FN: "Alex"}, {}})
+	if !errors.Is(err, vcard.ErrVCard) {
+		t.Fatalf("expected ErrVCard, got %v", err)
+	}
+	decoded, err = vcard.UnmarshalCodecs[Contact]([]byte("BEGIN:VCARD\r\nVERSION:4.0\r\nFN:Alex\r\nEND:VCARD\r\nBEGIN:VCARD\r\nVERSION:4.0\r\nEND:VCARD\r\n"))
+	if !errors.Is(err, vcard.ErrParsing) || len(decoded) != 1 {
+		t.Fatalf("expected ErrParsing after a single contact, got %+v %v", decoded, err)
+	}
+}
+
+func TestGeneratedAliasesAndErrors(t *testing.T) {
+
+	c := Contact{}
+	err := c.UnmarshalVCard([]byte("BEGIN:VCARD\r\nVERSION:3.0\r\nFN:Alex\r\nX-GENDER:F\r\nEND:VCARD\r\n"))
+	if err != nil || c.Gender != "F" {
+		t.Fatalf("unexpected %+v %v", c, err)
+	}
+
+	err = c.UnmarshalVCard([]byte("BEGIN:VCARD\r\nVERSION:4.0\r\nNOTE:x\r\nEND:VCARD\r\n"))
+	if !errors.Is(err, vcard.ErrParsing) {
+		t.Fatalf("expected ErrParsing, got %v", err)
+	}
+
+	_, err = Contact{}.MarshalVCard()
+	if !errors.Is(err, vcard.ErrVCard) {
+		t.Fatalf("expected ErrVCard, got %v", err)
+	}
+}
+
+func TestGeneratedUpToDate(t *testing.T) {
+
+	fields := []vcard.CodecField{}
+	typ := reflect.TypeFor[Contact]()
+	for i := range typ.NumField() {
+		f := typ.Field(i)
+		fields = append(fields, vcard.CodecField{Name: f.Name, Type: f.Type.String(), Tag: f.Tag})
+	}
+	src, err := vcard.GenerateCodec("codegentest", "Contact", "4.0", fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := os.ReadFile("contact_vcard.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(src, expected) {
+		t.Fatal("contact_vcard.go is out of date, run go generate")
+	}
+}
@@ -0,0 +1,72 @@
+// Code generated by vcardgen. DO NOT EDIT.
+
+package codegentest
+
+import (
+	"fmt"
+
+	"github.com/ioannuwu/vcard"
+)
+
+// Encodes v as a vCard 4.0 record. Implements [vcard.VCardMarshaler].
+func (v Contact) MarshalVCard() ([]byte, error) {
+	b := vcard.AppendRecordHeader(nil, "4.0")
+	if v.FN != "" {
+		b = vcard.AppendString(b, "FN", v.FN)
+	} else {
+		return nil, fmt.Errorf("%w: field %q of a struct %s is required but empty", vcard.ErrVCard, "FN", "Contact")
+	}
+	if v.Phone != "" {
+		b = vcard.AppendString(b, "TEL", v.Phone)
+	}
+	if v.Email != "" {
+		b = vcard.AppendString(b, "EMAIL", v.Email)
+	}
+	if v.Kind != "" {
+		b = vcard.AppendString(b, "KIND", v.Kind)
+	} else {
+		b = vcard.AppendString(b, "KIND", "individual")
+	}
+	if v.Gender != "" {
+		b = vcard.AppendString(b, "GENDER", v.Gender)
+	}
+	if v.Note != "" {
+		b = vcard.AppendString(b, "NOTE", v.Note)
+	} else {
+		b = vcard.AppendString(b, "NOTE", "")
+	}
+	return vcard.AppendRecordFooter(b), nil
+}
+
+// Decodes a single vCard record into v. Implements [vcard.VCardUnmarshaler].
+func (v *Contact) UnmarshalVCard(data []byte) error {
+	c, err := vcard.ParseRecord(data)
+	if err != nil {
+		return err
+	}
+	if s, found := c.Field("FN"); found {
+		v.FN = s
+	} else {
+		return fmt.Errorf("%w: document does not contain a field %q required by the schema", vcard.ErrParsing, "FN")
+	}
+	if s, found := c.Field("TEL"); found {
+		v.Phone = s
+	}
+	if s, found := c.Field("EMAIL"); found {
+		v.Email = s
+	}
+	if s, found := c.Field("KIND"); found {
+		v.Kind = s
+	} else {
+		v.Kind = "individual"
+	}
+	if s, found := c.Field("GENDER"); found {
+		v.Gender = s
+	} else if s, found := c.Field("X-GENDER"); found {
+		v.Gender = s
+	}
+	if s, found := c.Field("NOTE"); found {
+		v.Note = s
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package vcard
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"strings"
+)
+
+// Media types of public keys.
+const (
+	MediaTypePGPKeys = "application/pgp-keys"
+	MediaTypeX509    = "application/pkix-cert"
+)
+
+// Value of KEY property as per https://datatracker.ietf.org/doc/html/rfc6350#section-6.8.1
+//
+// Key either references a public key or certificate by URI or embeds it. See [Photo] for
+// encodings used by different vCard versions. TYPE=PGP and TYPE=X509 of older versions are
+// decoded as [MediaTypePGPKeys] and [MediaTypeX509].
+type Key struct {
+	// External URI e.g. "http://example.com/key.pgp". Empty if the key is embedded.
+	URI string
+
+	// Embedded key either in binary or ASCII-armored/PEM form. Nil if the key is referenced by URI.
+	Data []byte
+
+	// Media type e.g. [MediaTypePGPKeys] or [MediaTypeX509].
+	MediaType string
+}
+
+// Reports whether the key is embedded into a vCard.
+func (k Key) IsEmbedded() bool {
+	return k.Data != nil
+}
+
+// Returns DER bytes of an embedded X.509 certificate. PEM encoded certificates are decoded.
+func (k Key) DER() ([]byte, error) {
+	if k.Data == nil {
+		return nil, vCardErrf("key is not embedded")
+	}
+	if !bytes.HasPrefix(bytes.TrimSpace(k.Data), []byte("-----BEGIN")) {
+		return k.Data, nil
+	}
+	block, _ := pem.Decode(k.Data)
+	if block == nil {
+		return nil, parsingErrf("unable to decode PEM block of the key")
+	}
+	return block.Bytes, nil
+}
+
+// Parses an embedded X.509 certificate.
+func (k Key) Certificate() (*x509.Certificate, error) {
+	der, err := k.DER()
+	if err != nil {
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, parsingErrf("unable to parse certificate: %w", err)
+	}
+	return cert, nil
+}
+
+// Returns an embedded OpenPGP key in ASCII-armored form as per https://datatracker.ietf.org/doc/html/rfc4880#section-6.2
+// Binary keys are armored, armored keys are returned as is.
+func (k Key) Armored() ([]byte, error) {
+	if k.Data == nil {
+		return nil, vCardErrf("key is not embedded")
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(k.Data), []byte("-----BEGIN PGP")) {
+		return k.Data, nil
+	}
+
+	b := strings.Builder{}
+	b.WriteString("-----BEGIN PGP PUBLIC KEY BLOCK-----\n\n")
+	data := base64.StdEncoding.EncodeToString(k.Data)
+	for len(data) > 64 {
+		b.WriteString(data[:64])
+		b.WriteByte('\n')
+		data = data[64:]
+	}
+	b.WriteString(data)
+	b.WriteString("\n=")
+
+	crc := crc24(k.Data)
+	b.WriteString(base64.StdEncoding.EncodeToString([]byte{byte(crc >> 16), byte(crc >> 8), byte(crc)}))
+	b.WriteString("\n-----END PGP PUBLIC KEY BLOCK-----\n")
+
+	return []byte(b.String()), nil
+}
+
+// Computes CRC-24 checksum of ASCII armor as per https://datatracker.ietf.org/doc/html/rfc4880#section-6.1
+func crc24(data []byte) uint32 {
+	crc := uint32(0xb704ce)
+	for _, b := range data {
+		crc ^= uint32(b) << 16
+		for range 8 {
+			crc <<= 1
+			if crc&0x1000000 != 0 {
+				crc ^= 0x1864cfb
+			}
+		}
+	}
+	return crc & 0xffffff
+}
+
+func (k Key) media() media {
+	return media{k.URI, k.Data, k.MediaType}
+}
+
+// Encodes the key as vCard 4.0 data: URI or URI.
+func (k Key) MarshalVCardField() ([]byte, error) {
+	return k.MarshalVCardFieldVersion("4.0")
+}
+
+// Encodes the key in a form defined by the vCard version. Empty Key is omitted.
+func (k Key) MarshalVCardFieldVersion(version string) ([]byte, error) {
+	return k.media().marshal(version), nil
+}
+
+// Decodes the key from a URI, a data: URI or a base64 value with ENCODING parameter.
+func (k *Key) UnmarshalVCardField(data []byte) error {
+	m, err := unmarshalMedia(data, "application")
+	if err != nil {
+		return err
+	}
+	*k = Key{m.uri, m.data, m.mediaType}
+	return nil
+}
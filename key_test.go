@@ -0,0 +1,105 @@
+package vcard
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestKeyUnmarshal(t *testing.T) {
+
+	cases := map[string]Key{
+		";MEDIATYPE=application/pgp-keys:ftp://example.com/keys/jdoe": {URI: "ftp://example.com/keys/jdoe", MediaType: MediaTypePGPKeys},
+		":data:application/pgp-keys;base64,AQID":                      {Data: []byte{1, 2, 3}, MediaType: MediaTypePGPKeys},
+		";ENCODING=b;TYPE=X509:AQID":                                  {Data: []byte{1, 2, 3}, MediaType: MediaTypeX509},
+		";ENCODING=BASE64;TYPE=PGP:AQID":                              {Data: []byte{1, 2, 3}, MediaType: MediaTypePGPKeys},
+	}
+	for data, exp := range cases {
+		k := Key{}
+		err := k.UnmarshalVCardField([]byte(data))
+
+		assertEq(t, err, nil)
+		assertDeepEq(t, k, exp)
+	}
+}
+
+func TestKeyMarshal(t *testing.T) {
+
+	k := Key{Data: []byte{1, 2, 3}, MediaType: MediaTypeX509}
+
+	b, err := k.MarshalVCardField()
+	assertEq(t, err, nil)
+	assertStringsEq(t, string(b), ":data:application/pkix-cert;base64,AQID")
+
+	b, err = k.MarshalVCardFieldVersion("3.0")
+	assertEq(t, err, nil)
+	assertStringsEq(t, string(b), ";ENCODING=b;TYPE=X509:AQID")
+
+	b, err = Key{URI: "http://example.com/key.asc", MediaType: MediaTypePGPKeys}.MarshalVCardFieldVersion("3.0")
+	assertEq(t, err, nil)
+	assertStringsEq(t, string(b), ";VALUE=uri;TYPE=PGP:http://example.com/key.asc")
+}
+
+func TestKeyCertificate(t *testing.T) {
+
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assertEq(t, err, nil)
+
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "Alex"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &priv.PublicKey, priv)
+	assertEq(t, err, nil)
+
+	for _, data := range [][]byte{der, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})} {
+		k := Key{Data: data, MediaType: MediaTypeX509}
+
+		decoded, err := k.DER()
+		assertEq(t, err, nil)
+		assertSlicesEq(t, decoded, der)
+
+		cert, err := k.Certificate()
+		assertEq(t, err, nil)
+		assertEq(t, cert.Subject.CommonName, "Alex")
+	}
+
+	_, err = Key{URI: "http://example.com"}.DER()
+	assertErrIs(t, err, ErrVCard, "not embedded")
+
+	_, err = Key{Data: []byte("-----BEGIN nothing")}.Certificate()
+	assertErrIs(t, err, ErrParsing, "unable to decode PEM")
+}
+
+func TestKeyArmored(t *testing.T) {
+
+	assertEq(t, crc24([]byte("123456789")), uint32(0x21cf02))
+
+	armored, err := Key{Data: []byte("123456789"), MediaType: MediaTypePGPKeys}.Armored()
+
+	exp := `-----BEGIN PGP PUBLIC KEY BLOCK-----
+
+MTIzNDU2Nzg5
+=Ic8C
+-----END PGP PUBLIC KEY BLOCK-----
+`
+	assertEq(t, err, nil)
+	assertStringsEq(t, string(armored), exp)
+
+	again, err := Key{Data: armored}.Armored()
+	assertEq(t, err, nil)
+	assertStringsEq(t, string(again), exp)
+
+	long, err := Key{Data: []byte(strings.Repeat("x", 100))}.Armored()
+	assertEq(t, err, nil)
+	assertEq(t, strings.Count(string(long), "\n"), 7)
+}
@@ -0,0 +1,175 @@
+package vcard
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+	"strings"
+)
+
+// Value of LANG property as per https://datatracker.ietf.org/doc/html/rfc6350#section-6.4.4
+//
+// Lang implements [VCardFieldMarshaler] and [VCardFieldUnmarshaler], so it can be used as a type
+// of struct fields instead of string. See [Tel].
+type Lang struct {
+	// BCP 47 language tag e.g. "en-US".
+	Tag string
+
+	// Values of TYPE parameter e.g. "work", "home".
+	Types []string
+
+	// Preference in range 1..100 where 1 is the most preferred. 0 means no preference.
+	Pref int
+}
+
+// Encodes the language e.g. ";TYPE=work;PREF=1:en-US". Returns [*ValidationError] if the tag
+// is not a well-formed BCP 47 language tag, see [ValidLanguageTag].
+func (l Lang) MarshalVCardField() ([]byte, error) {
+	err := ValidLanguageTag(l.Tag)
+	if err != nil {
+		return nil, &ValidationError{"LANG", l.Tag, err}
+	}
+	b := appendParam([]byte{}, "TYPE", l.Types...)
+	b = appendPref(b, l.Pref)
+	b = append(b, ':')
+	return append(b, l.Tag...), nil
+}
+
+// Decodes the language from ";PREF=1:en-US". Returns [*ValidationError] if the tag is not
+// a well-formed BCP 47 language tag.
+func (l *Lang) UnmarshalVCardField(data []byte) error {
+	params, value, _ := splitParamsValue(string(data))
+
+	err := ValidLanguageTag(value)
+	if err != nil {
+		return &ValidationError{"LANG", value, err}
+	}
+	l.Tag = value
+	l.Types = withoutValue(paramValues(params, "TYPE"), "pref")
+	if len(l.Types) == 0 {
+		l.Types = nil
+	}
+	l.Pref = paramPref(params)
+	return nil
+}
+
+// Returns LANG properties of the card ordered by preference. Languages without preference
+// follow preferred ones in order of appearance. Properties with malformed tags are skipped.
+func (c *Card) Languages() []Lang {
+	langs := []Lang{}
+	for _, p := range c.props {
+		if p.Name != "LANG" {
+			continue
+		}
+		l := Lang{}
+		if l.UnmarshalVCardField([]byte(p.rest())) == nil {
+			langs = append(langs, l)
+		}
+	}
+	slices.SortStableFunc(langs, func(a, b Lang) int {
+		return cmp.Compare(prefOrder(a.Pref), prefOrder(b.Pref))
+	})
+	return langs
+}
+
+// Maps "no preference" to the lowest preference for ordering.
+func prefOrder(pref int) int {
+	if pref < 1 || pref > 100 {
+		return 101
+	}
+	return pref
+}
+
+// Accepts well-formed BCP 47 language tags as per https://datatracker.ietf.org/doc/html/rfc5646#section-2.1
+// e.g. "en", "de-CH", "zh-Hant-TW", "sl-rozaj-biske" or "x-klingon". Tags are checked syntactically,
+// subtags are not looked up in the IANA registry.
+func ValidLanguageTag(value string) error {
+	if value == "" {
+		return errors.New("language tag is empty")
+	}
+	subtags := strings.Split(value, "-")
+	for _, s := range subtags {
+		if len(s) == 0 || len(s) > 8 || !isAlphanum(s) {
+			return errors.New("language tag has malformed subtag")
+		}
+	}
+	if strings.EqualFold(subtags[0], "x") || strings.EqualFold(subtags[0], "i") {
+		return validPrivateUse(subtags)
+	}
+
+	lang := subtags[0]
+	if len(lang) < 2 || !isAlpha(lang) {
+		return errors.New("primary language subtag has to contain 2-8 letters")
+	}
+	i := 1
+	if len(lang) <= 3 {
+		for n := 0; n < 3 && i < len(subtags) && len(subtags[i]) == 3 && isAlpha(subtags[i]); n++ {
+			i++ // extlang
+		}
+	}
+	if i < len(subtags) && len(subtags[i]) == 4 && isAlpha(subtags[i]) {
+		i++ // script
+	}
+	if i < len(subtags) && (len(subtags[i]) == 2 && isAlpha(subtags[i]) || len(subtags[i]) == 3 && isDigits(subtags[i])) {
+		i++ // region
+	}
+	for i < len(subtags) && isVariant(subtags[i]) {
+		i++
+	}
+	for i < len(subtags) && len(subtags[i]) == 1 && !strings.EqualFold(subtags[i], "x") {
+		i++ // extension singleton
+		n := 0
+		for ; i < len(subtags) && len(subtags[i]) >= 2; i++ {
+			n++
+		}
+		if n == 0 {
+			return errors.New("extension of language tag is empty")
+		}
+	}
+	if i < len(subtags) && strings.EqualFold(subtags[i], "x") {
+		return validPrivateUse(subtags[i:])
+	}
+	if i < len(subtags) {
+		return errors.New("language tag has subtags in unexpected order")
+	}
+	return nil
+}
+
+func validPrivateUse(subtags []string) error {
+	if len(subtags) < 2 {
+		return errors.New("private use of language tag is empty")
+	}
+	return nil
+}
+
+func isVariant(s string) bool {
+	return len(s) >= 5 || len(s) == 4 && s[0] >= '0' && s[0] <= '9'
+}
+
+func isAlpha(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i] | 0x20
+		if c < 'a' || c > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func isAlphanum(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !isAlpha(s[i:i+1]) && !isDigits(s[i:i+1]) {
+			return false
+		}
+	}
+	return true
+}
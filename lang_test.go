@@ -0,0 +1,54 @@
+package vcard
+
+import "testing"
+
+func TestValidLanguageTag(t *testing.T) {
+
+	for _, tag := range []string{"en", "de-CH", "zh-Hant-TW", "sl-rozaj-biske", "es-419", "x-klingon",
+		"en-US-u-ca-gregory", "de-1996", "zh-yue-HK", "en-x-private", "i-klingon"} {
+		assertEq(t, ValidLanguageTag(tag), nil)
+	}
+	for _, tag := range []string{"", "e", "en_US", "en--US", "en-US-u", "toolongtag1", "12", "en-x", "en-US-Hant"} {
+		if ValidLanguageTag(tag) == nil {
+			t.Errorf("Tag %q is expected to be rejected", tag)
+		}
+	}
+}
+
+func TestLang(t *testing.T) {
+
+	l := Lang{}
+	err := l.UnmarshalVCardField([]byte(";TYPE=work;PREF=2:fr-CA"))
+
+	assertEq(t, err, nil)
+	assertDeepEq(t, l, Lang{"fr-CA", []string{"work"}, 2})
+
+	b, err := l.MarshalVCardField()
+	assertEq(t, err, nil)
+	assertStringsEq(t, string(b), ";TYPE=work;PREF=2:fr-CA")
+
+	_, err = Lang{Tag: "en_US"}.MarshalVCardField()
+	assertErrIs(t, err, ErrValidation, `property LANG has invalid value "en_US"`)
+}
+
+func TestCardLanguages(t *testing.T) {
+
+	text := `BEGIN:VCARD
+VERSION:4.0
+FN:Alex
+LANG:de
+LANG;PREF=2:en
+LANG:not a tag
+LANG;TYPE=work;PREF=1:fr
+END:VCARD
+`
+	c := Card{}
+	err := Unmarshal([]byte(crlfy(text)), &c)
+	assertEq(t, err, nil)
+
+	assertDeepEq(t, c.Languages(), []Lang{
+		{Tag: "fr", Types: []string{"work"}, Pref: 1},
+		{Tag: "en", Pref: 2},
+		{Tag: "de"},
+	})
+}
@@ -0,0 +1,375 @@
+package vcard
+
+import (
+	"bufio"
+	"encoding/base64"
+	"errors"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Reads inetOrgPerson entries of LDIF as per https://datatracker.ietf.org/doc/html/rfc2849 e.g. exported
+// by ldapsearch. Change records other than "changetype: add" are skipped.
+//
+// Attributes are mapped as follows, other attributes are dropped:
+//
+//	cn, displayName                     FN, displayName is preferred
+//	sn, givenName                       N
+//	mail                                EMAIL
+//	telephoneNumber                     TEL;TYPE=work
+//	mobile, homePhone, pager            TEL;TYPE=cell, home, pager
+//	facsimileTelephoneNumber            TEL;TYPE=fax
+//	o, ou, title                        ORG, TITLE
+//	street, l, st, postalCode,
+//	postOfficeBox, postalAddress        ADR;TYPE=work with postalAddress as LABEL
+//	homePostalAddress                   ADR;TYPE=home
+//	labeledURI                          URL
+//	description                         NOTE
+//	jpegPhoto                           PHOTO
+//
+// Lines of postalAddress and homePostalAddress become the street when the entry has no other
+// address components. Use [Contact.Card] to get cards of a version. Returns [ErrParsing] if LDIF is malformed.
+func ReadLDIF(r io.Reader) ([]Contact, error) {
+	records, err := readLDIFRecords(r)
+	if err != nil {
+		return nil, err
+	}
+
+	contacts := []Contact{}
+	for _, record := range records {
+		if changeType := record.get("changetype"); changeType != "" && !strings.EqualFold(changeType, "add") {
+			continue
+		}
+
+		c := Contact{
+			FormattedName: record.get("cn"),
+			Name:          Name{FamilyNames: record.values("sn"), GivenNames: record.values("givenName")},
+			Org:           Org{Name: record.get("o"), Units: record.values("ou")},
+			Title:         record.get("title"),
+			Notes:         record.values("description"),
+		}
+		if displayName := record.get("displayName"); displayName != "" {
+			c.FormattedName = displayName
+		}
+		for _, mail := range record.values("mail") {
+			c.Emails = append(c.Emails, Email{Address: mail})
+		}
+		for _, phone := range ldifPhones {
+			for _, number := range record.values(phone.attribute) {
+				c.Phones = append(c.Phones, Tel{Number: number, Types: phone.types})
+			}
+		}
+		for _, uri := range record.values("labeledURI") {
+			uri, _, _ = strings.Cut(uri, " ")
+			c.URLs = append(c.URLs, uri)
+		}
+
+		work := Adr{
+			POBox:      record.get("postOfficeBox"),
+			Street:     record.get("street"),
+			Locality:   record.get("l"),
+			Region:     record.get("st"),
+			PostalCode: record.get("postalCode"),
+			Label:      decodePostalAddress(record.get("postalAddress")),
+			Types:      []string{"work"},
+		}
+		if work.String() == "" {
+			work.Street, work.Label = work.Label, ""
+		}
+		if work.String() != "" {
+			c.Addresses = append(c.Addresses, work)
+		}
+		if home := decodePostalAddress(record.get("homePostalAddress")); home != "" {
+			c.Addresses = append(c.Addresses, Adr{Street: home, Types: []string{"home"}})
+		}
+
+		if photo := record.get("jpegPhoto"); photo != "" {
+			c.Photo = Photo{Data: []byte(photo), MediaType: "image/jpeg"}
+		}
+		contacts = append(contacts, c)
+	}
+	return contacts, nil
+}
+
+// Writes contacts as LDIF entries of inetOrgPerson with DN like "cn=Alex Smith,<baseDN>" e.g.
+// baseDN "ou=people,dc=example,dc=com". See [ReadLDIF] for the mapping. Use [ContactOf] to write cards.
+//
+// Phones are mapped by their first known type: cell, fax, pager and home, others are written as
+// telephoneNumber. The most preferred work or untyped address and the most preferred home address
+// are written. The photo is written only if it is an embedded JPEG since jpegPhoto can't hold other images.
+// sn is required by the schema, so FN is written as sn when the contact has no family name.
+//
+// Returns [ErrValidation] if a contact has no name to write cn. Entries with the same cn get
+// the same DN, which directories reject, so such contacts have to be made distinct by FN.
+func WriteLDIF(w io.Writer, contacts []Contact, baseDN string) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString("version: 1\n")
+
+	for i, c := range contacts {
+		cn := strings.TrimSpace(c.FormattedName)
+		if cn == "" {
+			cn = displayName(c.Name, c.Org)
+		}
+		if cn == "" {
+			return validationErrf("contact %d has no name required by cn of LDIF", i)
+		}
+		sn := strings.Join(c.Name.FamilyNames, " ")
+		if sn == "" {
+			sn = cn
+		}
+
+		dn := "cn=" + escapeDNValue(cn)
+		if baseDN != "" {
+			dn += "," + baseDN
+		}
+		attr := func(name string, value string) {
+			if value != "" {
+				writeLDIFAttribute(bw, name, []byte(value))
+			}
+		}
+
+		bw.WriteString("\n")
+		attr("dn", dn)
+		for _, class := range []string{"top", "person", "organizationalPerson", "inetOrgPerson"} {
+			attr("objectClass", class)
+		}
+		attr("cn", cn)
+		attr("sn", sn)
+		attr("givenName", strings.Join(c.Name.GivenNames, " "))
+		attr("displayName", strings.TrimSpace(c.FormattedName))
+		for _, e := range byPref(c.Emails, func(e Email) int { return e.Pref }) {
+			attr("mail", e.Address)
+		}
+		for _, tel := range byPref(c.Phones, func(t Tel) int { return t.Pref }) {
+			attribute := "telephoneNumber"
+			for _, phone := range ldifPhones[1:] {
+				if tel.HasType(phone.types[0]) {
+					attribute = phone.attribute
+					break
+				}
+			}
+			attr(attribute, tel.Number)
+		}
+		attr("o", c.Org.Name)
+		for _, unit := range c.Org.Units {
+			attr("ou", unit)
+		}
+		attr("title", c.Title)
+
+		var work, home *Adr
+		for _, a := range byPref(c.Addresses, func(a Adr) int { return a.Pref }) {
+			switch {
+			case a.HasType("home") && home == nil:
+				home = &a
+			case !a.HasType("home") && work == nil:
+				work = &a
+			}
+		}
+		if work != nil {
+			attr("street", work.Street)
+			attr("l", work.Locality)
+			attr("st", work.Region)
+			attr("postalCode", work.PostalCode)
+			attr("postOfficeBox", work.POBox)
+			attr("postalAddress", encodePostalAddress(*work))
+		}
+		if home != nil {
+			attr("homePostalAddress", encodePostalAddress(*home))
+		}
+
+		for _, uri := range c.URLs {
+			attr("labeledURI", uri)
+		}
+		for _, note := range c.Notes {
+			attr("description", note)
+		}
+		if c.Photo.IsEmbedded() && c.Photo.MediaType == "image/jpeg" {
+			writeLDIFAttribute(bw, "jpegPhoto", c.Photo.Data)
+		}
+	}
+
+	if err := bw.Flush(); err != nil {
+		return vCardErrf("unable to write: %w", err)
+	}
+	return nil
+}
+
+// Attributes of phone numbers with TEL types. telephoneNumber goes first since it is the default one.
+var ldifPhones = []struct {
+	attribute string
+	types     []string
+}{
+	{"telephoneNumber", []string{"work"}},
+	{"mobile", []string{"cell"}},
+	{"facsimileTelephoneNumber", []string{"fax"}},
+	{"pager", []string{"pager"}},
+	{"homePhone", []string{"home"}},
+}
+
+// Attributes of an LDIF record in order of appearance. Names are lower-case without options
+// e.g. "jpegPhoto;binary" is stored as "jpegphoto".
+type ldifRecord []struct{ name, value string }
+
+func (r ldifRecord) get(name string) string {
+	if values := r.values(name); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func (r ldifRecord) values(name string) []string {
+	name = strings.ToLower(name)
+	var values []string
+	for _, attr := range r {
+		if attr.name == name && strings.TrimSpace(attr.value) != "" {
+			values = append(values, attr.value)
+		}
+	}
+	return values
+}
+
+// Reads records separated by empty lines. Folded lines are unfolded, comments and the version line are skipped.
+func readLDIFRecords(r io.Reader) ([]ldifRecord, error) {
+	br := bufio.NewReader(r)
+	lines := []string{}
+	lineNumbers := []int{}
+	for n := 1; ; n++ {
+		line, err := br.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, vCardErrf("unable to read: %w", err)
+		}
+		if line == "" && err != nil {
+			break
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if strings.HasPrefix(line, " ") && len(lines) > 0 && lines[len(lines)-1] != "" {
+			lines[len(lines)-1] += line[1:]
+		} else {
+			lines = append(lines, line)
+			lineNumbers = append(lineNumbers, n)
+		}
+		if err != nil {
+			break
+		}
+	}
+
+	records := []ldifRecord{}
+	record := ldifRecord{}
+	for i, line := range append(lines, "") {
+		switch {
+		case line == "":
+			if len(record) > 0 {
+				records = append(records, record)
+				record = ldifRecord{}
+			}
+			continue
+		case strings.HasPrefix(line, "#"), line == "-":
+			continue
+		}
+
+		name, value, found := strings.Cut(line, ":")
+		if !found {
+			return nil, parsingErrf("line %d of LDIF is not an attribute: %q", lineNumbers[i], line)
+		}
+		name, _, _ = strings.Cut(strings.ToLower(strings.TrimSpace(name)), ";")
+		switch {
+		case strings.HasPrefix(value, ":"):
+			data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value[1:]))
+			if err != nil {
+				return nil, parsingErrf("line %d of LDIF has malformed base64 value: %w", lineNumbers[i], err)
+			}
+			value = string(data)
+		case strings.HasPrefix(value, "<"):
+			// Values referenced by URL are not fetched
+			continue
+		default:
+			value = strings.TrimLeft(value, " ")
+		}
+
+		if len(record) == 0 && name == "version" {
+			continue
+		}
+		if len(record) == 0 && name != "dn" {
+			return nil, parsingErrf("line %d of LDIF: record has to start with dn, got %q", lineNumbers[i], name)
+		}
+		record = append(record, struct{ name, value string }{name, value})
+	}
+	return records, nil
+}
+
+// Writes "name: value" folded at 76 characters. Values which are not safe strings of RFC 2849 are base64 encoded.
+func writeLDIFAttribute(w *bufio.Writer, name string, value []byte) {
+	line := name + ": " + string(value)
+	if !isLDIFSafe(value) {
+		line = name + ":: " + base64.StdEncoding.EncodeToString(value)
+	}
+	for len(line) > 76 {
+		w.WriteString(line[:76])
+		w.WriteString("\n ")
+		line = line[76:]
+	}
+	w.WriteString(line)
+	w.WriteByte('\n')
+}
+
+func isLDIFSafe(value []byte) bool {
+	if len(value) > 0 && (value[0] == ' ' || value[0] == ':' || value[0] == '<' || value[len(value)-1] == ' ') {
+		return false
+	}
+	for _, b := range value {
+		if b == 0 || b == '\n' || b == '\r' || b >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// Escapes special characters of an attribute value of DN as per https://datatracker.ietf.org/doc/html/rfc4514#section-2.4
+func escapeDNValue(s string) string {
+	b := strings.Builder{}
+	for i, r := range s {
+		if strings.ContainsRune(`,+"\<>;=`, r) || (i == 0 && (r == ' ' || r == '#')) || (i == len(s)-1 && r == ' ') {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Returns lines of the address joined with '$' as PostalAddress syntax of https://datatracker.ietf.org/doc/html/rfc4517#section-3.3.28
+// The label is used if the address has one.
+func encodePostalAddress(a Adr) string {
+	lines := strings.Split(a.Label, "\n")
+	if a.Label == "" {
+		lines = []string{a.POBox, a.Extended}
+		lines = append(lines, strings.Split(a.Street, "\n")...)
+		lines = append(lines, strings.Join(nonEmptyStrings(a.Locality, a.Region, a.PostalCode), " "), a.Country)
+	}
+	escaped := []string{}
+	for _, line := range lines {
+		if line = strings.TrimSpace(line); line != "" {
+			escaped = append(escaped, strings.NewReplacer(`\`, `\5C`, `$`, `\24`).Replace(line))
+		}
+	}
+	return strings.Join(escaped, "$")
+}
+
+// Returns lines of PostalAddress syntax joined with newlines.
+func decodePostalAddress(s string) string {
+	lines := strings.Split(s, "$")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(strings.NewReplacer(`\24`, `$`, `\5C`, `\`, `\5c`, `\`).Replace(line))
+	}
+	return strings.Join(nonEmptyStrings(lines...), "\n")
+}
+
+func nonEmptyStrings(values ...string) []string {
+	result := []string{}
+	for _, v := range values {
+		if v != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
@@ -0,0 +1,145 @@
+package vcard
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestReadLDIF(t *testing.T) {
+
+	doc := strings.Join([]string{
+		"version: 1",
+		"",
+		"# Alex",
+		"dn: cn=Alex Smith,ou=people,dc=example,dc=com",
+		"objectClass: inetOrgPerson",
+		"cn: Alex Smith",
+		"sn: Smith",
+		"givenName: Alex",
+		"mail: alex@example.com",
+		"mail: a@example.com",
+		"telephoneNumber: +1 555 0100",
+		"mobile: +1 555 0101",
+		"o: Acme",
+		"ou: R&D",
+		"title: Engineer",
+		"street: 1 Main St",
+		"l: Town",
+		"postalAddress: 1 Main St$Town",
+		`homePostalAddress: 2 Home Rd$Village \24 Co`,
+		"labeledURI: https://alex.example Homepage",
+		"description:: TGluZSAxCkxpbmUgMg==",
+		"jpegPhoto:: /9j/4A==",
+		"",
+		"dn: cn=Joerg,ou=people,dc=example,dc=com",
+		"cn:: SsO2cmc=",
+		"sn: M",
+		" üller",
+		"",
+		"dn: cn=Gone,ou=people,dc=example,dc=com",
+		"changetype: delete",
+	}, "\r\n")
+
+	contacts, err := ReadLDIF(strings.NewReader(doc))
+	assertEq(t, err, nil)
+	assertEq(t, len(contacts), 2)
+
+	c := contacts[0]
+	assertEq(t, c.FormattedName, "Alex Smith")
+	assertDeepEq(t, c.Name, Name{FamilyNames: []string{"Smith"}, GivenNames: []string{"Alex"}})
+	assertDeepEq(t, c.Emails, []Email{{Address: "alex@example.com"}, {Address: "a@example.com"}})
+	assertDeepEq(t, c.Phones, []Tel{{Number: "+1 555 0100", Types: []string{"work"}}, {Number: "+1 555 0101", Types: []string{"cell"}}})
+	assertDeepEq(t, c.Org, Org{Name: "Acme", Units: []string{"R&D"}})
+	assertEq(t, c.Title, "Engineer")
+	assertDeepEq(t, c.Addresses, []Adr{
+		{Street: "1 Main St", Locality: "Town", Label: "1 Main St\nTown", Types: []string{"work"}},
+		{Street: "2 Home Rd\nVillage $ Co", Types: []string{"home"}},
+	})
+	assertSlicesEq(t, c.URLs, []string{"https://alex.example"})
+	assertSlicesEq(t, c.Notes, []string{"Line 1\nLine 2"})
+	assertDeepEq(t, c.Photo, Photo{Data: []byte{0xff, 0xd8, 0xff, 0xe0}, MediaType: "image/jpeg"})
+
+	c = contacts[1]
+	assertEq(t, c.FormattedName, "Jörg")
+	assertSlicesEq(t, c.Name.FamilyNames, []string{"Müller"})
+
+	_, err = ReadLDIF(strings.NewReader("cn: Alex\n"))
+	assertErrIs(t, err, ErrParsing, "record has to start with dn")
+	_, err = ReadLDIF(strings.NewReader("dn: cn=Alex\ncn:: %%%\n"))
+	assertErrIs(t, err, ErrParsing, "line 2 of LDIF has malformed base64 value")
+}
+
+func TestWriteLDIF(t *testing.T) {
+
+	contacts := []Contact{
+		{
+			FormattedName: "Smith, Alex",
+			Name:          Name{FamilyNames: []string{"Smith"}, GivenNames: []string{"Alex"}},
+			Emails:        []Email{{Address: "a@example.com"}, {Address: "alex@example.com", Pref: 1}},
+			Phones:        []Tel{{Number: "555", Types: []string{"work", "fax"}}, {Number: "556"}},
+			Addresses: []Adr{
+				{Street: "2 Home Rd", Locality: "Village", Types: []string{"home"}},
+				{Street: "1 Main St", Locality: "Town", Region: "CA", PostalCode: "91921", Country: "USA"},
+			},
+			Notes: []string{"Met in Zürich"},
+			Photo: Photo{Data: []byte{0xff, 0xd8}, MediaType: "image/jpeg"},
+		},
+		{Org: Org{Name: "Acme"}, Photo: Photo{URI: "https://acme.example/logo.png"}},
+	}
+
+	b := bytes.Buffer{}
+	assertEq(t, WriteLDIF(&b, contacts, "ou=people,dc=example,dc=com"), nil)
+	assertStringLinesEq(t, b.String(), strings.Join([]string{
+		"version: 1",
+		"",
+		`dn: cn=Smith\, Alex,ou=people,dc=example,dc=com`,
+		"objectClass: top",
+		"objectClass: person",
+		"objectClass: organizationalPerson",
+		"objectClass: inetOrgPerson",
+		"cn: Smith, Alex",
+		"sn: Smith",
+		"givenName: Alex",
+		"displayName: Smith, Alex",
+		"mail: alex@example.com",
+		"mail: a@example.com",
+		"facsimileTelephoneNumber: 555",
+		"telephoneNumber: 556",
+		"street: 1 Main St",
+		"l: Town",
+		"st: CA",
+		"postalCode: 91921",
+		"postalAddress: 1 Main St$Town CA 91921$USA",
+		"homePostalAddress: 2 Home Rd$Village",
+		"description:: TWV0IGluIFrDvHJpY2g=",
+		"jpegPhoto:: /9g=",
+		"",
+		"dn: cn=Acme,ou=people,dc=example,dc=com",
+		"objectClass: top",
+		"objectClass: person",
+		"objectClass: organizationalPerson",
+		"objectClass: inetOrgPerson",
+		"cn: Acme",
+		"sn: Acme",
+		"o: Acme",
+		"",
+	}, "\n"))
+
+	roundTrip, err := ReadLDIF(&b)
+	assertEq(t, err, nil)
+	assertEq(t, len(roundTrip), 2)
+	assertEq(t, roundTrip[0].FormattedName, "Smith, Alex")
+	assertSlicesEq(t, roundTrip[0].Notes, []string{"Met in Zürich"})
+	assertDeepEq(t, roundTrip[0].Photo, contacts[0].Photo)
+
+	long := bytes.Buffer{}
+	assertEq(t, WriteLDIF(&long, []Contact{{FormattedName: strings.Repeat("a", 100)}}, ""), nil)
+	assertEq(t, strings.Contains(long.String(), "cn: "+strings.Repeat("a", 72)+"\n "+strings.Repeat("a", 28)+"\n"), true)
+	folded, err := ReadLDIF(&long)
+	assertEq(t, err, nil)
+	assertEq(t, folded[0].FormattedName, strings.Repeat("a", 100))
+
+	err = WriteLDIF(&bytes.Buffer{}, []Contact{{}}, "")
+	assertErrIs(t, err, ErrValidation, "contact 0 has no name")
+}
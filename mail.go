@@ -0,0 +1,152 @@
+package vcard
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"iter"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"path"
+	"strings"
+)
+
+// Card found in an email by [MailCards].
+type MailCard struct {
+	Card Card
+
+	// Name of the attachment e.g. "alex.vcf". Empty if the part has no file name.
+	Filename string
+
+	// Error of decoding the part or the card. Card is empty if Err is not nil.
+	Err error
+}
+
+// Returns cards attached to an email. Parts of text/vcard, text/x-vcard, text/directory and
+// application/vcard types are decoded, as well as other parts named *.vcf since mail clients often
+// attach contacts as application/octet-stream. Multipart bodies and forwarded message/rfc822
+// messages are walked recursively.
+//
+// base64 and quoted-printable transfer encodings are decoded. Charsets other than UTF-8, US-ASCII
+// and ISO-8859-1 are reported as errors. A part containing several cards yields every one of them.
+// Malformed parts and cards are yielded with Err, so one broken attachment doesn't hide the others:
+//
+//	msg, _ := mail.ReadMessage(r)
+//	for found := range vcard.MailCards(msg) {
+//		if found.Err != nil {
+//			log.Printf("%s: %v", found.Filename, found.Err)
+//			continue
+//		}
+//		...
+//	}
+func MailCards(msg *mail.Message) iter.Seq[MailCard] {
+	return func(yield func(MailCard) bool) {
+		walkMailPart(textproto.MIMEHeader(msg.Header), msg.Body, 0, yield)
+	}
+}
+
+// Maximum nesting of multipart bodies and forwarded messages.
+const maxMailDepth = 20
+
+// Walks a part and reports whether walking has to continue.
+func walkMailPart(header textproto.MIMEHeader, body io.Reader, depth int, yield func(MailCard) bool) bool {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType, params = "text/plain", map[string]string{}
+	}
+
+	switch {
+	case depth > maxMailDepth:
+		return yield(MailCard{Err: parsingErrf("email parts are nested deeper than %d levels", maxMailDepth)})
+
+	case strings.HasPrefix(mediaType, "multipart/"):
+		mr := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := mr.NextRawPart()
+			if err == io.EOF {
+				return true
+			}
+			if err != nil {
+				return yield(MailCard{Err: parsingErrf("malformed multipart body: %w", err)})
+			}
+			if !walkMailPart(part.Header, part, depth+1, yield) {
+				return false
+			}
+		}
+
+	case mediaType == "message/rfc822":
+		msg, err := mail.ReadMessage(decodeTransferEncoding(header, body))
+		if err != nil {
+			return yield(MailCard{Err: parsingErrf("malformed forwarded message: %w", err)})
+		}
+		return walkMailPart(textproto.MIMEHeader(msg.Header), msg.Body, depth+1, yield)
+	}
+
+	filename := mailFilename(header, params)
+	isCard := mediaType == "text/vcard" || mediaType == "text/x-vcard" || mediaType == "application/vcard" ||
+		(mediaType == "text/directory" && strings.EqualFold(params["profile"], "vcard")) ||
+		strings.EqualFold(path.Ext(filename), ".vcf")
+	if !isCard {
+		return true
+	}
+
+	data, err := io.ReadAll(decodeTransferEncoding(header, body))
+	if err != nil {
+		return yield(MailCard{Filename: filename, Err: vCardErrf("unable to read attachment: %w", err)})
+	}
+	switch charset := strings.ToLower(params["charset"]); charset {
+	case "", "utf-8", "utf8", "us-ascii":
+	case "iso-8859-1", "latin1":
+		data = []byte(string(latin1Runes(data)))
+	default:
+		return yield(MailCard{Filename: filename, Err: vCardErrf("unsupported charset %q of attachment", charset)})
+	}
+
+	for raw := range SplitRaw(bytes.NewReader(data)) {
+		found := MailCard{Filename: filename, Err: raw.Err}
+		if raw.Err == nil {
+			found.Card, found.Err = raw.Card()
+		}
+		if !yield(found) {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns body decoded according to Content-Transfer-Encoding header. Unknown encodings
+// e.g. 7bit and 8bit are returned as is.
+func decodeTransferEncoding(header textproto.MIMEHeader, body io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(header.Get("Content-Transfer-Encoding"))) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, body)
+	case "quoted-printable":
+		return quotedprintable.NewReader(body)
+	}
+	return body
+}
+
+// Returns file name of Content-Disposition or name parameter of Content-Type. RFC 2047 encoded
+// names used by some mail clients are decoded.
+func mailFilename(header textproto.MIMEHeader, contentTypeParams map[string]string) string {
+	name := contentTypeParams["name"]
+	if _, params, err := mime.ParseMediaType(header.Get("Content-Disposition")); err == nil && params["filename"] != "" {
+		name = params["filename"]
+	}
+	if decoded, err := new(mime.WordDecoder).DecodeHeader(name); err == nil {
+		name = decoded
+	}
+	return name
+}
+
+// Returns ISO-8859-1 bytes as runes since code points of ISO-8859-1 are the same in Unicode.
+func latin1Runes(data []byte) []rune {
+	runes := make([]rune, len(data))
+	for i, b := range data {
+		runes[i] = rune(b)
+	}
+	return runes
+}
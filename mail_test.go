@@ -0,0 +1,103 @@
+package vcard
+
+import (
+	"net/mail"
+	"strings"
+	"testing"
+)
+
+func TestMailCards(t *testing.T) {
+
+	msg, err := mail.ReadMessage(strings.NewReader(strings.Join([]string{
+		"From: alex@example.com",
+		"Subject: Contacts",
+		"MIME-Version: 1.0",
+		`Content-Type: multipart/mixed; boundary="outer"`,
+		"",
+		"--outer",
+		"Content-Type: text/plain; charset=utf-8",
+		"",
+		"BEGIN:VCARD in text is not an attachment",
+		"--outer",
+		`Content-Type: text/vcard; charset=utf-8; name="alex.vcf"`,
+		"Content-Transfer-Encoding: base64",
+		`Content-Disposition: attachment; filename="alex.vcf"`,
+		"",
+		"QkVHSU46VkNBUkQNClZFUlNJT046NC4wDQpGTjpBbGV4DQpFTkQ6VkNBUkQNCg==",
+		"--outer",
+		`Content-Type: application/octet-stream; name="=?UTF-8?Q?J=C3=B6rg.vcf?="`,
+		"Content-Transfer-Encoding: quoted-printable",
+		"",
+		"BEGIN:VCARD",
+		"VERSION:3.0",
+		"FN:J=C3=B6rg M=",
+		"=C3=BCller",
+		"N:M=C3=BCller;J=C3=B6rg;;;",
+		"END:VCARD",
+		"--outer",
+		"Content-Type: message/rfc822",
+		"",
+		"Subject: Fwd",
+		`Content-Type: multipart/alternative; boundary="inner"`,
+		"",
+		"--inner",
+		"Content-Type: text/x-vcard; charset=iso-8859-1",
+		"Content-Transfer-Encoding: 8bit",
+		"",
+		"BEGIN:VCARD\r\nVERSION:4.0\r\nFN:Sam\r\nEND:VCARD\r\nBEGIN:VCARD\r\nVERSION:4.0\r\nFN:Kim\r\nEND:VCARD",
+		"--inner",
+		"Content-Type: text/vcard; charset=koi8-r",
+		"",
+		"BEGIN:VCARD",
+		"--inner--",
+		"--outer--",
+	}, "\r\n")))
+	assertEq(t, err, nil)
+
+	fns := []string{}
+	filenames := []string{}
+	errs := []error{}
+	for found := range MailCards(msg) {
+		if found.Err != nil {
+			errs = append(errs, found.Err)
+			continue
+		}
+		fn, _ := found.Card.FN()
+		fns = append(fns, fn)
+		filenames = append(filenames, found.Filename)
+	}
+	assertSlicesEq(t, fns, []string{"Alex", "Jörg Müller", "Sam", "Kim"})
+	assertSlicesEq(t, filenames, []string{"alex.vcf", "Jörg.vcf", "", ""})
+	assertEq(t, len(errs), 1)
+	assertErrIs(t, errs[0], ErrVCard, `unsupported charset "koi8-r"`)
+}
+
+func TestMailCardsLatin1(t *testing.T) {
+
+	msg, err := mail.ReadMessage(strings.NewReader("Content-Type: text/vcard; charset=ISO-8859-1\r\n\r\n" +
+		"BEGIN:VCARD\r\nVERSION:4.0\r\nFN:J\xf6rg\r\nEND:VCARD\r\n"))
+	assertEq(t, err, nil)
+
+	count := 0
+	for found := range MailCards(msg) {
+		assertEq(t, found.Err, nil)
+		fn, _ := found.Card.FN()
+		assertEq(t, fn, "Jörg")
+		count++
+	}
+	assertEq(t, count, 1)
+}
+
+func TestMailCardsMalformed(t *testing.T) {
+
+	msg, err := mail.ReadMessage(strings.NewReader("Content-Type: multipart/mixed; boundary=b\r\n\r\n" +
+		"--b\r\nContent-Type: text/vcard\r\n\r\nBEGIN:VCARD\r\nVERSION:4.0\r\nFN:Alex\r\n--b--\r\n"))
+	assertEq(t, err, nil)
+
+	count := 0
+	for found := range MailCards(msg) {
+		assertErrIs(t, found.Err, ErrParsing, "has no END:VCARD")
+		count++
+	}
+	assertEq(t, count, 1)
+}
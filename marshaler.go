@@ -2,17 +2,24 @@ package vcard
 
 import (
 	"bytes"
+	"encoding"
 	"fmt"
 	"io"
+	"iter"
+	"net/url"
 	"reflect"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
 // Serializes a Go value as a vCard document using default vCard 4.0 schema.
+// See [RegisterSchema] to change the default schema.
 //
 // v has to be a map, struct or a slice.
 func Marshal(v any) ([]byte, error) {
-	return MarshalSchema(v, SchemaV4)
+	return MarshalSchema(v, defaultSchema())
 }
 
 // Serializes a Go value as a vCard document using provided [Schema].
@@ -36,9 +43,17 @@ type Encoder struct {
 
 	smartStrings    bool
 	newlineSequence string
-
-	// TODO: Cache prepared schema between EncodeSchema() calls
-	// TODO: Cache type info between encode() calls
+	parallelism     int
+	controlChars    ControlCharPolicy
+	downgradePolicy DowngradePolicy
+	report          *EncodeReport
+	tagKey          string
+	fallbackTagKey  string
+	warn            func(*ValidationError)
+	profile         Profile
+
+	mapper func(string) string
+	mapped sync.Map // schemas prepared with mapper
 }
 
 // Creates new Encoder that writes to w.
@@ -47,19 +62,24 @@ func NewEncoder(w io.Writer) *Encoder {
 		w:               w,
 		smartStrings:    true,
 		newlineSequence: "\r\n",
+		parallelism:     1,
+		tagKey:          defaultTagKey,
 	}
 }
 
 // Toggles smart string encoding. Enabled by default.
 //
-// In smart mode, encoder checks at runtime if string contains `:` (KEY:VALUE separator) and adds
-// it if neccesary. This is useful because some fields have more complex format e.g.:
+// In smart mode, encoder checks at runtime if string starts with parameters or `:` (KEY:VALUE separator)
+// and adds `:` if neccesary. This is useful because some fields have more complex format e.g.:
 //
 // For string "N:;Alex;;;" k="N", v=";Alex;;;" - `:` won't be a part of an encoded value.
 //
 // For string "TEL;TYPE=CELL:555" k="TEL", v=";TYPE=CELL:555" - `:` will be in the middle
 // of an encoded value.
 //
+// For string "URL:https://example.com" k="URL", v="https://example.com" - `:` will be added
+// since the value does not start with parameters.
+//
 // Disabling smart strings encoding will increase performance, but you have to ensure your
 // strings have proper puctuation in them e.g. you will have to deal with ":Name" instead of "Name".
 //
@@ -75,12 +95,82 @@ func (e *Encoder) SetNewlineSequence(seq string) *Encoder {
 	return e
 }
 
+// Sets key of struct tags used to match fields with properties. Defaults to "vCard".
+//
+// Useful when structs are already tagged with a different key e.g. `vcf:"FN,required"`.
+// Tag syntax is the same, see [SchemaFor].
+func (e *Encoder) SetTagKey(key string) *Encoder {
+	e.tagKey = key
+	return e
+}
+
+// Sets key of struct tags used for fields without a tag of [Encoder.SetTagKey]. Empty by default,
+// which means such fields are matched by their names.
+//
+// Useful to encode models already tagged for other formats without duplicating every tag e.g.
+// with "json" key a field tagged `json:"email,omitempty"` is encoded as EMAIL. Names of fallback
+// tags are converted by [UpperKebabCase] e.g. "photoUrl" becomes PHOTO-URL. Only omitempty and
+// omitzero options are recognized and `json:"-"` excludes a field.
+func (e *Encoder) SetFallbackTagKey(key string) *Encoder {
+	e.fallbackTagKey = key
+	e.mapped.Clear()
+	return e
+}
+
+// Sets a function which maps names of struct fields without a name in a tag to property names
+// e.g. Email to EMAIL. Nil disables mapping, which is the default.
+//
+// See [UpperKebabCase] for a mapper suitable for most idiomatic Go names.
+func (e *Encoder) SetFieldNameMapper(mapper func(goField string) string) *Encoder {
+	e.mapper = mapper
+	e.mapped.Clear()
+	return e
+}
+
+// Sets number of goroutines used to encode large slices. Defaults to 1 which means
+// records are encoded sequentially.
+//
+// Records of a slice are independent from each other, so for slices with thousands of elements
+// each worker encodes its own chunk into a separate buffer and buffers are joined in the original
+// order. Output is identical to sequential encoding. Short slices are always encoded sequentially.
+//
+// Note that custom [VCardFieldMarshaler] implementations have to be safe for concurrent use
+// when parallelism is enabled.
+func (e *Encoder) SetParallelism(workers int) *Encoder {
+	e.parallelism = max(workers, 1)
+	return e
+}
+
+// Defines how [Encoder] treats control characters found in encoded values.
+type ControlCharPolicy int
+
+const (
+	// Writes values as is. Raw CR/LF in a value corrupt the structure of a document.
+	ControlCharsAllow ControlCharPolicy = iota
+
+	// Returns [ErrValidation] if a value contains CR, LF or any other control character except HTAB.
+	ControlCharsReject
+
+	// Escapes line breaks as `\n` as per https://datatracker.ietf.org/doc/html/rfc6350#section-3.4
+	// and removes other control characters except HTAB.
+	ControlCharsEscape
+)
+
+// Sets how control characters in values are handled. Defaults to [ControlCharsAllow].
+//
+// Values are checked after [VCardFieldMarshaler] is called, so custom fields are validated as well.
+func (e *Encoder) SetControlCharPolicy(policy ControlCharPolicy) *Encoder {
+	e.controlChars = policy
+	return e
+}
+
 // Writes a vCard representation of v to the stream using default vCard 4.0 schema.
+// See [RegisterSchema] to change the default schema.
 //
 // fields of v have to either match the name and the type from the schema or implement
 // Marshaler for custom encoding logic.
 func (e *Encoder) Encode(v any) error {
-	return e.EncodeSchema(v, SchemaV4)
+	return e.EncodeSchema(v, defaultSchema())
 }
 
 // Writes a vCard representation of v to the stream using provided Schema.
@@ -92,12 +182,41 @@ func (e *Encoder) EncodeSchema(v any, schema Schema) error {
 	if v == nil {
 		return vCardErrf("cannot encode a nil interface")
 	}
+	return e.encodeValue(v, encoderCtx{schema: schema})
+}
+
+// Writes a vCard representation of v to the stream using prepared schema. See [MarshalPrepared].
+func (e *Encoder) EncodePrepared(v any, p PreparedSchema) error {
+	if v == nil {
+		return vCardErrf("cannot encode a nil interface")
+	}
+	return e.encodeValue(v, encoderCtx{schema: p.schema, prepared: p})
+}
+
+// Writes a single content line of property p e.g. "item1.TEL;TYPE=CELL:555" followed by the newline
+// sequence. Value is written as is, so TEXT values have to be escaped by the caller.
+//
+// WriteProperty is a low-level API: the caller is responsible for BEGIN:VCARD, VERSION and
+// END:VCARD lines. [ControlCharPolicy] of the encoder is applied to the line.
+func (e *Encoder) WriteProperty(p Property) error {
+	if p.Name == "" {
+		return vCardErrf("cannot write a property without a name")
+	}
+	b, err := e.appendField([]byte{}, p.fullName(), p.rest())
+	if err != nil {
+		return err
+	}
+	_, err = e.w.Write(b)
+	if err != nil {
+		return vCardErrf("cannot write: %w", err)
+	}
+	return nil
+}
+
+func (e *Encoder) encodeValue(v any, ctx encoderCtx) error {
 	// Intermidiate buffer makes sure there was no errors before writing to io.Writer
 	b := []byte{}
 
-	// TODO: Cache prepared schema between EncodeSchema() calls
-	ctx := encoderCtx{schema: schema}
-
 	b, err := e.encode(b, reflect.ValueOf(v), ctx)
 	if err != nil {
 		return err
@@ -109,6 +228,49 @@ func (e *Encoder) EncodeSchema(v any, schema Schema) error {
 	return nil
 }
 
+// Writes a vCard representation of every value produced by seq to the stream using provided [Schema].
+//
+// Unlike encoding a slice, each value is written as soon as it is produced, so a pipeline
+// generating contacts one at a time never has to materialize the whole collection.
+// Encoding stops at the first error; values written before it stay in the stream.
+func EncodeSeq[T any](e *Encoder, seq iter.Seq[T], schema Schema) error {
+	ctx := encoderCtx{schema: schema}
+
+	i := 0
+	for v := range seq {
+		rv := reflect.ValueOf(v)
+		if !rv.IsValid() {
+			return vCardErrf("cannot encode a nil interface at sequence idx=%v", i)
+		}
+		ctx.record = i
+		b, err := e.encode([]byte{}, rv, ctx)
+		if err != nil {
+			return vCardErrf("error during marshaling sequence member idx=%v: %w", i, err)
+		}
+		_, err = e.w.Write(b)
+		if err != nil {
+			return vCardErrf("cannot write: %w", err)
+		}
+		i++
+	}
+	return nil
+}
+
+// Writes a vCard representation of every value received from ch to the stream using provided [Schema].
+//
+// Returns once ch is closed or on the first error. See [EncodeSeq] for details.
+//
+// Note that in case of an error remaining values are not drained from ch.
+func EncodeChan[T any](e *Encoder, ch <-chan T, schema Schema) error {
+	return EncodeSeq(e, func(yield func(T) bool) {
+		for v := range ch {
+			if !yield(v) {
+				return
+			}
+		}
+	}, schema)
+}
+
 func (e *Encoder) encode(b []byte, v reflect.Value, ctx encoderCtx) ([]byte, error) {
 	switch v.Kind() {
 	case reflect.Map:
@@ -117,193 +279,422 @@ func (e *Encoder) encode(b []byte, v reflect.Value, ctx encoderCtx) ([]byte, err
 		return e.encodeStruct(b, v, ctx)
 	case reflect.Array, reflect.Slice:
 		return e.encodeSlice(b, v, ctx)
+	case reflect.Interface, reflect.Pointer:
+		if v.IsNil() {
+			return b, vCardErrf("cannot encode a nil %s", v.Type())
+		}
+		return e.encode(b, v.Elem(), ctx)
 	}
 	return b, vCardErrf("unable to encode %s type. Use struct, map or a slice", v.Type())
 }
 
 func (e *Encoder) encodeMap(b []byte, ma reflect.Value, ctx encoderCtx) ([]byte, error) {
+	if m, ok := asInterface[VCardVersionMarshaler](ma); ok {
+		return e.encodeMarshaler(b, m, func() ([]byte, error) { return m.MarshalVCardVersion(ctx.schema.version) })
+	}
+	if m, ok := asInterface[VCardMarshaler](ma); ok {
+		return e.encodeMarshaler(b, m, m.MarshalVCard)
+	}
 	keyKind := ma.Type().Key().Kind()
 	if keyKind != reflect.String {
 		return []byte{}, vCardErrf("type %s is not supported as a map key. Use string instead", keyKind)
 	}
-	for req := range ctx.schema.requiredFields {
+	for _, req := range ctx.schema.derive().required {
+		if _, found := ctx.schema.defaults[req]; found {
+			continue
+		}
 		if !ma.MapIndex(reflect.ValueOf(req)).IsValid() {
 			return b, vCardErrf("map does not contain field %q required by the schema", req)
 		}
 	}
-	buf := e.encodeRecordHeader([]byte{}, ctx)
-
-	i := ma.MapRange()
+	valueType := ma.Type().Elem()
+	if !encodableType(valueType) {
+		if valueType.Kind() == reflect.Struct {
+			return b, vCardErrf("map value is a struct of type %s which does not implement VCardFieldMarshaler", valueType)
+		}
+		return b, vCardErrf("type %s is not supported as a map value. Use string or a struct that implements VCardFieldMarshaler", valueType)
+	}
 
 	// In case of an empty map lets write BEGIN:VCARD, VERSION:.. and END:VCARD to simplify debugging
 	// This is only possible for user-defined schema with no required fields
-	if !i.Next() {
-		buf = e.encodeRecordFooter(buf, ctx)
-		return append(b, buf...), nil
-	}
-	// It's better to inspect kind of the first element single time at the start
-	valueKind := i.Value().Kind()
+	fields := []encodedField{}
 
-	switch valueKind {
-	case reflect.String:
-		m := ma.Interface().(map[string]string)
-
-		if !e.smartStrings {
-			for k, v := range m {
-				_, found := ctx.schema.fields[k]
-				if !found {
-					continue
-				}
-				buf = append(buf, fmt.Sprintf("%s%s%s", k, v, e.newlineSequence)...)
-			}
-		} else {
-			for k, v := range m {
-				_, found := ctx.schema.fields[k]
-				if !found {
-					continue
-				}
-				if strings.Contains(v, ":") {
-					buf = append(buf, fmt.Sprintf("%s%s%s", k, v, e.newlineSequence)...)
-				} else {
-					buf = append(buf, fmt.Sprintf("%s:%s%s", k, v, e.newlineSequence)...)
-				}
-			}
+	iter := ma.MapRange()
+	for iter.Next() {
+		k := iter.Key().String()
+
+		_, found := ctx.schema.fields[k]
+		if !found {
+			continue
 		}
-	case reflect.Struct:
-		if i.Value().Type().Implements(reflect.TypeFor[VCardFieldMarshaler]()) {
-			iter := ma.MapRange()
-			for iter.Next() {
-				k := iter.Key().String()
-
-				_, found := ctx.schema.fields[k]
-				if !found {
-					continue
-				}
-
-				v := iter.Value().Interface().(VCardFieldMarshaler)
-
-				field, err := v.MarshalVCardField()
-				if err != nil {
-					return b, vCardErrf("error during marshaling value for a key %q: %w", k, err)
-				}
-				buf = append(buf, fmt.Sprintf("%s%s%s", k, field, e.newlineSequence)...)
-			}
-		} else {
-			return b, vCardErrf("map value is a struct of type %s which does not implement VCardFieldMarshaler", i.Value().Type())
+		name := aliasedName(k, ctx.schema.aliases[k], ctx.schema.version)
+
+		if def, found := ctx.schema.defaults[k]; found && iter.Value().IsZero() {
+			fields = append(fields, encodedField{name, ":" + def})
+			continue
 		}
-	case reflect.Interface:
-		iter := ma.MapRange()
-		for iter.Next() {
-			k := iter.Key().String()
-			v, ok := iter.Value().Interface().(VCardFieldMarshaler)
 
-			if !ok {
-				return b, vCardErrf("map value for a key %q is a struct of type %s which does not implement VCardFieldMarshaler", k, iter.Value().Type())
-			}
-			field, err := v.MarshalVCardField()
-			if err != nil {
-				return b, vCardErrf("error during marshaling value for a key %q: %w", k, err)
+		rest, ok, err := e.marshalValue(iter.Value(), ctx.schema.version)
+		if err != nil {
+			return b, vCardErrf("error during marshaling value for a key %q: %w", k, err)
+		}
+		if !ok {
+			// Only values of interface type can be unsupported at this point
+			v := iter.Value().Elem()
+			err := vCardErrf("map value for a key %q has type %s which does not implement VCardFieldMarshaler", k, v.Type())
+			if e.report == nil {
+				return b, err
 			}
-			buf = append(buf, fmt.Sprintf("%s%s%s", k, field, e.newlineSequence)...)
+			e.report.skip(SkippedField{ctx.record, k, v.Type(), err})
+			continue
 		}
-	default:
-		return b, vCardErrf("type %s is not supported as a map value. Use string or a struct that implements VCardFieldMarshaler", i.Value().Type())
+		if rest == "" {
+			continue
+		}
+		fields = append(fields, encodedField{name, rest})
 	}
-	buf = e.encodeRecordFooter(buf, ctx)
 
-	return append(b, buf...), nil
+	for _, k := range ctx.schema.derive().defaults {
+		if !ma.MapIndex(reflect.ValueOf(k)).IsValid() {
+			name := aliasedName(k, ctx.schema.aliases[k], ctx.schema.version)
+			fields = append(fields, encodedField{name, ":" + ctx.schema.defaults[k]})
+		}
+	}
+
+	err := e.checkFields(ctx.schema, fields)
+	if err != nil {
+		return b, err
+	}
+
+	return e.encodeRecord(b, ctx.schema.version, fields)
 }
 
 func (e *Encoder) encodeStruct(b []byte, struc reflect.Value, ctx encoderCtx) ([]byte, error) {
+	if struc.Type() == cardType {
+		return e.encodeCard(b, struc.Interface().(Card), ctx)
+	}
+	if m, ok := asInterface[VCardVersionMarshaler](struc); ok {
+		return e.encodeMarshaler(b, m, func() ([]byte, error) { return m.MarshalVCardVersion(ctx.schema.version) })
+	}
+	if m, ok := asInterface[VCardMarshaler](struc); ok {
+		return e.encodeMarshaler(b, m, m.MarshalVCard)
+	}
+
+	p := e.prepare(ctx, struc.Type())
+	if p.missing != "" {
+		return b, vCardErrf("struct %v does not contain field %q or field tagged `vCard:\"%s\"` required by the schema", struc.Type(), p.missing, p.missing)
+	}
+	fields := []encodedField{}
 
-	// TODO: Cache struct fields lookup
-	for req := range ctx.schema.requiredFields {
+	for _, fieldDesc := range p.fields {
 
-		structField, _ := struc.Type().FieldByName(req)
-		fieldName := structField.Name
+		field := struc.Field(fieldDesc.index)
+		vCardName, taggedMsg := fieldDesc.name, fieldDesc.taggedMsg
+		vCardName = aliasedName(vCardName, fieldDesc.aliases, ctx.schema.version)
 
-		// Check for another field tagged `vCard:"N"`
-		// which has a priority above field `N`
-		for i := range struc.NumField() {
-			otherStructField := struc.Type().Field(i)
-			tag := otherStructField.Tag.Get("vCard")
-			if tag == req {
-				fieldName = otherStructField.Name
+		if field.IsZero() && fieldDesc.def != "" {
+			fields = append(fields, encodedField{vCardName, fieldDesc.withType(":" + fieldDesc.def)})
+			continue
+		}
+		if field.IsZero() {
+			if fieldDesc.required {
+				return b, vCardErrf("field %q %sof a struct %s is required but empty", fieldDesc.goName, taggedMsg, struc.Type())
+			}
+			if fieldDesc.omitEmpty {
+				continue
 			}
 		}
 
-		if fieldName == "" {
-			return b, vCardErrf("struct %v does not contain field %q or field tagged `vCard:\"%s\"` required by the schema", struc.Type(), req, req)
+		rest, ok, err := e.marshalValue(field, ctx.schema.version)
+		if err != nil {
+			return b, vCardErrf("error during marshaling field %q %sof struct %s: %w", fieldDesc.goName, taggedMsg, struc.Type(), err)
 		}
+		if !ok {
+			switch field.Kind() {
+			case reflect.Struct, reflect.Interface:
+				err = vCardErrf("field %q %sof a struct %s has type %s which does not implement VCardFieldMarshaler", fieldDesc.goName, taggedMsg, struc.Type(), field.Type())
+			default:
+				err = vCardErrf("field %q %sof a struct %s has unsupported type %s. Use string or a struct that implements VCardFieldMarshaler", fieldDesc.goName, taggedMsg, struc.Type(), field.Type())
+			}
+			if e.report == nil {
+				return b, err
+			}
+			e.report.skip(SkippedField{ctx.record, fieldDesc.goName, field.Type(), err})
+			continue
+		}
+		if rest == "" {
+			continue
+		}
+		fields = append(fields, encodedField{vCardName, fieldDesc.withType(rest)})
 	}
-	buf := e.encodeRecordHeader([]byte{}, ctx)
 
-	// In case of an empty struct lets write BEGIN:VCARD, VERSION:.. and END:VCARD to simplify debugging
-	// This is only possible for user-defined schema with no required fields
-	if struc.NumField() == 0 {
-		buf = e.encodeRecordFooter(buf, ctx)
-		return append(b, buf...), nil
+	if p.rest >= 0 {
+		var err error
+		fields, err = e.appendRestFields(fields, struc.Field(p.rest))
+		if err != nil {
+			return b, err
+		}
 	}
 
-	for i := range struc.NumField() {
+	err := e.checkFields(ctx.schema, fields)
+	if err != nil {
+		return b, err
+	}
 
-		field := struc.Field(i)
-		fieldDesc := struc.Type().Field(i)
+	return e.encodeRecord(b, ctx.schema.version, fields)
+}
 
-		vCardName := fieldDesc.Name
+func (e *Encoder) encodeCard(b []byte, card Card, ctx encoderCtx) ([]byte, error) {
 
-		tag := fieldDesc.Tag.Get("vCard")
-		taggedMsg := ""
-		if tag != "" {
-			vCardName = tag
-			taggedMsg = fmt.Sprintf("tagged `vCard:\"%s\"` ", vCardName)
-		}
+	version := card.Version()
+	if version == "" {
+		version = ctx.schema.version
+	}
 
-		_, found := ctx.schema.fields[vCardName]
-		if !found {
+	fields := make([]encodedField, 0, len(card.props))
+	versionWritten := false
+	for _, p := range card.props {
+		// VERSION of nested records e.g. AGENT of vCard 2.1 is kept
+		if p.Name == "VERSION" && !versionWritten {
+			versionWritten = true
 			continue
 		}
+		fields = append(fields, encodedField{p.fullName(), p.rest()})
+	}
 
-		switch field.Kind() {
-		case reflect.String:
-			s := field.String()
-			if !e.smartStrings {
-				buf = append(buf, fmt.Sprintf("%s%s%s", vCardName, s, e.newlineSequence)...)
-			} else {
-				if strings.Contains(s, ":") {
-					buf = append(buf, fmt.Sprintf("%s%s%s", vCardName, s, e.newlineSequence)...)
-				} else {
-					buf = append(buf, fmt.Sprintf("%s:%s%s", vCardName, s, e.newlineSequence)...)
-				}
-			}
-		case reflect.Struct, reflect.Interface:
-			v, ok := field.Interface().(VCardFieldMarshaler)
+	return e.encodeRecord(b, version, fields)
+}
 
-			if !ok {
-				return b, vCardErrf("field %q %sof a struct %s has type %s which does not implement VCardFieldMarshaler", fieldDesc.Name, taggedMsg, struc.Type(), field.Type())
-			}
+// Appends a record returned by marshal of m which is [VCardMarshaler] or [VCardVersionMarshaler].
+func (e *Encoder) encodeMarshaler(b []byte, m any, marshal func() ([]byte, error)) ([]byte, error) {
+	record, err := marshal()
+	if err != nil {
+		return b, vCardErrf("error during marshaling %T: %w", m, err)
+	}
+	b = append(b, record...)
+	if len(record) != 0 && record[len(record)-1] != '\n' {
+		b = append(b, e.newlineSequence...)
+	}
+	return b, nil
+}
 
-			fieldBytes, err := v.MarshalVCardField()
-			if err != nil {
-				return b, vCardErrf("error during marshaling field %q %sof struct %s: %w", fieldDesc.Name, taggedMsg, struc.Type(), err)
-			}
-			buf = append(buf, fmt.Sprintf("%s%s%s", vCardName, fieldBytes, e.newlineSequence)...)
+// Returns rest of a content line for a value of a struct field or a map e.g. ":Alex" or ";TYPE=CELL:555".
+//
+// Values are encoded using [VCardFieldVersionMarshaler], [VCardFieldMarshaler], then [encoding.TextMarshaler]
+// and then by kind. version is a version of the record being encoded. Slices of strings are encoded
+// as comma-separated lists of TEXT values e.g. CATEGORIES:work,friends.
+// ok is false if type of v is not supported. Empty rest means the value is nil and has to be omitted.
+func (e *Encoder) marshalValue(v reflect.Value, version string) (rest string, ok bool, err error) {
+	if v.Kind() == reflect.Interface || v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return "", true, nil
+		}
+	}
+	if m, found := asInterface[VCardFieldVersionMarshaler](v); found {
+		b, err := m.MarshalVCardFieldVersion(version)
+		return string(b), true, err
+	}
+	if m, found := asInterface[VCardFieldMarshaler](v); found {
+		b, err := m.MarshalVCardField()
+		return string(b), true, err
+	}
+	if v.Type() == timeType {
+		return marshalTime(v.Interface().(time.Time), version)
+	}
+	if v.Type() == urlType {
+		u := v.Interface().(url.URL)
+		if u == (url.URL{}) {
+			return "", true, nil
+		}
+		return ":" + u.String(), true, nil
+	}
+	if m, found := asInterface[encoding.TextMarshaler](v); found {
+		b, err := m.MarshalText()
+		// Text never contains parameters, so smart strings do not apply to it
+		return ":" + string(b), true, err
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return e.stringRest(v.String()), true, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return ":" + strconv.FormatInt(v.Int(), 10), true, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return ":" + strconv.FormatUint(v.Uint(), 10), true, nil
+	case reflect.Float32, reflect.Float64:
+		return ":" + strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), true, nil
+	case reflect.Bool:
+		return ":" + strconv.FormatBool(v.Bool()), true, nil
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			return "", false, nil
+		}
+		if v.Len() == 0 {
+			return "", true, nil
+		}
+		values := make([]string, v.Len())
+		for i := range values {
+			values[i] = v.Index(i).String()
+		}
+		return ":" + joinTextList(values), true, nil
+	case reflect.Interface, reflect.Pointer:
+		return e.marshalValue(v.Elem(), version)
+	}
+	return "", false, nil
+}
 
-		default:
-			return b, vCardErrf("field %q %sof a struct %s has unsupported type %s. Use string or a struct that implements VCardFieldMarshaler", fieldDesc.Name, taggedMsg, struc.Type(), field.Type())
+// URI-valued fields like URL or SOURCE may be of type url.URL or *url.URL.
+var urlType = reflect.TypeFor[url.URL]()
+
+// Reports whether values of type t may be supported by [Encoder.marshalValue].
+// Interfaces are checked for every value separately.
+func encodableType(t reflect.Type) bool {
+	switch {
+	case t.Implements(reflect.TypeFor[VCardFieldVersionMarshaler]()),
+		t.Implements(reflect.TypeFor[VCardFieldMarshaler]()),
+		t.Implements(reflect.TypeFor[encoding.TextMarshaler]()),
+		t == urlType, t == timeType:
+		return true
+	case t.Kind() == reflect.Pointer:
+		return encodableType(t.Elem())
+	}
+	if t.Kind() == reflect.Slice {
+		return t.Elem().Kind() == reflect.String
+	}
+	return t.Kind() == reflect.String || t.Kind() == reflect.Interface || isScalarKind(t.Kind())
+}
+
+// Reports whether values of kind k are encoded with strconv e.g. int, float64 and bool.
+func isScalarKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.Bool:
+		return true
+	}
+	return false
+}
+
+// Returns T implemented either by v or by a pointer to v.
+func asInterface[T any](v reflect.Value) (T, bool) {
+	if v.CanInterface() {
+		if i, ok := v.Interface().(T); ok {
+			return i, true
 		}
 	}
+	if v.CanAddr() && v.Addr().CanInterface() {
+		i, ok := v.Addr().Interface().(T)
+		return i, ok
+	}
+	var zero T
+	return zero, false
+}
+
+// Content line of a record being encoded. rest contains parameters and a value e.g. ";TYPE=CELL:555".
+type encodedField struct {
+	name string
+	rest string
+}
 
-	buf = e.encodeRecordFooter(buf, ctx)
+// Writes BEGIN:VCARD, VERSION, fields of a record and END:VCARD.
+//
+// Intermidiate buffer makes sure there was no errors before appending the record to b.
+func (e *Encoder) encodeRecord(b []byte, version string, fields []encodedField) ([]byte, error) {
+
+	if e.profile.exportCard != nil {
+		var err error
+		version, fields, err = e.profile.exportFields(version, fields)
+		if err != nil {
+			return b, err
+		}
+	}
+	fields = e.downgrade(fields, version)
+
+	buf := e.encodeRecordHeader([]byte{}, version)
+	for _, f := range fields {
+		var err error
+		buf, err = e.appendField(buf, f.name, f.rest)
+		if err != nil {
+			return b, err
+		}
+	}
+	buf = e.encodeRecordFooter(buf)
 
 	return append(b, buf...), nil
 }
 
-func (e *Encoder) encodeRecordHeader(b []byte, ctx encoderCtx) []byte {
-	return append(b, fmt.Sprintf("BEGIN:VCARD%sVERSION:%s%s", e.newlineSequence, ctx.schema.version, e.newlineSequence)...)
+// Returns rest of a string field. In smart mode `:` is added in front of s unless s already
+// starts with `:` or with parameters followed by `:`.
+//
+// See [Encoder.SetSmartStrings].
+func (e *Encoder) stringRest(s string) string {
+	if !e.smartStrings {
+		return s
+	}
+	if strings.HasPrefix(s, ":") {
+		return s
+	}
+	if strings.HasPrefix(s, ";") {
+		if _, _, found := splitParamsValue(s); found {
+			return s
+		}
+	}
+	return ":" + s
+}
+
+// Appends a content line "NAME" + rest where rest contains parameters and a value e.g. ";TYPE=CELL:555".
+func (e *Encoder) appendField(b []byte, name string, rest string) ([]byte, error) {
+	rest, err := e.checkControlChars(name, rest)
+	if err != nil {
+		return b, err
+	}
+	b = append(b, name...)
+	b = append(b, rest...)
+	return append(b, e.newlineSequence...), nil
+}
+
+// Applies [ControlCharPolicy] to the encoded field.
+func (e *Encoder) checkControlChars(name string, rest string) (string, error) {
+	if e.controlChars == ControlCharsAllow {
+		return rest, nil
+	}
+	idx := strings.IndexFunc(rest, isForbiddenControl)
+	if idx == -1 {
+		return rest, nil
+	}
+	if e.controlChars == ControlCharsReject {
+		return rest, validationErrf("field %q contains control character %q at offset %v", name, rest[idx], idx)
+	}
+
+	buf := strings.Builder{}
+	buf.WriteString(rest[:idx])
+	for i := idx; i < len(rest); i++ {
+		c := rest[i]
+		switch {
+		case c == '\r' && i+1 < len(rest) && rest[i+1] == '\n':
+			// CRLF is a single line break
+			continue
+		case c == '\r' || c == '\n':
+			buf.WriteString(`\n`)
+		case isForbiddenControl(rune(c)):
+			// dropped
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	return buf.String(), nil
+}
+
+// Reports whether r is a control character which is not allowed in a content line.
+// HTAB is allowed as per https://datatracker.ietf.org/doc/html/rfc6350#section-3.3
+func isForbiddenControl(r rune) bool {
+	return (r < 0x20 && r != '\t') || r == 0x7f
+}
+
+func (e *Encoder) encodeRecordHeader(b []byte, version string) []byte {
+	return append(b, fmt.Sprintf("BEGIN:VCARD%sVERSION:%s%s", e.newlineSequence, version, e.newlineSequence)...)
 }
 
-func (e *Encoder) encodeRecordFooter(b []byte, _ encoderCtx) []byte {
+func (e *Encoder) encodeRecordFooter(b []byte) []byte {
 	return append(b, fmt.Sprintf("END:VCARD%s", e.newlineSequence)...)
 }
 
@@ -311,47 +702,149 @@ func (e *Encoder) encodeSlice(b []byte, slice reflect.Value, ctx encoderCtx) ([]
 	if slice.Len() == 0 {
 		return b, nil
 	}
-	// Intermidiate buffer makes sure there was no errors before writing bytes
-	buf := []byte{}
 
 	elemKind := slice.Index(0).Kind()
 
+	var encodeElem func([]byte, reflect.Value, encoderCtx) ([]byte, error)
 	switch elemKind {
 	case reflect.Map:
-		for i := range slice.Len() {
-			elem := slice.Index(i)
-			var err error
-			buf, err = e.encodeMap(buf, elem, ctx)
-			if err != nil {
-				return b, vCardErrf("error during marshaling slice member idx=%v: %w", i, err)
-			}
-		}
+		encodeElem = e.encodeMap
 	case reflect.Struct:
-		for i := range slice.Len() {
-			elem := slice.Index(i)
-			var err error
-			buf, err = e.encodeStruct(buf, elem, ctx)
-			if err != nil {
-				return b, vCardErrf("error during marshaling slice member idx=%v: %w", i, err)
-			}
-		}
+		encodeElem = e.encodeStruct
 	case reflect.Interface:
-		for i := range slice.Len() {
-			elem := slice.Index(i)
-			var err error
-			buf, err = e.encode(buf, elem, ctx)
-			if err != nil {
-				return b, vCardErrf("error during marshaling slice member idx=%v: %w", i, err)
-			}
-		}
+		encodeElem = e.encode
 	default:
 		return b, vCardErrf("unable to encode slice of type %s. Use slice of structs or maps", elemKind)
 	}
+
+	if e.parallelism > 1 && slice.Len() >= parallelMinSliceLen {
+		buf, err := e.encodeSliceParallel(slice, encodeElem, ctx)
+		if err != nil {
+			return b, err
+		}
+		return append(b, buf...), nil
+	}
+
+	// Intermidiate buffer makes sure there was no errors before writing bytes
+	buf, err := encodeSliceRange(slice, 0, slice.Len(), encodeElem, ctx)
+	if err != nil {
+		return b, err
+	}
 	return append(b, buf...), nil
 }
 
+// Slices shorter than this are always encoded sequentially because
+// spawning goroutines costs more than encoding itself.
+const parallelMinSliceLen = 1024
+
+// Splits slice into a chunk per worker, encodes chunks concurrently into separate buffers and
+// stitches buffers together in the original order.
+func (e *Encoder) encodeSliceParallel(slice reflect.Value, encodeElem func([]byte, reflect.Value, encoderCtx) ([]byte, error), ctx encoderCtx) ([]byte, error) {
+	n := slice.Len()
+	workers := min(e.parallelism, n)
+	chunkLen := (n + workers - 1) / workers
+
+	bufs := make([][]byte, workers)
+	errs := make([]error, workers)
+
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Go(func() {
+			from := w * chunkLen
+			to := min(from+chunkLen, n)
+			bufs[w], errs[w] = encodeSliceRange(slice, from, to, encodeElem, ctx)
+		})
+	}
+	wg.Wait()
+
+	size := 0
+	for w := range workers {
+		// Chunks are ordered so the first error belongs to the member with the lowest index
+		if errs[w] != nil {
+			return []byte{}, errs[w]
+		}
+		size += len(bufs[w])
+	}
+
+	buf := make([]byte, 0, size)
+	for _, chunk := range bufs {
+		buf = append(buf, chunk...)
+	}
+	return buf, nil
+}
+
+func encodeSliceRange(slice reflect.Value, from int, to int, encodeElem func([]byte, reflect.Value, encoderCtx) ([]byte, error), ctx encoderCtx) ([]byte, error) {
+	buf := []byte{}
+	for i := from; i < to; i++ {
+		ctx.record = i
+		var err error
+		buf, err = encodeElem(buf, slice.Index(i), ctx)
+		if err != nil {
+			return []byte{}, vCardErrf("error during marshaling slice member idx=%v: %w", i, err)
+		}
+	}
+	return buf, nil
+}
+
+// Checks encoded fields against cardinalities, parameters and validators defined by the schema.
+func (e *Encoder) checkFields(s Schema, fields []encodedField) error {
+	err := s.checkCardinality(fieldNames(fields))
+	if err != nil {
+		return err
+	}
+	for _, f := range fields {
+		params, value, _ := splitParamsValue(f.rest)
+		err := s.checkParams(f.name, params)
+		if err != nil {
+			return err
+		}
+		err = reportViolation(e.warn, s.validate(f.name, value))
+		if err != nil {
+			return err
+		}
+	}
+	return s.checkConstraints(func(name string) []string {
+		values := []string{}
+		for _, f := range fields {
+			if canonicalPropertyName(f.name) == canonicalPropertyName(name) {
+				_, value, _ := splitParamsValue(f.rest)
+				values = append(values, value)
+			}
+		}
+		return values
+	})
+}
+
+// Returns names of encoded fields.
+func fieldNames(fields []encodedField) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for _, f := range fields {
+			if !yield(f.name) {
+				return
+			}
+		}
+	}
+}
+
 type encoderCtx struct {
 	schema Schema
+
+	// schema prepared by the caller of EncodePrepared, may be empty
+	prepared PreparedSchema
+
+	// index of a record being encoded in a slice or a sequence
+	record int
+}
+
+// Returns schema prepared for typ reusing the one provided by the caller if it matches.
+func (e *Encoder) prepare(ctx encoderCtx, typ reflect.Type) PreparedSchema {
+	if e.mapper != nil {
+		return ctx.schema.prepareMapped(typ, e.tagKey, e.fallbackTagKey, e.mapper, &e.mapped)
+	}
+	if ctx.prepared.typ == typ && ctx.prepared.tagKey == e.tagKey && ctx.prepared.fallbackTagKey == e.fallbackTagKey {
+		return ctx.prepared
+	}
+	return ctx.schema.prepareTags(typ, e.tagKey, e.fallbackTagKey)
 }
 
 // Implemented by fields that need custom Marshaling logic.
@@ -373,6 +866,37 @@ type encoderCtx struct {
 //			// final result is TEL;TYPE=CELL:(123) 555-5832
 //			return fmt.Sprintf(";TYPE=%s:%s", typ, tel), nil
 //		}
+//
+// See [Tel] for a complete implementation.
 type VCardFieldMarshaler interface {
 	MarshalVCardField() ([]byte, error)
 }
+
+// Implemented by fields which are encoded differently depending on vCard version of a record
+// e.g. [Geo] is a geo: URI in vCard 4.0 and "lat;lon" in vCard 3.0.
+//
+// Takes precedence over [VCardFieldMarshaler].
+type VCardFieldVersionMarshaler interface {
+	MarshalVCardFieldVersion(version string) ([]byte, error)
+}
+
+// Implemented by types that need custom Marshaling logic for an entire card.
+//
+// Unlike [VCardFieldMarshaler], MarshalVCard has to return a complete record from
+// BEGIN:VCARD to END:VCARD. Encoder writes it as is, so types with a bespoke format can
+// still use slices, [EncodeSeq] and other machinery of this package.
+//
+//	func (c Contact) MarshalVCard() ([]byte, error) {
+//		return fmt.Appendf(nil, "BEGIN:VCARD\r\nVERSION:4.0\r\nFN:%s\r\nEND:VCARD\r\n", c.name), nil
+//	}
+type VCardMarshaler interface {
+	MarshalVCard() ([]byte, error)
+}
+
+// Implemented by types which encode an entire card differently depending on vCard version
+// of the schema used for encoding e.g. [Contact].
+//
+// Takes precedence over [VCardMarshaler].
+type VCardVersionMarshaler interface {
+	MarshalVCardVersion(version string) ([]byte, error)
+}
@@ -1,8 +1,12 @@
 package vcard
 
 import (
+	"bytes"
 	"fmt"
+	"net/url"
+	"strings"
 	"testing"
+	"time"
 )
 
 // Map Marshaling Tests
@@ -244,7 +248,7 @@ func TestMapAnyValueDoesNotImplementMarshaler(t *testing.T) {
 
 func TestUnsupportedTypeAsMapValue(t *testing.T) {
 
-	m := map[string]int{
+	m := map[string]complex128{
 		"N":    10,
 		"FN":   11,
 		"NAME": 16,
@@ -252,7 +256,7 @@ func TestUnsupportedTypeAsMapValue(t *testing.T) {
 
 	b, err := Marshal(m)
 
-	assertErrIs(t, err, ErrVCard, "type int is not supported as a map value")
+	assertErrIs(t, err, ErrVCard, "type complex128 is not supported as a map value")
 	assertSlicesEq(t, b, []byte{})
 }
 
@@ -413,6 +417,35 @@ END:VCARD
 	assertStringLinesEq(t, string(b), crlfy(exp))
 }
 
+func TestStructStringFieldsSmartColon(t *testing.T) {
+
+	type ColonUser struct {
+		FN   string
+		TEL  string
+		URL  string
+		NOTE string
+	}
+	s := ColonUser{
+		FN:   ":Alex",
+		TEL:  ";TYPE=CELL:555",
+		URL:  "https://example.com",
+		NOTE: "Meet at 10:30",
+	}
+
+	b, err := Marshal(s)
+
+	exp := `BEGIN:VCARD
+VERSION:4.0
+FN:Alex
+TEL;TYPE=CELL:555
+URL:https://example.com
+NOTE:Meet at 10:30
+END:VCARD
+`
+	assertEq(t, err, nil)
+	assertStringLinesEq(t, string(b), crlfy(exp))
+}
+
 type CustomMarshalerUser struct {
 	N    MarshalVCardImpl
 	FN   MarshalVCardImpl
@@ -594,3 +627,379 @@ END:VCARD
 `
 	assertStringLinesEq(t, string(b), crlfy(exp))
 }
+
+func TestEncodeSeq(t *testing.T) {
+
+	seq := func(yield func(StringUser) bool) {
+		for i := range 3 {
+			u := StringUser{
+				N:    fmt.Sprintf("Alex %v", i+1),
+				FN:   fmt.Sprintf("Alex FullName %v", i+1),
+				NAME: fmt.Sprintf("Alex Name Hello %v", i+1),
+			}
+			if !yield(u) {
+				return
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	err := EncodeSeq(NewEncoder(&buf), seq, SchemaV4)
+
+	exp := `BEGIN:VCARD
+VERSION:4.0
+N:Alex 1
+FN:Alex FullName 1
+NAME:Alex Name Hello 1
+END:VCARD
+BEGIN:VCARD
+VERSION:4.0
+N:Alex 2
+FN:Alex FullName 2
+NAME:Alex Name Hello 2
+END:VCARD
+BEGIN:VCARD
+VERSION:4.0
+N:Alex 3
+FN:Alex FullName 3
+NAME:Alex Name Hello 3
+END:VCARD
+`
+	assertEq(t, err, nil)
+	assertStringLinesEq(t, buf.String(), crlfy(exp))
+}
+
+func TestEncodeSeqStopsOnError(t *testing.T) {
+
+	seq := func(yield func(any) bool) {
+		if !yield(StringUser{N: "Alex", FN: "Alex FullName"}) {
+			return
+		}
+		if !yield(NotMarshaler{"Alex"}) {
+			return
+		}
+		t.Errorf("sequence should not be consumed after an error")
+	}
+
+	var buf bytes.Buffer
+	err := EncodeSeq(NewEncoder(&buf), seq, SchemaV4)
+
+	exp := `BEGIN:VCARD
+VERSION:4.0
+N:Alex
+FN:Alex FullName
+NAME:
+END:VCARD
+`
+	assertErrIs(t, err, ErrVCard, "sequence member idx=1")
+	assertStringLinesEq(t, buf.String(), crlfy(exp))
+}
+
+func TestEncodeChan(t *testing.T) {
+
+	ch := make(chan map[string]string)
+	go func() {
+		defer close(ch)
+		ch <- map[string]string{"N": "Alex 1", "FN": "Alex FullName 1"}
+		ch <- map[string]string{"N": "Alex 2", "FN": "Alex FullName 2"}
+	}()
+
+	var buf bytes.Buffer
+	err := EncodeChan(NewEncoder(&buf), ch, SchemaV4)
+
+	exp := `BEGIN:VCARD
+VERSION:4.0
+N:Alex 1
+FN:Alex FullName 1
+END:VCARD
+BEGIN:VCARD
+VERSION:4.0
+N:Alex 2
+FN:Alex FullName 2
+END:VCARD
+`
+	assertEq(t, err, nil)
+	assertStringLinesEq(t, buf.String(), crlfy(exp))
+}
+
+func TestMarshalSliceParallel(t *testing.T) {
+
+	sl := make([]StringUser, 5000)
+	for i := range sl {
+		sl[i] = StringUser{
+			N:    fmt.Sprintf("Alex %v", i),
+			FN:   fmt.Sprintf("Alex FullName %v", i),
+			NAME: fmt.Sprintf("Alex Name Hello %v", i),
+		}
+	}
+
+	var seq bytes.Buffer
+	err := NewEncoder(&seq).Encode(sl)
+	assertEq(t, err, nil)
+
+	var par bytes.Buffer
+	err = NewEncoder(&par).SetParallelism(8).Encode(sl)
+	assertEq(t, err, nil)
+
+	assertEq(t, par.String(), seq.String())
+}
+
+func TestMarshalSliceParallelError(t *testing.T) {
+
+	sl := make([]any, 3000)
+	for i := range sl {
+		sl[i] = StringUser{N: "Alex", FN: "Alex FullName"}
+	}
+	sl[2500] = 10
+	sl[1200] = 11
+
+	var buf bytes.Buffer
+	err := NewEncoder(&buf).SetParallelism(4).Encode(sl)
+
+	assertErrIs(t, err, ErrVCard, "idx=1200")
+	assertEq(t, buf.Len(), 0)
+}
+
+func TestControlCharsAllowedByDefault(t *testing.T) {
+
+	s := StringUser{N: "Alex", FN: "Alex\r\nFullName"}
+
+	b, err := Marshal(s)
+
+	assertEq(t, err, nil)
+	assertEq(t, strings.Contains(string(b), "FN:Alex\r\nFullName\r\n"), true)
+}
+
+func TestControlCharsReject(t *testing.T) {
+
+	s := StringUser{N: "Alex", FN: "Alex\r\nFullName"}
+
+	var buf bytes.Buffer
+	err := NewEncoder(&buf).SetControlCharPolicy(ControlCharsReject).Encode(s)
+
+	assertErrIs(t, err, ErrValidation, "field \"FN\" contains control character '\\r'")
+	assertEq(t, buf.Len(), 0)
+}
+
+func TestControlCharsEscape(t *testing.T) {
+
+	m := map[string]VCardFieldMarshaler{
+		"FN":   MarshalVCardImpl{"Alex\x00"},
+		"NOTE": MarshalVCardImpl{"Line 1\r\nLine 2\nLine\t3"},
+	}
+
+	var buf bytes.Buffer
+	err := NewEncoder(&buf).SetControlCharPolicy(ControlCharsEscape).Encode(m)
+
+	exp := "BEGIN:VCARD\r\n" +
+		"VERSION:4.0\r\n" +
+		"FN;;Alex;;\r\n" +
+		"NOTE;;Line 1\\nLine 2\\nLine\t3;;\r\n" +
+		"END:VCARD\r\n"
+
+	assertEq(t, err, nil)
+	assertStringLinesEq(t, buf.String(), exp)
+}
+
+type RecordMarshalerImpl struct {
+	name string
+}
+
+func (r RecordMarshalerImpl) MarshalVCard() ([]byte, error) {
+	return fmt.Appendf(nil, "BEGIN:VCARD\r\nVERSION:3.0\r\nFN:%s\r\nEND:VCARD", r.name), nil
+}
+
+func TestStructRecordMarshaler(t *testing.T) {
+
+	sl := []RecordMarshalerImpl{{"Alex 1"}, {"Alex 2"}}
+
+	b, err := Marshal(sl)
+
+	exp := `BEGIN:VCARD
+VERSION:3.0
+FN:Alex 1
+END:VCARD
+BEGIN:VCARD
+VERSION:3.0
+FN:Alex 2
+END:VCARD
+`
+	assertEq(t, err, nil)
+	assertStringsEq(t, string(b), crlfy(exp))
+}
+
+type textGender int
+
+const (
+	textGenderUnknown textGender = iota
+	textGenderFemale
+)
+
+func (g textGender) MarshalText() ([]byte, error) {
+	if g == textGenderFemale {
+		return []byte("F"), nil
+	}
+	return []byte("U"), nil
+}
+
+func (g *textGender) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "F":
+		*g = textGenderFemale
+	case "U":
+		*g = textGenderUnknown
+	default:
+		return fmt.Errorf("unknown gender %q", text)
+	}
+	return nil
+}
+
+type TextMarshalerStruct struct {
+	FN     string
+	GENDER textGender
+	REV    time.Time
+	NOTE   *time.Time
+}
+
+func TestStructTextMarshaler(t *testing.T) {
+
+	s := TextMarshalerStruct{
+		FN:     "Alex",
+		GENDER: textGenderFemale,
+		REV:    time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	b, err := Marshal(s)
+
+	exp := `BEGIN:VCARD
+VERSION:4.0
+FN:Alex
+GENDER:F
+REV:20240102T030405Z
+END:VCARD
+`
+	assertEq(t, err, nil)
+	assertStringsEq(t, string(b), crlfy(exp))
+}
+
+func TestMapTextMarshaler(t *testing.T) {
+
+	m := map[string]textGender{"FN": textGenderFemale}
+
+	b, err := Marshal(m)
+
+	exp := `BEGIN:VCARD
+VERSION:4.0
+FN:F
+END:VCARD
+`
+	assertEq(t, err, nil)
+	assertStringsEq(t, string(b), crlfy(exp))
+}
+
+type DuplicateKindStruct struct {
+	FN       string
+	Kind     string `vCard:"KIND"`
+	KindTwin string `vCard:"KIND,type=X"`
+}
+
+func TestStructCardinalityViolation(t *testing.T) {
+
+	_, err := Marshal(DuplicateKindStruct{FN: "Alex", Kind: "org", KindTwin: "org"})
+
+	assertErrIs(t, err, ErrValidation, `cardinality defined by the schema is *1`)
+}
+
+type ListStruct struct {
+	FN         string
+	CATEGORIES []string
+	NICKNAME   []string `vCard:",omitempty"`
+}
+
+func TestStringListFields(t *testing.T) {
+
+	b, err := Marshal(ListStruct{FN: "Alex", CATEGORIES: []string{"work", "a,b;c"}})
+
+	exp := `BEGIN:VCARD
+VERSION:4.0
+FN:Alex
+CATEGORIES:work,a\,b\;c
+END:VCARD
+`
+	assertEq(t, err, nil)
+	assertStringsEq(t, string(b), crlfy(exp))
+
+	s := ListStruct{}
+	err = Unmarshal(b, &s)
+
+	assertEq(t, err, nil)
+	assertSlicesEq(t, s.CATEGORIES, []string{"work", "a,b;c"})
+	assertEq(t, len(s.NICKNAME), 0)
+
+	assertSlicesEq(t, splitTextList(`a\\,b,,c\n`), []string{`a\`, "b", "", "c\n"})
+	assertSlicesEq(t, splitTextList(""), []string{})
+}
+
+func TestEncURL(t *testing.T) {
+
+	type URLStruct struct {
+		FN     string
+		URL    url.URL
+		SOURCE *url.URL
+		FBURL  *url.URL
+	}
+	source, _ := url.Parse("https://example.com/alex.vcf")
+	s := URLStruct{
+		FN:     "Alex",
+		URL:    url.URL{Scheme: "https", Host: "example.com", Path: "/alex"},
+		SOURCE: source,
+	}
+	b, err := Marshal(s)
+
+	assertEq(t, err, nil)
+	assertStringLinesEq(t, string(b), crlfy(`BEGIN:VCARD
+VERSION:4.0
+FN:Alex
+URL:https://example.com/alex
+SOURCE:https://example.com/alex.vcf
+END:VCARD
+`))
+}
+
+type ScalarStruct struct {
+	FN        string
+	XPRIORITY int     `vCard:"X-PRIORITY"`
+	XSCORE    float64 `vCard:"X-SCORE"`
+	XFAVORITE bool    `vCard:"X-FAVORITE"`
+	XCOUNT    uint8   `vCard:"X-COUNT,omitempty"`
+}
+
+func TestScalarFields(t *testing.T) {
+
+	s := ScalarStruct{FN: "Alex", XPRIORITY: -2, XSCORE: 0.75, XFAVORITE: true}
+	schemas := []Schema{SchemaFor[ScalarStruct]("4.0")}
+
+	b, err := MarshalSchema(s, schemas[0])
+
+	exp := crlfy(`BEGIN:VCARD
+VERSION:4.0
+FN:Alex
+X-PRIORITY:-2
+X-SCORE:0.75
+X-FAVORITE:true
+END:VCARD
+`)
+	assertEq(t, err, nil)
+	assertStringLinesEq(t, string(b), exp)
+
+	decoded := ScalarStruct{}
+	err = UnmarshalSchema([]byte(exp), &decoded, schemas)
+	assertEq(t, err, nil)
+	assertEq(t, decoded, s)
+
+	err = UnmarshalSchema([]byte(strings.Replace(exp, "X-PRIORITY:-2", "X-PRIORITY:high", 1)), &decoded, schemas)
+	assertErrIs(t, err, ErrParsing, `unable to decode "high" as int`)
+
+	err = UnmarshalSchema([]byte(strings.Replace(exp, "X-FAVORITE:true", "X-COUNT:300", 1)), &decoded, schemas)
+	assertErrIs(t, err, ErrParsing, `unable to decode "300" as uint8`)
+}
@@ -0,0 +1,183 @@
+package vcard
+
+import (
+	"slices"
+	"strings"
+)
+
+// Encodes the card as MECARD used by QR codes of mobile phones e.g.
+// "MECARD:N:Smith,Alex;TEL:+15550100;EMAIL:alex@example.com;;". Use [Contact.Card] to encode a [Contact].
+//
+// MECARD is a subset of vCard: N (or FN if the card has no N), SORT-AS of N as SOUND, TEL, EMAIL,
+// ADR, URL, BDAY, the first NICKNAME, NOTE and ORG are encoded. Other properties are dropped.
+// Returns [ErrValidation] if the card has neither N nor FN since MECARD requires a name.
+func MarshalMECARD(card Card) ([]byte, error) {
+	b := []byte("MECARD:")
+	field := func(name string, values ...string) {
+		b = append(b, name...)
+		b = append(b, ':')
+		for i, v := range values {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			b = append(b, escapeMECARD(v)...)
+		}
+		b = append(b, ';')
+	}
+
+	n, hasN := card.Name()
+	fn, _ := card.FN()
+	switch {
+	case hasN && !n.isZero():
+		field("N", strings.Join(n.FamilyNames, " "), strings.Join(n.GivenNames, " "))
+	case strings.TrimSpace(fn) != "":
+		field("N", fn)
+	default:
+		return nil, validationErrf("card has neither N nor FN required by MECARD")
+	}
+	if len(n.SortAs) > 0 {
+		field("SOUND", n.SortAs...)
+	}
+
+	for _, p := range topLevelProperties(card.props) {
+		switch p.Name {
+		case "TEL":
+			field("TEL", strings.TrimPrefix(p.Value, "tel:"))
+		case "EMAIL":
+			field("EMAIL", strings.TrimPrefix(p.Value, "mailto:"))
+		case "ADR":
+			a := Adr{}
+			_ = a.UnmarshalVCardField([]byte(p.rest()))
+			field("ADR", a.POBox, a.Extended, a.Street, a.Locality, a.Region, a.PostalCode, a.Country)
+		case "URL":
+			field("URL", p.Value)
+		case "BDAY":
+			d := Date{}
+			if err := d.UnmarshalVCardField([]byte(p.rest())); err == nil && d.Year != 0 && d.Month != 0 && d.Day != 0 {
+				field("BDAY", Date{Year: d.Year, Month: d.Month, Day: d.Day}.String())
+			}
+		}
+	}
+	for _, name := range []string{"NICKNAME", "NOTE"} {
+		if p, found := card.first(name); found {
+			values := splitTextList(p.Value)
+			if name == "NOTE" {
+				values = []string{unescapeText(p.Value)}
+			}
+			if len(values) > 0 && values[0] != "" {
+				field(name, values[0])
+			}
+		}
+	}
+	if o, found := card.Organization(); found && o.Name != "" {
+		field("ORG", o.Name)
+	}
+	return append(b, ';'), nil
+}
+
+// Decodes MECARD e.g. "MECARD:N:Smith,Alex;TEL:+15550100;;" into a card of vCard 4.0.
+// FN is made of the name as "Alex Smith". Unknown fields are dropped.
+//
+// Returns [ErrParsing] if data does not start with "MECARD:" or contains no N.
+func UnmarshalMECARD(data []byte) (Card, error) {
+	s := strings.TrimSpace(string(data))
+	if len(s) < 7 || !strings.EqualFold(s[:7], "MECARD:") {
+		return Card{}, parsingErrf("MECARD has to start with \"MECARD:\"")
+	}
+
+	c := Card{}
+	c.Add("VERSION", "4.0")
+	name := Name{}
+	hasName := false
+	for _, f := range splitMECARD(s[7:], ';') {
+		key, value, found := strings.Cut(f, ":")
+		if !found {
+			continue
+		}
+		values := splitMECARD(value, ',')
+		for i, v := range values {
+			values[i] = unescapeMECARD(v)
+		}
+		text := unescapeMECARD(value)
+
+		switch strings.ToUpper(strings.TrimSpace(key)) {
+		case "N":
+			hasName = true
+			name.FamilyNames = []string{values[0]}
+			if len(values) > 1 {
+				name.GivenNames = []string{strings.Join(values[1:], " ")}
+			}
+		case "SOUND":
+			name.SortAs = values
+		case "TEL":
+			c.Add("TEL", text)
+		case "EMAIL":
+			c.Add("EMAIL", text)
+		case "URL":
+			c.Add("URL", text)
+		case "NOTE", "NICKNAME":
+			c.Add(strings.ToUpper(key), escapeText(text))
+		case "ORG":
+			c.Add("ORG", JoinStructured([]string{text}))
+		case "BDAY":
+			if d, err := ParseDate(text); err == nil {
+				c.Add("BDAY", d.String())
+			}
+		case "ADR":
+			if len(values) != 7 {
+				values = []string{"", "", text, "", "", "", ""}
+			}
+			c.Add("ADR", JoinStructured(values))
+		}
+	}
+	if !hasName {
+		return Card{}, parsingErrf("MECARD does not contain N")
+	}
+
+	rest, _ := name.MarshalVCardField()
+	n, err := ParseProperty("N" + string(rest))
+	if err != nil {
+		return Card{}, parsingErrf("unable to decode N of MECARD: %w", err)
+	}
+	fn := displayName(Name{GivenNames: name.GivenNames, FamilyNames: name.FamilyNames}, Org{})
+	c.props = slices.Insert(c.props, 1, Property{Name: "FN", Value: escapeText(fn)}, n)
+	return c, nil
+}
+
+// Escapes characters which are special in MECARD with a backslash.
+func escapeMECARD(s string) string {
+	return mecardEscaper.Replace(s)
+}
+
+var mecardEscaper = strings.NewReplacer(`\`, `\\`, `;`, `\;`, `,`, `\,`, `:`, `\:`, `"`, `\"`)
+
+func unescapeMECARD(s string) string {
+	b := strings.Builder{}
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// Splits s at sep which is not escaped with a backslash. Escapes are kept. Empty fields separated
+// by ';' are dropped, so the terminating ";;" yields nothing, while empty components separated by ',' are kept.
+func splitMECARD(s string, sep byte) []string {
+	parts := []string{}
+	start := 0
+	for i := 0; i <= len(s); i++ {
+		if i < len(s) && s[i] == '\\' {
+			i++
+			continue
+		}
+		if i >= len(s) || s[i] == sep {
+			if part := s[start:min(i, len(s))]; part != "" || sep == ',' {
+				parts = append(parts, part)
+			}
+			start = i + 1
+		}
+	}
+	return parts
+}
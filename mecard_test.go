@@ -0,0 +1,76 @@
+package vcard
+
+import "testing"
+
+func TestMarshalMECARD(t *testing.T) {
+
+	card := Card{}
+	err := Unmarshal([]byte(crlfy(`BEGIN:VCARD
+VERSION:3.0
+FN:Alex Smith
+N:Smith;Alex;;;
+SORT-STRING:smith
+TEL;TYPE=cell:+1 555 0100
+EMAIL:alex@example.com
+ADR:;;123 Main St.;Anytown;CA;91921;USA
+URL:https://example.com/a:b
+BDAY:1990-01-02
+NICKNAME:Al,Lex
+NOTE:Call me\; maybe\, later
+ORG:Acme\, Inc.;Sales
+PHOTO;VALUE=uri:http://example.com/a.jpg
+END:VCARD
+`)), &card)
+	assertEq(t, err, nil)
+
+	b, err := MarshalMECARD(card)
+	assertEq(t, err, nil)
+	assertEq(t, string(b), `MECARD:N:Smith,Alex;SOUND:smith;TEL:+1 555 0100;EMAIL:alex@example.com;`+
+		`ADR:,,123 Main St.,Anytown,CA,91921,USA;URL:https\://example.com/a\:b;BDAY:19900102;`+
+		`NICKNAME:Al;NOTE:Call me\; maybe\, later;ORG:Acme\, Inc.;;`)
+
+	decoded, err := UnmarshalMECARD(b)
+	assertEq(t, err, nil)
+	b, err = Marshal(decoded)
+	assertEq(t, err, nil)
+	assertStringLinesEq(t, string(b), crlfy(`BEGIN:VCARD
+VERSION:4.0
+FN:Alex Smith
+N;SORT-AS=smith:Smith;Alex;;;
+TEL:+1 555 0100
+EMAIL:alex@example.com
+ADR:;;123 Main St.;Anytown;CA;91921;USA
+URL:https://example.com/a:b
+BDAY:19900102
+NICKNAME:Al
+NOTE:Call me\; maybe\, later
+ORG:Acme\, Inc.
+END:VCARD
+`))
+
+	fnOnly := Card{}
+	fnOnly.Add("FN", "Kim")
+	b, err = MarshalMECARD(fnOnly)
+	assertEq(t, err, nil)
+	assertEq(t, string(b), "MECARD:N:Kim;;")
+
+	_, err = MarshalMECARD(Card{})
+	assertErrIs(t, err, ErrValidation, "card has neither N nor FN")
+}
+
+func TestUnmarshalMECARD(t *testing.T) {
+
+	card, err := UnmarshalMECARD([]byte("mecard:N:Doe,John;TEL:555;TEL:556;ADR:1 Main St\\, Town;BDAY:bad;X-UNKNOWN:1;;\n"))
+	assertEq(t, err, nil)
+	assertSlicesEq(t, card.Values("TEL"), []string{"555", "556"})
+	assertSlicesEq(t, card.Values("ADR"), []string{`;;1 Main St\, Town;;;;`})
+	fn, _ := card.FN()
+	assertEq(t, fn, "John Doe")
+	assertEq(t, card.Len(), 6)
+
+	_, err = UnmarshalMECARD([]byte("BEGIN:VCARD"))
+	assertErrIs(t, err, ErrParsing, `has to start with "MECARD:"`)
+
+	_, err = UnmarshalMECARD([]byte("MECARD:TEL:555;;"))
+	assertErrIs(t, err, ErrParsing, "does not contain N")
+}
@@ -0,0 +1,217 @@
+package vcard
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Binary value of properties like PHOTO, LOGO, SOUND and KEY which is either referenced by URI
+// or embedded into a vCard.
+//
+// vCard 4.0 embeds data as data: URIs, while vCard 2.1 and 3.0 use ENCODING parameter with
+// TYPE parameter holding a type like JPEG instead of a media type.
+type media struct {
+	uri       string
+	data      []byte
+	mediaType string
+}
+
+// Encodes media in a form defined by the vCard version. Empty media is omitted.
+func (m media) marshal(version string) []byte {
+	if m.data == nil && m.uri == "" {
+		return nil
+	}
+	b := []byte{}
+
+	if m.data != nil {
+		data := base64.StdEncoding.EncodeToString(m.data)
+		switch version {
+		case "2.1", "3.0":
+			enc := "b"
+			if version == "2.1" {
+				enc = "BASE64"
+			}
+			b = appendParam(b, "ENCODING", enc)
+			if typ := typeOfMediaType(m.mediaType); typ != "" {
+				b = appendParam(b, "TYPE", typ)
+			}
+			b = append(b, ':')
+			return append(b, data...)
+		}
+		b = append(b, ":data:"...)
+		b = append(b, m.mediaType...)
+		b = append(b, ";base64,"...)
+		return append(b, data...)
+	}
+
+	switch version {
+	case "2.1", "3.0":
+		value := "uri"
+		if version == "2.1" {
+			value = "URL"
+		}
+		b = appendParam(b, "VALUE", value)
+		if typ := typeOfMediaType(m.mediaType); typ != "" {
+			b = appendParam(b, "TYPE", typ)
+		}
+	default:
+		if m.mediaType != "" {
+			b = appendParam(b, "MEDIATYPE", m.mediaType)
+		}
+	}
+	b = append(b, ':')
+	return append(b, m.uri...)
+}
+
+// Decodes media from an inline value with ENCODING parameter, a data: URI or any other URI.
+// kind is a top-level media type like "image" used to convert TYPE parameter to a media type.
+func unmarshalMedia(data []byte, kind string) (media, error) {
+	params, value, _ := splitParamsValue(string(data))
+	m := media{}
+
+	if v := paramValues(params, "MEDIATYPE"); len(v) > 0 {
+		m.mediaType = v[0]
+	} else if v := withoutValue(paramValues(params, "TYPE"), "pref"); len(v) > 0 {
+		m.mediaType = mediaTypeOfType(v[0], kind)
+	}
+
+	if enc := paramValues(params, "ENCODING"); len(enc) > 0 {
+		if !strings.EqualFold(enc[0], "b") && !strings.EqualFold(enc[0], "BASE64") {
+			return m, parsingErrf("unsupported ENCODING=%s", enc[0])
+		}
+		decoded, err := decodeBase64(value)
+		if err != nil {
+			return m, parsingErrf("unable to decode base64 value: %w", err)
+		}
+		m.data = decoded
+		return m, nil
+	}
+
+	if len(value) >= 5 && strings.EqualFold(value[:5], "data:") {
+		header, payload, found := strings.Cut(value[5:], ",")
+		if !found {
+			return m, parsingErrf("data URI does not contain ','")
+		}
+		mediaType, isBase64 := strings.CutSuffix(header, ";base64")
+		if mediaType != "" {
+			m.mediaType = mediaType
+		}
+		var err error
+		if isBase64 {
+			m.data, err = decodeBase64(payload)
+		} else {
+			var s string
+			s, err = url.PathUnescape(payload)
+			m.data = []byte(s)
+		}
+		if err != nil {
+			return m, parsingErrf("unable to decode data URI: %w", err)
+		}
+		return m, nil
+	}
+
+	m.uri = value
+	return m, nil
+}
+
+// Returns a data: URI of embedded media or the URI of referenced one.
+func (m media) dataURI() string {
+	if m.data == nil {
+		return m.uri
+	}
+	return "data:" + m.mediaType + ";base64," + base64.StdEncoding.EncodeToString(m.data)
+}
+
+// Downloads media referenced by URI with client and embeds it. Media type is taken from
+// Content-Type header unless it is known already. Media larger than maxSize bytes is not embedded
+// and reported as an error. Zero maxSize means no limit.
+func (m *media) fetch(ctx context.Context, client *http.Client, maxSize int64) error {
+	if m.data != nil {
+		return nil
+	}
+	if m.uri == "" {
+		return vCardErrf("media has neither data nor URI")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, m.uri, nil)
+	if err != nil {
+		return vCardErrf("unable to fetch %q: %w", m.uri, err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return vCardErrf("unable to fetch %q: %w", m.uri, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return vCardErrf("unable to fetch %q: %s", m.uri, resp.Status)
+	}
+	if maxSize > 0 && resp.ContentLength > maxSize {
+		return vCardErrf("unable to fetch %q: size of %v bytes exceeds maximum of %v", m.uri, resp.ContentLength, maxSize)
+	}
+	body := io.Reader(resp.Body)
+	if maxSize > 0 {
+		body = io.LimitReader(resp.Body, maxSize+1)
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return vCardErrf("unable to fetch %q: %w", m.uri, err)
+	}
+	if maxSize > 0 && int64(len(data)) > maxSize {
+		return vCardErrf("unable to fetch %q: size exceeds maximum of %v bytes", m.uri, maxSize)
+	}
+	if m.mediaType == "" {
+		m.mediaType, _, _ = mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	}
+	m.data, m.uri = data, ""
+	return nil
+}
+
+// Decodes base64 ignoring whitespace of folded lines and missing padding.
+func decodeBase64(s string) ([]byte, error) {
+	s = strings.Map(func(r rune) rune {
+		if r == ' ' || r == '\t' || r == '\r' || r == '\n' {
+			return -1
+		}
+		return r
+	}, s)
+	return base64.RawStdEncoding.DecodeString(strings.TrimRight(s, "="))
+}
+
+// Types of older versions which don't match a subtype of a media type.
+var mediaTypesOfTypes = map[string]string{
+	"PGP":  "application/pgp-keys",
+	"X509": "application/pkix-cert",
+	"JPG":  "image/jpeg",
+	"WAVE": "audio/wav",
+	"MP3":  "audio/mpeg",
+}
+
+// Converts TYPE parameter like JPEG of vCard 2.1 and 3.0 to a media type like image/jpeg.
+func mediaTypeOfType(typ string, kind string) string {
+	if strings.Contains(typ, "/") {
+		return strings.ToLower(typ)
+	}
+	if mediaType, found := mediaTypesOfTypes[strings.ToUpper(typ)]; found {
+		return mediaType
+	}
+	return kind + "/" + strings.ToLower(typ)
+}
+
+// Converts a media type like image/jpeg to TYPE parameter like JPEG of vCard 2.1 and 3.0.
+func typeOfMediaType(mediaType string) string {
+	if mediaType == "" {
+		return ""
+	}
+	for typ, mt := range mediaTypesOfTypes {
+		if strings.EqualFold(mt, mediaType) && typ != "JPG" {
+			return typ
+		}
+	}
+	_, subtype, _ := strings.Cut(mediaType, "/")
+	return strings.ToUpper(subtype)
+}
@@ -0,0 +1,104 @@
+package vcard
+
+import (
+	"slices"
+	"strings"
+)
+
+// Defines how [Merge] resolves conflicts between two cards.
+//
+// One of [MergePreferA], [MergePreferB] and [MergePreferNewer] selects the card which wins
+// conflicts and may be combined with [MergeUnionMultivalued] e.g. MergePreferNewer|MergeUnionMultivalued.
+type MergePolicy int
+
+const (
+	// Properties of the first card win. Default.
+	MergePreferA MergePolicy = 0
+
+	// Properties of the second card win.
+	MergePreferB MergePolicy = 1
+
+	// Properties of the card with the latest REV win. Cards without REV are older than cards
+	// with one. The first card wins if revisions are equal.
+	MergePreferNewer MergePolicy = 2
+
+	// Values of multi-valued properties like TEL, EMAIL or ADR of both cards are kept.
+	// Without this flag the winning card keeps its values only.
+	MergeUnionMultivalued MergePolicy = 4
+
+	mergePreferMask = 3
+)
+
+// Returns a card with properties of both cards.
+//
+// Properties of the winning card defined by policy are kept in order. Properties of the other card
+// are appended if the winning card does not contain a property with the same name. With
+// [MergeUnionMultivalued] values of multi-valued properties which are missing in the winning card
+// are appended too. Telephone numbers are compared by digits and email addresses case-insensitively.
+//
+// Properties which may occur at most once as per RFC 6350 e.g. N, BDAY, UID as well as FN
+// are never duplicated. REV of the merged card is the latest one.
+func Merge(a, b Card, policy MergePolicy) Card {
+	winner, other := a, b
+	switch policy & mergePreferMask {
+	case MergePreferB:
+		winner, other = b, a
+	case MergePreferNewer:
+		revA, okA := a.Rev()
+		revB, okB := b.Rev()
+		if okB && (!okA || revB.After(revA)) {
+			winner, other = b, a
+		}
+	}
+
+	merged := Card{props: winner.Properties()}
+	names := map[string]bool{}
+	for _, p := range winner.props {
+		names[p.Name] = true
+	}
+
+	for _, p := range other.props {
+		switch {
+		case !names[p.Name]:
+		case policy&MergeUnionMultivalued == 0 || singleValued(p.Name):
+			continue
+		case slices.ContainsFunc(merged.props, func(m Property) bool { return m.Name == p.Name && sameValue(p.Name, m.Value, p.Value) }):
+			continue
+		}
+		merged.props = append(merged.props, p.clone())
+	}
+
+	revW, okW := winner.Rev()
+	if revO, ok := other.Rev(); ok && (!okW || revO.After(revW)) {
+		rev, _ := other.Get("REV")
+		merged.Set("REV", rev)
+	}
+	return merged
+}
+
+// Reports whether a property may occur at most once in a card.
+func singleValued(name string) bool {
+	return name == "VERSION" || name == "FN" || SchemaV4.Cardinality(name).Max == 1
+}
+
+// Reports whether values of properties with the given name are the same.
+func sameValue(name string, a string, b string) bool {
+	switch name {
+	case "TEL":
+		return digits(strings.TrimPrefix(a, "tel:")) == digits(strings.TrimPrefix(b, "tel:"))
+	case "EMAIL":
+		return strings.EqualFold(strings.TrimPrefix(a, "mailto:"), strings.TrimPrefix(b, "mailto:"))
+	}
+	return a == b
+}
+
+// Returns digits of s and a leading "+" e.g. "+15550100" of "+1 (555) 0100".
+func digits(s string) string {
+	b := strings.Builder{}
+	for i := 0; i < len(s); i++ {
+		if s[i] >= '0' && s[i] <= '9' || s[i] == '+' && b.Len() == 0 {
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
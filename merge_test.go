@@ -0,0 +1,50 @@
+package vcard
+
+import "testing"
+
+func TestMerge(t *testing.T) {
+
+	a := Card{}
+	a.Add("VERSION", "4.0")
+	a.Add("FN", "Alex")
+	a.Add("TEL", "+1 555 0100")
+	a.Add("EMAIL", "alex@example.com")
+	a.Add("REV", "20240101T000000Z")
+
+	b := Card{}
+	b.Add("VERSION", "4.0")
+	b.Add("FN", "Alex Doe")
+	b.Add("TEL", "tel:+1-555-0100")
+	b.Add("TEL", "+1 555 0199")
+	b.Add("EMAIL", "ALEX@example.com")
+	b.Add("BDAY", "19850415")
+	b.Add("REV", "20240201T000000Z")
+
+	m := Merge(a, b, MergePreferA)
+	assertSlicesEq(t, m.Values("FN"), []string{"Alex"})
+	assertSlicesEq(t, m.Values("TEL"), []string{"+1 555 0100"})
+	assertSlicesEq(t, m.Values("BDAY"), []string{"19850415"})
+	assertSlicesEq(t, m.Values("REV"), []string{"20240201T000000Z"})
+	assertSlicesEq(t, m.Values("VERSION"), []string{"4.0"})
+
+	m = Merge(a, b, MergePreferB)
+	assertSlicesEq(t, m.Values("FN"), []string{"Alex Doe"})
+	assertSlicesEq(t, m.Values("TEL"), []string{"tel:+1-555-0100", "+1 555 0199"})
+
+	m = Merge(a, b, MergePreferNewer|MergeUnionMultivalued)
+	assertSlicesEq(t, m.Values("FN"), []string{"Alex Doe"})
+	assertSlicesEq(t, m.Values("TEL"), []string{"tel:+1-555-0100", "+1 555 0199"})
+	assertSlicesEq(t, m.Values("EMAIL"), []string{"ALEX@example.com"})
+
+	m = Merge(a, b, MergeUnionMultivalued)
+	assertSlicesEq(t, m.Values("FN"), []string{"Alex"})
+	assertSlicesEq(t, m.Values("TEL"), []string{"+1 555 0100", "+1 555 0199"})
+	assertSlicesEq(t, m.Values("BDAY"), []string{"19850415"})
+
+	a.Del("REV")
+	m = Merge(a, b, MergePreferNewer)
+	assertSlicesEq(t, m.Values("FN"), []string{"Alex Doe"})
+
+	// a is not modified
+	assertSlicesEq(t, a.Values("TEL"), []string{"+1 555 0100"})
+}
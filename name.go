@@ -0,0 +1,172 @@
+package vcard
+
+import (
+	"strings"
+)
+
+// Value of N property as per https://datatracker.ietf.org/doc/html/rfc6350#section-6.2.2
+// e.g. N;SORT-AS="Doe,John":Doe;John;Philip,Paul;Dr.;Jr.
+//
+// Every component may contain multiple values. Name implements [VCardFieldMarshaler] and
+// [VCardFieldUnmarshaler], so it can be used as a type of struct fields instead of string.
+type Name struct {
+	FamilyNames       []string
+	GivenNames        []string
+	AdditionalNames   []string
+	HonorificPrefixes []string
+	HonorificSuffixes []string
+
+	// Values of SORT-AS parameter used to sort the name instead of its components
+	// e.g. ["Doe", "John"]. See [Card.SortKey].
+	SortAs []string
+}
+
+// Encodes the name e.g. `;SORT-AS=Doe,John:Doe;John;Philip,Paul;Dr.;Jr.`. Components are escaped.
+func (n Name) MarshalVCardField() ([]byte, error) {
+	b := appendParam([]byte{}, "SORT-AS", n.SortAs...)
+	b = append(b, ':')
+	b = append(b, joinComponentLists(n.FamilyNames, n.GivenNames, n.AdditionalNames, n.HonorificPrefixes, n.HonorificSuffixes)...)
+	return b, nil
+}
+
+// Decodes the name from "Doe;John;Philip,Paul;Dr.;Jr.". Missing components are left empty.
+func (n *Name) UnmarshalVCardField(data []byte) error {
+	params, value, _ := splitParamsValue(string(data))
+
+	lists := splitComponentLists(value, 5)
+	*n = Name{
+		FamilyNames:       lists[0],
+		GivenNames:        lists[1],
+		AdditionalNames:   lists[2],
+		HonorificPrefixes: lists[3],
+		HonorificSuffixes: lists[4],
+	}
+	if sortAs := paramValues(params, "SORT-AS"); len(sortAs) > 0 {
+		n.SortAs = sortAs
+	}
+	return nil
+}
+
+// Value of ORG property as per https://datatracker.ietf.org/doc/html/rfc6350#section-6.6.4
+// e.g. ORG;SORT-AS="ABC":ABC\, Inc.;North American Division;Marketing
+//
+// Org implements [VCardFieldMarshaler] and [VCardFieldUnmarshaler], so it can be used as a type
+// of struct fields instead of string.
+type Org struct {
+	// Name of the organization e.g. "ABC, Inc.".
+	Name string
+
+	// Organizational units from the largest to the smallest e.g. ["North American Division", "Marketing"].
+	Units []string
+
+	// Values of SORT-AS parameter used to sort the organization instead of its name.
+	SortAs []string
+}
+
+// Encodes the organization e.g. `;SORT-AS=ABC:ABC\, Inc.;Marketing`.
+func (o Org) MarshalVCardField() ([]byte, error) {
+	b := appendParam([]byte{}, "SORT-AS", o.SortAs...)
+	b = append(b, ':')
+	b = append(b, JoinStructured(append([]string{o.Name}, o.Units...))...)
+	return b, nil
+}
+
+// Decodes the organization from `ABC\, Inc.;Marketing`.
+func (o *Org) UnmarshalVCardField(data []byte) error {
+	params, value, _ := splitParamsValue(string(data))
+
+	components := SplitStructured(value)
+	*o = Org{Name: components[0]}
+	if len(components) > 1 {
+		o.Units = components[1:]
+	}
+	if sortAs := paramValues(params, "SORT-AS"); len(sortAs) > 0 {
+		o.SortAs = sortAs
+	}
+	return nil
+}
+
+// Returns N property of the card. SORT-STRING property of vCard 3.0 is used as SORT-AS
+// if N has no SORT-AS parameter.
+func (c *Card) Name() (Name, bool) {
+	p, found := c.first("N")
+	if !found {
+		return Name{}, false
+	}
+	n := Name{}
+	_ = n.UnmarshalVCardField([]byte(p.rest()))
+	if n.SortAs == nil {
+		if s, found := c.text("SORT-STRING"); found && s != "" {
+			n.SortAs = []string{s}
+		}
+	}
+	return n, true
+}
+
+// Returns ORG property of the card. See [Card.Name].
+func (c *Card) Organization() (Org, bool) {
+	p, found := c.first("ORG")
+	if !found {
+		return Org{}, false
+	}
+	o := Org{}
+	_ = o.UnmarshalVCardField([]byte(p.rest()))
+	return o, true
+}
+
+// Returns a string used to sort cards the way address books do. SORT-AS of N, SORT-STRING,
+// family and given names, SORT-AS of ORG, name of ORG and FN are tried in that order.
+// Parts are joined with spaces.
+func (c *Card) SortKey() string {
+	if n, found := c.Name(); found {
+		if len(n.SortAs) > 0 {
+			return strings.Join(n.SortAs, " ")
+		}
+		if key := strings.TrimSpace(strings.Join(append(n.FamilyNames, n.GivenNames...), " ")); key != "" {
+			return key
+		}
+	}
+	if o, found := c.Organization(); found {
+		if len(o.SortAs) > 0 {
+			return strings.Join(o.SortAs, " ")
+		}
+		if o.Name != "" {
+			return o.Name
+		}
+	}
+	fn, _ := c.FN()
+	return fn
+}
+
+func (c *Card) first(name string) (Property, bool) {
+	for _, p := range c.props {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return Property{}, false
+}
+
+// Joins lists of values into a structured value e.g. [["Doe"], ["John"], ["Philip", "Paul"]]
+// is "Doe;John;Philip,Paul".
+func joinComponentLists(lists ...[]string) string {
+	components := make([]string, len(lists))
+	for i, list := range lists {
+		components[i] = joinTextList(list)
+	}
+	return strings.Join(components, ";")
+}
+
+// Splits a structured value into n lists of values. Missing components are nil.
+func splitComponentLists(value string, n int) [][]string {
+	lists := make([][]string, n)
+	for i, c := range splitComponents(value) {
+		if i >= n {
+			break
+		}
+		if values := splitTextList(c); len(values) > 0 {
+			lists[i] = values
+		}
+	}
+	return lists
+}
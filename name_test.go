@@ -0,0 +1,110 @@
+package vcard
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestName(t *testing.T) {
+
+	n := Name{}
+	err := n.UnmarshalVCardField([]byte(`;SORT-AS="Doe,John":Doe;John;Philip,Paul;Dr.;Jr.\, MD`))
+
+	assertEq(t, err, nil)
+	assertDeepEq(t, n, Name{
+		FamilyNames:       []string{"Doe"},
+		GivenNames:        []string{"John"},
+		AdditionalNames:   []string{"Philip", "Paul"},
+		HonorificPrefixes: []string{"Dr."},
+		HonorificSuffixes: []string{"Jr., MD"},
+		SortAs:            []string{"Doe", "John"},
+	})
+
+	b, err := n.MarshalVCardField()
+	assertEq(t, err, nil)
+	assertStringsEq(t, string(b), `;SORT-AS=Doe,John:Doe;John;Philip,Paul;Dr.;Jr.\, MD`)
+
+	b, err = Name{GivenNames: []string{"Alex"}}.MarshalVCardField()
+	assertEq(t, err, nil)
+	assertStringsEq(t, string(b), ":;Alex;;;")
+}
+
+func TestOrg(t *testing.T) {
+
+	o := Org{}
+	err := o.UnmarshalVCardField([]byte(`;SORT-AS=ABC:ABC\, Inc.;North American Division;Marketing`))
+
+	assertEq(t, err, nil)
+	assertDeepEq(t, o, Org{"ABC, Inc.", []string{"North American Division", "Marketing"}, []string{"ABC"}})
+
+	b, err := o.MarshalVCardField()
+	assertEq(t, err, nil)
+	assertStringsEq(t, string(b), `;SORT-AS=ABC:ABC\, Inc.;North American Division;Marketing`)
+}
+
+func TestCardSortKey(t *testing.T) {
+
+	text := `BEGIN:VCARD
+VERSION:3.0
+FN:John van der Berg
+N:van der Berg;John;;;
+SORT-STRING:Berg
+END:VCARD
+BEGIN:VCARD
+VERSION:4.0
+FN:Jane Doe
+N:Doe;Jane;;;
+END:VCARD
+BEGIN:VCARD
+VERSION:4.0
+KIND:org
+FN:The ABC Company
+ORG;SORT-AS="ABC":The ABC Company
+END:VCARD
+`
+	cards := []Card{}
+	err := Unmarshal([]byte(crlfy(text)), &cards)
+	assertEq(t, err, nil)
+
+	n, _ := cards[0].Name()
+	assertSlicesEq(t, n.SortAs, []string{"Berg"})
+
+	assertStringsEq(t, cards[0].SortKey(), "Berg")
+	assertStringsEq(t, cards[1].SortKey(), "Doe Jane")
+	assertStringsEq(t, cards[2].SortKey(), "ABC")
+}
+
+func TestDowngradeSortAsTo3(t *testing.T) {
+
+	type Contact struct {
+		FN  string
+		N   Name
+		ORG Org
+	}
+	c := Contact{
+		FN:  "John van der Berg",
+		N:   Name{FamilyNames: []string{"van der Berg"}, GivenNames: []string{"John"}, SortAs: []string{"Berg", "John"}},
+		ORG: Org{Name: "ABC", SortAs: []string{"ABC"}},
+	}
+	b, err := MarshalSchema(c, SchemaFor[Contact]("3.0"))
+	assertEq(t, err, nil)
+	assertStringsEq(t, string(b), crlfy(`BEGIN:VCARD
+VERSION:3.0
+FN:John van der Berg
+N;SORT-AS=Berg,John:van der Berg;John;;;
+ORG;SORT-AS=ABC:ABC
+END:VCARD
+`))
+
+	buf := bytes.Buffer{}
+	err = NewEncoder(&buf).SetDowngradePolicy(DowngradeTranslate).EncodeSchema(c, SchemaFor[Contact]("3.0"))
+	assertEq(t, err, nil)
+	assertStringsEq(t, buf.String(), crlfy(`BEGIN:VCARD
+VERSION:3.0
+FN:John van der Berg
+N:van der Berg;John;;;
+ORG:ABC
+SORT-STRING:Berg John
+END:VCARD
+`))
+}
@@ -0,0 +1,160 @@
+package vcard
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Defines what [Normalize] changes. Zero value enables every normalization.
+type NormalizeOptions struct {
+	// Region of national telephone numbers e.g. "US" or "DE". Without it only numbers with
+	// an international prefix are converted to E.164. See [Tel.E164].
+	Region string
+
+	// Leading, trailing and repeated whitespace of values is kept.
+	KeepWhitespace bool
+
+	// Letters followed by combining diacritics are not composed.
+	KeepUnicodeForm bool
+
+	// Case of email addresses is kept.
+	KeepEmailCase bool
+
+	// Telephone numbers are kept as written.
+	KeepPhones bool
+}
+
+// Returns a copy of the card with normalized values:
+//
+//   - Components of text values are trimmed and runs of whitespace become a single space
+//     e.g. "  Acme   Inc. ; Sales" becomes "Acme Inc.;Sales".
+//   - Latin letters followed by combining diacritics are composed as per Unicode NFC
+//     e.g. "é" becomes "é". Other scripts are kept as is.
+//   - Email addresses are lower-cased.
+//   - Telephone numbers are converted to E.164 e.g. "+1 (555) 010-0100" becomes "+15550100100"
+//     and "tel:+1-555-010-0100" becomes "tel:+15550100100". Numbers which can't be converted
+//     e.g. "555 ext. 12" are kept as written.
+//
+// Binary values of ENCODING parameter and QUOTED-PRINTABLE values of vCard 2.1 are not changed.
+func Normalize(card Card, opts NormalizeOptions) Card {
+	normalized := Card{props: card.Properties()}
+	for i := range normalized.props {
+		p := &normalized.props[i]
+		if p.Name == "VERSION" || p.Name == "BEGIN" || p.Name == "END" || isEncoded(*p) {
+			continue
+		}
+		if !opts.KeepUnicodeForm {
+			p.Value = composeLatin(p.Value)
+		}
+		if !opts.KeepWhitespace {
+			p.Value = collapseComponents(p.Value)
+		}
+		switch p.Name {
+		case "EMAIL":
+			if !opts.KeepEmailCase {
+				p.Value = strings.ToLower(p.Value)
+			}
+		case "TEL":
+			if !opts.KeepPhones {
+				p.Value = normalizeTel(p.Value, opts.Region)
+			}
+		}
+	}
+	return normalized
+}
+
+// Reports whether the value is encoded with ENCODING parameter or nameless QUOTED-PRINTABLE
+// and BASE64 parameters of vCard 2.1.
+func isEncoded(p Property) bool {
+	for _, param := range p.Params {
+		if strings.EqualFold(param.Name, "ENCODING") ||
+			param.Values == nil && (strings.EqualFold(param.Name, "QUOTED-PRINTABLE") || strings.EqualFold(param.Name, "BASE64")) {
+			return true
+		}
+	}
+	return false
+}
+
+// Trims components of a raw value separated by unescaped ';' and ',' and collapses whitespace within them.
+func collapseComponents(value string) string {
+	b := strings.Builder{}
+	start := 0
+	for i := 0; i <= len(value); i++ {
+		if i < len(value) && value[i] == '\\' {
+			i++
+			continue
+		}
+		if i == len(value) || value[i] == ';' || value[i] == ',' {
+			b.WriteString(strings.Join(strings.Fields(value[start:min(i, len(value))]), " "))
+			if i < len(value) {
+				b.WriteByte(value[i])
+			}
+			start = i + 1
+		}
+	}
+	return b.String()
+}
+
+// Converts a number to E.164 keeping "tel:" prefix and URI parameters e.g. ";ext=12".
+func normalizeTel(value string, region string) string {
+	number, params, hasParams := strings.Cut(value, ";")
+	prefix := ""
+	if strings.HasPrefix(number, "tel:") {
+		prefix, number = "tel:", strings.TrimPrefix(number, "tel:")
+	}
+	e164, err := Tel{Number: number}.E164(region)
+	if err != nil {
+		return value
+	}
+	if hasParams {
+		return prefix + e164 + ";" + params
+	}
+	return prefix + e164
+}
+
+// Composes Latin letters followed by a combining diacritic into precomposed letters.
+func composeLatin(s string) string {
+	if !strings.ContainsFunc(s, func(r rune) bool { return r >= 0x300 && r <= 0x36F }) {
+		return s
+	}
+	b := strings.Builder{}
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		if mark, markSize := utf8.DecodeRuneInString(s[i:]); r < utf8.RuneSelf && markSize > 0 {
+			if composed, found := latinCompositions[[2]rune{r, mark}]; found {
+				r = composed
+				i += markSize
+			}
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Precomposed letters of Latin-1 and Latin Extended-A/B by base letters and combining diacritics.
+var latinCompositions = func() map[[2]rune]rune {
+	compositions := map[[2]rune]rune{}
+	for mark, letters := range map[rune][2]string{
+		0x300: {"AEIOUaeiouNn", "ÀÈÌÒÙàèìòùǸǹ"},
+		0x301: {"AEIOUYaeiouyCcLlNnRrSsZzGg", "ÁÉÍÓÚÝáéíóúýĆćĹĺŃńŔŕŚśŹźǴǵ"},
+		0x302: {"AEIOUaeiouCcGgHhJjSsWwYy", "ÂÊÎÔÛâêîôûĈĉĜĝĤĥĴĵŜŝŴŵŶŷ"},
+		0x303: {"ANOanoIiUu", "ÃÑÕãñõĨĩŨũ"},
+		0x304: {"AaEeIiOoUuYy", "ĀāĒēĪīŌōŪūȲȳ"},
+		0x306: {"AaEeGgIiOoUu", "ĂăĔĕĞğĬĭŎŏŬŭ"},
+		0x307: {"CcEeGgIZzAaOo", "ĊċĖėĠġİŻżȦȧȮȯ"},
+		0x308: {"AEIOUaeiouyY", "ÄËÏÖÜäëïöüÿŸ"},
+		0x30A: {"AaUu", "ÅåŮů"},
+		0x30B: {"OoUu", "ŐőŰű"},
+		0x30C: {"CcDdEeLlNnRrSsTtZzAaIiOoUuGgKkjHh", "ČčĎďĚěĽľŇňŘřŠšŤťŽžǍǎǏǐǑǒǓǔǦǧǨǩǰȞȟ"},
+		0x326: {"SsTt", "ȘșȚț"},
+		0x327: {"CcGgKkLlNnRrSsTtEe", "ÇçĢģĶķĻļŅņŖŗŞşŢţȨȩ"},
+		0x328: {"AaEeIiUuOo", "ĄąĘęĮįŲųǪǫ"},
+	} {
+		composed := []rune(letters[1])
+		for i, base := range letters[0] {
+			compositions[[2]rune{base, mark}] = composed[i]
+		}
+	}
+	return compositions
+}()
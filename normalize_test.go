@@ -0,0 +1,45 @@
+package vcard
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+
+	card := Card{}
+	card.Add("VERSION", "4.0")
+	card.Add("FN", "  Renée   Smith ")
+	card.Add("N", " Smith ;Renée;; Dr. ;")
+	card.Add("ORG", "Acme\\, Inc.  ;  Sales")
+	card.Add("EMAIL", " Renee@Example.COM ")
+	card.Add("TEL", "+1 (555) 010-0100")
+	card.Add("TEL", "tel:+49-30-1234567;ext=12")
+	card.Add("TEL", "030 1234567")
+	card.Add("TEL", "555 ext. 12")
+	card.Add("NOTE", "Line 1\\n  Line 2")
+	card.AddProperty(Property{Name: "PHOTO", Params: Params{{"ENCODING", []string{"b"}}}, Value: "QU  JD"})
+
+	normalized := Normalize(card, NormalizeOptions{Region: "DE"})
+	b, err := Marshal(normalized)
+	assertEq(t, err, nil)
+	assertStringLinesEq(t, string(b), crlfy(`BEGIN:VCARD
+VERSION:4.0
+FN:Renée Smith
+N:Smith;Renée;;Dr.;
+ORG:Acme\, Inc.;Sales
+EMAIL:renee@example.com
+TEL:+15550100100
+TEL:tel:+49301234567;ext=12
+TEL:+49301234567
+TEL:555 ext. 12
+NOTE:Line 1\n Line 2
+PHOTO;ENCODING=b:QU  JD
+END:VCARD
+`))
+
+	kept := Normalize(card, NormalizeOptions{KeepWhitespace: true, KeepUnicodeForm: true, KeepEmailCase: true, KeepPhones: true})
+	assertDeepEq(t, kept.Properties(), card.Properties())
+
+	// National numbers are kept without a region
+	national := Normalize(card, NormalizeOptions{})
+	tels := national.Values("TEL")
+	assertSlicesEq(t, tels, []string{"+15550100100", "tel:+49301234567;ext=12", "030 1234567", "555 ext. 12"})
+}
@@ -0,0 +1,297 @@
+package vcard
+
+import (
+	"cmp"
+	"encoding/csv"
+	"io"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Defines headers and formats of Outlook CSV used by [ReadOutlookCSV] and [WriteOutlookCSV].
+type OutlookCSVOptions struct {
+	// Headers of a localized Outlook by English headers e.g. {"First Name": "Vorname", "E-mail Address": "E-Mail-Adresse"}.
+	// Headers missing in the map are English.
+	Columns map[string]string
+
+	// Layout of Birthday and Anniversary as defined by time.Parse. Default is "1/2/2006" of US Outlook.
+	DateLayout string
+}
+
+// Phone columns of Outlook CSV with types of TEL in order they are filled by [WriteOutlookCSV].
+var outlookPhones = []struct {
+	column string
+	types  []string
+}{
+	{"Mobile Phone", []string{"cell"}},
+	{"Business Phone", []string{"work"}},
+	{"Business Phone 2", []string{"work"}},
+	{"Home Phone", []string{"home"}},
+	{"Home Phone 2", []string{"home"}},
+	{"Business Fax", []string{"work", "fax"}},
+	{"Home Fax", []string{"home", "fax"}},
+	{"Pager", []string{"pager"}},
+	{"Car Phone", []string{"car"}},
+	{"Primary Phone", nil},
+	{"Other Phone", nil},
+}
+
+// Prefixes of address columns of Outlook CSV with types of ADR e.g. "Business Street".
+var outlookAddresses = []struct {
+	prefix string
+	types  []string
+}{
+	{"Business", []string{"work"}},
+	{"Home", []string{"home"}},
+	{"Other", nil},
+}
+
+var outlookEmails = []string{"E-mail Address", "E-mail 2 Address", "E-mail 3 Address"}
+
+// Reads contacts exported from Microsoft Outlook as CSV with headers like "First Name",
+// "E-mail Address" and "Business Phone". Headers of a localized Outlook are defined by opts.Columns.
+//
+// Phones and addresses get types of their columns e.g. "Mobile Phone" becomes TEL;TYPE=cell and
+// "Home Street" becomes ADR;TYPE=home. "Primary Phone" is the most preferred phone. Streets of
+// "Street 2" and "Street 3" columns are joined with newlines. Categories are separated with ';'.
+// Dates like "0/0/00" which Outlook writes for empty dates are ignored.
+//
+// Use [Contact.Card] to get cards of a version. Returns [ErrParsing] if the CSV is malformed.
+func ReadOutlookCSV(r io.Reader, opts OutlookCSVOptions) ([]Contact, error) {
+	t, err := readCSVTable(r)
+	if err != nil {
+		return nil, err
+	}
+	layout := cmp.Or(opts.DateLayout, "1/2/2006")
+
+	contacts := []Contact{}
+	for _, row := range t.rows {
+		get := func(column string) string {
+			if localized, found := opts.Columns[column]; found {
+				return t.get(row, localized, column)
+			}
+			return t.get(row, column)
+		}
+		date := func(column string) Date {
+			v := get(column)
+			if parsed, err := time.Parse(layout, v); err == nil {
+				return DateOf(parsed)
+			}
+			if d, err := ParseDate(v); err == nil {
+				return d
+			}
+			return Date{}
+		}
+
+		c := Contact{
+			Name: Name{
+				HonorificPrefixes: nonEmpty(get("Title")),
+				GivenNames:        nonEmpty(get("First Name")),
+				AdditionalNames:   nonEmpty(get("Middle Name")),
+				FamilyNames:       nonEmpty(get("Last Name")),
+				HonorificSuffixes: nonEmpty(get("Suffix")),
+			},
+			Nicknames:   nonEmpty(get("Nickname")),
+			Org:         Org{Name: get("Company"), Units: nonEmpty(get("Department"))},
+			Title:       get("Job Title"),
+			Birthday:    date("Birthday"),
+			Anniversary: date("Anniversary"),
+			Notes:       nonEmpty(get("Notes")),
+			URLs:        nonEmpty(get("Web Page")),
+		}
+		for _, category := range strings.Split(get("Categories"), ";") {
+			if category = strings.TrimSpace(category); category != "" {
+				c.Categories = append(c.Categories, category)
+			}
+		}
+		for _, column := range outlookEmails {
+			if v := get(column); v != "" {
+				c.Emails = append(c.Emails, Email{Address: v})
+			}
+		}
+		for _, phone := range outlookPhones {
+			if v := get(phone.column); v != "" {
+				tel := Tel{Number: v, Types: phone.types}
+				if phone.column == "Primary Phone" {
+					tel.Pref = 1
+				}
+				c.Phones = append(c.Phones, tel)
+			}
+		}
+		for _, adr := range outlookAddresses {
+			streets := []string{}
+			for _, suffix := range []string{"", " 2", " 3"} {
+				if v := get(adr.prefix + " Street" + suffix); v != "" {
+					streets = append(streets, v)
+				}
+			}
+			a := Adr{
+				POBox:      get(adr.prefix + " PO Box"),
+				Street:     strings.Join(streets, "\n"),
+				Locality:   get(adr.prefix + " City"),
+				Region:     get(adr.prefix + " State"),
+				PostalCode: get(adr.prefix + " Postal Code"),
+				Country:    get(adr.prefix + " Country/Region"),
+				Types:      adr.types,
+			}
+			if a.String() != "" {
+				c.Addresses = append(c.Addresses, a)
+			}
+		}
+
+		c.FormattedName = displayName(c.Name, c.Org)
+		if c.FormattedName == "" && len(c.Emails) > 0 {
+			c.FormattedName = c.Emails[0].Address
+		}
+		contacts = append(contacts, c)
+	}
+	return contacts, nil
+}
+
+// Writes contacts as Outlook CSV with headers defined by opts.Columns. See [ReadOutlookCSV] for the mapping.
+// Use [ContactOf] to write cards.
+//
+// Outlook has a fixed set of columns, so only the first three email addresses, a single address of
+// every type and as many phones as there are matching columns are written. The most preferred values
+// go first. Phones without a matching column go to "Other Phone" while it is free. Birthday and
+// Anniversary without a year are not written. FormattedName of a contact without a name becomes
+// "First Name" unless it is the company.
+func WriteOutlookCSV(w io.Writer, contacts []Contact, opts OutlookCSVOptions) error {
+	layout := cmp.Or(opts.DateLayout, "1/2/2006")
+
+	columns := []string{"Title", "First Name", "Middle Name", "Last Name", "Suffix", "Nickname", "Company", "Department", "Job Title"}
+	for _, adr := range outlookAddresses {
+		for _, col := range []string{"Street", "Street 2", "Street 3", "City", "State", "Postal Code", "Country/Region", "PO Box"} {
+			columns = append(columns, adr.prefix+" "+col)
+		}
+	}
+	for _, phone := range outlookPhones {
+		columns = append(columns, phone.column)
+	}
+	columns = append(columns, outlookEmails...)
+	columns = append(columns, "Birthday", "Anniversary", "Categories", "Notes", "Web Page")
+
+	header := make([]string, len(columns))
+	for i, col := range columns {
+		header[i] = cmp.Or(opts.Columns[col], col)
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return vCardErrf("unable to write: %w", err)
+	}
+	for _, c := range contacts {
+		values := map[string]string{
+			"Title":       strings.Join(c.Name.HonorificPrefixes, " "),
+			"First Name":  strings.Join(c.Name.GivenNames, " "),
+			"Middle Name": strings.Join(c.Name.AdditionalNames, " "),
+			"Last Name":   strings.Join(c.Name.FamilyNames, " "),
+			"Suffix":      strings.Join(c.Name.HonorificSuffixes, " "),
+			"Company":     c.Org.Name,
+			"Department":  strings.Join(c.Org.Units, " "),
+			"Job Title":   c.Title,
+			"Categories":  strings.Join(c.Categories, ";"),
+			"Notes":       strings.Join(c.Notes, "\n"),
+		}
+		// Outlook shows the company of contacts without a name, other contacts
+		// without a name keep FormattedName as the first name
+		if displayName(c.Name, Org{}) == "" && displayName(c.Name, c.Org) != strings.TrimSpace(c.FormattedName) {
+			values["First Name"] = c.FormattedName
+		}
+		if len(c.Nicknames) > 0 {
+			values["Nickname"] = c.Nicknames[0]
+		}
+		if len(c.URLs) > 0 {
+			values["Web Page"] = c.URLs[0]
+		}
+		for column, d := range map[string]Date{"Birthday": c.Birthday, "Anniversary": c.Anniversary} {
+			if t, ok := d.Time(); ok {
+				values[column] = t.Format(layout)
+			}
+		}
+
+		for i, e := range byPref(c.Emails, func(e Email) int { return e.Pref }) {
+			if i < len(outlookEmails) {
+				values[outlookEmails[i]] = e.Address
+			}
+		}
+		for _, tel := range byPref(c.Phones, func(t Tel) int { return t.Pref }) {
+			i := slices.IndexFunc(outlookPhones, func(p struct {
+				column string
+				types  []string
+			}) bool {
+				return values[p.column] == "" && p.types != nil && sameTypes(p.types, tel.Types)
+			})
+			column := "Other Phone"
+			if i >= 0 {
+				column = outlookPhones[i].column
+			}
+			if values[column] == "" {
+				values[column] = tel.Number
+			}
+		}
+		for _, a := range byPref(c.Addresses, func(a Adr) int { return a.Pref }) {
+			prefix := "Other"
+			for _, adr := range outlookAddresses {
+				if adr.types != nil && a.HasType(adr.types[0]) {
+					prefix = adr.prefix
+					break
+				}
+			}
+			if values[prefix+" City"]+values[prefix+" Street"]+values[prefix+" Country/Region"] != "" {
+				continue
+			}
+			streets := strings.SplitN(a.Street, "\n", 3)
+			for i, suffix := range []string{"", " 2", " 3"} {
+				if i < len(streets) {
+					values[prefix+" Street"+suffix] = streets[i]
+				}
+			}
+			values[prefix+" City"] = a.Locality
+			values[prefix+" State"] = a.Region
+			values[prefix+" Postal Code"] = a.PostalCode
+			values[prefix+" Country/Region"] = a.Country
+			values[prefix+" PO Box"] = a.POBox
+		}
+
+		row := make([]string, len(columns))
+		for i, col := range columns {
+			row[i] = values[col]
+		}
+		if err := cw.Write(row); err != nil {
+			return vCardErrf("unable to write: %w", err)
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return vCardErrf("unable to write: %w", err)
+	}
+	return nil
+}
+
+// Returns a copy of values sorted by preference where 0 means no preference. Order of equally
+// preferred values is kept.
+func byPref[T any](values []T, pref func(T) int) []T {
+	sorted := slices.Clone(values)
+	slices.SortStableFunc(sorted, func(a, b T) int {
+		return cmp.Compare(cmp.Or(pref(a), 101), cmp.Or(pref(b), 101))
+	})
+	return sorted
+}
+
+// Reports whether types contain the same values regardless of order and case. "voice", "pref"
+// and "internet" are ignored.
+func sameTypes(a, b []string) bool {
+	normalize := func(types []string) []string {
+		n := []string{}
+		for _, t := range types {
+			if t = strings.ToLower(t); t != "voice" && t != "pref" && t != "internet" {
+				n = append(n, t)
+			}
+		}
+		slices.Sort(n)
+		return slices.Compact(n)
+	}
+	return slices.Equal(normalize(a), normalize(b))
+}
@@ -0,0 +1,145 @@
+package vcard
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestReadOutlookCSV(t *testing.T) {
+
+	doc := "\ufeffTitle,First Name,Middle Name,Last Name,Suffix,Company,Department,Job Title,Business Street,Business Street 2,Business City,Business Postal Code,Business Country/Region,Home Street,Home City,Business Fax,Business Phone,Home Phone,Mobile Phone,Primary Phone,Anniversary,Birthday,Categories,E-mail Address,E-mail Display Name,E-mail 2 Address,Notes,Web Page\n" +
+		`Dr.,Alex,Q,Smith,Jr.,Acme,R&D,Engineer,1 Main St,Floor 2,Town,12345,USA,2 Home Rd,Village,+1 555 0101,+1 555 0102,,+1 555 0100,+1 555 0199,0/0/00,1/2/1990,Friends;VIP,alex@acme.example,Alex Smith (alex@acme.example),alex@home.example,"Line 1` + "\n" + `Line 2",https://alex.example` + "\n" +
+		`,,,,,Acme,,,,,,,,,,,,555,,,,,,,,,,` + "\n"
+
+	contacts, err := ReadOutlookCSV(strings.NewReader(doc), OutlookCSVOptions{})
+	assertEq(t, err, nil)
+	assertEq(t, len(contacts), 2)
+
+	c := contacts[0]
+	assertEq(t, c.FormattedName, "Dr. Alex Q Smith Jr.")
+	assertDeepEq(t, c.Name, Name{FamilyNames: []string{"Smith"}, GivenNames: []string{"Alex"}, AdditionalNames: []string{"Q"}, HonorificPrefixes: []string{"Dr."}, HonorificSuffixes: []string{"Jr."}})
+	assertDeepEq(t, c.Org, Org{Name: "Acme", Units: []string{"R&D"}})
+	assertEq(t, c.Title, "Engineer")
+	assertEq(t, c.Birthday, Date{Year: 1990, Month: 1, Day: 2})
+	assertEq(t, c.Anniversary, Date{})
+	assertSlicesEq(t, c.Categories, []string{"Friends", "VIP"})
+	assertSlicesEq(t, c.Notes, []string{"Line 1\nLine 2"})
+	assertSlicesEq(t, c.URLs, []string{"https://alex.example"})
+	assertDeepEq(t, c.Emails, []Email{{Address: "alex@acme.example"}, {Address: "alex@home.example"}})
+	assertDeepEq(t, c.Phones, []Tel{
+		{Number: "+1 555 0100", Types: []string{"cell"}},
+		{Number: "+1 555 0102", Types: []string{"work"}},
+		{Number: "+1 555 0101", Types: []string{"work", "fax"}},
+		{Number: "+1 555 0199", Pref: 1},
+	})
+	assertDeepEq(t, c.Addresses, []Adr{
+		{Street: "1 Main St\nFloor 2", Locality: "Town", PostalCode: "12345", Country: "USA", Types: []string{"work"}},
+		{Street: "2 Home Rd", Locality: "Village", Types: []string{"home"}},
+	})
+
+	c = contacts[1]
+	assertEq(t, c.FormattedName, "Acme")
+	assertDeepEq(t, c.Phones, []Tel{{Number: "555", Types: []string{"home"}}})
+
+	card, err := contacts[0].Card("4.0")
+	assertEq(t, err, nil)
+	fn, _ := card.FN()
+	assertEq(t, fn, "Dr. Alex Q Smith Jr.")
+}
+
+func TestReadOutlookCSVLocalized(t *testing.T) {
+
+	doc := "Vorname;Nachname;E-Mail-Adresse;Mobiltelefon;Geburtstag\n" +
+		"Jörg;Müller;joerg@example.de;+49 170 0000;15.4.1985\n"
+	doc = strings.ReplaceAll(doc, ";", ",")
+
+	opts := OutlookCSVOptions{
+		Columns: map[string]string{
+			"First Name":     "Vorname",
+			"Last Name":      "Nachname",
+			"E-mail Address": "E-Mail-Adresse",
+			"Mobile Phone":   "Mobiltelefon",
+			"Birthday":       "Geburtstag",
+		},
+		DateLayout: "2.1.2006",
+	}
+	contacts, err := ReadOutlookCSV(strings.NewReader(doc), opts)
+	assertEq(t, err, nil)
+	assertEq(t, len(contacts), 1)
+	assertEq(t, contacts[0].FormattedName, "Jörg Müller")
+	assertDeepEq(t, contacts[0].Emails, []Email{{Address: "joerg@example.de"}})
+	assertDeepEq(t, contacts[0].Phones, []Tel{{Number: "+49 170 0000", Types: []string{"cell"}}})
+	assertEq(t, contacts[0].Birthday, Date{Year: 1985, Month: 4, Day: 15})
+
+	b := bytes.Buffer{}
+	assertEq(t, WriteOutlookCSV(&b, contacts, opts), nil)
+	roundTrip, err := ReadOutlookCSV(&b, opts)
+	assertEq(t, err, nil)
+	assertDeepEq(t, roundTrip, contacts)
+
+	_, err = ReadOutlookCSV(strings.NewReader(""), OutlookCSVOptions{})
+	assertErrIs(t, err, ErrParsing, "CSV does not contain a header")
+}
+
+func TestWriteOutlookCSV(t *testing.T) {
+
+	contacts := []Contact{
+		{
+			FormattedName: "Alex Smith",
+			Name:          Name{FamilyNames: []string{"Smith"}, GivenNames: []string{"Alex"}},
+			Emails:        []Email{{Address: "a@example.com"}, {Address: "alex@example.com", Types: []string{"work", "internet"}, Pref: 1}},
+			Phones: []Tel{
+				{Number: "555", Types: []string{"cell"}},
+				{Number: "556", Types: []string{"fax", "home"}},
+				{Number: "557", Types: []string{"cell"}},
+				{Number: "558", Types: []string{"video"}},
+			},
+			Addresses:  []Adr{{Street: "1 Main St", Locality: "Town", Types: []string{"home"}}, {Locality: "Elsewhere"}},
+			Birthday:   Date{Year: 1990, Month: 1, Day: 2},
+			Categories: []string{"Friends", "VIP"},
+		},
+		{FormattedName: "Acme", Org: Org{Name: "Acme"}, Birthday: Date{Month: 4, Day: 15}},
+	}
+
+	b := bytes.Buffer{}
+	assertEq(t, WriteOutlookCSV(&b, contacts, OutlookCSVOptions{}), nil)
+
+	contacts, err := ReadOutlookCSV(&b, OutlookCSVOptions{})
+	assertEq(t, err, nil)
+	assertEq(t, len(contacts), 2)
+
+	c := contacts[0]
+	assertEq(t, c.FormattedName, "Alex Smith")
+	assertDeepEq(t, c.Emails, []Email{{Address: "alex@example.com"}, {Address: "a@example.com"}})
+	assertDeepEq(t, c.Phones, []Tel{
+		{Number: "555", Types: []string{"cell"}},
+		{Number: "556", Types: []string{"home", "fax"}},
+		{Number: "557"},
+	})
+	assertDeepEq(t, c.Addresses, []Adr{{Street: "1 Main St", Locality: "Town", Types: []string{"home"}}, {Locality: "Elsewhere"}})
+	assertEq(t, c.Birthday, Date{Year: 1990, Month: 1, Day: 2})
+	assertSlicesEq(t, c.Categories, []string{"Friends", "VIP"})
+
+	c = contacts[1]
+	assertEq(t, c.FormattedName, "Acme")
+	assertEq(t, c.Birthday, Date{})
+}
+
+func TestWriteOutlookCSVFormattedNameOnly(t *testing.T) {
+
+	card := Card{}
+	err := Unmarshal([]byte(crlfy("BEGIN:VCARD\nVERSION:4.0\nFN:Alex Smith\nEMAIL:alex@example.com\nEND:VCARD\n")), &card)
+	assertEq(t, err, nil)
+
+	b := bytes.Buffer{}
+	assertEq(t, WriteOutlookCSV(&b, []Contact{ContactOf(card)}, OutlookCSVOptions{}), nil)
+
+	contacts, err := ReadOutlookCSV(&b, OutlookCSVOptions{})
+	assertEq(t, err, nil)
+	assertEq(t, len(contacts), 1)
+	assertEq(t, contacts[0].FormattedName, "Alex Smith")
+
+	_, err = contacts[0].Card("4.0")
+	assertEq(t, err, nil)
+}
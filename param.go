@@ -0,0 +1,248 @@
+package vcard
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Parameter of a property e.g. TYPE=work,voice. Nameless parameters of vCard 2.1 like
+// CELL of TEL;CELL:555 have a Name and nil Values.
+type Param struct {
+	Name   string
+	Values []string
+}
+
+// Parameters of a property in order of appearance e.g. ";TYPE=work,voice;PREF=1".
+//
+// Values are kept unquoted and unescaped. Quoting and ^-escaping of
+// https://datatracker.ietf.org/doc/html/rfc6868 is applied by [Params.String] and reverted
+// by [ParseParams], so a value may contain newlines, quotes, `;`, `:` and `,`.
+type Params []Param
+
+// Returns a copy of parameters which does not share values with ps. Nil values of nameless
+// parameters stay nil.
+func (ps Params) clone() Params {
+	if ps == nil {
+		return nil
+	}
+	cloned := make(Params, len(ps))
+	for i, p := range ps {
+		cloned[i] = Param{p.Name, slices.Clone(p.Values)}
+	}
+	return cloned
+}
+
+// Parses raw ";param=value;param=value" string of a content line e.g.
+// `;TYPE="work,voice";LABEL="123 Main St^nAnytown"`.
+//
+// Values are split at `,` outside of quotes. TYPE and SORT-AS values are split inside quotes too,
+// since TYPE="work,voice" and SORT-AS="Harten,Rene" are common ways to write lists. Returns [ErrParsing] if a quoted
+// value is not terminated or a parameter has no name.
+func ParseParams(params string) (Params, error) {
+	if strings.Count(params, `"`)%2 != 0 {
+		return nil, parsingErrf("parameters %q contain unterminated quoted value", params)
+	}
+	if params != "" && params[0] != ';' {
+		return nil, parsingErrf("parameters %q have to start with ';'", params)
+	}
+	for _, param := range splitParams(params) {
+		if param == "" || param[0] == '=' {
+			return nil, parsingErrf("parameters %q contain a parameter without a name", params)
+		}
+	}
+	return parseParams(params), nil
+}
+
+// Parses parameters like [ParseParams] ignoring malformed ones.
+func parseParams(params string) Params {
+	ps := Params{}
+	for _, param := range splitParams(params) {
+		k, v, hasValue := strings.Cut(param, "=")
+		if !hasValue {
+			ps = append(ps, Param{Name: k})
+			continue
+		}
+		values := []string{}
+		for _, value := range splitParamValues(v) {
+			// TYPE="work,voice" and SORT-AS="Harten,Rene" of RFC 6350 are lists
+			if strings.EqualFold(k, "TYPE") || strings.EqualFold(k, "SORT-AS") {
+				values = append(values, strings.Split(value, ",")...)
+			} else {
+				values = append(values, value)
+			}
+		}
+		ps = append(ps, Param{k, slices.DeleteFunc(values, func(v string) bool { return v == "" })})
+	}
+	return ps
+}
+
+// Splits a raw parameter value at `,` which are not a part of quoted values and unquotes
+// every value e.g. `"Doe, John",x` is ["Doe, John", "x"].
+func splitParamValues(s string) []string {
+	values := []string{}
+	quoted := false
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			quoted = !quoted
+		case ',':
+			if !quoted {
+				values = append(values, unquoteParamValue(s[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(values, unquoteParamValue(s[start:]))
+}
+
+// Returns values of a parameter with the given name compared case-insensitively. Values of
+// repeated parameters are concatenated. Nameless parameters are treated as TYPE.
+func (ps Params) Values(name string) []string {
+	values := []string{}
+	for _, p := range ps {
+		switch {
+		case p.Values == nil && strings.EqualFold(name, "TYPE"):
+			values = append(values, p.Name)
+		case p.Values != nil && strings.EqualFold(p.Name, name):
+			values = append(values, p.Values...)
+		}
+	}
+	return values
+}
+
+// Returns the first value of a parameter with the given name. See [Params.Values].
+func (ps Params) Get(name string) (string, bool) {
+	values := ps.Values(name)
+	if len(values) == 0 {
+		return "", false
+	}
+	return values[0], true
+}
+
+// Reports whether a parameter with the given name is present. See [Params.Values].
+func (ps Params) Has(name string) bool {
+	return slices.ContainsFunc(ps, func(p Param) bool {
+		return p.Values != nil && strings.EqualFold(p.Name, name) ||
+			p.Values == nil && strings.EqualFold(name, "TYPE")
+	})
+}
+
+// Appends values to a parameter with the given name or adds a new parameter.
+func (ps *Params) Add(name string, values ...string) {
+	for i, p := range *ps {
+		if p.Values != nil && strings.EqualFold(p.Name, name) {
+			(*ps)[i].Values = append(slices.Clip(p.Values), values...)
+			return
+		}
+	}
+	*ps = append(*ps, Param{name, append([]string{}, values...)})
+}
+
+// Replaces values of a parameter with the given name. The parameter keeps position of the first
+// replaced one. Nameless parameters are replaced when name is TYPE.
+func (ps *Params) Set(name string, values ...string) {
+	param := Param{name, append([]string{}, values...)}
+
+	i := slices.IndexFunc(*ps, func(p Param) bool { return p.matches(name) })
+	if i < 0 {
+		*ps = append(*ps, param)
+		return
+	}
+	if (*ps)[i].Values != nil {
+		param.Name = (*ps)[i].Name
+	}
+	(*ps)[i] = param
+	rest := slices.DeleteFunc((*ps)[i+1:], func(p Param) bool { return p.matches(name) })
+	*ps = append((*ps)[:i+1], rest...)
+}
+
+// Removes all parameters with the given name. Nameless parameters are removed when name is TYPE.
+func (ps *Params) Del(name string) {
+	*ps = slices.DeleteFunc(*ps, func(p Param) bool { return p.matches(name) })
+}
+
+func (p Param) matches(name string) bool {
+	if p.Values == nil {
+		return strings.EqualFold(name, "TYPE")
+	}
+	return strings.EqualFold(p.Name, name)
+}
+
+// Returns parameters in a form of a content line e.g. ";TYPE=work,voice;PREF=1".
+func (ps Params) String() string {
+	b := []byte{}
+	for _, p := range ps {
+		if p.Values == nil {
+			b = append(b, ';')
+			b = append(b, p.Name...)
+			continue
+		}
+		if len(p.Values) == 0 {
+			b = append(b, ';')
+			b = append(b, p.Name...)
+			b = append(b, '=')
+			continue
+		}
+		b = appendParam(b, p.Name, p.Values...)
+	}
+	return string(b)
+}
+
+// Returns values of a parameter e.g. ["work", "voice"] for TYPE of ";TYPE=work,voice;PREF=1".
+// See [Params.Values].
+func paramValues(params string, name string) []string {
+	return parseParams(params).Values(name)
+}
+
+// Returns PREF of a property in range 1..100 or 0 if the property has no preference.
+// TYPE=PREF of older versions is treated as PREF=1.
+func paramPref(params string) int {
+	pref := propertyPref(Property{Params: parseParams(params)})
+	if pref == 100 && len(paramValues(params, "PREF")) == 0 {
+		return 0
+	}
+	return pref
+}
+
+// Appends ";NAME=value,value" to b. Values are quoted and ^-escaped if they contain characters
+// which are not allowed in parameter values. Nothing is appended if there are no values.
+func appendParam(b []byte, name string, values ...string) []byte {
+	if len(values) == 0 {
+		return b
+	}
+	b = append(b, ';')
+	b = append(b, name...)
+	b = append(b, '=')
+	for i, value := range values {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		if strings.ContainsAny(value, ";:,^\"\r\n") {
+			b = append(b, quoteParamValue(value)...)
+		} else {
+			b = append(b, value...)
+		}
+	}
+	return b
+}
+
+// Appends ";PREF=pref" to b if pref is in range 1..100.
+func appendPref(b []byte, pref int) []byte {
+	if pref < 1 || pref > 100 {
+		return b
+	}
+	return appendParam(b, "PREF", strconv.Itoa(pref))
+}
+
+// Returns values without ones equal to v compared case-insensitively.
+func withoutValue(values []string, v string) []string {
+	kept := values[:0]
+	for _, value := range values {
+		if !strings.EqualFold(value, v) {
+			kept = append(kept, value)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,47 @@
+package vcard
+
+import "testing"
+
+func TestParseParams(t *testing.T) {
+
+	ps, err := ParseParams(`;TYPE="work,voice";LABEL="123 Main St^nAnytown^, ^'HQ^'";X-A=a,"b,c";PREF`)
+
+	assertEq(t, err, nil)
+	assertDeepEq(t, ps, Params{
+		{"TYPE", []string{"work", "voice"}},
+		{"LABEL", []string{"123 Main St\nAnytown^, \"HQ\""}},
+		{"X-A", []string{"a", "b,c"}},
+		{Name: "PREF"},
+	})
+	assertStringsEq(t, ps.String(), `;TYPE=work,voice;LABEL="123 Main St^nAnytown^^, ^'HQ^'";X-A=a,"b,c";PREF`)
+
+	again, err := ParseParams(ps.String())
+	assertEq(t, err, nil)
+	assertDeepEq(t, again, ps)
+
+	_, err = ParseParams(`;LABEL="unterminated`)
+	assertErrIs(t, err, ErrParsing, "unterminated quoted value")
+
+	_, err = ParseParams(`;=x`)
+	assertErrIs(t, err, ErrParsing, "without a name")
+}
+
+func TestParamsModify(t *testing.T) {
+
+	ps := Params{}
+	ps.Add("TYPE", "work")
+	ps.Add("type", "voice")
+	ps.Add("PREF", "1")
+	assertStringsEq(t, ps.String(), ";TYPE=work,voice;PREF=1")
+	assertEq(t, ps.Has("pref"), true)
+
+	ps.Set("TYPE", "cell")
+	assertStringsEq(t, ps.String(), ";TYPE=cell;PREF=1")
+
+	ps.Del("pref")
+	assertEq(t, ps.Has("PREF"), false)
+
+	ps = parseParams(";CELL;PREF=1;TYPE=home")
+	ps.Set("TYPE", "work")
+	assertStringsEq(t, ps.String(), ";TYPE=work;PREF=1")
+}
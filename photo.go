@@ -0,0 +1,199 @@
+package vcard
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+)
+
+// Value of PHOTO and LOGO properties as per https://datatracker.ietf.org/doc/html/rfc6350#section-6.2.4
+//
+// Photo either references an image by URI or embeds it. Embedded images are encoded as data: URIs
+// in vCard 4.0 and as base64 values with ENCODING parameter in vCard 2.1 and 3.0. All forms
+// are accepted during decoding.
+type Photo struct {
+	// External URI e.g. "http://example.com/photo.jpg". Empty if the image is embedded.
+	URI string
+
+	// Embedded image. Nil if the image is referenced by URI.
+	Data []byte
+
+	// Media type e.g. "image/jpeg". TYPE=JPEG of older versions is decoded as image/jpeg.
+	MediaType string
+}
+
+// Reports whether the image is embedded into a vCard.
+func (p Photo) IsEmbedded() bool {
+	return p.Data != nil
+}
+
+// Returns a data: URI of an embedded image or URI of a referenced one.
+func (p Photo) DataURI() string {
+	return p.media().dataURI()
+}
+
+// Downloads an image referenced by URI with client and embeds it. Does nothing if the image is
+// embedded already. Media type is taken from Content-Type header unless it is known.
+func (p *Photo) Fetch(ctx context.Context, client *http.Client) error {
+	m := p.media()
+	err := m.fetch(ctx, client, 0)
+	if err != nil {
+		return err
+	}
+	*p = Photo{m.uri, m.data, m.mediaType}
+	return nil
+}
+
+func (p Photo) media() media {
+	return media{p.URI, p.Data, p.MediaType}
+}
+
+// Encodes the image as vCard 4.0 data: URI or URI.
+func (p Photo) MarshalVCardField() ([]byte, error) {
+	return p.MarshalVCardFieldVersion("4.0")
+}
+
+// Encodes the image in a form defined by the vCard version. Empty Photo is omitted.
+func (p Photo) MarshalVCardFieldVersion(version string) ([]byte, error) {
+	return p.media().marshal(version), nil
+}
+
+// Decodes the image from a URI, a data: URI or a base64 value with ENCODING parameter.
+func (p *Photo) UnmarshalVCardField(data []byte) error {
+	m, err := unmarshalMedia(data, "image")
+	if err != nil {
+		return err
+	}
+	*p = Photo{m.uri, m.data, m.mediaType}
+	return nil
+}
+
+// Options of [ResolvePhotos] and [ExternalizePhotos].
+type PhotoOptions struct {
+	// Maximum size of a downloaded image in bytes. Larger images stay referenced by URI and are
+	// reported as errors. Defaults to 1 MiB.
+	MaxSize int64
+
+	// Minimum size of an embedded image in bytes moved out by [ExternalizePhotos]. Smaller images
+	// e.g. thumbnails stay embedded. Zero moves every image.
+	MinSize int64
+}
+
+// Storage of images moved out of cards by [ExternalizePhotos] e.g. a bucket served over HTTP.
+type BlobStore interface {
+	// Stores data of a media type e.g. "image/jpeg" and returns URI the data can be downloaded from.
+	// Media type is empty if it is unknown.
+	PutBlob(ctx context.Context, data []byte, mediaType string) (string, error)
+}
+
+const defaultMaxPhotoSize = 1 << 20
+
+// Returns a copy of the card with images of PHOTO and LOGO referenced by http and https URIs
+// downloaded with client and embedded into the card in a form of its version. Other parameters
+// of the properties e.g. PREF are kept. URIs of other schemes are left as is.
+//
+// Images which can't be downloaded, including ones larger than opts.MaxSize, stay referenced
+// and their errors are joined with [errors.Join] and returned along with the card. If ctx is
+// canceled, remaining images are not downloaded and the error of ctx is returned.
+func ResolvePhotos(ctx context.Context, card Card, client *http.Client, opts PhotoOptions) (Card, error) {
+	maxSize := opts.MaxSize
+	if maxSize <= 0 {
+		maxSize = defaultMaxPhotoSize
+	}
+	return rewritePhotos(ctx, card, func(m *media) error {
+		if m.data != nil || !isHTTPURI(m.uri) {
+			return nil
+		}
+		return m.fetch(ctx, client, maxSize)
+	})
+}
+
+// Reverts [ResolvePhotos]. Returns a copy of the card with embedded images of PHOTO and LOGO
+// put into store and referenced by URIs it returns, which keeps cards small e.g. for syncing.
+// Images smaller than opts.MinSize stay embedded.
+//
+// Errors of the store are handled like errors of downloading by [ResolvePhotos].
+func ExternalizePhotos(ctx context.Context, card Card, store BlobStore, opts PhotoOptions) (Card, error) {
+	return rewritePhotos(ctx, card, func(m *media) error {
+		if m.data == nil || int64(len(m.data)) < opts.MinSize {
+			return nil
+		}
+		uri, err := store.PutBlob(ctx, m.data, m.mediaType)
+		if err != nil {
+			return vCardErrf("unable to store image: %w", err)
+		}
+		m.uri, m.data = uri, nil
+		return nil
+	})
+}
+
+// Applies rewrite to images of PHOTO and LOGO of a copy of the card. Properties of nested records
+// are not rewritten.
+func rewritePhotos(ctx context.Context, card Card, rewrite func(m *media) error) (Card, error) {
+	card = card.Clone()
+	version := card.Version()
+
+	errs := []error{}
+	depth := 0
+	for i, p := range card.props {
+		switch p.Name {
+		case "BEGIN":
+			depth++
+		case "END":
+			depth--
+		}
+		if depth > 0 || p.Name != "PHOTO" && p.Name != "LOGO" {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return card, vCardErrf("images are not resolved: %w", err)
+		}
+
+		m, err := unmarshalMedia([]byte(p.rest()), "image")
+		if err != nil {
+			errs = append(errs, vCardErrf("error during decoding %s: %w", p.Name, err))
+			continue
+		}
+		embedded := m.data != nil
+		if err := rewrite(&m); err != nil {
+			if ctx.Err() != nil {
+				return card, vCardErrf("images are not resolved: %w", ctx.Err())
+			}
+			errs = append(errs, vCardErrf("error during resolving %s: %w", p.Name, err))
+			continue
+		}
+		if embedded == (m.data != nil) {
+			continue
+		}
+		card.props[i], err = withMedia(p, m, version)
+		if err != nil {
+			errs = append(errs, vCardErrf("error during encoding %s: %w", p.Name, err))
+		}
+	}
+	return card, errors.Join(errs...)
+}
+
+// Returns the property with media encoded for the version. Parameters which don't describe
+// the media e.g. PREF or ALTID are kept.
+func withMedia(p Property, m media, version string) (Property, error) {
+	rewritten, err := ParseProperty(p.fullName() + string(m.marshal(version)))
+	if err != nil {
+		return p, err
+	}
+	for _, param := range p.Params {
+		switch {
+		case param.matches("VALUE"), param.matches("ENCODING"), param.matches("MEDIATYPE"):
+		case version != "4.0" && param.matches("TYPE"):
+		default:
+			rewritten.Params = append(rewritten.Params, param)
+		}
+	}
+	return rewritten, nil
+}
+
+// Reports whether uri is an absolute http or https URI.
+func isHTTPURI(uri string) bool {
+	u, err := url.Parse(uri)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
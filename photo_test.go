@@ -0,0 +1,174 @@
+package vcard
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+var jpeg = []byte{0xff, 0xd8, 0xff, 0xe0}
+
+func TestPhotoUnmarshal(t *testing.T) {
+
+	cases := map[string]Photo{
+		":http://example.com/photo.jpg":                 {URI: "http://example.com/photo.jpg"},
+		";MEDIATYPE=image/png:http://example.com/p.png": {URI: "http://example.com/p.png", MediaType: "image/png"},
+		";VALUE=uri;TYPE=GIF:http://example.com/p.gif":  {URI: "http://example.com/p.gif", MediaType: "image/gif"},
+		":data:image/jpeg;base64,/9j/4A==":              {Data: jpeg, MediaType: "image/jpeg"},
+		";ENCODING=b;TYPE=JPEG:/9j/4A==":                {Data: jpeg, MediaType: "image/jpeg"},
+		";ENCODING=BASE64;TYPE=JPG:/9j/\r\n 4A":         {Data: jpeg, MediaType: "image/jpeg"},
+		":data:text/plain,hello%20world":                {Data: []byte("hello world"), MediaType: "text/plain"},
+	}
+	for data, exp := range cases {
+		p := Photo{}
+		err := p.UnmarshalVCardField([]byte(data))
+
+		assertEq(t, err, nil)
+		assertDeepEq(t, p, exp)
+	}
+
+	p := Photo{}
+	assertErrIs(t, p.UnmarshalVCardField([]byte(";ENCODING=QUOTED-PRINTABLE:abc")), ErrParsing, "unsupported ENCODING")
+	assertErrIs(t, p.UnmarshalVCardField([]byte(":data:image/jpeg;base64")), ErrParsing, "does not contain ','")
+}
+
+func TestPhotoMarshal(t *testing.T) {
+
+	embedded := Photo{Data: jpeg, MediaType: "image/jpeg"}
+	referenced := Photo{URI: "http://example.com/p.png", MediaType: "image/png"}
+
+	cases := []struct {
+		photo   Photo
+		version string
+		exp     string
+	}{
+		{embedded, "4.0", ":data:image/jpeg;base64,/9j/4A=="},
+		{embedded, "3.0", ";ENCODING=b;TYPE=JPEG:/9j/4A=="},
+		{embedded, "2.1", ";ENCODING=BASE64;TYPE=JPEG:/9j/4A=="},
+		{referenced, "4.0", ";MEDIATYPE=image/png:http://example.com/p.png"},
+		{referenced, "3.0", ";VALUE=uri;TYPE=PNG:http://example.com/p.png"},
+		{referenced, "2.1", ";VALUE=URL;TYPE=PNG:http://example.com/p.png"},
+		{Photo{URI: "http://example.com/p"}, "4.0", ":http://example.com/p"},
+		{Photo{}, "4.0", ""},
+	}
+	for _, c := range cases {
+		b, err := c.photo.MarshalVCardFieldVersion(c.version)
+
+		assertEq(t, err, nil)
+		assertStringsEq(t, string(b), c.exp)
+	}
+
+	assertStringsEq(t, embedded.DataURI(), "data:image/jpeg;base64,/9j/4A==")
+	assertStringsEq(t, referenced.DataURI(), "http://example.com/p.png")
+}
+
+func TestPhotoFetch(t *testing.T) {
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/photo" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "image/jpeg")
+		_, _ = w.Write(jpeg)
+	}))
+	defer srv.Close()
+
+	p := Photo{URI: srv.URL + "/photo"}
+	err := p.Fetch(context.Background(), srv.Client())
+
+	assertEq(t, err, nil)
+	assertDeepEq(t, p, Photo{Data: jpeg, MediaType: "image/jpeg"})
+	assertEq(t, p.IsEmbedded(), true)
+
+	p = Photo{URI: srv.URL + "/missing"}
+	err = p.Fetch(context.Background(), srv.Client())
+
+	assertErrIs(t, err, ErrVCard, "404")
+}
+
+func TestResolvePhotos(t *testing.T) {
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/photo":
+			w.Header().Set("Content-Type", "image/jpeg")
+			_, _ = w.Write(jpeg)
+		case "/large":
+			_, _ = w.Write(make([]byte, 100))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	card := Card{}
+	card.Add("VERSION", "3.0")
+	card.Add("FN", "Alex")
+	card.AddProperty(Property{Name: "PHOTO", Params: Params{{"VALUE", []string{"uri"}}, {"X-SOURCE", []string{"web"}}}, Value: srv.URL + "/photo"})
+	card.AddProperty(Property{Name: "LOGO", Params: Params{{"VALUE", []string{"uri"}}}, Value: srv.URL + "/large"})
+	card.AddProperty(Property{Name: "LOGO", Params: Params{{"VALUE", []string{"uri"}}}, Value: "cid:logo@example.com"})
+	card.AddProperty(Property{Name: "PHOTO", Params: Params{{"VALUE", []string{"uri"}}}, Value: srv.URL + "/missing"})
+
+	resolved, err := ResolvePhotos(context.Background(), card, srv.Client(), PhotoOptions{MaxSize: 10})
+	assertErrIs(t, err, ErrVCard, "exceeds maximum of 10")
+	assertErrIs(t, err, ErrVCard, "404")
+
+	lines := []string{}
+	for _, p := range resolved.Properties() {
+		lines = append(lines, p.String())
+	}
+	assertSlicesEq(t, lines, []string{
+		"VERSION:3.0",
+		"FN:Alex",
+		"PHOTO;ENCODING=b;TYPE=JPEG;X-SOURCE=web:/9j/4A==",
+		"LOGO;VALUE=uri:" + srv.URL + "/large",
+		"LOGO;VALUE=uri:cid:logo@example.com",
+		"PHOTO;VALUE=uri:" + srv.URL + "/missing",
+	})
+
+	// The card is not modified
+	photo, _ := card.Get("PHOTO")
+	assertEq(t, photo, srv.URL+"/photo")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = ResolvePhotos(ctx, card, srv.Client(), PhotoOptions{})
+	assertErrIs(t, err, context.Canceled, "images are not resolved")
+}
+
+type mapBlobStore map[string][]byte
+
+func (s mapBlobStore) PutBlob(ctx context.Context, data []byte, mediaType string) (string, error) {
+	uri := "https://blobs.example.com/" + strconv.Itoa(len(s))
+	s[uri] = data
+	return uri, nil
+}
+
+func TestExternalizePhotos(t *testing.T) {
+
+	card := Card{}
+	card.Add("VERSION", "4.0")
+	card.AddProperty(Property{Name: "PHOTO", Params: Params{{"PREF", []string{"1"}}}, Value: "data:image/jpeg;base64,/9j/4A=="})
+	card.Add("LOGO", "data:image/png;base64,iVA=")
+	card.Add("PHOTO", "https://example.com/p.jpg")
+
+	store := mapBlobStore{}
+	externalized, err := ExternalizePhotos(context.Background(), card, store, PhotoOptions{MinSize: 3})
+	assertEq(t, err, nil)
+	assertDeepEq(t, store["https://blobs.example.com/0"], jpeg)
+	assertEq(t, len(store), 1)
+
+	lines := []string{}
+	for _, p := range externalized.Properties() {
+		lines = append(lines, p.String())
+	}
+	assertSlicesEq(t, lines, []string{
+		"VERSION:4.0",
+		"PHOTO;MEDIATYPE=image/jpeg;PREF=1:https://blobs.example.com/0",
+		"LOGO:data:image/png;base64,iVA=",
+		"PHOTO:https://example.com/p.jpg",
+	})
+}
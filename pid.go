@@ -0,0 +1,143 @@
+package vcard
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Value of PID parameter identifying a property instance for synchronization as per
+// https://datatracker.ietf.org/doc/html/rfc6350#section-5.5 e.g. "1.1" is Local=1, Source=1.
+type PID struct {
+	// Identifier of the property instance.
+	Local int
+
+	// Identifier of a source defined by CLIENTPIDMAP. 0 means PID has no source.
+	Source int
+}
+
+// Returns PID in form "local.source" or "local".
+func (p PID) String() string {
+	if p.Source == 0 {
+		return strconv.Itoa(p.Local)
+	}
+	return strconv.Itoa(p.Local) + "." + strconv.Itoa(p.Source)
+}
+
+// Parses PID like "1.1" or "2".
+func ParsePID(s string) (PID, error) {
+	local, source, hasSource := strings.Cut(s, ".")
+
+	l, err := strconv.Atoi(local)
+	if err != nil || l < 0 {
+		return PID{}, parsingErrf("invalid PID %q", s)
+	}
+	p := PID{Local: l}
+	if hasSource {
+		p.Source, err = strconv.Atoi(source)
+		if err != nil || p.Source < 1 {
+			return PID{}, parsingErrf("invalid source of PID %q", s)
+		}
+	}
+	return p, nil
+}
+
+// Value of CLIENTPIDMAP property mapping a source identifier used by PID parameters to URI of
+// a client e.g. CLIENTPIDMAP:1;urn:uuid:3df403f4-5924-4bb7-b077-3c711d9eb34b
+// See https://datatracker.ietf.org/doc/html/rfc6350#section-6.7.7
+type ClientPIDMap struct {
+	Source int
+	URI    string
+}
+
+// Encodes the map e.g. ":1;urn:uuid:3df403f4-5924-4bb7-b077-3c711d9eb34b".
+func (m ClientPIDMap) MarshalVCardField() ([]byte, error) {
+	if m.Source < 1 {
+		return nil, validationErrf("source of CLIENTPIDMAP has to be positive, got %v", m.Source)
+	}
+	return []byte(":" + strconv.Itoa(m.Source) + ";" + m.URI), nil
+}
+
+// Decodes the map from "1;urn:uuid:...".
+func (m *ClientPIDMap) UnmarshalVCardField(data []byte) error {
+	_, value, _ := splitParamsValue(string(data))
+
+	source, uri, found := strings.Cut(value, ";")
+	n, err := strconv.Atoi(source)
+	if !found || err != nil || n < 1 {
+		return parsingErrf("invalid CLIENTPIDMAP %q", value)
+	}
+	*m = ClientPIDMap{n, uri}
+	return nil
+}
+
+// Returns every CLIENTPIDMAP of the card. Invalid values are skipped.
+func (c *Card) ClientPIDMaps() []ClientPIDMap {
+	maps := []ClientPIDMap{}
+	for _, p := range c.props {
+		if p.Name != "CLIENTPIDMAP" {
+			continue
+		}
+		m := ClientPIDMap{}
+		if m.UnmarshalVCardField([]byte(":"+p.Value)) == nil {
+			maps = append(maps, m)
+		}
+	}
+	return maps
+}
+
+// Returns URI of a client which is the source of pid according to CLIENTPIDMAP properties.
+func (c *Card) SourceURI(pid PID) (string, bool) {
+	for _, m := range c.ClientPIDMaps() {
+		if m.Source == pid.Source {
+			return m.URI, true
+		}
+	}
+	return "", false
+}
+
+// Returns PIDs of every instance of property name in order of [Card.Values].
+// Instances without PID parameter have no PIDs. Invalid PIDs are skipped.
+func (c *Card) PIDs(name string) [][]PID {
+	name = strings.ToUpper(name)
+	pids := [][]PID{}
+	for _, p := range c.props {
+		if p.Name != name {
+			continue
+		}
+		instance := []PID{}
+		for _, v := range p.Params.Values("PID") {
+			if pid, err := ParsePID(v); err == nil {
+				instance = append(instance, pid)
+			}
+		}
+		pids = append(pids, instance)
+	}
+	return pids
+}
+
+// Returns value of the instance of property name identified by pid. Instances are matched
+// by local identifier and URI of the source, so the same instance is found in cards where
+// the same client has a different source identifier.
+//
+// sourceURI is URI of a client from CLIENTPIDMAP of the card which pid comes from. Empty
+// sourceURI matches PIDs without a source.
+func (c *Card) ValueByPID(name string, local int, sourceURI string) (string, bool) {
+	values := c.Values(name)
+	for i, pids := range c.PIDs(name) {
+		matches := slices.ContainsFunc(pids, func(p PID) bool {
+			if p.Local != local {
+				return false
+			}
+			if p.Source == 0 {
+				return sourceURI == ""
+			}
+			uri, found := c.SourceURI(p)
+			return found && uri == sourceURI
+		})
+		if matches {
+			return values[i], true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,76 @@
+package vcard
+
+import "testing"
+
+func TestParsePID(t *testing.T) {
+
+	p, err := ParsePID("1.2")
+	assertEq(t, err, nil)
+	assertEq(t, p, PID{1, 2})
+	assertStringsEq(t, p.String(), "1.2")
+
+	p, err = ParsePID("3")
+	assertEq(t, err, nil)
+	assertEq(t, p, PID{Local: 3})
+	assertStringsEq(t, p.String(), "3")
+
+	_, err = ParsePID("a.1")
+	assertErrIs(t, err, ErrParsing, `invalid PID "a.1"`)
+
+	_, err = ParsePID("1.0")
+	assertErrIs(t, err, ErrParsing, `invalid source of PID "1.0"`)
+}
+
+func TestClientPIDMap(t *testing.T) {
+
+	m := ClientPIDMap{}
+	err := m.UnmarshalVCardField([]byte(":1;urn:uuid:3df403f4"))
+
+	assertEq(t, err, nil)
+	assertEq(t, m, ClientPIDMap{1, "urn:uuid:3df403f4"})
+
+	b, err := m.MarshalVCardField()
+	assertEq(t, err, nil)
+	assertStringsEq(t, string(b), ":1;urn:uuid:3df403f4")
+
+	assertErrIs(t, m.UnmarshalVCardField([]byte(":urn:uuid:3df403f4")), ErrParsing, "invalid CLIENTPIDMAP")
+
+	_, err = ClientPIDMap{URI: "urn:uuid:1"}.MarshalVCardField()
+	assertErrIs(t, err, ErrValidation, "has to be positive")
+}
+
+func TestCardPIDs(t *testing.T) {
+
+	text := `BEGIN:VCARD
+VERSION:4.0
+FN:J. Doe
+EMAIL;PID=1.1:jdoe@example.com
+EMAIL;PID=2.1,2.2:john@example.com
+EMAIL:other@example.com
+TEL;PID=1:555
+CLIENTPIDMAP:1;urn:uuid:53e374d9
+CLIENTPIDMAP:2;urn:uuid:1f762d2b
+END:VCARD
+`
+	c := Card{}
+	err := Unmarshal([]byte(crlfy(text)), &c)
+	assertEq(t, err, nil)
+
+	assertDeepEq(t, c.ClientPIDMaps(), []ClientPIDMap{{1, "urn:uuid:53e374d9"}, {2, "urn:uuid:1f762d2b"}})
+	assertDeepEq(t, c.PIDs("email"), [][]PID{{{1, 1}}, {{2, 1}, {2, 2}}, {}})
+
+	uri, found := c.SourceURI(PID{2, 2})
+	assertEq(t, found, true)
+	assertStringsEq(t, uri, "urn:uuid:1f762d2b")
+
+	v, found := c.ValueByPID("EMAIL", 2, "urn:uuid:1f762d2b")
+	assertEq(t, found, true)
+	assertStringsEq(t, v, "john@example.com")
+
+	_, found = c.ValueByPID("EMAIL", 1, "urn:uuid:1f762d2b")
+	assertEq(t, found, false)
+
+	v, found = c.ValueByPID("TEL", 1, "")
+	assertEq(t, found, true)
+	assertStringsEq(t, v, "555")
+}
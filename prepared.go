@@ -0,0 +1,262 @@
+package vcard
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+)
+
+// [Schema] resolved against a Go type. Use [Schema.Prepare] to create one.
+//
+// Encoding or decoding a struct requires matching its fields and `vCard` tags against the schema.
+// PreparedSchema does it once, so [MarshalPrepared] and [UnmarshalPrepared] skip reflection over
+// struct fields on every call.
+//
+// Prepared schemas are cached by the schema they were prepared from, so [Encoder] and [Decoder]
+// reuse them even when a plain [Schema] is provided, and the cache is freed together with the schema.
+// Schemas and prepared schemas are immutable and safe to share between goroutines, e.g. concurrent
+// calls to [Marshal] and [Unmarshal] using the same schema prepare it only once.
+type PreparedSchema struct {
+	schema         Schema
+	typ            reflect.Type
+	tagKey         string
+	fallbackTagKey string
+
+	// Fields of a struct present in the schema in order of declaration
+	fields []preparedField
+
+	// First required field of the schema missing in a struct
+	missing string
+
+	// Index of a field tagged `vCard:"rest"` or -1
+	rest int
+}
+
+type preparedField struct {
+	index     int
+	name      string // vCard property name
+	goName    string
+	taggedMsg string
+
+	required  bool
+	omitEmpty bool
+	typ       string // TYPE parameter from a tag
+	def       string // default raw value from a tag or the schema
+	aliases   []string
+}
+
+// Adds TYPE parameter from a tag to rest of a content line.
+func (f preparedField) withType(rest string) string {
+	if f.typ == "" {
+		return rest
+	}
+	params, value, _ := splitParamsValue(rest)
+	return params + ";TYPE=" + f.typ + ":" + value
+}
+
+// Key of a cache of a single schema. See [schemaDerived].
+type preparedKey struct {
+	typ            reflect.Type
+	tagKey         string
+	fallbackTagKey string
+}
+
+// Key of a cache owned by [Encoder] or [Decoder] which prepares multiple schemas.
+type mappedKey struct {
+	preparedKey
+
+	// Identity of a schema. Fields of a schema are never mutated after creation and
+	// cached PreparedSchema keeps a reference to them, so address is never reused
+	// while the cache exists.
+	fields uintptr
+}
+
+// Resolves schema against type typ which is usually a struct. Result is cached in the schema,
+// so preparing the same schema for the same type multiple times is cheap.
+//
+// Struct fields are matched using `vCard` tags. See [Schema.PrepareTag] for other tag keys.
+func (s Schema) Prepare(typ reflect.Type) PreparedSchema {
+	return s.PrepareTag(typ, defaultTagKey)
+}
+
+// Same as [Schema.Prepare], but struct fields are matched using tags with a key tagKey
+// e.g. `vcf:"FN"`. See [Encoder.SetTagKey].
+func (s Schema) PrepareTag(typ reflect.Type, tagKey string) PreparedSchema {
+	return s.prepareTags(typ, tagKey, "")
+}
+
+// Same as [Schema.PrepareTag] but fields without tagKey tag are matched using tags with
+// fallbackTagKey. See [Encoder.SetFallbackTagKey].
+func (s Schema) prepareTags(typ reflect.Type, tagKey string, fallbackTagKey string) PreparedSchema {
+	key := preparedKey{typ, tagKey, fallbackTagKey}
+
+	return loadPrepared(&s.derive().prepared, key, func() PreparedSchema {
+		return prepareSchema(s, typ, tagKey, fallbackTagKey, nil)
+	})
+}
+
+// Same as [Schema.prepareTags] but names of untagged fields are mapped with mapper.
+// Functions are not comparable, so result is cached in cache owned by [Encoder] or [Decoder]
+// instead of the cache of the schema.
+func (s Schema) prepareMapped(typ reflect.Type, tagKey string, fallbackTagKey string, mapper func(string) string, cache *sync.Map) PreparedSchema {
+	key := mappedKey{preparedKey{typ, tagKey, fallbackTagKey}, reflect.ValueOf(s.fields).Pointer()}
+
+	return loadPrepared(cache, key, func() PreparedSchema {
+		return prepareSchema(s, typ, tagKey, fallbackTagKey, mapper)
+	})
+}
+
+// Cached preparation of a schema. Goroutines racing to prepare the same schema
+// wait for the first one instead of preparing it again.
+type preparedEntry struct {
+	once     sync.Once
+	prepared PreparedSchema
+}
+
+func loadPrepared(cache *sync.Map, key any, prepare func() PreparedSchema) PreparedSchema {
+	e, found := cache.Load(key)
+	if !found {
+		e, _ = cache.LoadOrStore(key, &preparedEntry{})
+	}
+	entry := e.(*preparedEntry)
+	entry.once.Do(func() {
+		entry.prepared = prepare()
+	})
+	return entry.prepared
+}
+
+func prepareSchema(s Schema, typ reflect.Type, tagKey string, fallbackTagKey string, mapper func(string) string) PreparedSchema {
+	p := PreparedSchema{schema: s, typ: typ, tagKey: tagKey, fallbackTagKey: fallbackTagKey, rest: -1}
+	if typ.Kind() != reflect.Struct {
+		return p
+	}
+
+	names := make(map[string]struct{}, typ.NumField())
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+
+		vCardName := field.Name
+		taggedMsg := ""
+
+		tag, tagged := field.Tag.Lookup(tagKey)
+		opts := parseTag(tag)
+		if !tagged && fallbackTagKey != "" {
+			if tag, tagged = field.Tag.Lookup(fallbackTagKey); tagged {
+				opts = parseFallbackTag(tag)
+				taggedMsg = fmt.Sprintf("tagged `%s:\"%s\"` ", fallbackTagKey, tag)
+			}
+		}
+		if opts.skip {
+			continue
+		}
+		if opts.rest {
+			p.rest = i
+			continue
+		}
+		if opts.name != "" {
+			vCardName = opts.name
+		} else if mapper != nil {
+			vCardName = mapper(field.Name)
+		}
+		if tag != "" && taggedMsg == "" {
+			taggedMsg = fmt.Sprintf("tagged `%s:\"%s\"` ", tagKey, tag)
+		}
+		names[vCardName] = struct{}{}
+
+		_, found := s.fields[vCardName]
+		if !found {
+			continue
+		}
+		if opts.def == "" {
+			opts.def = s.defaults[vCardName]
+		}
+		opts.aliases = slices.Concat(opts.aliases, s.aliases[vCardName])
+		p.fields = append(p.fields, preparedField{
+			index:     i,
+			name:      vCardName,
+			goName:    field.Name,
+			taggedMsg: taggedMsg,
+			required:  opts.required,
+			omitEmpty: opts.omitEmpty,
+			typ:       opts.typ,
+			def:       opts.def,
+			aliases:   opts.aliases,
+		})
+	}
+
+	for _, req := range s.derive().required {
+		if _, found := names[req]; !found {
+			p.missing = req
+			break
+		}
+	}
+	return p
+}
+
+// Returns the schema p was prepared from.
+func (p PreparedSchema) Schema() Schema {
+	return p.schema
+}
+
+// Returns the type p was prepared for.
+func (p PreparedSchema) Type() reflect.Type {
+	return p.typ
+}
+
+// Returns names of properties encoded from struct fields in order of declaration.
+func (p PreparedSchema) Fields() []string {
+	names := make([]string, len(p.fields))
+	for i, f := range p.fields {
+		names[i] = f.name
+	}
+	return names
+}
+
+// Returns names of properties encoded from struct fields which are required either
+// by the schema or by a tag.
+func (p PreparedSchema) Required() []string {
+	names := []string{}
+	for _, f := range p.fields {
+		if p.schema.IsRequired(f.name) || f.required {
+			names = append(names, f.name)
+		}
+	}
+	return names
+}
+
+// Returns type of a struct field property name is encoded from.
+func (p PreparedSchema) FieldType(name string) (reflect.Type, bool) {
+	for _, f := range p.fields {
+		if f.name == name {
+			return p.typ.Field(f.index).Type, true
+		}
+	}
+	return nil, false
+}
+
+// Serializes a Go value as a vCard document using prepared schema.
+//
+// v has to be a value of a type p was prepared for or a slice of them. Values of other types
+// are encoded using the schema p was prepared from.
+func MarshalPrepared(v any, p PreparedSchema) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf).SetTagKey(p.tagKey).SetFallbackTagKey(p.fallbackTagKey)
+
+	err := enc.EncodePrepared(v, p)
+	if err != nil {
+		return []byte{}, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Deserializes a vCard document into a Go value using prepared schema.
+//
+// v has to be a pointer to a value of a type p was prepared for or a slice of them.
+func UnmarshalPrepared(data []byte, v any, p PreparedSchema) error {
+	dec := NewDecoder(bytes.NewReader(data), []Schema{p.schema}).SetTagKey(p.tagKey).SetFallbackTagKey(p.fallbackTagKey)
+	dec.prepared = map[string]PreparedSchema{p.schema.version: p}
+	return dec.Decode(v)
+}
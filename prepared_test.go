@@ -0,0 +1,515 @@
+package vcard
+
+import (
+	"bytes"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type PreparedStruct struct {
+	Name  string `vCard:"FN"`
+	Phone string `vCard:"TEL"`
+	Extra string
+}
+
+func TestPrepareCached(t *testing.T) {
+
+	p1 := SchemaV4.Prepare(reflect.TypeFor[PreparedStruct]())
+	p2 := SchemaV4.Prepare(reflect.TypeFor[PreparedStruct]())
+
+	assertEq(t, len(p1.fields), 2)
+	assertEq(t, &p1.fields[0], &p2.fields[0])
+	assertEq(t, p1.Type(), reflect.TypeFor[PreparedStruct]())
+
+	p3 := SchemaV3.Prepare(reflect.TypeFor[PreparedStruct]())
+	assertEq(t, p3.missing, "N")
+
+	// Runtime-built schemas keep their own cache which is freed with them
+	built := NewSchemaBuilder("4.0").Field("FN", Required).Field("TEL", Optional).Build()
+	p4 := built.Prepare(reflect.TypeFor[PreparedStruct]())
+	_, found := built.derived.prepared.Load(preparedKey{reflect.TypeFor[PreparedStruct](), defaultTagKey, ""})
+	assertEq(t, found, true)
+	assertEq(t, &p4.fields[0] != &p1.fields[0], true)
+	_, found = SchemaV4.derived.prepared.Load(preparedKey{reflect.TypeFor[PreparedStruct](), defaultTagKey, ""})
+	assertEq(t, found, true)
+}
+
+func TestMarshalUnmarshalPrepared(t *testing.T) {
+
+	p := SchemaV4.Prepare(reflect.TypeFor[PreparedStruct]())
+
+	b, err := MarshalPrepared([]PreparedStruct{{Name: "Alex", Phone: "555", Extra: "x"}}, p)
+
+	exp := `BEGIN:VCARD
+VERSION:4.0
+FN:Alex
+TEL:555
+END:VCARD
+`
+	assertEq(t, err, nil)
+	assertStringsEq(t, string(b), crlfy(exp))
+
+	s := PreparedStruct{}
+	err = UnmarshalPrepared(b, &s, p)
+
+	assertEq(t, err, nil)
+	assertEq(t, s, PreparedStruct{Name: "Alex", Phone: "555"})
+}
+
+func TestMarshalPreparedMissingRequired(t *testing.T) {
+
+	p := SchemaV3.Prepare(reflect.TypeFor[PreparedStruct]())
+
+	_, err := MarshalPrepared(PreparedStruct{Name: "Alex"}, p)
+
+	assertErrIs(t, err, ErrVCard, "does not contain field \"N\"")
+}
+
+type TagOptionsStruct struct {
+	Name  string `vCard:"FN,required"`
+	Cell  string `vCard:"TEL,type=CELL,omitempty"`
+	Home  string `vCard:"TEL,type=HOME,omitempty"`
+	Email string `vCard:"EMAIL,omitempty"`
+	Note  string `vCard:"NOTE"`
+}
+
+func TestMarshalTagOptions(t *testing.T) {
+
+	b, err := Marshal(TagOptionsStruct{Name: "Alex", Cell: "555"})
+
+	exp := `BEGIN:VCARD
+VERSION:4.0
+FN:Alex
+TEL;TYPE=CELL:555
+NOTE:
+END:VCARD
+`
+	assertEq(t, err, nil)
+	assertStringsEq(t, string(b), crlfy(exp))
+
+	_, err = Marshal(TagOptionsStruct{Cell: "555"})
+	assertErrIs(t, err, ErrVCard, "required but empty")
+}
+
+func TestUnmarshalTagOptions(t *testing.T) {
+
+	text := `BEGIN:VCARD
+VERSION:4.0
+FN:Alex
+TEL;TYPE=CELL,VOICE:555
+TEL;TYPE=HOME:777
+END:VCARD
+`
+	s := TagOptionsStruct{}
+	err := Unmarshal([]byte(crlfy(text)), &s)
+
+	assertEq(t, err, nil)
+	assertStringsEq(t, s.Cell, ";TYPE=VOICE:555")
+	assertStringsEq(t, s.Home, "777")
+}
+
+type SkipTagStruct struct {
+	FN  string
+	UID string `vCard:"-"`
+}
+
+func TestSkipTag(t *testing.T) {
+
+	b, err := Marshal(SkipTagStruct{FN: "Alex", UID: "db-42"})
+
+	exp := `BEGIN:VCARD
+VERSION:4.0
+FN:Alex
+END:VCARD
+`
+	assertEq(t, err, nil)
+	assertStringsEq(t, string(b), crlfy(exp))
+
+	text := `BEGIN:VCARD
+VERSION:4.0
+FN:Alex
+UID:urn:uuid:1
+END:VCARD
+`
+	s := SkipTagStruct{UID: "db-42"}
+	err = Unmarshal([]byte(crlfy(text)), &s)
+
+	assertEq(t, err, nil)
+	assertEq(t, s, SkipTagStruct{FN: "Alex", UID: "db-42"})
+}
+
+type VcfTaggedStruct struct {
+	Name  string `vcf:"FN,required"`
+	Phone string `vcf:"TEL,omitempty" vCard:"-"`
+}
+
+func TestTagKey(t *testing.T) {
+
+	var buf bytes.Buffer
+	err := NewEncoder(&buf).SetTagKey("vcf").Encode(VcfTaggedStruct{Name: "Alex", Phone: "555"})
+
+	exp := `BEGIN:VCARD
+VERSION:4.0
+FN:Alex
+TEL:555
+END:VCARD
+`
+	assertEq(t, err, nil)
+	assertStringsEq(t, buf.String(), crlfy(exp))
+
+	s := VcfTaggedStruct{}
+	err = NewDecoder(&buf, DefaultSchemas).SetTagKey("vcf").Decode(&s)
+
+	assertEq(t, err, nil)
+	assertEq(t, s, VcfTaggedStruct{Name: "Alex", Phone: "555"})
+
+	// Default key ignores vcf tags
+	_, err = Marshal(VcfTaggedStruct{Name: "Alex"})
+	assertErrIs(t, err, ErrVCard, `does not contain field "FN"`)
+
+	schema := SchemaForTag[VcfTaggedStruct]("4.0", "vcf")
+	assertSlicesEq(t, slices.Sorted(maps.Keys(schema.fields)), []string{"FN", "TEL"})
+
+	buf.Reset()
+	err = NewEncoder(&buf).SetTagKey("vcf").EncodeSchema(VcfTaggedStruct{Name: "Alex"}, schema)
+	assertEq(t, err, nil)
+	assertStringsEq(t, buf.String(), crlfy("BEGIN:VCARD\nVERSION:4.0\nFN:Alex\nEND:VCARD\n"))
+
+	err = NewEncoder(&buf).SetTagKey("vcf").EncodeSchema(VcfTaggedStruct{Phone: "555"}, schema)
+	assertErrIs(t, err, ErrVCard, "required but empty")
+}
+
+type JSONTaggedStruct struct {
+	Name     string `json:"fn"`
+	Email    string `json:"email,omitempty"`
+	Phone    string `json:"phone" vCard:"TEL"`
+	Password string `json:"-"`
+}
+
+func TestFallbackTagKey(t *testing.T) {
+
+	var buf bytes.Buffer
+	err := NewEncoder(&buf).SetFallbackTagKey("json").Encode(JSONTaggedStruct{Name: "Alex", Phone: "555", Password: "secret"})
+
+	exp := `BEGIN:VCARD
+VERSION:4.0
+FN:Alex
+TEL:555
+END:VCARD
+`
+	assertEq(t, err, nil)
+	assertStringsEq(t, buf.String(), crlfy(exp))
+
+	text := `BEGIN:VCARD
+VERSION:4.0
+FN:Alex
+EMAIL:alex@example.com
+TEL:555
+END:VCARD
+`
+	s := JSONTaggedStruct{}
+	err = NewDecoder(strings.NewReader(crlfy(text)), DefaultSchemas).SetFallbackTagKey("json").Decode(&s)
+
+	assertEq(t, err, nil)
+	assertEq(t, s, JSONTaggedStruct{Name: "Alex", Email: "alex@example.com", Phone: "555"})
+
+	// Without fallback key json tags are ignored
+	_, err = Marshal(JSONTaggedStruct{Name: "Alex"})
+	assertErrIs(t, err, ErrVCard, `does not contain field "FN"`)
+}
+
+type DefaultsStruct struct {
+	FN   string
+	Kind string `vCard:"KIND,default=individual"`
+	Lang string `vCard:"LANG"`
+}
+
+func TestDefaults(t *testing.T) {
+
+	schema := SchemaV4.WithDefault("LANG", "en")
+
+	b, err := MarshalSchema(DefaultsStruct{FN: "Alex"}, schema)
+
+	exp := `BEGIN:VCARD
+VERSION:4.0
+FN:Alex
+KIND:individual
+LANG:en
+END:VCARD
+`
+	assertEq(t, err, nil)
+	assertStringsEq(t, string(b), crlfy(exp))
+
+	text := `BEGIN:VCARD
+VERSION:4.0
+FN:Alex
+LANG:de
+END:VCARD
+`
+	s := DefaultsStruct{}
+	err = UnmarshalSchema([]byte(crlfy(text)), &s, []Schema{schema})
+
+	assertEq(t, err, nil)
+	assertEq(t, s, DefaultsStruct{FN: "Alex", Kind: "individual", Lang: "de"})
+}
+
+func TestMapDefaults(t *testing.T) {
+
+	schema := NewSchemaBuilder("4.0").Field("FN", Required).Build().WithDefault("FN", "Unknown").WithDefault("KIND", "individual")
+
+	b, err := MarshalSchema(map[string]string{"KIND": ""}, schema)
+
+	exp := `BEGIN:VCARD
+VERSION:4.0
+KIND:individual
+FN:Unknown
+END:VCARD
+`
+	assertEq(t, err, nil)
+	assertStringsEq(t, string(b), crlfy(exp))
+
+	m := map[string]string{}
+	err = UnmarshalSchema([]byte(crlfy("BEGIN:VCARD\nVERSION:4.0\nEND:VCARD\n")), &m, []Schema{schema})
+
+	assertEq(t, err, nil)
+	assertMapsEq(t, m, map[string]string{"FN": ":Unknown", "KIND": ":individual"})
+}
+
+type AliasStruct struct {
+	FN          string
+	Anniversary string `vCard:"ANNIVERSARY"`
+	Dept        string `vCard:"X-DEPT,alias=X-DEPARTMENT"`
+}
+
+func TestAliases(t *testing.T) {
+
+	text := `BEGIN:VCARD
+VERSION:4.0
+FN:Alex
+X-ANNIVERSARY:20090808
+X-DEPARTMENT:Sales
+END:VCARD
+`
+	schema := SchemaV4.WithAliases("X-DEPT")
+
+	s := AliasStruct{}
+	err := UnmarshalSchema([]byte(crlfy(text)), &s, []Schema{schema})
+
+	assertEq(t, err, nil)
+	assertEq(t, s, AliasStruct{FN: "Alex", Anniversary: "20090808", Dept: "Sales"})
+
+	m := map[string]string{}
+	err = Unmarshal([]byte(crlfy(text)), &m)
+
+	assertEq(t, err, nil)
+	assertStringsEq(t, m["ANNIVERSARY"], ":20090808")
+
+	// ANNIVERSARY is not defined in 3.0, so the first alias is used
+	v3 := SchemaV3.WithAliases("ANNIVERSARY", "X-ANNIVERSARY")
+	b, err := MarshalSchema(map[string]string{"FN": "Alex", "N": "Alex", "ANNIVERSARY": "20090808"}, v3)
+
+	assertEq(t, err, nil)
+	assertEq(t, strings.Contains(string(b), "\r\nX-ANNIVERSARY:20090808\r\n"), true)
+
+	b, err = Marshal(s)
+
+	assertEq(t, err, nil)
+	assertEq(t, strings.Contains(string(b), "\r\nANNIVERSARY:20090808\r\n"), true)
+}
+
+type RestMapStruct struct {
+	FN   string
+	Rest map[string]string `vCard:"rest"`
+}
+
+type RestCardStruct struct {
+	FN    string
+	Extra Card `vCard:",rest"`
+}
+
+func TestRestField(t *testing.T) {
+
+	text := `BEGIN:VCARD
+VERSION:4.0
+FN:Alex
+item1.X-ABLABEL:Work
+TEL;TYPE=CELL:555
+TEL;TYPE=HOME:777
+END:VCARD
+`
+	m := RestMapStruct{}
+	err := Unmarshal([]byte(crlfy(text)), &m)
+
+	assertEq(t, err, nil)
+	assertStringsEq(t, m.FN, "Alex")
+	assertMapsEq(t, m.Rest, map[string]string{"item1.X-ABLABEL": ":Work", "TEL": ";TYPE=HOME:777"})
+
+	c := RestCardStruct{}
+	err = Unmarshal([]byte(crlfy(text)), &c)
+
+	assertEq(t, err, nil)
+	assertEq(t, c.Extra.Len(), 3)
+
+	b, err := Marshal(c)
+
+	assertEq(t, err, nil)
+	assertStringsEq(t, string(b), crlfy(text))
+}
+
+type RestTypedStruct struct {
+	FN   string
+	Cell string `vCard:"TEL,type=CELL"`
+	Rest Card   `vCard:"rest"`
+}
+
+func TestRestFieldKeepsOtherInstances(t *testing.T) {
+
+	text := `BEGIN:VCARD
+VERSION:4.0
+FN:Old
+FN:Alex
+TEL;TYPE=CELL:555
+TEL;TYPE=WORK:777
+END:VCARD
+`
+	s := RestTypedStruct{}
+	err := Unmarshal([]byte(crlfy(text)), &s)
+
+	assertEq(t, err, nil)
+	assertStringsEq(t, s.FN, "Alex")
+	assertStringsEq(t, s.Cell, "555")
+	lines := []string{}
+	for _, p := range s.Rest.Properties() {
+		lines = append(lines, p.String())
+	}
+	assertSlicesEq(t, lines, []string{"FN:Old", "TEL;TYPE=WORK:777"})
+}
+
+type RestWrongType struct {
+	FN   string
+	Rest []string `vCard:"rest"`
+}
+
+func TestRestFieldWrongType(t *testing.T) {
+
+	_, err := Marshal(RestWrongType{FN: "Alex"})
+
+	assertErrIs(t, err, ErrVCard, "unsupported type []string")
+}
+
+type MappedStruct struct {
+	FN           string
+	Email        string
+	OrgDirectory string
+	ContactURI   string
+	Phone        string `vCard:"TEL"`
+}
+
+func TestFieldNameMapper(t *testing.T) {
+
+	var buf bytes.Buffer
+	err := NewEncoder(&buf).SetFieldNameMapper(UpperKebabCase).Encode(MappedStruct{
+		FN:           "Alex",
+		Email:        "alex@example.com",
+		OrgDirectory: "http://example.com/dir",
+		ContactURI:   "mailto:alex@example.com",
+		Phone:        "555",
+	})
+
+	exp := `BEGIN:VCARD
+VERSION:4.0
+FN:Alex
+EMAIL:alex@example.com
+ORG-DIRECTORY:http://example.com/dir
+CONTACT-URI:mailto:alex@example.com
+TEL:555
+END:VCARD
+`
+	assertEq(t, err, nil)
+	assertStringLinesEq(t, buf.String(), crlfy(exp))
+
+	s := MappedStruct{}
+	err = NewDecoder(&buf, DefaultSchemas).SetFieldNameMapper(UpperKebabCase).Decode(&s)
+
+	assertEq(t, err, nil)
+	assertEq(t, s.Email, "alex@example.com")
+	assertEq(t, s.ContactURI, "mailto:alex@example.com")
+	assertEq(t, s.Phone, "555")
+
+	// Without mapper Go names are used as is
+	b, err := Marshal(MappedStruct{FN: "Alex", Email: "alex@example.com"})
+
+	assertEq(t, err, nil)
+	assertEq(t, strings.Contains(string(b), "EMAIL"), false)
+}
+
+func TestUpperKebabCase(t *testing.T) {
+
+	assertEq(t, UpperKebabCase("Email"), "EMAIL")
+	assertEq(t, UpperKebabCase("OrgDirectory"), "ORG-DIRECTORY")
+	assertEq(t, UpperKebabCase("ContactURI"), "CONTACT-URI")
+	assertEq(t, UpperKebabCase("URLList"), "URL-LIST")
+	assertEq(t, UpperKebabCase("X_ABLabel"), "X-AB-LABEL")
+	assertEq(t, UpperKebabCase("FN"), "FN")
+}
+
+type ConcurrentStruct struct {
+	Name string `vCard:"FN"`
+	Kind string `vCard:"KIND,omitempty"`
+}
+
+func TestConcurrentPrepare(t *testing.T) {
+
+	s := SchemaV4.WithDefault("KIND", "individual")
+	typ := reflect.TypeFor[ConcurrentStruct]()
+
+	var wg sync.WaitGroup
+	prepared := make([]PreparedSchema, 16)
+	for i := range prepared {
+		wg.Go(func() {
+			prepared[i] = s.Prepare(typ)
+
+			// Encoder and Decoder of the schema share its cached prepared schema
+			buf := bytes.Buffer{}
+			err := NewEncoder(&buf).EncodeSchema(ConcurrentStruct{Name: "Alex"}, s)
+			assertEq(t, err, nil)
+
+			v := ConcurrentStruct{}
+			err = NewDecoder(&buf, []Schema{s}).Decode(&v)
+			assertEq(t, err, nil)
+			assertEq(t, v, ConcurrentStruct{Name: "Alex", Kind: "individual"})
+		})
+	}
+	wg.Wait()
+
+	for _, p := range prepared {
+		assertEq(t, &p.fields[0], &prepared[0].fields[0])
+	}
+}
+
+func BenchmarkMarshalParallel(b *testing.B) {
+
+	v := PreparedStruct{Name: "Alex", Phone: "555"}
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_, _ = Marshal(v)
+		}
+	})
+}
+
+func BenchmarkUnmarshalParallel(b *testing.B) {
+
+	data, _ := Marshal(PreparedStruct{Name: "Alex", Phone: "555"})
+
+	b.RunParallel(func(pb *testing.PB) {
+		v := PreparedStruct{}
+		for pb.Next() {
+			_ = Unmarshal(data, &v)
+		}
+	})
+}
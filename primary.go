@@ -0,0 +1,106 @@
+package vcard
+
+import "strings"
+
+// Returns the most preferred email address. Preference is defined by the lowest PREF parameter
+// of vCard 4.0 or TYPE=pref of older versions. Among equally preferred addresses the first one wins.
+// Empty addresses are skipped.
+func (c *Card) PrimaryEmail() (Email, bool) {
+	return primaryOf(c, "EMAIL", func(e *Email) bool { return strings.TrimSpace(e.Address) != "" })
+}
+
+// Returns the most preferred telephone number. See [Card.PrimaryEmail].
+func (c *Card) PrimaryTel() (Tel, bool) {
+	return primaryOf(c, "TEL", func(t *Tel) bool { return strings.TrimSpace(t.Number) != "" })
+}
+
+// Returns a name to show for the card. The most preferred non-empty FN is used, then names of N
+// in order of "prefixes given additional family suffixes", then the name of ORG. Returns an empty
+// string if the card has none of them.
+func (c *Card) DisplayName() string {
+	fn := Property{}
+	bestPref := 101
+	for _, p := range c.props {
+		if p.Name == "FN" && strings.TrimSpace(unescapeText(p.Value)) != "" && propertyPref(p) < bestPref {
+			fn, bestPref = p, propertyPref(p)
+		}
+	}
+	if bestPref <= 100 {
+		return strings.TrimSpace(unescapeText(fn.Value))
+	}
+	n, _ := c.Name()
+	o, _ := c.Organization()
+	return displayName(n, o)
+}
+
+// Returns the most preferred email address. Addresses with Pref 0 are the least preferred.
+// See [Card.PrimaryEmail].
+func (c Contact) PrimaryEmail() (Email, bool) {
+	return mostPreferred(c.Emails, func(e Email) int { return e.Pref }, func(e Email) bool { return strings.TrimSpace(e.Address) != "" })
+}
+
+// Returns the most preferred telephone number. See [Contact.PrimaryEmail].
+func (c Contact) PrimaryTel() (Tel, bool) {
+	return mostPreferred(c.Phones, func(t Tel) int { return t.Pref }, func(t Tel) bool { return strings.TrimSpace(t.Number) != "" })
+}
+
+// Returns a name to show for the contact: FormattedName, Name or the name of Org. See [Card.DisplayName].
+func (c Contact) DisplayName() string {
+	if fn := strings.TrimSpace(c.FormattedName); fn != "" {
+		return fn
+	}
+	return displayName(c.Name, c.Org)
+}
+
+func displayName(n Name, o Org) string {
+	parts := []string{}
+	for _, components := range [][]string{n.HonorificPrefixes, n.GivenNames, n.AdditionalNames, n.FamilyNames, n.HonorificSuffixes} {
+		for _, s := range components {
+			if s = strings.TrimSpace(s); s != "" {
+				parts = append(parts, s)
+			}
+		}
+	}
+	if len(parts) > 0 {
+		return strings.Join(parts, " ")
+	}
+	return strings.TrimSpace(o.Name)
+}
+
+// Decodes properties with the given name and returns the most preferred one accepted by ok.
+func primaryOf[T any, PT interface {
+	*T
+	VCardFieldUnmarshaler
+}](c *Card, name string, ok func(PT) bool) (T, bool) {
+	var best T
+	bestPref := 101
+	for _, p := range c.props {
+		if p.Name != name || propertyPref(p) >= bestPref {
+			continue
+		}
+		var v T
+		if err := PT(&v).UnmarshalVCardField([]byte(p.rest())); err == nil && ok(&v) {
+			best, bestPref = v, propertyPref(p)
+		}
+	}
+	return best, bestPref <= 100
+}
+
+// Returns the value with the lowest preference in range 1..100 accepted by ok. 0 means no preference.
+func mostPreferred[T any](values []T, pref func(T) int, ok func(T) bool) (T, bool) {
+	var best T
+	bestPref := 101
+	for _, v := range values {
+		if !ok(v) {
+			continue
+		}
+		p := pref(v)
+		if p < 1 || p > 100 {
+			p = 100
+		}
+		if p < bestPref {
+			best, bestPref = v, p
+		}
+	}
+	return best, bestPref <= 100
+}
@@ -0,0 +1,70 @@
+package vcard
+
+import "testing"
+
+func TestPrimaryAccessors(t *testing.T) {
+
+	cards := []Card{}
+	err := Unmarshal([]byte(crlfy(`BEGIN:VCARD
+VERSION:4.0
+FN:
+FN;PREF=2:Alex Smith
+FN;PREF=1:Alexander Smith
+EMAIL:first@example.com
+EMAIL;PREF=1:
+EMAIL;TYPE=work;PREF=3:work@example.com
+TEL:+1 555 0100
+TEL;PREF=1:+1 555 0101
+END:VCARD
+BEGIN:VCARD
+VERSION:3.0
+N:Doe;John;Q.;Dr.;
+EMAIL:mailto:john@example.com
+TEL;TYPE=cell:555
+TEL;TYPE=home,pref:556
+END:VCARD
+BEGIN:VCARD
+VERSION:4.0
+FN: 
+ORG:Acme Inc.;Sales
+END:VCARD
+`)), &cards)
+	assertEq(t, err, nil)
+
+	assertEq(t, cards[0].DisplayName(), "Alexander Smith")
+	email, found := cards[0].PrimaryEmail()
+	assertEq(t, found, true)
+	assertDeepEq(t, email, Email{Address: "work@example.com", Types: []string{"work"}, Pref: 3})
+	tel, _ := cards[0].PrimaryTel()
+	assertEq(t, tel.Number, "+1 555 0101")
+
+	assertEq(t, cards[1].DisplayName(), "Dr. John Q. Doe")
+	email, _ = cards[1].PrimaryEmail()
+	assertEq(t, email.Address, "john@example.com")
+	tel, _ = cards[1].PrimaryTel()
+	assertEq(t, tel.Number, "556")
+
+	assertEq(t, cards[2].DisplayName(), "Acme Inc.")
+	_, found = cards[2].PrimaryEmail()
+	assertEq(t, found, false)
+	_, found = cards[2].PrimaryTel()
+	assertEq(t, found, false)
+	assertEq(t, (&Card{}).DisplayName(), "")
+
+	// ContactOf keeps the first non-empty FN
+	contact := ContactOf(cards[0])
+	assertEq(t, contact.DisplayName(), "Alex Smith")
+	email, _ = contact.PrimaryEmail()
+	assertEq(t, email.Address, "work@example.com")
+	tel, _ = contact.PrimaryTel()
+	assertEq(t, tel.Number, "+1 555 0101")
+
+	contact = ContactOf(cards[1])
+	assertEq(t, contact.DisplayName(), "Dr. John Q. Doe")
+	tel, _ = contact.PrimaryTel()
+	assertEq(t, tel.Number, "556")
+
+	_, found = Contact{}.PrimaryEmail()
+	assertEq(t, found, false)
+	assertEq(t, Contact{Org: Org{Name: "Acme"}}.DisplayName(), "Acme")
+}
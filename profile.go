@@ -0,0 +1,606 @@
+package vcard
+
+import (
+	"io"
+	"mime/quotedprintable"
+	"slices"
+	"strings"
+)
+
+// Quirks of an application which exports or imports vCards e.g. [ProfileAndroid].
+//
+// Export rewrites a card into the form the application reads without losing data, Import
+// normalizes a card written by the application into vCard 4.0. Profiles are applied by
+// [Encoder.SetProfile] and [Decoder.SetProfile] or called directly. Zero Profile changes nothing.
+type Profile struct {
+	name       string
+	exportCard func(Card) (Card, error)
+	importCard func(Card) (Card, error)
+}
+
+// Returns name of the profile e.g. "android".
+func (p Profile) String() string {
+	return p.name
+}
+
+// Returns a copy of the card rewritten for the application. The card is not modified.
+func (p Profile) Export(card Card) (Card, error) {
+	if p.exportCard == nil {
+		return card.Clone(), nil
+	}
+	exported, err := p.exportCard(card.Clone())
+	if err != nil {
+		return Card{}, vCardErrf("error during exporting a card with %s profile: %w", p.name, err)
+	}
+	return exported, nil
+}
+
+// Returns a copy of the card written by the application normalized into vCard 4.0.
+// The card is not modified.
+func (p Profile) Import(card Card) (Card, error) {
+	if p.importCard == nil {
+		return card.Clone(), nil
+	}
+	imported, err := p.importCard(card.Clone())
+	if err != nil {
+		return Card{}, vCardErrf("error during importing a card with %s profile: %w", p.name, err)
+	}
+	return imported, nil
+}
+
+// Sets a profile applied to every encoded record, so the output is readable by the application
+// of the profile, see [Profile.Export]. Records of structs and maps are converted to a [Card] first.
+// Defaults to zero Profile which writes records as is.
+func (e *Encoder) SetProfile(p Profile) *Encoder {
+	e.profile = p
+	return e
+}
+
+// Sets a profile applied to every record decoded into [Card], see [Profile.Import].
+// Records decoded into structs and maps are not affected. Defaults to zero Profile
+// which keeps records as is.
+func (d *Decoder) SetProfile(p Profile) *Decoder {
+	d.profile = p
+	return d
+}
+
+// Applies the export of the profile to fields of a record. Returns version and fields
+// of the exported record.
+func (p Profile) exportFields(version string, fields []encodedField) (string, []encodedField, error) {
+	card := Card{props: []Property{{Name: "VERSION", Value: version}}}
+	for _, f := range fields {
+		prop, err := ParseProperty(f.name + f.rest)
+		if err != nil {
+			return version, fields, vCardErrf("error during exporting a card with %s profile: %w", p.name, err)
+		}
+		card.props = append(card.props, prop)
+	}
+
+	card, err := p.Export(card)
+	if err != nil {
+		return version, fields, err
+	}
+
+	exported := make([]encodedField, 0, len(card.props))
+	versionSkipped := false
+	for _, prop := range card.props {
+		if prop.Name == "VERSION" && !versionSkipped {
+			versionSkipped = true
+			continue
+		}
+		exported = append(exported, encodedField{prop.fullName(), prop.rest()})
+	}
+	return card.Version(), exported, nil
+}
+
+// Quirks of contacts app of Android:
+//
+//   - Cards are exported as vCard 2.1 with CHARSET=UTF-8 for non-ASCII values.
+//   - NICKNAME, RELATED and ANNIVERSARY are written as X-ANDROID-CUSTOM rows of nickname,
+//     relation and contact_event kinds, which Android imports into its own fields.
+//   - TYPE parameters are written in nameless form e.g. TEL;CELL;PREF. Types without vCard 2.1
+//     equivalent are written as X- types or as X-CUSTOM(CHARSET=UTF-8,ENCODING=QUOTED-PRINTABLE,...)
+//     labels the way Android writes custom labels.
+//
+// Import reverts these rows and accepts TYPE parameters in every form Android writes them,
+// including X-CUSTOM labels, comma-separated nameless types and upper-cased values.
+var ProfileAndroid = Profile{
+	name:       "android",
+	exportCard: androidExport,
+	importCard: androidImport,
+}
+
+// Prefix of MIME types of X-ANDROID-CUSTOM rows e.g. vnd.android.cursor.item/nickname.
+const androidItemPrefix = "vnd.android.cursor.item/"
+
+// Types of a relation row of Android mapped to RELATED types. Relations without equivalent
+// are mapped to the closest type.
+var androidRelationTypes = map[string]string{
+	"1":  "agent",        // assistant
+	"2":  "sibling",      // brother
+	"3":  "child",        // child
+	"4":  "sweetheart",   // domestic partner
+	"5":  "parent",       // father
+	"6":  "friend",       // friend
+	"7":  "co-worker",    // manager
+	"8":  "parent",       // mother
+	"9":  "parent",       // parent
+	"10": "sweetheart",   // partner
+	"11": "acquaintance", // referred by
+	"12": "kin",          // relative
+	"13": "sibling",      // sister
+	"14": "spouse",       // spouse
+}
+
+// RELATED types mapped to types of a relation row of Android. Other types are written as custom
+// relations labeled with the type.
+var androidRelationCodes = map[string]string{
+	"agent":      "1",
+	"child":      "3",
+	"friend":     "6",
+	"parent":     "9",
+	"sweetheart": "10",
+	"kin":        "12",
+	"spouse":     "14",
+}
+
+// Types of vCard 2.1 written in upper case by Android and Outlook.
+var types21 = []string{
+	"PREF", "WORK", "HOME", "VOICE", "FAX", "MSG", "CELL", "PAGER", "BBS", "MODEM", "CAR", "ISDN", "VIDEO",
+	"DOM", "INTL", "POSTAL", "PARCEL", "INTERNET", "X400", "GIF", "JPEG", "PNG", "BMP", "TIFF", "PDF",
+}
+
+func androidExport(card Card) (Card, error) {
+	props := make([]Property, 0, len(card.props))
+	depth := 0
+	for _, p := range card.props {
+		if p.Name == "BEGIN" {
+			depth++
+		}
+		if depth > 0 {
+			if p.Name == "END" {
+				depth--
+			}
+			props = append(props, p)
+			continue
+		}
+
+		switch p.Name {
+		case "NICKNAME":
+			for _, nick := range splitTextList(decodedValue(p)) {
+				props = append(props, androidRow("nickname", nick, "1"))
+			}
+		case "RELATED":
+			name := decodedValue(p)
+			if v, _ := p.Params.Get("VALUE"); strings.EqualFold(v, "text") || card.Version() != "4.0" {
+				name = unescapeText(name)
+			}
+			code, label := "0", ""
+			for _, t := range p.Params.Values("TYPE") {
+				if c, found := androidRelationCodes[strings.ToLower(t)]; found {
+					code, label = c, ""
+					break
+				}
+				if label == "" {
+					label = t
+				}
+			}
+			props = append(props, androidRow("relation", name, code, label))
+		case "ANNIVERSARY", "X-ANNIVERSARY":
+			d := Date{}
+			if err := d.UnmarshalVCardField([]byte(p.rest())); err != nil {
+				return Card{}, err
+			}
+			date := d.Text
+			if date == "" {
+				date = d.format(true)
+			}
+			props = append(props, androidRow("contact_event", date, "1"))
+		default:
+			props = append(props, p)
+		}
+	}
+
+	converted, _, err := Convert(Card{props: props}, "2.1")
+	if err != nil {
+		return Card{}, err
+	}
+	depth = 0
+	for i, p := range converted.props {
+		switch {
+		case p.Name == "BEGIN":
+			depth++
+		case p.Name == "END":
+			depth--
+		case depth == 0:
+			converted.props[i].Params = namelessTypeParams(p.Params, androidCustomType)
+		}
+	}
+	return converted, nil
+}
+
+// Returns X-ANDROID-CUSTOM row of a kind e.g. "nickname" with data columns. Android writes
+// all 15 data columns, so missing ones are left empty.
+func androidRow(kind string, data ...string) Property {
+	components := make([]string, 16)
+	components[0] = androidItemPrefix + kind
+	copy(components[1:], data)
+	return Property{Name: XAndroidCustom, Value: JoinStructured(components)}
+}
+
+// Rewrites TYPE parameters into nameless parameters of vCard 2.1 e.g. TYPE=cell,pref into CELL;PREF.
+// Types of vCard 2.1 are upper-cased, X- types are kept and other types are passed to custom,
+// which returns a parameter name for them or "" to drop them.
+func namelessTypeParams(params Params, custom func(t string) string) Params {
+	if !slices.ContainsFunc(params, func(p Param) bool { return p.matches("TYPE") }) {
+		return params
+	}
+	rewritten := Params{}
+	for _, param := range params {
+		if !param.matches("TYPE") {
+			rewritten = append(rewritten, param)
+			continue
+		}
+		values := param.Values
+		if values == nil {
+			values = []string{param.Name}
+		}
+		for _, t := range values {
+			switch {
+			case slices.Contains(types21, strings.ToUpper(t)):
+				t = strings.ToUpper(t)
+			case len(t) > 2 && strings.EqualFold(t[:2], "X-") && isParamName(t):
+			default:
+				t = custom(t)
+			}
+			if t != "" {
+				rewritten = append(rewritten, Param{Name: t})
+			}
+		}
+	}
+	if len(rewritten) == 0 {
+		return nil
+	}
+	return rewritten
+}
+
+// Returns custom type as an X- type or as X-CUSTOM label encoded as quoted-printable if the type
+// can't be a parameter name.
+func androidCustomType(t string) string {
+	if isParamName(t) {
+		return "X-" + t
+	}
+	return "X-CUSTOM(CHARSET=UTF-8,ENCODING=QUOTED-PRINTABLE," + encodeQuotedPrintable(t, isAlphanumeric) + ")"
+}
+
+// Encodes bytes of s other than literal ones as quoted-printable e.g. "Work Mobile" is "Work=20Mobile"
+// when only alphanumeric bytes are literal. Lines are not wrapped.
+func encodeQuotedPrintable(s string, literal func(c byte) bool) string {
+	const hex = "0123456789ABCDEF"
+
+	b := strings.Builder{}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if literal(c) {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteByte('=')
+		b.WriteByte(hex[c>>4])
+		b.WriteByte(hex[c&0xF])
+	}
+	return b.String()
+}
+
+func isAlphanumeric(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9'
+}
+
+// Reports whether s is not empty and consists of ASCII letters, digits and `-` only.
+func isParamName(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !isAlphanumeric(s[i]) && s[i] != '-' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func androidImport(card Card) (Card, error) {
+	for i, p := range card.props {
+		card.props[i].Params = androidImportParams(p.Params)
+	}
+	converted, _, err := Convert(card, "4.0")
+	if err != nil {
+		return Card{}, err
+	}
+
+	_, hasBDay := converted.Get("BDAY")
+	props := make([]Property, 0, len(converted.props))
+	depth := 0
+	for _, p := range converted.props {
+		switch {
+		case p.Name == "BEGIN":
+			depth++
+		case p.Name == "END":
+			depth--
+		case depth == 0:
+			p = androidImportRow(p, &hasBDay)
+			for i, param := range p.Params {
+				if param.matches("TYPE") && param.Values != nil {
+					p.Params[i].Values = lowerTypes(param.Values)
+				}
+			}
+		}
+		props = append(props, p)
+	}
+	return Card{props: props}, nil
+}
+
+// Rewrites X-CUSTOM labels and comma-separated nameless types into TYPE parameters.
+func androidImportParams(params Params) Params {
+	for i, param := range params {
+		switch {
+		case len(param.Name) > 9 && strings.EqualFold(param.Name[:9], "X-CUSTOM("):
+			params[i] = Param{"TYPE", []string{androidCustomLabel(param)}}
+		case param.Values == nil && strings.Contains(param.Name, ","):
+			params[i] = Param{"TYPE", strings.Split(param.Name, ",")}
+		}
+	}
+	return params
+}
+
+// Returns label of X-CUSTOM(CHARSET=UTF-8,ENCODING=QUOTED-PRINTABLE,label) parameter decoding
+// quoted-printable labels. The parameter is split at `=` and `,` by the parser, so it is joined back first.
+func androidCustomLabel(param Param) string {
+	raw := param.Name
+	if param.Values != nil {
+		raw += "=" + strings.Join(param.Values, ",")
+	}
+	parts := strings.Split(strings.TrimSuffix(raw[len("X-CUSTOM("):], ")"), ",")
+	label := parts[len(parts)-1]
+	if slices.ContainsFunc(parts, func(p string) bool { return strings.EqualFold(p, "ENCODING=QUOTED-PRINTABLE") }) {
+		if b, err := io.ReadAll(quotedprintable.NewReader(strings.NewReader(label))); err == nil {
+			label = string(b)
+		}
+	}
+	return label
+}
+
+// Returns types of vCard 2.1 lower-cased e.g. ["CELL", "Work"] is ["cell", "Work"]. Custom labels keep their case.
+func lowerTypes(types []string) []string {
+	lowered := make([]string, len(types))
+	for i, t := range types {
+		if slices.Contains(types21, strings.ToUpper(t)) {
+			t = strings.ToLower(t)
+		}
+		lowered[i] = t
+	}
+	return lowered
+}
+
+// Returns a property represented by X-ANDROID-CUSTOM row or p as is. Birthday events become BDAY
+// only if the card has no BDAY yet.
+func androidImportRow(p Property, hasBDay *bool) Property {
+	if p.Name != XAndroidCustom {
+		return p
+	}
+	components := SplitStructured(p.Value)
+	for len(components) < 4 {
+		components = append(components, "")
+	}
+	kind, data := strings.TrimPrefix(components[0], androidItemPrefix), components[1:]
+	if data[0] == "" {
+		return p
+	}
+
+	switch kind {
+	case "nickname":
+		return Property{Group: p.Group, Name: "NICKNAME", Value: escapeText(data[0])}
+
+	case "relation":
+		related := Property{Group: p.Group, Name: "RELATED", Params: Params{{"VALUE", []string{"text"}}}, Value: escapeText(data[0])}
+		t, found := androidRelationTypes[data[1]]
+		if !found {
+			t = data[2]
+		}
+		if t != "" {
+			related.Params.Add("TYPE", t)
+		}
+		return related
+
+	case "contact_event":
+		name := ""
+		switch {
+		case data[1] == "1":
+			name = "ANNIVERSARY"
+		case data[1] == "3" && !*hasBDay:
+			name = "BDAY"
+		default:
+			return p
+		}
+		d, err := ParseDate(data[0])
+		if err != nil {
+			return p
+		}
+		event, err := withRest(Property{Group: p.Group, Name: name}, d, "4.0")
+		if err != nil {
+			return p
+		}
+		*hasBDay = *hasBDay || name == "BDAY"
+		return event
+	}
+	return p
+}
+
+// Quirks of vCard 2.1 reader of old Outlook versions:
+//
+//   - Cards are exported as vCard 2.1 with TYPE parameters in nameless form e.g. TEL;WORK;VOICE.
+//     Custom types which can't be a parameter name are dropped.
+//   - Non-ASCII values and values with line breaks are encoded as QUOTED-PRINTABLE with CHARSET=UTF-8.
+//   - Every ADR is followed by LABEL with the same types, since Outlook shows the label instead of
+//     address components. Labels missing in the card are made of the components.
+//
+// Import converts cards to vCard 4.0, which decodes QUOTED-PRINTABLE values and turns LABEL
+// back into LABEL parameter of ADR.
+var ProfileOutlook21 = Profile{
+	name:       "outlook21",
+	exportCard: outlookExport,
+	importCard: convertImport,
+}
+
+func outlookExport(card Card) (Card, error) {
+	converted, _, err := Convert(card, "2.1")
+	if err != nil {
+		return Card{}, err
+	}
+	props := converted.props
+	labels := outlookLabels(props)
+	paired := map[int]bool{}
+	for _, j := range labels {
+		paired[j] = true
+	}
+
+	exported := Card{props: make([]Property, 0, len(props)+len(labels))}
+	depth := 0
+	for i, p := range props {
+		if p.Name == "BEGIN" {
+			depth++
+		}
+		if depth > 0 {
+			if p.Name == "END" {
+				depth--
+			}
+			exported.props = append(exported.props, p)
+			continue
+		}
+		if p.Name == "LABEL" && paired[i] {
+			continue
+		}
+
+		p.Params = namelessTypeParams(p.Params, outlookCustomType)
+		exported.props = append(exported.props, outlookQuotedPrintable(p))
+		if p.Name != "ADR" || p.Value == "" {
+			continue
+		}
+
+		text := outlookLabel(p)
+		if j, found := labels[i]; found {
+			text = unescapeText(decodedValue(props[j]))
+		}
+		if text == "" {
+			continue
+		}
+		label := Property{Group: p.Group, Name: "LABEL", Params: p.Params.clone(), Value: strings.ReplaceAll(text, "\n", "\r\n")}
+		label.Params.Del("CHARSET")
+		exported.props = append(exported.props, outlookQuotedPrintable(label))
+	}
+	return exported, nil
+}
+
+// Converts a card to vCard 4.0 ignoring warnings.
+func convertImport(card Card) (Card, error) {
+	converted, _, err := Convert(card, "4.0")
+	return converted, err
+}
+
+// Returns custom type as an X- type or "" if the type can't be a parameter name.
+func outlookCustomType(t string) string {
+	if isParamName(t) {
+		return "X-" + t
+	}
+	return ""
+}
+
+// Returns indices of LABEL properties mapped by indices of ADR they describe. LABEL right after ADR
+// belongs to it, otherwise ADR takes the first unpaired LABEL with the same types.
+func outlookLabels(props []Property) map[int]int {
+	top := make([]bool, len(props))
+	depth := 0
+	for i, p := range props {
+		if p.Name == "BEGIN" {
+			depth++
+		}
+		top[i] = depth == 0
+		if depth > 0 && p.Name == "END" {
+			depth--
+		}
+	}
+
+	labels := map[int]int{}
+	paired := map[int]bool{}
+	for i, p := range props {
+		if top[i] && p.Name == "ADR" && i+1 < len(props) && props[i+1].Name == "LABEL" {
+			labels[i] = i + 1
+			paired[i+1] = true
+		}
+	}
+	for i, p := range props {
+		if _, found := labels[i]; found || !top[i] || p.Name != "ADR" {
+			continue
+		}
+		for j, l := range props {
+			if top[j] && l.Name == "LABEL" && !paired[j] && sameTypes(l.Params.Values("TYPE"), p.Params.Values("TYPE")) {
+				labels[i] = j
+				paired[j] = true
+				break
+			}
+		}
+	}
+	return labels
+}
+
+// Returns label made of ADR components the way Outlook formats addresses e.g.
+//
+//	123 Main St.
+//	Anytown, CA 91921
+//	USA
+func outlookLabel(adr Property) string {
+	c := SplitStructured(decodedValue(adr))
+	for len(c) < 7 {
+		c = append(c, "")
+	}
+	region := strings.TrimSpace(c[4] + " " + c[5])
+	city := strings.Join(nonEmptyStrings(c[3], region), ", ")
+	return strings.Join(nonEmptyStrings(c[0], c[1], c[2], city, c[6]), "\n")
+}
+
+// Encodes value of the property as QUOTED-PRINTABLE with CHARSET=UTF-8 if it contains non-ASCII
+// characters or line breaks. Escaped line breaks of TEXT values become CRLF. Values which are
+// already encoded are kept as is.
+func outlookQuotedPrintable(p Property) Property {
+	if p.Params.Has("ENCODING") || isQuotedPrintable(p.String()) {
+		return p
+	}
+	value := unescapeNewlines(p.Value)
+	if isASCII(value) && !strings.ContainsAny(value, "\r\n") {
+		return p
+	}
+	p.Params = p.Params.clone()
+	p.Params.Del("CHARSET")
+	p.Params = append(p.Params, Param{"CHARSET", []string{"UTF-8"}}, Param{"ENCODING", []string{"QUOTED-PRINTABLE"}})
+	p.Value = encodeQuotedPrintable(value, func(c byte) bool { return c >= ' ' && c < 0x7f && c != '=' })
+	return p
+}
+
+// Replaces escaped line breaks of a TEXT value with CRLF keeping other escapes e.g.
+// `a\nb\;c` is "a\r\nb\;c".
+func unescapeNewlines(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	b := strings.Builder{}
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			if s[i+1] == 'n' || s[i+1] == 'N' {
+				b.WriteString("\r\n")
+			} else {
+				b.WriteString(s[i : i+2])
+			}
+			i++
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,203 @@
+package vcard
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestProfileAndroidExport(t *testing.T) {
+
+	card, err := ParseRecord([]byte(strings.Join([]string{
+		"BEGIN:VCARD",
+		"VERSION:4.0",
+		"FN:Jörg Müller",
+		"NICKNAME:Jo,Mü",
+		"TEL;TYPE=cell,voice;PREF=1:555",
+		"TEL;TYPE=\"Work Mobile\":556",
+		"TEL;TYPE=school:557",
+		"RELATED;VALUE=text;TYPE=spouse:Sam",
+		"RELATED;VALUE=text;TYPE=co-worker:Kim\\, Jr.",
+		"ANNIVERSARY:20100615",
+		"END:VCARD",
+	}, "\r\n")))
+	assertEq(t, err, nil)
+
+	b := bytes.Buffer{}
+	err = NewEncoder(&b).SetProfile(ProfileAndroid).Encode(card)
+	assertEq(t, err, nil)
+	assertStringLinesEq(t, b.String(), strings.Join([]string{
+		"BEGIN:VCARD",
+		"VERSION:2.1",
+		"N:;;;;",
+		"FN;CHARSET=UTF-8:Jörg Müller",
+		"X-ANDROID-CUSTOM:vnd.android.cursor.item/nickname;Jo;1;;;;;;;;;;;;;",
+		"X-ANDROID-CUSTOM;CHARSET=UTF-8:vnd.android.cursor.item/nickname;Mü;1;;;;;;;;;;;;;",
+		"TEL;CELL;VOICE;PREF:555",
+		"TEL;X-CUSTOM(CHARSET=UTF-8,ENCODING=QUOTED-PRINTABLE,Work=20Mobile):556",
+		"TEL;X-school:557",
+		"X-ANDROID-CUSTOM:vnd.android.cursor.item/relation;Sam;14;;;;;;;;;;;;;",
+		"X-ANDROID-CUSTOM:vnd.android.cursor.item/relation;Kim\\, Jr.;0;co-worker;;;;;;;;;;;;",
+		"X-ANDROID-CUSTOM:vnd.android.cursor.item/contact_event;2010-06-15;1;;;;;;;;;;;;;",
+		"END:VCARD",
+		"",
+	}, "\r\n"))
+
+	// Export doesn't modify the card
+	nick, _ := card.Get("NICKNAME")
+	assertEq(t, nick, "Jo,Mü")
+}
+
+func TestProfileAndroidImport(t *testing.T) {
+
+	data := strings.Join([]string{
+		"BEGIN:VCARD",
+		"VERSION:2.1",
+		"N;CHARSET=UTF-8:Müller;Jörg;;;",
+		"FN;CHARSET=UTF-8;ENCODING=QUOTED-PRINTABLE:J=C3=B6rg M=C3=BCller",
+		"TEL;CELL;PREF:555",
+		"TEL;WORK,FAX:556",
+		"TEL;X-CUSTOM(CHARSET=UTF-8,ENCODING=QUOTED-PRINTABLE,B=C3=BCro):557",
+		"EMAIL;TYPE=HOME:jo@example.com",
+		"BDAY:1990-01-02",
+		"X-ANDROID-CUSTOM:vnd.android.cursor.item/nickname;Jo;1;;;;;;;;;;;;;",
+		"X-ANDROID-CUSTOM:vnd.android.cursor.item/relation;Sam;14;;;;;;;;;;;;;",
+		"X-ANDROID-CUSTOM:vnd.android.cursor.item/relation;Kim;0;Coach;;;;;;;;;;;;",
+		"X-ANDROID-CUSTOM:vnd.android.cursor.item/contact_event;2010-06-15;1;;;;;;;;;;;;;",
+		"X-ANDROID-CUSTOM:vnd.android.cursor.item/contact_event;1991-01-02;3;;;;;;;;;;;;;",
+		"X-ANDROID-CUSTOM:vnd.com.google.cursor.item/contact_misc;x;;;;;;;;;;;;;;",
+		"END:VCARD",
+		"",
+	}, "\r\n")
+
+	cards := []Card{}
+	err := NewDecoder(strings.NewReader(data), nil).SetProfile(ProfileAndroid).Decode(&cards)
+	assertEq(t, err, nil)
+	assertEq(t, len(cards), 1)
+
+	lines := []string{}
+	for _, p := range cards[0].Properties() {
+		lines = append(lines, p.String())
+	}
+	assertSlicesEq(t, lines, []string{
+		"VERSION:4.0",
+		"N:Müller;Jörg;;;",
+		"FN:Jörg Müller",
+		"TEL;TYPE=cell;PREF=1:555",
+		"TEL;TYPE=work,fax:556",
+		"TEL;TYPE=Büro:557",
+		"EMAIL;TYPE=home:jo@example.com",
+		"BDAY:19900102",
+		"NICKNAME:Jo",
+		"RELATED;VALUE=text;TYPE=spouse:Sam",
+		"RELATED;VALUE=text;TYPE=Coach:Kim",
+		"ANNIVERSARY:20100615",
+		"X-ANDROID-CUSTOM:vnd.android.cursor.item/contact_event;1991-01-02;3;;;;;;;;;;;;;",
+		"X-ANDROID-CUSTOM:vnd.com.google.cursor.item/contact_misc;x;;;;;;;;;;;;;;",
+	})
+}
+
+func TestProfileAndroidRoundTrip(t *testing.T) {
+
+	card := Card{}
+	card.Add("VERSION", "4.0")
+	card.Add("N", ";;;;")
+	card.Add("FN", "Alex")
+	card.Add("NICKNAME", "Al")
+	card.AddProperty(Property{Name: "RELATED", Params: Params{{"VALUE", []string{"text"}}, {"TYPE", []string{"friend"}}}, Value: "Sam"})
+	card.AddProperty(Property{Name: "TEL", Params: Params{{"TYPE", []string{"home"}}}, Value: "555"})
+	card.Add("ANNIVERSARY", "--0615")
+
+	exported, err := ProfileAndroid.Export(card)
+	assertEq(t, err, nil)
+	assertEq(t, exported.Version(), "2.1")
+
+	imported, err := ProfileAndroid.Import(exported)
+	assertEq(t, err, nil)
+	assertEq(t, Equal(imported, card), true)
+}
+
+func TestZeroProfile(t *testing.T) {
+
+	card := newStoreCard("1", "Alex")
+	b := bytes.Buffer{}
+	err := NewEncoder(&b).SetProfile(Profile{}).Encode(card)
+	assertEq(t, err, nil)
+	assertStringLinesEq(t, b.String(), "BEGIN:VCARD\r\nVERSION:4.0\r\nUID:1\r\nFN:Alex\r\nEND:VCARD\r\n")
+
+	imported, err := Profile{}.Import(card)
+	assertEq(t, err, nil)
+	assertEq(t, Equal(imported, card), true)
+	assertEq(t, ProfileAndroid.String(), "android")
+}
+
+func TestProfileOutlook21Export(t *testing.T) {
+
+	card, err := ParseRecord([]byte(strings.Join([]string{
+		"BEGIN:VCARD",
+		"VERSION:4.0",
+		"N:Müller;Jörg;;;",
+		"FN:Jörg Müller",
+		"TEL;TYPE=work,voice;PREF=1:555",
+		"TEL;TYPE=\"Work Mobile\":556",
+		"ADR;TYPE=work;LABEL=\"Hauptstraße 1\\n10115 Berlin\":;;Hauptstraße 1;Berlin;;10115;Germany",
+		"ADR;TYPE=home:;;1 Main St.;Anytown;CA;91921;USA",
+		"NOTE:First line\\nSecond\\, line",
+		"END:VCARD",
+	}, "\r\n")))
+	assertEq(t, err, nil)
+
+	b := bytes.Buffer{}
+	err = NewEncoder(&b).SetProfile(ProfileOutlook21).Encode(card)
+	assertEq(t, err, nil)
+	assertStringLinesEq(t, b.String(), strings.Join([]string{
+		"BEGIN:VCARD",
+		"VERSION:2.1",
+		"N;CHARSET=UTF-8;ENCODING=QUOTED-PRINTABLE:M=C3=BCller;J=C3=B6rg;;;",
+		"FN;CHARSET=UTF-8;ENCODING=QUOTED-PRINTABLE:J=C3=B6rg M=C3=BCller",
+		"TEL;WORK;VOICE;PREF:555",
+		"TEL:556",
+		"ADR;WORK;CHARSET=UTF-8;ENCODING=QUOTED-PRINTABLE:;;Hauptstra=C3=9Fe 1;Berlin;;10115;Germany",
+		"LABEL;WORK;CHARSET=UTF-8;ENCODING=QUOTED-PRINTABLE:Hauptstra=C3=9Fe 1=0D=0A10115 Berlin",
+		"ADR;HOME:;;1 Main St.;Anytown;CA;91921;USA",
+		"LABEL;HOME;CHARSET=UTF-8;ENCODING=QUOTED-PRINTABLE:1 Main St.=0D=0AAnytown, CA 91921=0D=0AUSA",
+		"NOTE;CHARSET=UTF-8;ENCODING=QUOTED-PRINTABLE:First line=0D=0ASecond\\, line",
+		"END:VCARD",
+		"",
+	}, "\r\n"))
+
+	exported, err := ParseRecord(b.Bytes())
+	assertEq(t, err, nil)
+	imported, err := ProfileOutlook21.Import(exported)
+	assertEq(t, err, nil)
+	fn, _ := imported.FN()
+	assertEq(t, fn, "Jörg Müller")
+	assertEq(t, imported.Version(), "4.0")
+	assertEq(t, len(imported.Values("LABEL")), 0)
+}
+
+func TestProfileOutlook21Labels(t *testing.T) {
+
+	card := Card{}
+	card.Add("VERSION", "3.0")
+	card.Add("FN", "Alex")
+	card.AddProperty(Property{Name: "LABEL", Params: Params{{"TYPE", []string{"home"}}}, Value: "Home\\nlabel"})
+	card.AddProperty(Property{Name: "ADR", Params: Params{{"TYPE", []string{"work"}}}, Value: ";;1 Work St.;;;;"})
+	card.AddProperty(Property{Name: "ADR", Params: Params{{"TYPE", []string{"home"}}}, Value: ";;1 Home St.;;;;"})
+
+	exported, err := ProfileOutlook21.Export(card)
+	assertEq(t, err, nil)
+	lines := []string{}
+	for _, p := range exported.Properties() {
+		lines = append(lines, p.String())
+	}
+	assertSlicesEq(t, lines, []string{
+		"VERSION:2.1",
+		"N:;;;;",
+		"FN:Alex",
+		"ADR;WORK:;;1 Work St.;;;;",
+		"LABEL;WORK:1 Work St.",
+		"ADR;HOME:;;1 Home St.;;;;",
+		"LABEL;HOME;CHARSET=UTF-8;ENCODING=QUOTED-PRINTABLE:Home=0D=0Alabel",
+	})
+}
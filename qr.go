@@ -0,0 +1,176 @@
+package vcard
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Export of the smallest valid vCard 3.0 card for QR codes, which get denser and harder
+// to scan with every byte:
+//
+//   - Only N, FN, TEL, EMAIL and ORG are kept. Groups, other parameters than TYPE and nested
+//     records are dropped. FN is made of N if the card has none.
+//   - Types implied by the property e.g. voice of TEL and internet of EMAIL are dropped and
+//     remaining ones are written as a single lower-cased TYPE parameter e.g. TEL;TYPE=cell,pref.
+//   - Whitespace of values is collapsed, Latin letters with combining diacritics are composed,
+//     telephone numbers are converted to E.164 if they have an international prefix and trailing
+//     empty components of ORG are dropped.
+//
+// Values are written in UTF-8 without CHARSET and lines are never folded, since [Encoder] doesn't
+// fold them. Import converts cards to vCard 4.0. See [EstimateQR] for the size of exported cards.
+var ProfileQR = Profile{
+	name:       "qr",
+	exportCard: qrExport,
+	importCard: convertImport,
+}
+
+// Properties kept by [ProfileQR].
+var qrProperties = []string{"N", "FN", "TEL", "EMAIL", "ORG"}
+
+func qrExport(card Card) (Card, error) {
+	converted, _, err := Convert(card, "3.0")
+	if err != nil {
+		return Card{}, err
+	}
+
+	exported := Card{props: []Property{{Name: "VERSION", Value: "3.0"}}}
+	for _, p := range topLevelProperties(converted.props) {
+		if !slices.Contains(qrProperties, p.Name) || (singleValued(p.Name) && exported.count(p.Name) > 0) {
+			continue
+		}
+		value := p.Value
+		if !isEncoded(p) {
+			value = collapseComponents(composeLatin(value))
+		}
+		switch p.Name {
+		case "TEL":
+			value = strings.TrimPrefix(normalizeTel(value, ""), "tel:")
+		case "ORG":
+			components := splitComponents(value)
+			for len(components) > 1 && components[len(components)-1] == "" {
+				components = components[:len(components)-1]
+			}
+			value = strings.Join(components, ";")
+		}
+		if value == "" && p.Name != "N" {
+			continue
+		}
+		exported.props = append(exported.props, Property{Name: p.Name, Params: qrParams(p), Value: value})
+	}
+
+	if exported.count("FN") == 0 {
+		n, _ := exported.Name()
+		fn := strings.Join(slices.Concat(n.HonorificPrefixes, n.GivenNames, n.AdditionalNames, n.FamilyNames, n.HonorificSuffixes), " ")
+		if strings.TrimSpace(fn) == "" {
+			return Card{}, vCardErrf("card contains neither FN nor N required by vCard 3.0")
+		}
+		exported.props = slices.Insert(exported.props, 1, Property{Name: "FN", Value: escapeText(fn)})
+	}
+	return exported, nil
+}
+
+// Returns TYPE parameter of TEL and EMAIL without types implied by the property. Binary values
+// keep their ENCODING, other parameters are dropped.
+func qrParams(p Property) Params {
+	params := Params{}
+	if encoding, found := p.Params.Get("ENCODING"); found {
+		params.Add("ENCODING", encoding)
+	}
+	if p.Name != "TEL" && p.Name != "EMAIL" {
+		return params
+	}
+	types := []string{}
+	for _, t := range p.Params.Values("TYPE") {
+		t = strings.ToLower(t)
+		if t == "voice" && p.Name == "TEL" || t == "internet" && p.Name == "EMAIL" || slices.Contains(types, t) {
+			continue
+		}
+		types = append(types, t)
+	}
+	if len(types) > 0 {
+		params.Set("TYPE", types...)
+	}
+	return params
+}
+
+// Error correction level of a QR code. Higher levels recover more damaged symbols
+// but hold less data.
+type QRLevel int
+
+const (
+	QRLevelL QRLevel = iota // Recovers 7% of symbols.
+	QRLevelM                // Recovers 15% of symbols.
+	QRLevelQ                // Recovers 25% of symbols.
+	QRLevelH                // Recovers 30% of symbols.
+)
+
+// Returns name of the level e.g. "M".
+func (l QRLevel) String() string {
+	if l < QRLevelL || l > QRLevelH {
+		return "QRLevel(" + strconv.Itoa(int(l)) + ")"
+	}
+	return string("LMQH"[l])
+}
+
+// Capacities in bytes of QR codes of versions 1..40 in byte mode at levels L, M, Q and H
+// as per ISO/IEC 18004.
+var qrCapacities = [40][4]int{
+	{17, 14, 11, 7}, {32, 26, 20, 14}, {53, 42, 32, 24}, {78, 62, 46, 34}, {106, 84, 60, 44},
+	{134, 106, 74, 58}, {154, 122, 86, 64}, {192, 152, 108, 84}, {230, 180, 130, 98}, {271, 213, 151, 119},
+	{321, 251, 177, 137}, {367, 287, 203, 155}, {425, 331, 241, 177}, {458, 362, 258, 194}, {520, 412, 292, 220},
+	{586, 450, 322, 250}, {644, 504, 364, 280}, {718, 560, 394, 310}, {792, 624, 442, 338}, {858, 666, 482, 382},
+	{929, 711, 509, 403}, {1003, 779, 565, 439}, {1091, 857, 611, 461}, {1171, 911, 661, 511}, {1273, 997, 715, 535},
+	{1367, 1059, 751, 593}, {1465, 1125, 805, 625}, {1528, 1190, 868, 658}, {1628, 1264, 908, 698}, {1732, 1370, 982, 742},
+	{1840, 1452, 1030, 790}, {1952, 1538, 1112, 842}, {2068, 1628, 1168, 898}, {2188, 1722, 1228, 958}, {2303, 1809, 1283, 983},
+	{2431, 1911, 1351, 1051}, {2563, 1989, 1423, 1093}, {2699, 2099, 1499, 1139}, {2809, 2213, 1579, 1219}, {2953, 2331, 1663, 1273},
+}
+
+// Size of a card exported with [ProfileQR]. See [EstimateQR].
+type QREstimate struct {
+	// Exported record in bytes including CRLF line breaks.
+	Size int
+}
+
+// Returns the smallest version 1..40 of a QR code which holds the record in byte mode at the level
+// or 0 if the record doesn't fit into any version.
+func (e QREstimate) Version(level QRLevel) int {
+	for i, capacities := range qrCapacities {
+		if e.Size <= capacities[level] {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// Returns the highest error correction level at which the record fits into a QR code of version
+// maxVersion or smaller e.g. 10 for codes which are still easy to scan from a printed card.
+// Returns false if the record doesn't fit even at [QRLevelL].
+func (e QREstimate) Level(maxVersion int) (QRLevel, bool) {
+	for level := QRLevelH; level >= QRLevelL; level-- {
+		if v := e.Version(level); v != 0 && v <= maxVersion {
+			return level, true
+		}
+	}
+	return QRLevelL, false
+}
+
+// Exports the card with [ProfileQR] and returns size of the encoded record, so callers can pick
+// error correction level and version of a QR code:
+//
+//	estimate, err := vcard.EstimateQR(card)
+//	if err != nil {
+//		return err
+//	}
+//	level, ok := estimate.Level(10)
+func EstimateQR(card Card) (QREstimate, error) {
+	exported, err := ProfileQR.Export(card)
+	if err != nil {
+		return QREstimate{}, err
+	}
+	b, err := Marshal(exported)
+	if err != nil {
+		return QREstimate{}, err
+	}
+	return QREstimate{Size: len(b)}, nil
+}
@@ -0,0 +1,95 @@
+package vcard
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestProfileQRExport(t *testing.T) {
+
+	card, err := ParseRecord([]byte(strings.Join([]string{
+		"BEGIN:VCARD",
+		"VERSION:4.0",
+		"UID:urn:uuid:4fbe8971-0bc3-424c-9c26-36c3e1eff6b1",
+		"FN:  Jörg   Müller ",
+		"N:Müller;Jörg;;;",
+		"item1.TEL;VALUE=uri;TYPE=cell,voice;PREF=1:tel:+49 30 1234-567",
+		"TEL;TYPE=work:555 0100",
+		"EMAIL;TYPE=work;PID=1.1:jorg@example.com",
+		"ORG:Example GmbH;;",
+		"ADR;TYPE=work:;;Hauptstraße 1;Berlin;;10115;Germany",
+		"PHOTO:https://example.com/jorg.jpg",
+		"NOTE:Long note",
+		"END:VCARD",
+	}, "\r\n")))
+	assertEq(t, err, nil)
+
+	b := bytes.Buffer{}
+	err = NewEncoder(&b).SetProfile(ProfileQR).Encode(card)
+	assertEq(t, err, nil)
+	assertStringLinesEq(t, b.String(), strings.Join([]string{
+		"BEGIN:VCARD",
+		"VERSION:3.0",
+		"FN:Jörg Müller",
+		"N:Müller;Jörg;;;",
+		"TEL;TYPE=cell,pref:+49301234567",
+		"TEL;TYPE=work:555 0100",
+		"EMAIL;TYPE=work:jorg@example.com",
+		"ORG:Example GmbH",
+		"END:VCARD",
+		"",
+	}, "\r\n"))
+
+	exported, err := ParseRecord(b.Bytes())
+	assertEq(t, err, nil)
+	assertEq(t, exported.Validate().Valid(), true)
+
+	imported, err := ProfileQR.Import(exported)
+	assertEq(t, err, nil)
+	assertEq(t, imported.Version(), "4.0")
+}
+
+func TestProfileQRMissingFN(t *testing.T) {
+
+	card := Card{}
+	card.Add("VERSION", "4.0")
+	card.Add("N", "Doe;Jane;;Dr.;")
+	card.Add("EMAIL", "jane@example.com")
+
+	exported, err := ProfileQR.Export(card)
+	assertEq(t, err, nil)
+	fn, _ := exported.FN()
+	assertEq(t, fn, "Dr. Jane Doe")
+
+	card.Del("N")
+	_, err = ProfileQR.Export(card)
+	assertErrIs(t, err, ErrVCard, "neither FN nor N")
+}
+
+func TestEstimateQR(t *testing.T) {
+
+	card := newStoreCard("1", "Alex")
+	card.Add("TEL", "+1 555 010 0100")
+
+	estimate, err := EstimateQR(card)
+	assertEq(t, err, nil)
+	exported, _ := ProfileQR.Export(card)
+	b, _ := Marshal(exported)
+	assertEq(t, estimate.Size, len(b))
+	assertStringLinesEq(t, string(b), "BEGIN:VCARD\r\nVERSION:3.0\r\nN:;;;;\r\nFN:Alex\r\nTEL:+15550100100\r\nEND:VCARD\r\n")
+	assertEq(t, estimate.Size, 72)
+
+	assertEq(t, estimate.Version(QRLevelL), 4)
+	assertEq(t, estimate.Version(QRLevelH), 8)
+	level, ok := estimate.Level(5)
+	assertEq(t, ok, true)
+	assertEq(t, level, QRLevelM)
+	_, ok = estimate.Level(3)
+	assertEq(t, ok, false)
+
+	assertEq(t, QREstimate{Size: 2954}.Version(QRLevelL), 0)
+	assertEq(t, QREstimate{Size: 1273}.Version(QRLevelH), 40)
+	assertEq(t, QRLevelM.String(), "M")
+	assertEq(t, QRLevel(7).String(), "QRLevel(7)")
+}
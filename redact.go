@@ -0,0 +1,121 @@
+package vcard
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// Defines what [Redact] does with a property.
+type RedactAction int
+
+const (
+	// Keeps the property as is. Default for properties missing from [RedactionPolicy].
+	RedactKeep RedactAction = iota
+
+	// Removes the property.
+	RedactRemove
+
+	// Replaces the value with a hash which keeps grammar of the property e.g. EMAIL becomes
+	// "3a7bd3e2360a@redacted.invalid". Equal values have equal hashes, so redacted datasets
+	// can still be used to debug matching and deduplication.
+	RedactHash
+)
+
+// Defines which properties [Redact] removes or hashes.
+type RedactionPolicy struct {
+	// Action for every property name e.g. {"TEL": RedactHash}. Names are case-insensitive.
+	Actions map[string]RedactAction
+
+	// Secret prepended to values before hashing, so hashes of well-known values like phone
+	// numbers can't be reversed by brute force.
+	Salt string
+}
+
+// Returns a policy which hashes TEL, EMAIL, ADR and IMPP and removes PHOTO, NOTE, LABEL, GEO and KEY.
+func DefaultRedactionPolicy() RedactionPolicy {
+	return RedactionPolicy{Actions: map[string]RedactAction{
+		"TEL":   RedactHash,
+		"EMAIL": RedactHash,
+		"ADR":   RedactHash,
+		"IMPP":  RedactHash,
+		"PHOTO": RedactRemove,
+		"NOTE":  RedactRemove,
+		"LABEL": RedactRemove,
+		"GEO":   RedactRemove,
+		"KEY":   RedactRemove,
+	}}
+}
+
+// Returns a copy of the card with properties removed or hashed as defined by policy.
+//
+// The card stays valid: FN and N are hashed instead of removed because they are required,
+// parameters which may contain personal data like LABEL and GEO as well as ENCODING are removed
+// from redacted properties and other parameters are kept.
+func Redact(card Card, policy RedactionPolicy) Card {
+	actions := make(map[string]RedactAction, len(policy.Actions))
+	for name, action := range policy.Actions {
+		actions[strings.ToUpper(name)] = action
+	}
+
+	redacted := Card{props: make([]Property, 0, len(card.props))}
+	for _, p := range card.props {
+		action := actions[p.Name]
+		if action == RedactRemove && (p.Name == "FN" || p.Name == "N") {
+			action = RedactHash
+		}
+		switch action {
+		case RedactRemove:
+			continue
+		case RedactHash:
+			p = p.clone()
+			p.Params.Del("LABEL")
+			p.Params.Del("GEO")
+			p.Params.Del("ENCODING") // hashes are plain ASCII
+			p.Value = redactedValue(p, policy.Salt)
+		default:
+			p = p.clone()
+		}
+		redacted.props = append(redacted.props, p)
+	}
+	return redacted
+}
+
+// Returns a hash of the value of p in a form valid for the property.
+func redactedValue(p Property, salt string) string {
+	switch p.Name {
+	case "EMAIL":
+		return redactionHash(salt, strings.ToLower(p.Value)) + "@redacted.invalid"
+	case "TEL":
+		// Digits of the hash keep numbers valid for [Tel.E164]
+		h := redactionHash(salt, digits(p.Value))
+		d := make([]byte, 10)
+		for i := range d {
+			d[i] = '0' + h[i]%10
+		}
+		if strings.HasPrefix(p.Value, "tel:") {
+			return "tel:+1" + string(d)
+		}
+		return "+1" + string(d)
+	case "IMPP", "URL", "PHOTO", "LOGO", "SOUND", "KEY", "SOURCE", "MEMBER", "RELATED", "CALURI", "CALADRURI", "FBURL":
+		return "urn:redacted:" + redactionHash(salt, p.Value)
+	case "N", "ADR", "ORG", "GENDER":
+		components := splitComponents(p.Value)
+		for i, c := range components {
+			if c != "" {
+				components[i] = redactionHash(salt, c)
+			}
+		}
+		return strings.Join(components, ";")
+	}
+	if p.Value == "" {
+		return ""
+	}
+	return redactionHash(salt, p.Value)
+}
+
+// Returns the first 12 hex digits of SHA-256 of salt and value.
+func redactionHash(salt string, value string) string {
+	sum := sha256.Sum256([]byte(salt + value))
+	return hex.EncodeToString(sum[:6])
+}
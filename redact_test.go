@@ -0,0 +1,57 @@
+package vcard
+
+import "testing"
+
+func TestRedact(t *testing.T) {
+
+	card := Card{}
+	err := Unmarshal([]byte(crlfy(`BEGIN:VCARD
+VERSION:3.0
+FN:Alex Doe
+N:Doe;Alex;;;
+TEL;TYPE=cell:+1 555 0100
+EMAIL:Alex@example.com
+EMAIL:alex@EXAMPLE.com
+ADR;TYPE=home:;;1 Main St.;Anytown;;;
+PHOTO;ENCODING=b;TYPE=JPEG:AQID
+NOTE:secret
+TITLE:Manager
+END:VCARD
+`)), &card)
+	assertEq(t, err, nil)
+
+	policy := DefaultRedactionPolicy()
+	policy.Actions["fn"] = RedactRemove
+	policy.Actions["N"] = RedactHash
+	redacted := Redact(card, policy)
+
+	assertSlicesEq(t, redacted.Values("PHOTO"), []string{})
+	assertSlicesEq(t, redacted.Values("NOTE"), []string{})
+	assertSlicesEq(t, redacted.Values("TITLE"), []string{"Manager"})
+
+	fn, _ := redacted.Get("FN")
+	assertEq(t, len(fn), 12)
+
+	emails := redacted.Values("EMAIL")
+	assertEq(t, emails[0], emails[1])
+	assertEq(t, (Email{Address: emails[0]}).Validate(), nil)
+
+	tel, _ := redacted.first("TEL")
+	assertStringsEq(t, tel.Params.String(), ";TYPE=cell")
+	_, err = Tel{Number: tel.Value}.E164("")
+	assertEq(t, err, nil)
+
+	adr, _ := redacted.Get("ADR")
+	assertEq(t, len(splitComponents(adr)), 7)
+	assertEq(t, adr[:2], ";;")
+
+	n, _ := redacted.Name()
+	assertEq(t, len(n.FamilyNames), 1)
+	assertEq(t, n.FamilyNames[0] != "Doe", true)
+
+	salted := Redact(card, RedactionPolicy{Actions: map[string]RedactAction{"EMAIL": RedactHash}, Salt: "s"})
+	assertEq(t, salted.Values("EMAIL")[0] != emails[0], true)
+
+	// the card is not modified
+	assertSlicesEq(t, card.Values("NOTE"), []string{"secret"})
+}
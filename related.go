@@ -0,0 +1,88 @@
+package vcard
+
+import (
+	"slices"
+	"strings"
+)
+
+// Values of TYPE parameter of RELATED property as per https://datatracker.ietf.org/doc/html/rfc6350#section-6.6.6
+const (
+	RelatedContact      = "contact"
+	RelatedAcquaintance = "acquaintance"
+	RelatedFriend       = "friend"
+	RelatedMet          = "met"
+	RelatedCoWorker     = "co-worker"
+	RelatedColleague    = "colleague"
+	RelatedCoResident   = "co-resident"
+	RelatedNeighbor     = "neighbor"
+	RelatedChild        = "child"
+	RelatedParent       = "parent"
+	RelatedSibling      = "sibling"
+	RelatedSpouse       = "spouse"
+	RelatedKin          = "kin"
+	RelatedMuse         = "muse"
+	RelatedCrush        = "crush"
+	RelatedDate         = "date"
+	RelatedSweetheart   = "sweetheart"
+	RelatedMe           = "me"
+	RelatedAgent        = "agent"
+	RelatedEmergency    = "emergency"
+)
+
+// Value of RELATED property of vCard 4.0 as per https://datatracker.ietf.org/doc/html/rfc6350#section-6.6.6
+//
+// Related person is referenced either by URI e.g. "urn:uuid:f81d4fae-7dec-11d0-a765-00a0c91e6bf6"
+// or described by free form text e.g. "Please contact my assistant Jane Doe for any inquiries.".
+type Related struct {
+	// URI of the related person, usually UID of another vCard. Empty if Text is set.
+	URI string
+
+	// Free form text of VALUE=text.
+	Text string
+
+	// Values of TYPE parameter e.g. [RelatedSpouse].
+	Types []string
+
+	// Preference in range 1..100 where 1 is the most preferred. 0 means no preference.
+	Pref int
+}
+
+// Reports whether TYPE parameter contains typ compared case-insensitively.
+func (r Related) HasType(typ string) bool {
+	return slices.ContainsFunc(r.Types, func(s string) bool { return strings.EqualFold(s, typ) })
+}
+
+// Encodes the relation e.g. ";TYPE=spouse:urn:uuid:..." or ";VALUE=text;TYPE=agent:Jane Doe".
+// Empty Related is omitted.
+func (r Related) MarshalVCardField() ([]byte, error) {
+	if r.URI == "" && r.Text == "" {
+		return nil, nil
+	}
+	b := []byte{}
+	if r.URI == "" {
+		b = appendParam(b, "VALUE", "text")
+	}
+	b = appendParam(b, "TYPE", r.Types...)
+	b = appendPref(b, r.Pref)
+	b = append(b, ':')
+	if r.URI == "" {
+		return append(b, escapeText(r.Text)...), nil
+	}
+	return append(b, r.URI...), nil
+}
+
+// Decodes the relation from a URI or a text of VALUE=text.
+func (r *Related) UnmarshalVCardField(data []byte) error {
+	params, value, _ := splitParamsValue(string(data))
+
+	*r = Related{Pref: paramPref(params)}
+	if types := paramValues(params, "TYPE"); len(types) > 0 {
+		r.Types = types
+	}
+	if v := paramValues(params, "VALUE"); len(v) > 0 && strings.EqualFold(v[0], "text") {
+		r.Text = unescapeText(value)
+	} else {
+		r.URI = value
+	}
+	return nil
+}
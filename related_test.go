@@ -0,0 +1,32 @@
+package vcard
+
+import "testing"
+
+func TestRelated(t *testing.T) {
+
+	cases := map[string]Related{
+		";TYPE=friend:urn:uuid:f81d4fae-7dec-11d0-a765-00a0c91e6bf6": {URI: "urn:uuid:f81d4fae-7dec-11d0-a765-00a0c91e6bf6", Types: []string{RelatedFriend}},
+		";TYPE=co-worker,crush;PREF=1:http://example.com/jdoe.vcf":   {URI: "http://example.com/jdoe.vcf", Types: []string{RelatedCoWorker, RelatedCrush}, Pref: 1},
+		";VALUE=text;TYPE=agent:Jane Doe\\, assistant":               {Text: "Jane Doe, assistant", Types: []string{RelatedAgent}},
+	}
+	for data, exp := range cases {
+		r := Related{}
+		err := r.UnmarshalVCardField([]byte(data))
+
+		assertEq(t, err, nil)
+		assertDeepEq(t, r, exp)
+
+		b, err := r.MarshalVCardField()
+
+		assertEq(t, err, nil)
+		assertStringsEq(t, string(b), data)
+	}
+
+	r := Related{Types: []string{"Spouse"}}
+	assertEq(t, r.HasType(RelatedSpouse), true)
+	assertEq(t, r.HasType(RelatedChild), false)
+
+	b, err := r.MarshalVCardField()
+	assertEq(t, err, nil)
+	assertEq(t, len(b), 0)
+}
@@ -0,0 +1,60 @@
+package vcard
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Collects fields [Encoder] skipped instead of failing the whole record.
+//
+// See [Encoder.SetReport].
+type EncodeReport struct {
+	mu      sync.Mutex
+	skipped []SkippedField
+}
+
+// Struct field or map value which was not encoded because its type is not supported.
+type SkippedField struct {
+	Record int          // Index of a record in a slice or a sequence. 0 for a single value.
+	Field  string       // Name of a struct field or a map key.
+	Type   reflect.Type // Type of the skipped value.
+	Err    error        // Error which would be returned without the report.
+}
+
+// Enables graceful degradation of unsupported value types. Disabled by default.
+//
+// When report is not nil, struct fields and map values of unsupported types are skipped
+// and recorded in report instead of aborting encoding of the entire card. This way
+// partially-mapped legacy structs can still be exported while migration proceeds.
+//
+// Errors returned by [VCardFieldMarshaler] implementations still abort encoding.
+func (e *Encoder) SetReport(report *EncodeReport) *Encoder {
+	e.report = report
+	return e
+}
+
+// Returns fields skipped so far.
+func (r *EncodeReport) Skipped() []SkippedField {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	skipped := make([]SkippedField, len(r.skipped))
+	copy(skipped, r.skipped)
+	return skipped
+}
+
+// Reports whether every field was encoded.
+func (r *EncodeReport) Complete() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.skipped) == 0
+}
+
+// Report is shared between workers when parallel encoding is enabled.
+func (r *EncodeReport) skip(f SkippedField) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.skipped = append(r.skipped, f)
+}
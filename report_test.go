@@ -0,0 +1,78 @@
+package vcard
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+type LegacyUser struct {
+	N       string
+	FN      string
+	NOTE    NotMarshaler
+	VERSION complex128
+}
+
+func TestReportSkipsUnsupportedFields(t *testing.T) {
+
+	s := []LegacyUser{
+		{N: "Alex 1", FN: "Alex FullName 1"},
+		{N: "Alex 2", FN: "Alex FullName 2"},
+	}
+
+	var buf bytes.Buffer
+	report := EncodeReport{}
+	err := NewEncoder(&buf).SetReport(&report).Encode(s)
+
+	exp := `BEGIN:VCARD
+VERSION:4.0
+N:Alex 1
+FN:Alex FullName 1
+END:VCARD
+BEGIN:VCARD
+VERSION:4.0
+N:Alex 2
+FN:Alex FullName 2
+END:VCARD
+`
+	assertEq(t, err, nil)
+	assertStringsEq(t, buf.String(), crlfy(exp))
+
+	skipped := report.Skipped()
+	assertEq(t, report.Complete(), false)
+	assertEq(t, len(skipped), 4)
+
+	assertEq(t, skipped[0].Record, 0)
+	assertEq(t, skipped[0].Field, "NOTE")
+	assertEq(t, skipped[0].Type, reflect.TypeFor[NotMarshaler]())
+	assertErrIs(t, skipped[0].Err, ErrVCard, "does not implement VCardFieldMarshaler")
+
+	assertEq(t, skipped[3].Record, 1)
+	assertEq(t, skipped[3].Field, "VERSION")
+	assertErrIs(t, skipped[3].Err, ErrVCard, "has unsupported type complex128")
+}
+
+func TestReportMapValues(t *testing.T) {
+
+	m := map[string]any{
+		"FN":   MarshalVCardImpl{"Alex"},
+		"NOTE": NotMarshaler{"Note"},
+	}
+
+	var buf bytes.Buffer
+	report := EncodeReport{}
+	err := NewEncoder(&buf).SetReport(&report).Encode(m)
+
+	exp := `BEGIN:VCARD
+VERSION:4.0
+FN;;Alex;;
+END:VCARD
+`
+	assertEq(t, err, nil)
+	assertStringsEq(t, buf.String(), crlfy(exp))
+	assertEq(t, len(report.Skipped()), 1)
+	assertEq(t, report.Skipped()[0].Field, "NOTE")
+
+	_, err = Marshal(map[string]any{"FN": "Alex", "NOTE": complex(1, 2)})
+	assertErrIs(t, err, ErrVCard, `map value for a key "NOTE" has type complex128 which does not implement VCardFieldMarshaler`)
+}
@@ -0,0 +1,116 @@
+package vcard
+
+import (
+	"reflect"
+	"slices"
+)
+
+// Appends properties stored in a field tagged `vCard:"rest"` to encoded fields.
+func (e *Encoder) appendRestFields(fields []encodedField, v reflect.Value) ([]encodedField, error) {
+	if v.Type() == cardType {
+		for _, p := range v.Interface().(Card).props {
+			if p.Name == "VERSION" {
+				continue
+			}
+			fields = append(fields, encodedField{p.fullName(), p.rest()})
+		}
+		return fields, nil
+	}
+	if !isRestMap(v.Type()) {
+		return fields, vCardErrf("field tagged `vCard:\"rest\"` has unsupported type %s. Use map[string]string or Card", v.Type())
+	}
+	keys := []string{}
+	for _, k := range v.MapKeys() {
+		keys = append(keys, k.String())
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		value := v.MapIndex(reflect.ValueOf(k).Convert(v.Type().Key()))
+		fields = append(fields, encodedField{k, e.stringRest(value.String())})
+	}
+	return fields, nil
+}
+
+// Stores properties which were not decoded into other fields in a field tagged `vCard:"rest"`.
+// consumed contains indices of decoded properties, so other properties with the same name
+// e.g. TEL;TYPE=WORK next to a field tagged `vCard:"TEL,type=CELL"` are kept.
+func fillRest(v reflect.Value, props []Property, consumed map[int]bool) error {
+	rest := []Property{}
+	depth := 0
+	for i := 0; i < len(props); i++ {
+		p := props[i]
+		switch {
+		case depth == 0 && consumed[i]:
+			// Nested record e.g. AGENT of vCard 2.1 belongs to the decoded property
+			if i+1 < len(props) && props[i+1].Name == "BEGIN" {
+				i = nestedRecordEnd(props, i+1)
+			}
+			continue
+		case depth == 0 && p.Name == "VERSION":
+			continue
+		case p.Name == "BEGIN":
+			depth++
+		case p.Name == "END" && depth > 0:
+			depth--
+		}
+		rest = append(rest, p)
+	}
+
+	if v.Type() == cardType {
+		v.Set(reflect.ValueOf(Card{props: rest}))
+		return nil
+	}
+	if !isRestMap(v.Type()) {
+		return vCardErrf("field tagged `vCard:\"rest\"` has unsupported type %s. Use map[string]string or Card", v.Type())
+	}
+	m := reflect.MakeMapWithSize(v.Type(), len(rest))
+	for _, p := range rest {
+		k := reflect.ValueOf(p.fullName()).Convert(v.Type().Key())
+		m.SetMapIndex(k, reflect.ValueOf(p.rest()).Convert(v.Type().Elem()))
+	}
+	v.Set(m)
+	return nil
+}
+
+// Returns index of END of a nested record which starts with BEGIN at index start.
+func nestedRecordEnd(props []Property, start int) int {
+	depth := 0
+	for i := start; i < len(props); i++ {
+		switch props[i].Name {
+		case "BEGIN":
+			depth++
+		case "END":
+			if depth--; depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(props) - 1
+}
+
+// Returns index of the top-level property which value the decoder stored under name, following
+// aliases of the schema the same way, or -1 if the value is a default of the schema.
+func decodedIndex(props []Property, name string, schema Schema) int {
+	for _, n := range append([]string{name}, schema.aliases[name]...) {
+		index := -1
+		depth := 0
+		for i, p := range props {
+			switch {
+			case p.Name == "BEGIN":
+				depth++
+			case p.Name == "END":
+				depth--
+			case depth == 0 && p.Name == n:
+				index = i
+			}
+		}
+		if index >= 0 {
+			return index
+		}
+	}
+	return -1
+}
+
+func isRestMap(t reflect.Type) bool {
+	return t.Kind() == reflect.Map && t.Key().Kind() == reflect.String && t.Elem().Kind() == reflect.String
+}
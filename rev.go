@@ -0,0 +1,63 @@
+package vcard
+
+import "time"
+
+// Layout used to write REV as per https://datatracker.ietf.org/doc/html/rfc6350#section-6.7.4
+const revLayout = "20060102T150405Z"
+
+// Layouts of timestamps accepted when reading REV. vCard 4.0 uses basic ISO 8601 format
+// while vCard 3.0 producers often write extended format.
+var timestampLayouts = []string{
+	"20060102T150405Z",
+	"20060102T150405Z0700",
+	"20060102T150405Z07",
+	"2006-01-02T15:04:05Z07:00",
+	"2006-01-02T15:04:05Z",
+	"20060102T150405",
+	"2006-01-02T15:04:05",
+	"20060102",
+	"2006-01-02",
+}
+
+func parseTimestamp(s string) (time.Time, bool) {
+	for _, layout := range timestampLayouts {
+		t, err := time.Parse(layout, s)
+		if err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Returns the time of the last update of the card stored in REV property.
+func (c *Card) Rev() (time.Time, bool) {
+	v, found := c.Get("REV")
+	if !found {
+		return time.Time{}, false
+	}
+	return parseTimestamp(v)
+}
+
+// Updates REV property of the card to max(now, previous REV + 1s) and returns the new value.
+//
+// REV is written with a second precision, so bumping it guarantees strictly increasing revisions
+// even if the card is updated twice within a second or the clock went backwards, which is
+// required by sync conflict resolution based on REV comparison.
+//
+// Returns an error if the card already contains REV which cannot be parsed as a timestamp.
+func (c *Card) BumpRev(now time.Time) (time.Time, error) {
+	rev := now.UTC().Truncate(time.Second)
+
+	if v, found := c.Get("REV"); found {
+		prev, ok := parseTimestamp(v)
+		if !ok {
+			return time.Time{}, vCardErrf("unable to parse REV %q as a timestamp", v)
+		}
+		if !rev.After(prev) {
+			rev = prev.UTC().Truncate(time.Second).Add(time.Second)
+		}
+	}
+
+	c.Set("REV", rev.Format(revLayout))
+	return rev, nil
+}
@@ -0,0 +1,58 @@
+package vcard
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBumpRevEmpty(t *testing.T) {
+
+	c := Card{}
+	now := time.Date(2024, 3, 1, 10, 20, 30, 500, time.UTC)
+
+	rev, err := c.BumpRev(now)
+
+	assertEq(t, err, nil)
+	assertEq(t, rev, time.Date(2024, 3, 1, 10, 20, 30, 0, time.UTC))
+	assertSlicesEq(t, c.Values("REV"), []string{"20240301T102030Z"})
+}
+
+func TestBumpRevClockSkew(t *testing.T) {
+
+	c := Card{}
+	c.Add("REV", "2024-03-01T10:20:30+02:00")
+
+	// Local clock is behind REV written by another client
+	now := time.Date(2024, 3, 1, 7, 0, 0, 0, time.UTC)
+
+	rev, err := c.BumpRev(now)
+
+	assertEq(t, err, nil)
+	assertEq(t, rev, time.Date(2024, 3, 1, 8, 20, 31, 0, time.UTC))
+	assertSlicesEq(t, c.Values("REV"), []string{"20240301T082031Z"})
+}
+
+func TestBumpRevSameSecond(t *testing.T) {
+
+	c := Card{}
+	now := time.Date(2024, 3, 1, 10, 20, 30, 0, time.UTC)
+
+	first, _ := c.BumpRev(now)
+	second, _ := c.BumpRev(now.Add(100 * time.Millisecond))
+
+	assertEq(t, second.After(first), true)
+
+	parsed, found := c.Rev()
+	assertEq(t, found, true)
+	assertEq(t, parsed, second)
+}
+
+func TestBumpRevMalformed(t *testing.T) {
+
+	c := Card{}
+	c.Add("REV", "yesterday")
+
+	_, err := c.BumpRev(time.Now())
+
+	assertErrIs(t, err, ErrVCard, "unable to parse REV \"yesterday\"")
+}
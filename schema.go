@@ -2,8 +2,13 @@ package vcard
 
 import (
 	"fmt"
+	"iter"
+	"maps"
 	"reflect"
+	"slices"
 	"strings"
+	"sync"
+	"unicode"
 )
 
 // Struct used for schema definition. See [StringSchemaV4] as an example.
@@ -16,30 +21,425 @@ import (
 // Built-in schemas are based on https://en.wikipedia.org/wiki/VCard so it is recommended to
 // provide custom set of schemas e.g. if TEL field is required in your case.
 type Schema struct {
-	version        string
-	fields         map[string]struct{}
-	requiredFields map[string]struct{}
+	version string
+	fields  map[string]struct{}
+
+	// Cardinality of fields. Fields with Min greater than zero are required,
+	// fields which are not present have [ZeroOrMore].
+	cardinality map[string]Cardinality
+
+	// Parameters accepted by fields. Fields without definitions accept any parameters
+	// unless strictParams is set.
+	params       map[string][]ParamDef
+	strictParams bool
+
+	// Validators of values of fields.
+	validators map[string][]Validator
+
+	// Raw values used when a field is empty or a property is absent.
+	defaults map[string]string
+
+	// Alternative names of fields e.g. X-ANNIVERSARY for ANNIVERSARY.
+	aliases map[string][]string
+
+	// Rules spanning multiple fields.
+	constraints []Constraint
+
+	// Data computed from the fields above on first use.
+	derived *schemaDerived
+}
+
+// Data derived from a [Schema] once on first use, so concurrent encoders and decoders
+// sharing a schema don't recompute it for every record. Copies of a schema share it,
+// which is safe because schemas are never mutated after creation.
+type schemaDerived struct {
+	once sync.Once
+
+	required    []string // sorted required fields
+	cardinality []string // sorted fields with cardinality other than [OneOrMore], see [Schema.checkCardinality]
+	defaults    []string // sorted fields with default values
+
+	extendedOnce sync.Once
+	extended     Schema // see [Schema.WithExtensions]
+
+	prepared sync.Map // preparedKey -> *preparedEntry, see [Schema.Prepare]
+}
+
+// Returns data derived from the schema. Schemas created without a constructor
+// e.g. Schema{} are not cached.
+func (s Schema) derive() *schemaDerived {
+	d := s.derived
+	if d == nil {
+		d = &schemaDerived{}
+	}
+	d.once.Do(func() {
+		for _, name := range slices.Sorted(maps.Keys(s.cardinality)) {
+			c := s.cardinality[name]
+			if c.Min > 0 {
+				d.required = append(d.required, name)
+			}
+			if c != OneOrMore {
+				d.cardinality = append(d.cardinality, name)
+			}
+		}
+		d.defaults = slices.Sorted(maps.Keys(s.defaults))
+	})
+	return d
+}
+
+// Number of occurrences of a property allowed in a single vCard.
+// See https://datatracker.ietf.org/doc/html/rfc6350#section-6
+type Cardinality struct {
+	Min int
+	Max int // Zero means there is no upper limit.
+}
+
+var (
+	ZeroOrMore = Cardinality{0, 0} // "*" in RFC 6350
+	OneOrMore  = Cardinality{1, 0} // "1*" in RFC 6350
+	ExactlyOne = Cardinality{1, 1} // "1" in RFC 6350
+	ZeroOrOne  = Cardinality{0, 1} // "*1" in RFC 6350
+)
+
+// Cardinalities commonly used with [SchemaBuilder].
+var (
+	Required = OneOrMore
+	Optional = ZeroOrMore
+)
+
+// Returns cardinality in RFC 6350 notation e.g. "*1". Other cardinalities are written as "2..5".
+func (c Cardinality) String() string {
+	switch c {
+	case ZeroOrMore:
+		return "*"
+	case OneOrMore:
+		return "1*"
+	case ExactlyOne:
+		return "1"
+	case ZeroOrOne:
+		return "*1"
+	}
+	if c.Max == 0 {
+		return fmt.Sprintf("%v..", c.Min)
+	}
+	return fmt.Sprintf("%v..%v", c.Min, c.Max)
+}
+
+// Parses cardinality in RFC 6350 notation e.g. "*1".
+func parseCardinality(s string) (Cardinality, bool) {
+	for _, c := range []Cardinality{ZeroOrMore, OneOrMore, ExactlyOne, ZeroOrOne} {
+		if c.String() == s {
+			return c, true
+		}
+	}
+	return Cardinality{}, false
+}
+
+// Creates a [Schema] without defining a struct, e.g. when a set of properties is configured at runtime.
+//
+//	schema := NewSchemaBuilder("4.0").
+//		Field("FN", Required).
+//		Field("X-DEPT", Optional).
+//		Build()
+type SchemaBuilder struct {
+	schema Schema
+}
+
+// Creates new SchemaBuilder for a schema of a version.
+func NewSchemaBuilder(version string) *SchemaBuilder {
+	return &SchemaBuilder{Schema{version: version}.clone()}
+}
+
+// Adds field with cardinality c. See [Schema.WithCardinality].
+func (b *SchemaBuilder) Field(name string, c Cardinality) *SchemaBuilder {
+	b.schema.fields[name] = struct{}{}
+	b.schema.setCardinality(name, c)
+	return b
+}
+
+// Defines parameters accepted by a field. See [Schema.WithParams].
+func (b *SchemaBuilder) Params(name string, params ...ParamDef) *SchemaBuilder {
+	b.schema.fields[name] = struct{}{}
+	b.schema.params[canonicalPropertyName(name)] = slices.Clone(params)
+	return b
+}
+
+// Returns built schema. Builder may be used further without affecting returned schema.
+func (b *SchemaBuilder) Build() Schema {
+	return b.schema.clone()
+}
+
+// Returns a copy of the schema where field name has cardinality c. Field is added to the schema
+// if it is not there yet. Field becomes required if c.Min is greater than zero.
+func (s Schema) WithCardinality(name string, c Cardinality) Schema {
+	s = s.clone()
+
+	s.fields[name] = struct{}{}
+	s.setCardinality(name, c)
+
+	return s
+}
+
+// Sets cardinality of a field. [ZeroOrMore] is implied, so it is not stored.
+func (s *Schema) setCardinality(name string, c Cardinality) {
+	if c == ZeroOrMore {
+		delete(s.cardinality, name)
+		return
+	}
+	s.cardinality[name] = c
+}
+
+// Parameter accepted by a property of a [Schema] e.g. TYPE of TEL.
+type ParamDef struct {
+	Name string
+
+	// Values accepted by the parameter compared case-insensitively. Empty means any value.
+	//
+	// Values starting with "X-" are always accepted as experimental values
+	// as per https://datatracker.ietf.org/doc/html/rfc6350#section-5.6
+	Values []string
+}
+
+// Returns a copy of the schema where property name accepts parameters params.
+// Field is added to the schema if it is not there yet.
+//
+// [Encoder] and [Decoder] return [ErrValidation] if a value of a defined parameter is not accepted,
+// e.g. EMAIL;TYPE=HOME,BANANA for TYPE accepting only HOME and WORK. Parameters which are
+// not defined are not checked, as well as properties without definitions.
+//
+// Nameless parameters of vCard 2.1 like TEL;CELL:555 are checked as TYPE.
+func (s Schema) WithParams(name string, params ...ParamDef) Schema {
+	s = s.clone()
+
+	s.fields[name] = struct{}{}
+	s.params[canonicalPropertyName(name)] = slices.Clone(params)
+
+	return s
+}
+
+// Returns a copy of the schema which rejects parameters not defined with [Schema.WithParams].
+// Properties without definitions accept no parameters at all. Experimental parameters
+// starting with "X-" are always accepted.
+//
+// It is useful to validate documents against requirements of a specific consumer
+// e.g. to check own output with [Encoder].
+func (s Schema) WithStrictParams(strict bool) Schema {
+	s = s.clone()
+	s.strictParams = strict
+	return s
+}
+
+// Checks raw ";param=value..." parameters of a property against definitions of the schema.
+func (s Schema) checkParams(name string, params string) error {
+	defs, found := s.params[canonicalPropertyName(name)]
+	if !found && !s.strictParams {
+		return nil
+	}
+	for _, param := range splitParams(params) {
+		k, v, hasValue := strings.Cut(param, "=")
+		if !hasValue {
+			k, v = "TYPE", k
+		}
+		i := slices.IndexFunc(defs, func(def ParamDef) bool { return strings.EqualFold(def.Name, k) })
+		if i < 0 && s.strictParams && !(len(k) > 2 && strings.EqualFold(k[:2], "X-")) {
+			return validationErrf("parameter %s of property %q is not defined by the schema", k, name)
+		}
+		if i < 0 || len(defs[i].Values) == 0 {
+			continue
+		}
+		for value := range strings.SplitSeq(unquoteParamValue(v), ",") {
+			if len(value) > 2 && strings.EqualFold(value[:2], "X-") {
+				continue
+			}
+			if !slices.ContainsFunc(defs[i].Values, func(allowed string) bool { return strings.EqualFold(allowed, value) }) {
+				return validationErrf("parameter %s of property %q has value %q which is not accepted by the schema", k, name, value)
+			}
+		}
+	}
+	return nil
+}
+
+// Returns a copy of the schema where field name has a default raw value e.g. "individual" for KIND.
+// Field is added to the schema if it is not there yet.
+//
+// [Encoder] writes the default when a struct field or a map value is empty or a map does not
+// contain the field. [Decoder] uses the default when a record does not contain the property.
+// Field with a default satisfies required constraint.
+func (s Schema) WithDefault(name string, value string) Schema {
+	s = s.clone()
+
+	s.fields[name] = struct{}{}
+	s.defaults[name] = value
+
+	return s
+}
+
+// Returns a copy of the schema where field name has alternative names e.g. X-ANNIVERSARY
+// for ANNIVERSARY. Aliases are added to ones already defined. Field is added to the schema
+// if it is not there yet.
+//
+// [Decoder] decodes a property named by an alias into the field if the record does not contain
+// the field itself. [Encoder] writes the field under the first alias when the field is not defined
+// in the version of the schema, see https://en.wikipedia.org/wiki/VCard#Properties
+func (s Schema) WithAliases(name string, aliases ...string) Schema {
+	s = s.clone()
+
+	s.fields[name] = struct{}{}
+	s.aliases[name] = slices.Concat(s.aliases[name], aliases)
+
+	return s
+}
+
+// Returns name of a field written in a record of a version. See [Schema.WithAliases].
+func aliasedName(name string, aliases []string, version string) string {
+	if len(aliases) == 0 || propertyDefinedIn(name, version) {
+		return name
+	}
+	return aliases[0]
+}
+
+// Returns a deep copy of the schema. Schemas are immutable, so every With... method works on a copy.
+func (s Schema) clone() Schema {
+	c := Schema{
+		version:      s.version,
+		fields:       maps.Clone(s.fields),
+		cardinality:  maps.Clone(s.cardinality),
+		params:       maps.Clone(s.params),
+		strictParams: s.strictParams,
+		validators:   maps.Clone(s.validators),
+		defaults:     maps.Clone(s.defaults),
+		aliases:      maps.Clone(s.aliases),
+		constraints:  slices.Clone(s.constraints),
+		derived:      &schemaDerived{},
+	}
+	if c.fields == nil {
+		c.fields = make(map[string]struct{})
+	}
+	if c.cardinality == nil {
+		c.cardinality = make(map[string]Cardinality)
+	}
+	if c.params == nil {
+		c.params = make(map[string][]ParamDef)
+	}
+	if c.validators == nil {
+		c.validators = make(map[string][]Validator)
+	}
+	if c.defaults == nil {
+		c.defaults = make(map[string]string)
+	}
+	if c.aliases == nil {
+		c.aliases = make(map[string][]string)
+	}
+	return c
+}
+
+// Returns vCard version of the schema e.g. "4.0".
+func (s Schema) Version() string {
+	return s.version
+}
+
+// Returns sorted names of all fields of the schema.
+func (s Schema) Fields() []string {
+	return slices.Sorted(maps.Keys(s.fields))
+}
+
+// Returns sorted names of required fields of the schema.
+func (s Schema) Required() []string {
+	return slices.Clone(s.derive().required)
+}
+
+// Reports whether name is a field of the schema.
+func (s Schema) HasField(name string) bool {
+	_, found := s.fields[name]
+	return found
+}
+
+// Reports whether name is a required field of the schema.
+func (s Schema) IsRequired(name string) bool {
+	return s.cardinalityOf(name).Min > 0
+}
+
+// Returns cardinality of a field. Required fields without explicit cardinality have [OneOrMore]
+// and other fields have [ZeroOrMore].
+func (s Schema) Cardinality(name string) Cardinality {
+	return s.cardinalityOf(name)
+}
+
+// Returns parameters accepted by a field or nil if the field accepts any parameters.
+func (s Schema) Params(name string) []ParamDef {
+	return slices.Clone(s.params[canonicalPropertyName(name)])
+}
+
+// Returns default raw value of a field e.g. "individual". See [Schema.WithDefault].
+func (s Schema) Default(name string) (string, bool) {
+	def, found := s.defaults[name]
+	return def, found
+}
+
+// Returns alternative names of a field.
+func (s Schema) Aliases(name string) []string {
+	return slices.Clone(s.aliases[name])
+}
+
+// Returns cardinality of a field.
+func (s Schema) cardinalityOf(name string) Cardinality {
+	if c, found := s.cardinality[name]; found {
+		return c
+	}
+	return ZeroOrMore
+}
+
+// Checks number of occurrences of properties against cardinalities defined in the schema.
+// Properties are counted by canonical name, so grouped properties like item1.TEL count as TEL.
+//
+// [OneOrMore] is skipped, since required fields are checked separately taking defaults into account.
+func (s Schema) checkCardinality(names iter.Seq[string]) error {
+	if len(s.derive().cardinality) == 0 {
+		return nil
+	}
+	counts := map[string]int{}
+	for name := range names {
+		counts[canonicalPropertyName(name)]++
+	}
+	for _, name := range s.derive().cardinality {
+		c := s.cardinality[name]
+		n := counts[canonicalPropertyName(name)]
+		if n < c.Min || (c.Max > 0 && n > c.Max) {
+			return validationErrf("property %q occurs %v times, but cardinality defined by the schema is %s", name, n, c)
+		}
+	}
+	return nil
 }
 
 // Creates a new schema from slice of fields and required fields
 func NewSchema(version string, fields []string, requiredFields []string) Schema {
 	fieldsSet := make(map[string]struct{})
-	reqFieldsSet := make(map[string]struct{})
+	cardinality := make(map[string]Cardinality)
 
 	for _, field := range fields {
 		fieldsSet[field] = struct{}{}
 	}
 	for _, reqField := range requiredFields {
-		reqFieldsSet[reqField] = struct{}{}
+		cardinality[reqField] = OneOrMore
 	}
-	return Schema{version, fieldsSet, reqFieldsSet}
+	return Schema{version: version, fields: fieldsSet, cardinality: cardinality, derived: &schemaDerived{}}
 }
 
 // Creates a schema for any struct. See [StringSchemaV4] as an example.
 //
 // Use tag `vCard:"required"` on a field to make [Encoder] and [Decoder] return errors
-// in case a field was not found.
-func SchemaFor[T any](version string) Schema {
+// in case a field was not found. Tag may also rename a field e.g. `vCard:"X-SKYPE,required"`.
+//
+// Options like [WithRequired] adjust the schema without changing tags of the struct e.g.
+//
+//	SchemaFor[StringSchemaV4]("4.0", WithRequired("TEL"), WithOptional("FN"))
+func SchemaFor[T any](version string, options ...SchemaOption) Schema {
+	return SchemaForTag[T](version, defaultTagKey, options...)
+}
+
+// Same as [SchemaFor], but fields are read from tags with a key tagKey e.g. `vcf:"FN,required"`.
+// Use it together with [Encoder.SetTagKey] and [Decoder.SetTagKey].
+func SchemaForTag[T any](version string, tagKey string, options ...SchemaOption) Schema {
 	typ := reflect.TypeFor[T]()
 
 	if typ.Kind() != reflect.Struct {
@@ -47,18 +447,208 @@ func SchemaFor[T any](version string) Schema {
 	}
 
 	fields := make(map[string]struct{})
-	requiredFields := make(map[string]struct{})
+	cardinality := make(map[string]Cardinality)
+	var defaults map[string]string
+	var aliases map[string][]string
 
 	for i := range typ.NumField() {
 		field := typ.Field(i)
 
-		fields[field.Name] = struct{}{}
+		opts := parseTag(field.Tag.Get(tagKey))
+		if opts.skip || opts.rest {
+			continue
+		}
+		name := field.Name
+		if opts.name != "" {
+			name = opts.name
+		}
+
+		fields[name] = struct{}{}
 
-		if strings.Contains(field.Tag.Get("vCard"), "required") {
-			requiredFields[field.Name] = struct{}{}
+		switch {
+		case opts.cardinality != nil && *opts.cardinality != ZeroOrMore:
+			cardinality[name] = *opts.cardinality
+		case opts.cardinality == nil && opts.required:
+			cardinality[name] = OneOrMore
+		}
+		if opts.def != "" {
+			if defaults == nil {
+				defaults = make(map[string]string)
+			}
+			defaults[name] = opts.def
+		}
+		if len(opts.aliases) > 0 {
+			if aliases == nil {
+				aliases = make(map[string][]string)
+			}
+			aliases[name] = opts.aliases
+		}
+	}
+	schema := Schema{
+		version:     version,
+		fields:      fields,
+		cardinality: cardinality,
+		defaults:    defaults,
+		aliases:     aliases,
+		derived:     &schemaDerived{},
+	}
+	if len(options) > 0 {
+		schema = schema.clone()
+		for _, option := range options {
+			option(&schema)
+		}
+	}
+	return schema
+}
+
+// Option of [SchemaFor].
+type SchemaOption func(*Schema)
+
+// Makes fields required regardless of tags. Fields which are not part of the struct are added
+// to the schema.
+func WithRequired(names ...string) SchemaOption {
+	return func(s *Schema) {
+		for _, name := range names {
+			s.fields[name] = struct{}{}
+			if c := s.cardinalityOf(name); c.Min == 0 {
+				s.cardinality[name] = Cardinality{Min: 1, Max: c.Max}
+			}
 		}
 	}
-	return Schema{version, fields, requiredFields}
+}
+
+// Makes fields optional regardless of tags. Cardinality of a field is relaxed
+// e.g. [ExactlyOne] becomes [ZeroOrOne].
+func WithOptional(names ...string) SchemaOption {
+	return func(s *Schema) {
+		for _, name := range names {
+			s.setCardinality(name, Cardinality{Min: 0, Max: s.cardinalityOf(name).Max})
+		}
+	}
+}
+
+// Maps idiomatic Go names to property names e.g. Email to EMAIL and OrgDirectory to ORG-DIRECTORY.
+// Acronyms are kept together e.g. ContactURI becomes CONTACT-URI.
+// See [Encoder.SetFieldNameMapper].
+func UpperKebabCase(goField string) string {
+	runes := []rune(goField)
+	b := strings.Builder{}
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prevLower := unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || (nextLower && unicode.IsUpper(runes[i-1])) {
+				b.WriteByte('-')
+			}
+		}
+		if r == '_' {
+			r = '-'
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
+
+// Key of struct tags used by default. See [Encoder.SetTagKey].
+const defaultTagKey = "vCard"
+
+// Options of a `vCard` struct tag which is a comma-separated list of a property name
+// followed by options e.g. `vCard:"TEL,type=CELL,omitempty"`. Supported options:
+//
+//   - required: [Encoder] returns an error if the field is empty, [Decoder] returns an error
+//     if the property is missing.
+//   - omitempty: [Encoder] skips the field if it has zero value.
+//   - type=CELL: [Encoder] adds TYPE parameter to the property, [Decoder] only decodes a property
+//     with that TYPE and removes it from the value.
+//   - default=individual: raw value used when the field is empty on encoding or the property
+//     is absent on decoding. See [Schema.WithDefault].
+//   - alias=X-ANNIVERSARY: alternative name of the property, may be repeated.
+//     See [Schema.WithAliases].
+//   - cardinality=*1: number of occurrences of the property allowed by a schema created with
+//     [SchemaFor] in RFC 6350 notation: "1", "*1", "1*" or "*". See [Cardinality].
+//
+// Name may be omitted e.g. `vCard:",omitempty"` to keep the name of a field.
+// Unknown options are ignored.
+//
+// Tag `vCard:"rest"` marks a catch-all field of type map[string]string or [Card] which receives
+// every property not decoded into other fields and is written back by [Encoder]. Map keys are property
+// names with groups e.g. "item1.X-ABLABEL" and values are raw rests e.g. ";TYPE=HOME:Alex".
+// Unlike a map, Card keeps repeated properties.
+//
+// Tag `vCard:"-"` excludes a field from schemas, encoding and decoding entirely.
+// Use `vCard:"-,"` for a property named "-".
+type tagOptions struct {
+	skip      bool
+	rest      bool
+	name      string
+	required  bool
+	omitEmpty bool
+	typ       string
+	def       string
+	aliases   []string
+
+	cardinality *Cardinality
+}
+
+func parseTag(tag string) tagOptions {
+	if tag == "-" {
+		return tagOptions{skip: true}
+	}
+	name, rest, _ := strings.Cut(tag, ",")
+
+	opts := tagOptions{name: strings.TrimSpace(name)}
+
+	// Single `vCard:"required"` predates options and has to keep its meaning
+	if opts.name == "required" && rest == "" {
+		return tagOptions{required: true}
+	}
+	if opts.name == "rest" && rest == "" {
+		return tagOptions{rest: true}
+	}
+
+	for opt := range strings.SplitSeq(rest, ",") {
+		k, v, _ := strings.Cut(strings.TrimSpace(opt), "=")
+		switch strings.ToLower(k) {
+		case "required":
+			opts.required = true
+		case "omitempty":
+			opts.omitEmpty = true
+		case "rest":
+			opts.rest = true
+		case "type":
+			opts.typ = v
+		case "default":
+			opts.def = v
+		case "alias":
+			opts.aliases = append(opts.aliases, v)
+		case "cardinality":
+			if c, ok := parseCardinality(v); ok {
+				opts.cardinality = &c
+			}
+		}
+	}
+	return opts
+}
+
+// Parses a tag of a fallback key like `json:"email,omitempty"`. Name is converted by [UpperKebabCase]
+// and options other than omitempty and omitzero are ignored, since they belong to another format.
+func parseFallbackTag(tag string) tagOptions {
+	if tag == "-" {
+		return tagOptions{skip: true}
+	}
+	name, rest, _ := strings.Cut(tag, ",")
+
+	opts := tagOptions{}
+	if name = strings.TrimSpace(name); name != "" {
+		opts.name = UpperKebabCase(name)
+	}
+	for opt := range strings.SplitSeq(rest, ",") {
+		switch strings.TrimSpace(opt) {
+		case "omitempty", "omitzero":
+			opts.omitEmpty = true
+		}
+	}
+	return opts
 }
 
 // Simple vCard 4.0 schema
@@ -77,6 +667,63 @@ var DefaultSchemas = []Schema{
 	SchemaV2_1,
 }
 
+var (
+	registryMu sync.RWMutex
+	registry   = newRegistry(DefaultSchemas)
+)
+
+func newRegistry(schemas []Schema) map[string]Schema {
+	r := make(map[string]Schema, len(schemas))
+	for _, s := range schemas {
+		r[s.version] = s
+	}
+	return r
+}
+
+// Registers schema globally replacing previously registered schema of the same version.
+//
+// Registered schemas are used by [Unmarshal] and schema for version 4.0 is used by [Marshal]
+// and [Encoder.Encode], so custom schemas don't have to be passed to every call.
+// Initially registry contains [DefaultSchemas]. Usually called from init functions.
+//
+// Safe for concurrent use.
+func RegisterSchema(schema Schema) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	registry[schema.version] = schema
+}
+
+// Returns globally registered schema for a version. See [RegisterSchema].
+func SchemaForVersion(version string) (Schema, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	s, found := registry[version]
+	return s, found
+}
+
+// Returns every registered schema ordered by version.
+func registeredSchemas() []Schema {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	schemas := make([]Schema, 0, len(registry))
+	for _, version := range slices.Sorted(maps.Keys(registry)) {
+		schemas = append(schemas, registry[version])
+	}
+	return schemas
+}
+
+// Returns registered schema for version 4.0 used by default by [Encoder].
+func defaultSchema() Schema {
+	s, found := SchemaForVersion("4.0")
+	if !found {
+		return SchemaV4
+	}
+	return s
+}
+
 // Simple vCard v4.0 schema implementation from https://en.wikipedia.org/wiki/VCard
 //
 // Note that this struct can be safely used as argument in vCard.Unmarshal without
@@ -86,8 +733,8 @@ var DefaultSchemas = []Schema{
 type StringSchemaV4 struct {
 	ADR         string // A structured representation of the delivery address for the person.
 	AGENT       string // Information about another person who will act on behalf of this one.
-	ANNIVERSARY string // Defines the person's anniversary.
-	BDAY        string // Date of birth of the individual.
+	ANNIVERSARY string `vCard:",cardinality=*1,alias=X-ANNIVERSARY"` // Defines the person's anniversary.
+	BDAY        string `vCard:",cardinality=*1"`                     // Date of birth of the individual.
 
 	// BEGIN:VCARD - All vCards must start with this property.
 
@@ -101,12 +748,12 @@ type StringSchemaV4 struct {
 	// END:VCARD - All vCards must end with this property.
 
 	FBURL  string // Defines a URL that shows when the person is "free" or "busy" on their calendar.
-	FN     string `vCard:"required"` // The formatted name string.
-	GENDER string // Defines the person's gender.
+	FN     string `vCard:"required"`                       // The formatted name string.
+	GENDER string `vCard:",cardinality=*1,alias=X-GENDER"` // Defines the person's gender.
 	GEO    string // Specifies a latitude and longitude.
 	IMPP   string // Defines an instant messenger handle.
 	KEY    string // The public encryption key associated with the person.
-	KIND   string // Defines the type of entity that this vCard represents.
+	KIND   string `vCard:",cardinality=*1,alias=X-ADDRESSBOOKSERVER-KIND"` // Defines the type of entity that this vCard represents.
 
 	// Represents the actual text that should be put on the mailing label. Not supported in version 4.0.
 	// Instead, this information is stored in the LABEL parameter of the ADR property.
@@ -115,8 +762,8 @@ type StringSchemaV4 struct {
 	LANG   string // Defines a language that the person speaks.
 	LOGO   string // An image or graphic of the logo of the organization that is associated with the individual.
 	MAILER string // Type of email program used.
-	MEMBER string // Defines a member that is part of the group that this vCard represents.
-	N      string // A structured representation of the name of the person
+	MEMBER string `vCard:",alias=X-ADDRESSBOOKSERVER-MEMBER"` // Defines a member that is part of the group that this vCard represents.
+	N      string `vCard:",cardinality=*1"`                   // A structured representation of the name of the person
 
 	// Provides a textual representation of the SOURCE property. Not to be confused with N property
 	// which defines person's name.
@@ -127,11 +774,11 @@ type StringSchemaV4 struct {
 	ORG      string // The name and optionally the unit(s) of the organization associated with the person.
 
 	PHOTO   string // An image of the individual. It may point to an external URL or may be embedded as a Base64.
-	PRODID  string // The identifier for the product that created the vCard object.
+	PRODID  string `vCard:",cardinality=*1"` // The identifier for the product that created the vCard object.
 	PROFILE string // States that the vCard is a vCard.
 
 	RELATED string // Another entity that the person is related to.
-	REV     string // A timestamp for the last time the vCard was updated.
+	REV     string `vCard:",cardinality=*1"` // A timestamp for the last time the vCard was updated.
 	ROLE    string // The role, occupation, or business category of the person within an organization.
 
 	// Defines a string that should be used when an application sorts this vCard in some way.
@@ -144,12 +791,29 @@ type StringSchemaV4 struct {
 
 	TITLE string // Specifies the job title, functional position or function of the individual.
 	TZ    string // The time zone of the person.
-	UID   string // Specifies a persistent, globally unique identifier associated with the person.
+	UID   string `vCard:",cardinality=*1"` // Specifies a persistent, globally unique identifier associated with the person.
 	URL   string // A URL pointing to a website that represents the person in some way.
 
 	VERSION string // The version of the vCard specification.
 
 	XML string // Any XML data that is attached to the vCard.
+
+	// Properties registered by https://datatracker.ietf.org/doc/html/rfc6474
+
+	BIRTHPLACE string `vCard:",cardinality=*1"` // The place of birth of the individual.
+	DEATHPLACE string `vCard:",cardinality=*1"` // The place of death of the individual.
+	DEATHDATE  string `vCard:",cardinality=*1"` // The date of death of the individual.
+
+	// Properties registered by https://datatracker.ietf.org/doc/html/rfc6715
+
+	EXPERTISE     string // A professional subject area the person has knowledge of.
+	HOBBY         string // A recreational activity the person actively engages in.
+	INTEREST      string // A recreational activity the person is interested in.
+	ORG_DIRECTORY string `vCard:"ORG-DIRECTORY"` // A URI of a directory of an organization the person belongs to.
+
+	// Property registered by https://datatracker.ietf.org/doc/html/rfc8605
+
+	CONTACT_URI string `vCard:"CONTACT-URI"` // A URI to contact the person instead of email or phone e.g. a web form.
 }
 
 // Simple vCard v3.0 schema implementation from https://en.wikipedia.org/wiki/VCard
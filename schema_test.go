@@ -1,6 +1,9 @@
 package vcard
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+)
 
 type TestImplementation struct {
 	N    string
@@ -19,13 +22,274 @@ func TestSchemaToMap(t *testing.T) {
 			"NAME": {},
 			"FN":   {},
 		},
-		requiredFields: map[string]struct{}{
-			"NAME": {},
-			"FN":   {},
+		cardinality: map[string]Cardinality{
+			"NAME": OneOrMore,
+			"FN":   OneOrMore,
 		},
 	}
 
 	assertStringsEq(t, schema.version, exp.version)
 	assertMapsEq(t, schema.fields, exp.fields)
-	assertMapsEq(t, schema.requiredFields, exp.requiredFields)
+	assertMapsEq(t, schema.cardinality, exp.cardinality)
+}
+
+func TestParseTag(t *testing.T) {
+
+	assertDeepEq(t, parseTag(""), tagOptions{})
+	assertDeepEq(t, parseTag("required"), tagOptions{required: true})
+	assertDeepEq(t, parseTag("FN,required"), tagOptions{name: "FN", required: true})
+	assertDeepEq(t, parseTag(",omitempty"), tagOptions{omitEmpty: true})
+	assertDeepEq(t, parseTag("TEL,type=CELL,omitempty,unknown"), tagOptions{name: "TEL", omitEmpty: true, typ: "CELL"})
+}
+
+type RenamedSchema struct {
+	Name string `vCard:"FN,required"`
+	Note string `vCard:"NOTE"`
+}
+
+func TestSchemaForTagOptions(t *testing.T) {
+
+	schema := SchemaFor[RenamedSchema]("4.0")
+
+	assertMapsEq(t, schema.fields, map[string]struct{}{"FN": {}, "NOTE": {}})
+	assertSlicesEq(t, schema.Required(), []string{"FN"})
+}
+
+type SkippedFieldSchema struct {
+	FN string
+	ID string `vCard:"-"`
+}
+
+func TestSchemaForSkipTag(t *testing.T) {
+
+	assertDeepEq(t, parseTag("-"), tagOptions{skip: true})
+	assertDeepEq(t, parseTag("-,"), tagOptions{name: "-"})
+
+	schema := SchemaFor[SkippedFieldSchema]("4.0")
+
+	assertMapsEq(t, schema.fields, map[string]struct{}{"FN": {}})
+}
+
+type CardinalitySchema struct {
+	FN   string `vCard:",cardinality=1"`
+	KIND string `vCard:",cardinality=*1"`
+	TEL  string `vCard:",cardinality=1*"`
+	NOTE string
+}
+
+func TestSchemaForCardinality(t *testing.T) {
+
+	schema := SchemaFor[CardinalitySchema]("4.0")
+
+	assertSlicesEq(t, schema.Required(), []string{"FN", "TEL"})
+	assertEq(t, schema.cardinalityOf("FN"), ExactlyOne)
+	assertEq(t, schema.cardinalityOf("KIND"), ZeroOrOne)
+	assertEq(t, schema.cardinalityOf("TEL"), OneOrMore)
+	assertEq(t, schema.cardinalityOf("NOTE"), ZeroOrMore)
+}
+
+func TestWithCardinality(t *testing.T) {
+
+	schema := SchemaV4.WithCardinality("FN", ExactlyOne).WithCardinality("X-DEPT", ZeroOrOne)
+
+	assertEq(t, schema.cardinalityOf("FN"), ExactlyOne)
+	assertEq(t, schema.cardinalityOf("X-DEPT"), ZeroOrOne)
+	assertEq(t, SchemaV4.cardinalityOf("FN"), OneOrMore)
+
+	_, found := SchemaV4.fields["X-DEPT"]
+	assertEq(t, found, false)
+
+	// Required fields are the ones with Min greater than zero
+	relaxed := schema.WithCardinality("FN", ZeroOrOne).WithCardinality("X-DEPT", Cardinality{2, 3})
+	assertEq(t, relaxed.IsRequired("FN"), false)
+	assertSlicesEq(t, relaxed.Required(), []string{"X-DEPT"})
+}
+
+func TestCardinalityString(t *testing.T) {
+
+	assertStringsEq(t, ZeroOrOne.String(), "*1")
+	assertStringsEq(t, Cardinality{2, 5}.String(), "2..5")
+	assertStringsEq(t, Cardinality{2, 0}.String(), "2..")
+}
+
+var emailParamsSchema = SchemaV4.WithParams("EMAIL",
+	ParamDef{Name: "TYPE", Values: []string{"HOME", "WORK"}},
+	ParamDef{Name: "PREF"},
+)
+
+func TestCheckParams(t *testing.T) {
+
+	assertEq(t, emailParamsSchema.checkParams("EMAIL", ";TYPE=home,WORK;PREF=1"), nil)
+	assertEq(t, emailParamsSchema.checkParams("EMAIL", ";TYPE=x-custom"), nil)
+	assertEq(t, emailParamsSchema.checkParams("EMAIL", ";LABEL=anything"), nil)
+	assertEq(t, emailParamsSchema.checkParams("TEL", ";TYPE=BANANA"), nil)
+
+	err := emailParamsSchema.checkParams("EMAIL", ";TYPE=HOME,BANANA")
+	assertErrIs(t, err, ErrValidation, `value "BANANA" which is not accepted`)
+
+	err = emailParamsSchema.checkParams("item1.EMAIL", ";BANANA")
+	assertErrIs(t, err, ErrValidation, `value "BANANA" which is not accepted`)
+}
+
+func TestStrictParams(t *testing.T) {
+
+	s := emailParamsSchema.WithStrictParams(true)
+
+	assertEq(t, s.checkParams("EMAIL", ";TYPE=home;PREF=1;X-CUSTOM=1"), nil)
+	assertEq(t, s.checkParams("TEL", ""), nil)
+
+	err := s.checkParams("EMAIL", ";LABEL=anything")
+	assertErrIs(t, err, ErrValidation, `parameter LABEL of property "EMAIL" is not defined`)
+
+	err = s.checkParams("TEL", ";TYPE=CELL")
+	assertErrIs(t, err, ErrValidation, `parameter TYPE of property "TEL" is not defined`)
+
+	// Flag is kept by copies and can be turned off
+	assertEq(t, s.WithDefault("KIND", "individual").strictParams, true)
+	assertEq(t, s.WithStrictParams(false).checkParams("EMAIL", ";LABEL=anything"), nil)
+
+	_, err = MarshalSchema(map[string]string{"FN": "Alex", "TEL": ";TYPE=CELL:555"}, s)
+	assertErrIs(t, err, ErrValidation, `parameter TYPE of property "TEL"`)
+}
+
+func TestRegisterSchema(t *testing.T) {
+
+	custom := NewSchema("4.0-custom", []string{"FN", "X-DEPT"}, []string{"X-DEPT"})
+	RegisterSchema(custom)
+	t.Cleanup(func() {
+		registryMu.Lock()
+		delete(registry, "4.0-custom")
+		registryMu.Unlock()
+	})
+
+	s, found := SchemaForVersion("4.0-custom")
+	assertEq(t, found, true)
+	assertSlicesEq(t, s.Required(), []string{"X-DEPT"})
+
+	text := `BEGIN:VCARD
+VERSION:4.0-custom
+FN:Alex
+X-DEPT:Sales
+END:VCARD
+`
+	m := map[string]string{}
+	err := Unmarshal([]byte(crlfy(text)), &m)
+
+	assertEq(t, err, nil)
+	assertMapsEq(t, m, map[string]string{"FN": ":Alex", "X-DEPT": ":Sales"})
+
+	_, found = SchemaForVersion("5.0")
+	assertEq(t, found, false)
+}
+
+func TestSchemaBuilder(t *testing.T) {
+
+	b := NewSchemaBuilder("4.0").
+		Field("FN", Required).
+		Field("X-DEPT", Optional).
+		Field("KIND", ZeroOrOne).
+		Params("TEL", ParamDef{Name: "TYPE", Values: []string{"CELL"}})
+
+	schema := b.Build()
+	b.Field("NOTE", Required)
+
+	assertEq(t, schema.version, "4.0")
+	assertMapsEq(t, schema.fields, map[string]struct{}{"FN": {}, "X-DEPT": {}, "KIND": {}, "TEL": {}})
+	assertSlicesEq(t, schema.Required(), []string{"FN"})
+	assertEq(t, schema.cardinalityOf("KIND"), ZeroOrOne)
+
+	m := map[string]string{"FN": "Alex", "X-DEPT": "Sales", "NOTE": "skipped"}
+	out, err := MarshalSchema(m, schema)
+
+	assertEq(t, err, nil)
+	assertStringLinesEq(t, string(out), "BEGIN:VCARD\r\nVERSION:4.0\r\nFN:Alex\r\nX-DEPT:Sales\r\nEND:VCARD\r\n")
+}
+
+type DefaultTagSchema struct {
+	FN   string `vCard:"required"`
+	KIND string `vCard:",default=individual"`
+}
+
+func TestSchemaForDefaults(t *testing.T) {
+
+	assertDeepEq(t, parseTag("KIND,default=org"), tagOptions{name: "KIND", def: "org"})
+
+	schema := SchemaFor[DefaultTagSchema]("4.0")
+	assertMapsEq(t, schema.defaults, map[string]string{"KIND": "individual"})
+}
+
+func TestParseTagAliases(t *testing.T) {
+
+	assertDeepEq(t, parseTag("ANNIVERSARY,alias=X-ANNIVERSARY,alias=X-ABDATE"), tagOptions{
+		name:    "ANNIVERSARY",
+		aliases: []string{"X-ANNIVERSARY", "X-ABDATE"},
+	})
+}
+
+func TestSchemaIntrospection(t *testing.T) {
+
+	s := NewSchemaBuilder("4.0").
+		Field("FN", ExactlyOne).
+		Field("TEL", ZeroOrMore).
+		Params("TEL", ParamDef{Name: "TYPE", Values: []string{"home", "work"}}).
+		Build().
+		WithDefault("KIND", "individual").
+		WithAliases("TEL", "X-PHONE")
+
+	assertEq(t, s.Version(), "4.0")
+	assertSlicesEq(t, s.Fields(), []string{"FN", "KIND", "TEL"})
+	assertSlicesEq(t, s.Required(), []string{"FN"})
+	assertEq(t, s.HasField("TEL"), true)
+	assertEq(t, s.HasField("EMAIL"), false)
+	assertEq(t, s.IsRequired("FN"), true)
+	assertEq(t, s.IsRequired("TEL"), false)
+	assertEq(t, s.Cardinality("FN"), ExactlyOne)
+	assertEq(t, s.Cardinality("TEL"), ZeroOrMore)
+	assertEq(t, len(s.Params("TEL")), 1)
+	assertEq(t, s.Params("FN") == nil, true)
+	assertSlicesEq(t, s.Aliases("TEL"), []string{"X-PHONE"})
+
+	def, found := s.Default("KIND")
+	assertEq(t, found, true)
+	assertEq(t, def, "individual")
+}
+
+func TestPreparedSchemaIntrospection(t *testing.T) {
+
+	p := SchemaV4.Prepare(reflect.TypeFor[PreparedStruct]())
+
+	assertSlicesEq(t, p.Fields(), []string{"FN", "TEL"})
+	assertSlicesEq(t, p.Required(), []string{"FN"})
+
+	typ, found := p.FieldType("TEL")
+	assertEq(t, found, true)
+	assertEq(t, typ, reflect.TypeFor[string]())
+
+	_, found = p.FieldType("EMAIL")
+	assertEq(t, found, false)
+}
+
+type RequiredOptionsStruct struct {
+	FN   string `vCard:"required"`
+	TEL  string
+	UID  string `vCard:",cardinality=*1"`
+	NOTE string `vCard:",cardinality=1"`
+}
+
+func TestSchemaForOptions(t *testing.T) {
+
+	s := SchemaFor[RequiredOptionsStruct]("4.0")
+
+	assertSlicesEq(t, s.Required(), []string{"FN", "NOTE"})
+
+	s = SchemaFor[RequiredOptionsStruct]("4.0", WithRequired("TEL", "UID", "EMAIL"), WithOptional("FN", "NOTE"))
+
+	assertSlicesEq(t, s.Required(), []string{"EMAIL", "TEL", "UID"})
+	assertEq(t, s.HasField("EMAIL"), true)
+	assertEq(t, s.Cardinality("UID"), ExactlyOne)
+	assertEq(t, s.Cardinality("NOTE"), ZeroOrOne)
+	assertEq(t, s.Cardinality("FN"), ZeroOrMore)
+
+	_, err := MarshalSchema(map[string]string{"FN": "Alex", "TEL": "555", "UID": "1"}, s)
+	assertErrIs(t, err, ErrVCard, `"EMAIL"`)
 }
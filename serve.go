@@ -0,0 +1,155 @@
+package vcard
+
+import (
+	"iter"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Returns media type of vCard with charset and version parameters as per
+// https://datatracker.ietf.org/doc/html/rfc6350#section-10.1 e.g. "text/vcard; charset=utf-8; version=4.0".
+// Version parameter is omitted if version is empty.
+func ContentType(version string) string {
+	if version == "" {
+		return "text/vcard; charset=utf-8"
+	}
+	return "text/vcard; charset=utf-8; version=" + version
+}
+
+// Returns name of a .vcf file for the card made of [Card.DisplayName] e.g. "Alex Smith.vcf".
+// Characters not allowed in file names on common systems are replaced with '_' and long names
+// are truncated. Returns "contact.vcf" if the card has no name.
+func FilenameOf(c *Card) string {
+	name := strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || strings.ContainsRune(`/\:*?"<>|`, r) {
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(c.DisplayName()))
+
+	const maxLen = 100
+	for len(name) > maxLen {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+	name = strings.Trim(name, ". ")
+	if name == "" {
+		return "contact.vcf"
+	}
+	return name + ".vcf"
+}
+
+// Returns Content-Disposition header value of a download e.g. `attachment; filename="Alex Smith.vcf"`.
+// Non-ASCII names are written with ASCII fallback and filename* parameter of
+// https://datatracker.ietf.org/doc/html/rfc6266 e.g.
+//
+//	attachment; filename="J_rg.vcf"; filename*=UTF-8''J%C3%B6rg.vcf
+func ContentDisposition(filename string) string {
+	fallback := strings.Map(func(r rune) rune {
+		if r < 0x20 || r >= utf8.RuneSelf || r == '"' || r == '\\' || r == 0x7f {
+			return '_'
+		}
+		return r
+	}, filename)
+	header := `attachment; filename="` + fallback + `"`
+	if fallback != filename {
+		header += "; filename*=UTF-8''" + encodeExtValue(filename)
+	}
+	return header
+}
+
+// Percent-encodes bytes other than attr-char of https://datatracker.ietf.org/doc/html/rfc5987#section-3.2.1
+func encodeExtValue(s string) string {
+	const hex = "0123456789ABCDEF"
+
+	b := strings.Builder{}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || strings.IndexByte("!#$&+-.^_`|~", c) >= 0 {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteByte('%')
+		b.WriteByte(hex[c>>4])
+		b.WriteByte(hex[c&0xF])
+	}
+	return b.String()
+}
+
+// Writes the card as a .vcf download named by [FilenameOf] with ETag of [CardETag].
+// Responds with 304 if If-None-Match matches the ETag. Body is not written for HEAD requests.
+func ServeCard(w http.ResponseWriter, r *http.Request, card Card) {
+	etag := CardETag(card)
+	w.Header().Set("ETag", etag)
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	b, err := Marshal(card)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", ContentType(card.Version()))
+	w.Header().Set("Content-Disposition", ContentDisposition(FilenameOf(&card)))
+	w.Header().Set("Content-Length", strconv.Itoa(len(b)))
+	if r.Method != http.MethodHead {
+		_, _ = w.Write(b)
+	}
+}
+
+// Returns [http.Handler] streaming cards produced by cards for every request as a single .vcf
+// download named filename e.g. "contacts.vcf":
+//
+//	http.Handle("/export.vcf", vcard.ServeCards("contacts.vcf", func(r *http.Request) iter.Seq[vcard.Card] {
+//		return slices.Values(addressBook)
+//	}))
+//
+// Cards are encoded one by one as they are produced, so the whole export is never held in memory.
+// Version parameter of Content-Type is taken from the first card. If a card fails to encode after
+// the response has started, the connection is aborted so clients don't take a truncated file as complete.
+func ServeCards(filename string, cards func(r *http.Request) iter.Seq[Card]) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		next, stop := iter.Pull(cards(r))
+		defer stop()
+
+		card, ok := next()
+		first, version := []byte{}, ""
+		if ok {
+			b, err := Marshal(card)
+			if err != nil {
+				http.Error(w, vCardErrf("error during marshaling card 0: %w", err).Error(), http.StatusInternalServerError)
+				return
+			}
+			first, version = b, card.Version()
+		}
+
+		w.Header().Set("Content-Type", ContentType(version))
+		w.Header().Set("Content-Disposition", ContentDisposition(filename))
+		if r.Method == http.MethodHead {
+			return
+		}
+		if _, err := w.Write(first); err != nil {
+			return
+		}
+
+		for card, ok := next(); ok; card, ok = next() {
+			b, err := Marshal(card)
+			if err != nil {
+				panic(http.ErrAbortHandler)
+			}
+			if _, err := w.Write(b); err != nil {
+				return
+			}
+		}
+	})
+}
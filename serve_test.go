@@ -0,0 +1,88 @@
+package vcard
+
+import (
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestContentType(t *testing.T) {
+	assertEq(t, ContentType("4.0"), "text/vcard; charset=utf-8; version=4.0")
+	assertEq(t, ContentType(""), "text/vcard; charset=utf-8")
+}
+
+func TestFilenameOf(t *testing.T) {
+
+	c := Card{}
+	assertEq(t, FilenameOf(&c), "contact.vcf")
+
+	c.Add("FN", "Alex Smith")
+	assertEq(t, FilenameOf(&c), "Alex Smith.vcf")
+
+	c.Set("FN", `AC/DC: "Live"?`)
+	assertEq(t, FilenameOf(&c), "AC_DC_ _Live__.vcf")
+
+	c.Set("FN", "...")
+	assertEq(t, FilenameOf(&c), "contact.vcf")
+
+	c.Set("FN", strings.Repeat("ö", 60))
+	assertEq(t, FilenameOf(&c), strings.Repeat("ö", 50)+".vcf")
+}
+
+func TestContentDisposition(t *testing.T) {
+	assertEq(t, ContentDisposition("Alex Smith.vcf"), `attachment; filename="Alex Smith.vcf"`)
+	assertEq(t, ContentDisposition("Jörg+1 (1).vcf"), `attachment; filename="J_rg+1 (1).vcf"; filename*=UTF-8''J%C3%B6rg+1%20%281%29.vcf`)
+}
+
+func TestServeCard(t *testing.T) {
+
+	card := newStoreCard("1", "Alex Smith")
+
+	w := httptest.NewRecorder()
+	ServeCard(w, httptest.NewRequest("GET", "/alex.vcf", nil), card)
+	assertEq(t, w.Code, http.StatusOK)
+	assertEq(t, w.Header().Get("Content-Type"), "text/vcard; charset=utf-8; version=4.0")
+	assertEq(t, w.Header().Get("Content-Disposition"), `attachment; filename="Alex Smith.vcf"`)
+	assertEq(t, w.Header().Get("ETag"), CardETag(card))
+	assertStringLinesEq(t, w.Body.String(), "BEGIN:VCARD\r\nVERSION:4.0\r\nUID:1\r\nFN:Alex Smith\r\nEND:VCARD\r\n")
+
+	r := httptest.NewRequest("GET", "/alex.vcf", nil)
+	r.Header.Set("If-None-Match", CardETag(card))
+	w = httptest.NewRecorder()
+	ServeCard(w, r, card)
+	assertEq(t, w.Code, http.StatusNotModified)
+	assertEq(t, w.Body.Len(), 0)
+}
+
+func TestServeCards(t *testing.T) {
+
+	cards := []Card{newStoreCard("1", "Alex"), newStoreCard("2", "Sam")}
+	h := ServeCards("contacts.vcf", func(r *http.Request) iter.Seq[Card] { return slices.Values(cards) })
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/contacts.vcf", nil))
+	assertEq(t, w.Code, http.StatusOK)
+	assertEq(t, w.Header().Get("Content-Type"), "text/vcard; charset=utf-8; version=4.0")
+	assertEq(t, w.Header().Get("Content-Disposition"), `attachment; filename="contacts.vcf"`)
+	assertEq(t, strings.Count(w.Body.String(), "BEGIN:VCARD"), 2)
+	assertEq(t, strings.Contains(w.Body.String(), "FN:Sam"), true)
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("HEAD", "/contacts.vcf", nil))
+	assertEq(t, w.Code, http.StatusOK)
+	assertEq(t, w.Body.Len(), 0)
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "/contacts.vcf", nil))
+	assertEq(t, w.Code, http.StatusMethodNotAllowed)
+
+	cards = nil
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/contacts.vcf", nil))
+	assertEq(t, w.Code, http.StatusOK)
+	assertEq(t, w.Header().Get("Content-Type"), "text/vcard; charset=utf-8")
+	assertEq(t, w.Body.Len(), 0)
+}
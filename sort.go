@@ -0,0 +1,116 @@
+package vcard
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Options of [SortCards].
+type SortOptions struct {
+	// Sorts names by given names e.g. "John Doe" as "John Doe" instead of "Doe John".
+	// SORT-AS parameter is honored regardless of this option.
+	GivenNameFirst bool
+
+	// Compares sort keys. Nil means case-insensitive comparison of letters with diacritics folded
+	// e.g. "Émile" sorts as "Emile". Locale-specific collation can be plugged in e.g.
+	// collate.New(language.Swedish).CompareString of golang.org/x/text/collate.
+	Collator func(a, b string) int
+
+	// Reverses the order.
+	Descending bool
+}
+
+// Sorts cards in place the way address books display them: by SORT-AS of N or ORG, then
+// by N, ORG and FN, see [Card.SortKey]. Cards which keys don't start with a letter go after
+// ones which do, like the "#" section of phone contacts, and cards without a name go last.
+// Sorting is stable.
+func SortCards(cards []Card, opts SortOptions) {
+	collate := opts.Collator
+	if collate == nil {
+		collate = foldedCompare
+	}
+
+	keys := make([]string, len(cards))
+	for i := range cards {
+		keys[i] = sortKey(&cards[i], opts.GivenNameFirst)
+	}
+	order := make([]int, len(cards))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		ka, kb := keys[a], keys[b]
+		// Sections are not reversed by Descending, so cards without a name always go last
+		if sa, sb := sortSection(ka), sortSection(kb); sa != sb {
+			return sa - sb
+		}
+		c := collate(ka, kb)
+		if opts.Descending {
+			return -c
+		}
+		return c
+	})
+
+	sorted := make([]Card, len(cards))
+	for i, j := range order {
+		sorted[i] = cards[j]
+	}
+	copy(cards, sorted)
+}
+
+func sortKey(c *Card, givenNameFirst bool) string {
+	if givenNameFirst {
+		if n, found := c.Name(); found && len(n.SortAs) == 0 {
+			if key := strings.TrimSpace(strings.Join(append(n.GivenNames, n.FamilyNames...), " ")); key != "" {
+				return key
+			}
+		}
+	}
+	return strings.TrimSpace(c.SortKey())
+}
+
+// Returns 0 for keys starting with a letter, 1 for other keys and 2 for empty keys.
+func sortSection(key string) int {
+	for _, r := range key {
+		if unicode.IsLetter(r) {
+			return 0
+		}
+		return 1
+	}
+	return 2
+}
+
+// Compares strings case-insensitively with diacritics of Latin letters folded.
+// Equal strings are ordered by their original form, so the order is total.
+func foldedCompare(a, b string) int {
+	if c := strings.Compare(foldKey(a), foldKey(b)); c != 0 {
+		return c
+	}
+	return strings.Compare(a, b)
+}
+
+func foldKey(s string) string {
+	return strings.Map(func(r rune) rune {
+		r = unicode.ToLower(r)
+		if folded, found := latinFolds[r]; found {
+			return folded
+		}
+		return r
+	}, s)
+}
+
+// Lower-case Latin letters with diacritics mapped to their base letters.
+var latinFolds = func() map[rune]rune {
+	folds := map[rune]rune{}
+	for base, letters := range map[rune]string{
+		'a': "àáâãäåāăą", 'c': "çćĉċč", 'd': "ďđ", 'e': "èéêëēĕėęě", 'g': "ĝğġģ", 'h': "ĥħ",
+		'i': "ìíîïĩīĭįı", 'j': "ĵ", 'k': "ķ", 'l': "ĺļľŀł", 'n': "ñńņňŉ", 'o': "òóôõöøōŏő",
+		'r': "ŕŗř", 's': "śŝşšß", 't': "ţťŧ", 'u': "ùúûüũūŭůűų", 'w': "ŵ", 'y': "ýÿŷ", 'z': "źżž",
+	} {
+		for _, r := range letters {
+			folds[r] = base
+		}
+	}
+	return folds
+}()
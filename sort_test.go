@@ -0,0 +1,44 @@
+package vcard
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSortCards(t *testing.T) {
+
+	card := func(props ...string) Card {
+		c := Card{}
+		for i := 0; i < len(props); i += 2 {
+			c.Add(props[i], props[i+1])
+		}
+		return c
+	}
+	fns := func(cards []Card) []string {
+		names := []string{}
+		for _, c := range cards {
+			fn, _ := c.FN()
+			names = append(names, fn)
+		}
+		return names
+	}
+	cards := []Card{
+		card("FN", "123 Pizza"),
+		card("FN", "Zoe Adams", "N", "Adams;Zoe;;;"),
+		card(),
+		card("FN", "Émile Zola", "N", "Zola;Émile;;;"),
+		card("FN", "The Beatles"),
+		card("FN", "acme"),
+		card("FN", "Bob Young", "N", "Young;Bob;;;"),
+	}
+	cards[4].AddProperty(Property{Name: "N", Params: Params{{"SORT-AS", []string{"Beatles"}}}, Value: ";;;;"})
+
+	SortCards(cards, SortOptions{})
+	assertSlicesEq(t, fns(cards), []string{"acme", "Zoe Adams", "The Beatles", "Bob Young", "Émile Zola", "123 Pizza", ""})
+
+	SortCards(cards, SortOptions{GivenNameFirst: true})
+	assertSlicesEq(t, fns(cards), []string{"acme", "The Beatles", "Bob Young", "Émile Zola", "Zoe Adams", "123 Pizza", ""})
+
+	SortCards(cards, SortOptions{Descending: true, Collator: strings.Compare})
+	assertSlicesEq(t, fns(cards), []string{"acme", "Émile Zola", "Bob Young", "The Beatles", "Zoe Adams", "123 Pizza", ""})
+}
@@ -0,0 +1,67 @@
+package vcard
+
+import (
+	"context"
+	"net/http"
+)
+
+// Value of SOUND property as per https://datatracker.ietf.org/doc/html/rfc6350#section-6.7.5
+// e.g. a pronunciation of the name.
+//
+// Sound either references a clip by URI or embeds it. See [Photo] for encodings used by
+// different vCard versions. TYPE=WAVE of older versions is decoded as audio/wav.
+type Sound struct {
+	// External URI e.g. "http://example.com/name.ogg". Empty if the clip is embedded.
+	URI string
+
+	// Embedded clip. Nil if the clip is referenced by URI.
+	Data []byte
+
+	// Media type e.g. "audio/ogg".
+	MediaType string
+}
+
+// Reports whether the clip is embedded into a vCard.
+func (s Sound) IsEmbedded() bool {
+	return s.Data != nil
+}
+
+// Returns a data: URI of an embedded clip or URI of a referenced one.
+func (s Sound) DataURI() string {
+	return s.media().dataURI()
+}
+
+// Downloads a clip referenced by URI with client and embeds it. See [Photo.Fetch].
+func (s *Sound) Fetch(ctx context.Context, client *http.Client) error {
+	m := s.media()
+	err := m.fetch(ctx, client, 0)
+	if err != nil {
+		return err
+	}
+	*s = Sound{m.uri, m.data, m.mediaType}
+	return nil
+}
+
+func (s Sound) media() media {
+	return media{s.URI, s.Data, s.MediaType}
+}
+
+// Encodes the clip as vCard 4.0 data: URI or URI.
+func (s Sound) MarshalVCardField() ([]byte, error) {
+	return s.MarshalVCardFieldVersion("4.0")
+}
+
+// Encodes the clip in a form defined by the vCard version. Empty Sound is omitted.
+func (s Sound) MarshalVCardFieldVersion(version string) ([]byte, error) {
+	return s.media().marshal(version), nil
+}
+
+// Decodes the clip from a URI, a data: URI or a base64 value with ENCODING parameter.
+func (s *Sound) UnmarshalVCardField(data []byte) error {
+	m, err := unmarshalMedia(data, "audio")
+	if err != nil {
+		return err
+	}
+	*s = Sound{m.uri, m.data, m.mediaType}
+	return nil
+}
@@ -0,0 +1,51 @@
+package vcard
+
+import "testing"
+
+type SoundStruct struct {
+	FN    string
+	N     string
+	SOUND Sound
+}
+
+func TestSoundRoundTrip(t *testing.T) {
+
+	clip := []byte("RIFF")
+
+	text := `BEGIN:VCARD
+VERSION:3.0
+FN:Alex
+N:;Alex;;;
+SOUND;TYPE=WAVE;ENCODING=b:UklGRg==
+END:VCARD
+`
+	s := SoundStruct{}
+	err := Unmarshal([]byte(crlfy(text)), &s)
+
+	assertEq(t, err, nil)
+	assertDeepEq(t, s.SOUND, Sound{Data: clip, MediaType: "audio/wav"})
+	assertEq(t, s.SOUND.IsEmbedded(), true)
+	assertStringsEq(t, s.SOUND.DataURI(), "data:audio/wav;base64,UklGRg==")
+
+	b, err := MarshalSchema(s, SchemaV3)
+
+	assertEq(t, err, nil)
+	assertStringLinesEq(t, string(b), crlfy("BEGIN:VCARD\nVERSION:3.0\nFN:Alex\nN:;Alex;;;\nSOUND;ENCODING=b;TYPE=WAVE:UklGRg==\nEND:VCARD\n"))
+
+	b, err = Marshal(s)
+
+	assertEq(t, err, nil)
+	assertStringLinesEq(t, string(b), crlfy("BEGIN:VCARD\nVERSION:4.0\nFN:Alex\nN:;Alex;;;\nSOUND:data:audio/wav;base64,UklGRg==\nEND:VCARD\n"))
+
+	s = SoundStruct{}
+	err = Unmarshal(b, &s)
+
+	assertEq(t, err, nil)
+	assertDeepEq(t, s.SOUND, Sound{Data: clip, MediaType: "audio/wav"})
+
+	snd := Sound{}
+	err = snd.UnmarshalVCardField([]byte(";MEDIATYPE=audio/ogg:http://example.com/name.ogg"))
+
+	assertEq(t, err, nil)
+	assertDeepEq(t, snd, Sound{URI: "http://example.com/name.ogg", MediaType: "audio/ogg"})
+}
@@ -0,0 +1,102 @@
+package vcard
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"iter"
+	"strings"
+)
+
+// Single record of a document found by [SplitRaw] with its position in the document.
+type RawCard struct {
+	// Bytes of the record from BEGIN:VCARD up to and including the line terminator of END:VCARD
+	// exactly as they appear in the document.
+	Data []byte
+
+	// Byte offset of BEGIN:VCARD from the start of the document.
+	Offset int64
+
+	// Numbers of lines of BEGIN:VCARD and END:VCARD starting with 1.
+	StartLine int
+	EndLine   int
+
+	// Error of reading the document or [ErrParsing] if the document ends before END:VCARD.
+	// In this case Data contains the bytes read so far and it is the last yielded record.
+	Err error
+}
+
+// Parses the record. See [ParseRecord].
+func (r RawCard) Card() (Card, error) {
+	if r.Err != nil {
+		return Card{}, r.Err
+	}
+	return ParseRecord(r.Data)
+}
+
+// Returns records of a document without decoding them, so tools can report positions of
+// records or write records they didn't modify back untouched:
+//
+//	for raw := range vcard.SplitRaw(f) {
+//		card, err := raw.Card()
+//		if err != nil {
+//			log.Printf("card at line %d: %v", raw.StartLine, err)
+//			continue
+//		}
+//		...
+//	}
+//
+// Records nested into a record e.g. AGENT of vCard 2.1 are part of the outer record.
+// Bytes between records e.g. blank lines are skipped. Lines may end with CRLF or LF.
+func SplitRaw(r io.Reader) iter.Seq[RawCard] {
+	return func(yield func(RawCard) bool) {
+		br := bufio.NewReader(r)
+		var offset int64
+		line := 0
+		depth := 0
+		current := RawCard{}
+
+		for {
+			s, err := br.ReadString('\n')
+			if len(s) > 0 {
+				line++
+				name, value, _ := strings.Cut(strings.TrimRight(s, "\r\n"), ":")
+				switch {
+				case strings.EqualFold(name, "BEGIN") && strings.EqualFold(strings.TrimSpace(value), "VCARD"):
+					if depth == 0 {
+						current = RawCard{Offset: offset, StartLine: line}
+					}
+					depth++
+				case depth > 0 && strings.EqualFold(name, "END") && strings.EqualFold(strings.TrimSpace(value), "VCARD"):
+					depth--
+				}
+				if depth > 0 || current.StartLine != 0 {
+					current.Data = append(current.Data, s...)
+				}
+				if depth == 0 && current.StartLine != 0 {
+					current.EndLine = line
+					if !yield(current) {
+						return
+					}
+					current = RawCard{}
+				}
+				offset += int64(len(s))
+			}
+
+			if errors.Is(err, io.EOF) {
+				if depth > 0 {
+					current.EndLine = line
+					current.Err = parsingErrf("record starting at line %d has no END:VCARD", current.StartLine)
+					yield(current)
+				}
+				return
+			}
+			if err != nil {
+				current.EndLine = line
+				current.Err = vCardErrf("unable to read: %w", err)
+				yield(current)
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package vcard
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestSplitRaw(t *testing.T) {
+
+	first := "BEGIN:VCARD\r\nVERSION:4.0\r\nFN:Alex\r\nEND:VCARD\r\n"
+	second := "begin:vcard\nVERSION:2.1\nN:Doe;John\nAGENT:\nBEGIN:VCARD\nVERSION:2.1\nN:Agent\nEND:VCARD\nEND:VCARD"
+	doc := "\r\n" + first + "\r\n\r\n" + second
+
+	raws := slices.Collect(SplitRaw(strings.NewReader(doc)))
+	assertEq(t, len(raws), 2)
+
+	assertEq(t, string(raws[0].Data), first)
+	assertEq(t, raws[0].Offset, int64(2))
+	assertEq(t, raws[0].StartLine, 2)
+	assertEq(t, raws[0].EndLine, 5)
+	assertEq(t, raws[0].Err, nil)
+	assertEq(t, doc[raws[0].Offset:int(raws[0].Offset)+len(raws[0].Data)], first)
+
+	assertEq(t, string(raws[1].Data), second)
+	assertEq(t, raws[1].Offset, int64(len(doc)-len(second)))
+	assertEq(t, raws[1].StartLine, 8)
+	assertEq(t, raws[1].EndLine, 16)
+
+	card, err := raws[0].Card()
+	assertEq(t, err, nil)
+	fn, _ := card.FN()
+	assertEq(t, fn, "Alex")
+
+	// Iteration stops early
+	for raw := range SplitRaw(strings.NewReader(doc)) {
+		assertEq(t, raw.StartLine, 2)
+		break
+	}
+
+	raws = slices.Collect(SplitRaw(strings.NewReader(first + "BEGIN:VCARD\r\nFN:Bob\r\n")))
+	assertEq(t, len(raws), 2)
+	assertErrIs(t, raws[1].Err, ErrParsing, "record starting at line 5 has no END:VCARD")
+	assertEq(t, string(raws[1].Data), "BEGIN:VCARD\r\nFN:Bob\r\n")
+	_, err = raws[1].Card()
+	assertErrIs(t, err, ErrParsing, "has no END:VCARD")
+
+	broken := errors.New("broken")
+	raws = slices.Collect(SplitRaw(iotest.DataErrReader(iotest.ErrReader(broken))))
+	assertEq(t, len(raws), 1)
+	assertErrIs(t, raws[0].Err, ErrVCard, "unable to read: broken")
+}
@@ -0,0 +1,53 @@
+package vcard
+
+import (
+	"database/sql/driver"
+)
+
+// Returns the card encoded by [Marshal] as a single .vcf record, so cards can be stored in TEXT
+// columns and passed to database/sql and ORMs as is. Card without properties is stored as NULL.
+// Implements [driver.Valuer].
+//
+// Columns for lookups can be stored next to the card e.g. a key of its UID and its fingerprint
+// which changes whenever the card does:
+//
+//	uid, _ := card.UID()
+//	_, err := db.Exec("INSERT INTO contacts (uid, etag, card) VALUES (?, ?, ?)",
+//		vcard.UID(uid).Key(), card.Fingerprint(), card)
+//
+// See [UID.Key] and [Card.Fingerprint].
+func (c Card) Value() (driver.Value, error) {
+	if len(c.props) == 0 {
+		return nil, nil
+	}
+	b, err := Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Decodes a card stored by [Card.Value] from string or []byte column. NULL is decoded as
+// an empty card. Returns [ErrParsing] if the column contains anything but a single vCard.
+// Implements [database/sql.Scanner].
+func (c *Card) Scan(src any) error {
+	var data []byte
+	switch src := src.(type) {
+	case nil:
+		*c = Card{}
+		return nil
+	case string:
+		data = []byte(src)
+	case []byte:
+		data = src
+	default:
+		return vCardErrf("unable to scan %T into Card", src)
+	}
+
+	card, err := ParseRecord(data)
+	if err != nil {
+		return err
+	}
+	*c = card
+	return nil
+}
@@ -0,0 +1,50 @@
+package vcard
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+)
+
+var (
+	_ driver.Valuer = Card{}
+	_ sql.Scanner   = (*Card)(nil)
+)
+
+func TestCardValue(t *testing.T) {
+
+	card := newStoreCard("urn:uuid:F81D4FAE-7DEC-11D0-A765-00A0C91E6BF6", "Alex")
+	v, err := card.Value()
+	assertEq(t, err, nil)
+	assertEq(t, v, driver.Value("BEGIN:VCARD\r\nVERSION:4.0\r\nUID:urn:uuid:F81D4FAE-7DEC-11D0-A765-00A0C91E6BF6\r\nFN:Alex\r\nEND:VCARD\r\n"))
+
+	v, err = Card{}.Value()
+	assertEq(t, err, nil)
+	assertEq(t, v, nil)
+
+	uid, _ := card.UID()
+	assertEq(t, UID(uid).Key(), "f81d4fae-7dec-11d0-a765-00a0c91e6bf6")
+	assertEq(t, UID(" 42 ").Key(), "42")
+}
+
+func TestCardScan(t *testing.T) {
+
+	card := newStoreCard("1", "Alex")
+	v, err := card.Value()
+	assertEq(t, err, nil)
+
+	scanned := Card{}
+	assertEq(t, scanned.Scan(v), nil)
+	assertEq(t, Equal(scanned, card), true)
+
+	scanned = Card{}
+	assertEq(t, scanned.Scan([]byte(v.(string))), nil)
+	assertEq(t, Equal(scanned, card), true)
+
+	assertEq(t, scanned.Scan(nil), nil)
+	assertEq(t, scanned.Len(), 0)
+
+	assertErrIs(t, scanned.Scan(int64(42)), ErrVCard, "unable to scan int64 into Card")
+	assertErrIs(t, scanned.Scan("BEGIN:VCARD\r\nVERSION:4.0\r\nFN:A\r\nEND:VCARD\r\nBEGIN:VCARD\r\nVERSION:4.0\r\nFN:B\r\nEND:VCARD\r\n"),
+		ErrParsing, "more than a single record")
+}
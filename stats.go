@@ -0,0 +1,174 @@
+package vcard
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+)
+
+// Statistics of a set of cards collected to profile a corpus before importing it:
+//
+//	stats, err := vcard.CollectStats(f)
+//	fmt.Print(stats)
+//
+// Names of properties and parameters are upper-cased and values of parameters lower-cased.
+type Stats struct {
+	// Number of cards.
+	Cards int
+
+	// Number of records which are not valid vCards. See [CollectStats].
+	Malformed int
+
+	// Number of cards by VERSION e.g. "3.0". Cards without VERSION are counted as "".
+	Versions map[string]int
+
+	// Number of occurrences of properties by name e.g. "TEL".
+	Properties map[string]int
+
+	// Number of cards containing a property by name.
+	CardsWithProperty map[string]int
+
+	// Number of occurrences of parameters by name e.g. "TYPE". Nameless parameters of vCard 2.1
+	// are counted as TYPE, ENCODING or CHARSET they stand for.
+	Params map[string]int
+
+	// Number of properties by encoding e.g. "b", "base64" or "quoted-printable" and by charset
+	// e.g. "charset=utf-8".
+	Encodings map[string]int
+
+	sizes []int // sorted lazily by SizePercentile
+}
+
+// Creates empty statistics.
+func NewStats() *Stats {
+	return &Stats{
+		Versions:          map[string]int{},
+		Properties:        map[string]int{},
+		CardsWithProperty: map[string]int{},
+		Params:            map[string]int{},
+		Encodings:         map[string]int{},
+	}
+}
+
+// Reads every record of a document and collects its statistics. Sizes are sizes of records as
+// they appear in the document. Records which can't be parsed are counted as [Stats.Malformed].
+//
+// Returns an error only if reading fails.
+func CollectStats(r io.Reader) (*Stats, error) {
+	s := NewStats()
+	for raw := range SplitRaw(r) {
+		if raw.Err != nil && !errors.Is(raw.Err, ErrParsing) {
+			return s, raw.Err
+		}
+		c, err := raw.Card()
+		if err != nil {
+			s.Malformed++
+			continue
+		}
+		s.add(&c, len(raw.Data))
+	}
+	return s, nil
+}
+
+// Adds a card to the statistics. Its size is the size of its unfolded content lines.
+func (s *Stats) Add(c *Card) {
+	size := len("BEGIN:VCARD\r\n") + len("END:VCARD\r\n")
+	for _, p := range c.props {
+		size += len(p.String()) + 2
+	}
+	s.add(c, size)
+}
+
+func (s *Stats) add(c *Card, size int) {
+	s.Cards++
+	s.Versions[c.Version()]++
+	s.sizes = append(s.sizes, size)
+
+	seen := map[string]bool{}
+	for _, p := range topLevelProperties(c.props) {
+		s.Properties[p.Name]++
+		if !seen[p.Name] {
+			seen[p.Name] = true
+			s.CardsWithProperty[p.Name]++
+		}
+		for _, param := range p.Params {
+			name := strings.ToUpper(param.Name)
+			if param.Values == nil {
+				switch name {
+				case "QUOTED-PRINTABLE", "BASE64", "8BIT", "7BIT", "B":
+					s.Params["ENCODING"]++
+					s.Encodings[strings.ToLower(name)]++
+				default:
+					s.Params["TYPE"]++
+				}
+				continue
+			}
+			s.Params[name]++
+			for _, v := range param.Values {
+				switch name {
+				case "ENCODING":
+					s.Encodings[strings.ToLower(v)]++
+				case "CHARSET":
+					s.Encodings["charset="+strings.ToLower(v)]++
+				}
+			}
+		}
+	}
+}
+
+// Returns the size of a card in bytes at percentile p in range 0..100 using the nearest-rank method
+// e.g. SizePercentile(50) is the median. Returns 0 if there are no cards.
+func (s *Stats) SizePercentile(p float64) int {
+	if len(s.sizes) == 0 {
+		return 0
+	}
+	slices.Sort(s.sizes)
+	rank := int(math.Ceil(min(max(p, 0), 100) / 100 * float64(len(s.sizes))))
+	return s.sizes[max(rank, 1)-1]
+}
+
+// Returns a human-readable report e.g.
+//
+//	cards: 2 (malformed: 0)
+//	versions: 4.0=2
+//	sizes: p50=120 p90=180 p99=180 max=180
+//	properties:
+//	  FN 2 (2 cards)
+//	...
+//
+// Counts are sorted in descending order.
+func (s *Stats) String() string {
+	b := strings.Builder{}
+	fmt.Fprintf(&b, "cards: %d (malformed: %d)\n", s.Cards, s.Malformed)
+	fmt.Fprintf(&b, "versions: %s\n", formatCounts(s.Versions))
+	fmt.Fprintf(&b, "sizes: p50=%d p90=%d p99=%d max=%d\n",
+		s.SizePercentile(50), s.SizePercentile(90), s.SizePercentile(99), s.SizePercentile(100))
+	b.WriteString("properties:\n")
+	for _, name := range sortedByCount(s.Properties) {
+		fmt.Fprintf(&b, "  %s %d (%d cards)\n", name, s.Properties[name], s.CardsWithProperty[name])
+	}
+	fmt.Fprintf(&b, "params: %s\n", formatCounts(s.Params))
+	fmt.Fprintf(&b, "encodings: %s\n", formatCounts(s.Encodings))
+	return b.String()
+}
+
+// Formats counts as "a=2 b=1".
+func formatCounts(counts map[string]int) string {
+	parts := []string{}
+	for _, k := range sortedByCount(counts) {
+		parts = append(parts, fmt.Sprintf("%s=%d", k, counts[k]))
+	}
+	return strings.Join(parts, " ")
+}
+
+// Returns keys sorted by descending counts and then by name.
+func sortedByCount(counts map[string]int) []string {
+	return slices.SortedFunc(maps.Keys(counts), func(a, b string) int {
+		return cmp.Or(cmp.Compare(counts[b], counts[a]), strings.Compare(a, b))
+	})
+}
@@ -0,0 +1,57 @@
+package vcard
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCollectStats(t *testing.T) {
+
+	doc := crlfy(`BEGIN:VCARD
+VERSION:4.0
+FN:Alex
+TEL;TYPE=cell:555
+TEL;TYPE=work;PREF=1:556
+END:VCARD
+BEGIN:VCARD
+VERSION:2.1
+N:Doe;John
+TEL;CELL:557
+NOTE;CHARSET=UTF-8;QUOTED-PRINTABLE:caf=C3=A9
+PHOTO;ENCODING=BASE64;TYPE=GIF:R0lG
+END:VCARD
+BEGIN:VCARD
+VERSION:4.0
+FN
+END:VCARD
+`)
+	stats, err := CollectStats(strings.NewReader(doc))
+	assertEq(t, err, nil)
+
+	assertEq(t, stats.Cards, 2)
+	assertEq(t, stats.Malformed, 1)
+	assertMapsEq(t, stats.Versions, map[string]int{"4.0": 1, "2.1": 1})
+	assertMapsEq(t, stats.Properties, map[string]int{"VERSION": 2, "FN": 1, "N": 1, "TEL": 3, "NOTE": 1, "PHOTO": 1})
+	assertEq(t, stats.CardsWithProperty["TEL"], 2)
+	assertMapsEq(t, stats.Params, map[string]int{"TYPE": 4, "PREF": 1, "CHARSET": 1, "ENCODING": 2})
+	assertMapsEq(t, stats.Encodings, map[string]int{"quoted-printable": 1, "base64": 1, "charset=utf-8": 1})
+
+	first := len("BEGIN:VCARD\r\nVERSION:4.0\r\nFN:Alex\r\nTEL;TYPE=cell:555\r\nTEL;TYPE=work;PREF=1:556\r\nEND:VCARD\r\n")
+	second := len(doc) - first - len("BEGIN:VCARD\r\nVERSION:4.0\r\nFN\r\nEND:VCARD\r\n")
+	assertEq(t, stats.SizePercentile(0), first)
+	assertEq(t, stats.SizePercentile(50), first)
+	assertEq(t, stats.SizePercentile(100), second)
+
+	report := stats.String()
+	assertEq(t, strings.HasPrefix(report, "cards: 2 (malformed: 1)\nversions: 2.1=1 4.0=1\n"), true)
+	assertEq(t, strings.Contains(report, "\n  TEL 3 (2 cards)\n  VERSION 2 (2 cards)\n"), true)
+
+	empty := NewStats()
+	assertEq(t, empty.SizePercentile(50), 0)
+
+	c := Card{}
+	c.Add("FN", "Alex")
+	empty.Add(&c)
+	assertEq(t, empty.SizePercentile(50), len("BEGIN:VCARD\r\nFN:Alex\r\nEND:VCARD\r\n"))
+	assertEq(t, empty.Versions[""], 1)
+}
@@ -0,0 +1,229 @@
+package vcard
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Persistence layer for cards identified by their UID.
+//
+// Implementations have to be safe for concurrent use. [DirStore] stores cards in a directory
+// of .vcf files, other implementations may be backed by a CardDAV server or a database.
+type Store interface {
+	// Returns every card in the store.
+	List() ([]Card, error)
+
+	// Returns a card with given UID or [ErrNotFound].
+	Get(uid string) (Card, error)
+
+	// Creates or replaces a card with the same UID. Card has to contain UID property.
+	Put(card Card) error
+
+	// Deletes a card with given UID or returns [ErrNotFound].
+	Delete(uid string) error
+
+	// Returns cards created, modified or deleted after since, ordered by time of a change.
+	Changes(since time.Time) ([]Change, error)
+}
+
+// Single change of a [Store] returned by [Store.Changes].
+type Change struct {
+	UID  string
+	Time time.Time
+
+	// Card after the change. Empty for deleted cards.
+	Card Card
+
+	Deleted bool
+}
+
+// [Store] over a directory of .vcf files with one card per file following vdir convention of
+// vdirsyncer and khard, see https://vdirsyncer.pimutils.org/en/stable/vdir.html and [ReadVdir].
+// Files are written atomically through a hidden temporary file, so other programs reading
+// the directory never observe partially written cards.
+//
+// File names are derived from UIDs, but Get and Delete also find cards in files
+// named differently e.g. ones created by other applications.
+//
+// Changes are detected using modification time of files. Deletions are only reported
+// for cards deleted using the same DirStore since the directory has no record of them.
+type DirStore struct {
+	dir string
+
+	mu      sync.Mutex
+	deleted []Change
+}
+
+var _ Store = (*DirStore)(nil)
+
+// Creates new DirStore over directory dir. Directory is created if it does not exist.
+func NewDirStore(dir string) (*DirStore, error) {
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return nil, vCardErrf("unable to create store directory: %w", err)
+	}
+	return &DirStore{dir: dir}, nil
+}
+
+// Returns every card stored in .vcf files of the directory.
+func (s *DirStore) List() ([]Card, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	files, err := s.files()
+	if err != nil {
+		return nil, err
+	}
+	cards := make([]Card, 0, len(files))
+	for _, item := range files {
+		cards = append(cards, item.Card)
+	}
+	return cards, nil
+}
+
+// Returns a card with given UID or [ErrNotFound].
+func (s *DirStore) Get(uid string) (Card, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, err := s.find(uid)
+	if err != nil {
+		return Card{}, err
+	}
+	return item.Card, nil
+}
+
+// Writes card into a file named after its UID replacing previous version of the card.
+//
+// File is replaced atomically, so concurrent readers never observe partially written card.
+func (s *DirStore) Put(card Card) error {
+	uid, found := card.UID()
+	if !found || uid == "" {
+		return vCardErrf("unable to store a card without UID")
+	}
+	b, err := Marshal(card)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := filepath.Join(s.dir, storeFileName(uid))
+
+	// Card may be stored in a file named differently, it has to be replaced instead of duplicated
+	item, err := s.find(uid)
+	if err == nil {
+		path = filepath.Join(s.dir, item.Name)
+	} else if !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	if err := writeFileAtomic(path, b); err != nil {
+		return vCardErrf("unable to store card %q: %w", uid, err)
+	}
+	return nil
+}
+
+// Deletes a file containing card with given UID or returns [ErrNotFound].
+func (s *DirStore) Delete(uid string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, err := s.find(uid)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(filepath.Join(s.dir, item.Name))
+	if err != nil {
+		return vCardErrf("unable to delete card %q: %w", uid, err)
+	}
+	s.deleted = append(s.deleted, Change{UID: uid, Time: time.Now(), Deleted: true})
+	return nil
+}
+
+// Returns cards stored in files modified after since and cards deleted by this DirStore after since.
+func (s *DirStore) Changes(since time.Time) ([]Change, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	files, err := s.files()
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []Change{}
+	for _, item := range files {
+		if !item.ModTime.After(since) {
+			continue
+		}
+		uid, _ := item.Card.UID()
+		changes = append(changes, Change{UID: uid, Time: item.ModTime, Card: item.Card})
+	}
+	for _, c := range s.deleted {
+		if c.Time.After(since) {
+			changes = append(changes, c)
+		}
+	}
+	slices.SortStableFunc(changes, func(a, b Change) int {
+		return a.Time.Compare(b.Time)
+	})
+	return changes, nil
+}
+
+func (s *DirStore) find(uid string) (VdirItem, error) {
+	item, err := readVdirItem(os.DirFS(s.dir), storeFileName(uid))
+	if err == nil {
+		if u, _ := item.Card.UID(); u == uid {
+			return item, nil
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return VdirItem{}, err
+	}
+
+	items, err := s.files()
+	if err != nil {
+		return VdirItem{}, err
+	}
+	for _, item := range items {
+		if u, _ := item.Card.UID(); u == uid {
+			return item, nil
+		}
+	}
+	return VdirItem{}, notFoundErrf("card with UID %q", uid)
+}
+
+func (s *DirStore) files() ([]VdirItem, error) {
+	return readVdirItems(os.DirFS(s.dir))
+}
+
+// Returns name of a file for a card with given UID.
+//
+// UIDs are often URNs like "urn:uuid:..." so characters not allowed in file names
+// on some systems are percent-encoded. Leading `.` is percent-encoded as well, since
+// hidden files are not part of a vdir.
+func storeFileName(uid string) string {
+	const hex = "0123456789ABCDEF"
+
+	var b strings.Builder
+	for i := 0; i < len(uid); i++ {
+		c := uid[i]
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.' && i > 0, c == '@':
+			b.WriteByte(c)
+		default:
+			b.WriteByte('%')
+			b.WriteByte(hex[c>>4])
+			b.WriteByte(hex[c&0xF])
+		}
+	}
+	b.WriteString(".vcf")
+	return b.String()
+}
@@ -0,0 +1,130 @@
+package vcard
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newStoreCard(uid string, fn string) Card {
+	c := Card{}
+	c.Add("VERSION", "4.0")
+	c.Add("UID", uid)
+	c.Add("FN", fn)
+	return c
+}
+
+func TestDirStorePutGet(t *testing.T) {
+
+	s, err := NewDirStore(t.TempDir())
+	assertEq(t, err, nil)
+
+	err = s.Put(newStoreCard("urn:uuid:1", "Alex"))
+	assertEq(t, err, nil)
+
+	err = s.Put(newStoreCard("urn:uuid:1", "Alex Smith"))
+	assertEq(t, err, nil)
+
+	c, err := s.Get("urn:uuid:1")
+	assertEq(t, err, nil)
+
+	fn, _ := c.FN()
+	assertStringsEq(t, fn, "Alex Smith")
+
+	cards, err := s.List()
+	assertEq(t, err, nil)
+	assertEq(t, len(cards), 1)
+
+	_, err = os.Stat(filepath.Join(s.dir, "urn%3Auuid%3A1.vcf"))
+	assertEq(t, err, nil)
+}
+
+func TestDirStoreLeadingDot(t *testing.T) {
+
+	s, err := NewDirStore(t.TempDir())
+	assertEq(t, err, nil)
+
+	err = s.Put(newStoreCard(".abc", "Alex"))
+	assertEq(t, err, nil)
+
+	_, err = os.Stat(filepath.Join(s.dir, "%2Eabc.vcf"))
+	assertEq(t, err, nil)
+
+	cards, err := s.List()
+	assertEq(t, err, nil)
+	assertEq(t, len(cards), 1)
+
+	_, err = s.Get(".abc")
+	assertEq(t, err, nil)
+}
+
+func TestDirStoreForeignFile(t *testing.T) {
+
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "alex.vcf"), []byte(crlfy(`BEGIN:VCARD
+VERSION:3.0
+UID:123
+FN:Alex
+END:VCARD
+`)), 0o644)
+	assertEq(t, err, nil)
+
+	s, err := NewDirStore(dir)
+	assertEq(t, err, nil)
+
+	err = s.Put(newStoreCard("123", "Alex Smith"))
+	assertEq(t, err, nil)
+
+	entries, err := os.ReadDir(dir)
+	assertEq(t, err, nil)
+	assertEq(t, len(entries), 1)
+	assertStringsEq(t, entries[0].Name(), "alex.vcf")
+
+	err = s.Delete("123")
+	assertEq(t, err, nil)
+
+	_, err = s.Get("123")
+	assertErrIs(t, err, ErrNotFound, `"123"`)
+
+	err = s.Delete("123")
+	assertErrIs(t, err, ErrNotFound, `"123"`)
+}
+
+func TestDirStorePutWithoutUID(t *testing.T) {
+
+	s, err := NewDirStore(t.TempDir())
+	assertEq(t, err, nil)
+
+	c := Card{}
+	c.Add("FN", "Alex")
+
+	err = s.Put(c)
+	assertErrIs(t, err, ErrVCard, "without UID")
+}
+
+func TestDirStoreChanges(t *testing.T) {
+
+	s, err := NewDirStore(t.TempDir())
+	assertEq(t, err, nil)
+
+	assertEq(t, s.Put(newStoreCard("1", "Alex")), nil)
+	assertEq(t, s.Put(newStoreCard("2", "Sam")), nil)
+
+	old := time.Now().Add(-time.Hour)
+	assertEq(t, os.Chtimes(filepath.Join(s.dir, "1.vcf"), old, old), nil)
+
+	since := time.Now().Add(-time.Minute)
+	assertEq(t, s.Delete("2"), nil)
+	assertEq(t, s.Put(newStoreCard("3", "Kim")), nil)
+
+	changes, err := s.Changes(since)
+	assertEq(t, err, nil)
+	assertEq(t, len(changes), 2)
+
+	uids := map[string]bool{}
+	for _, c := range changes {
+		uids[c.UID] = c.Deleted
+	}
+	assertMapsEq(t, uids, map[string]bool{"2": true, "3": false})
+}
@@ -0,0 +1,62 @@
+package vcard
+
+import (
+	"slices"
+	"strings"
+)
+
+// Defines which payloads [StripBinary] removes and what it does with them.
+type StripOptions struct {
+	// Payloads up to MaxSize bytes after decoding are kept. Zero strips every payload.
+	MaxSize int
+
+	// Stores a stripped payload of the property e.g. in a blob storage and returns a URI referencing it.
+	// If set, the property is kept with the URI as a value instead of being removed.
+	Externalize func(p Property, data []byte, mediaType string) (uri string, err error)
+}
+
+// Returns a copy of the card without payloads embedded into PHOTO, LOGO, SOUND and KEY properties
+// either as base64 values with ENCODING parameter or as data: URIs, so the card stays small:
+//
+//	small, err := vcard.StripBinary(card, vcard.StripOptions{MaxSize: 4 << 10})
+//
+// Properties referencing payloads by URI and payloads which are not larger than opts.MaxSize are kept.
+// Returns an error of opts.Externalize.
+func StripBinary(card Card, opts StripOptions) (Card, error) {
+	version := card.Version()
+	if version == "" {
+		version = "4.0"
+	}
+
+	stripped := Card{props: make([]Property, 0, len(card.props))}
+	for _, p := range card.props {
+		kind, isMedia := mediaKinds[p.Name]
+		if !isMedia {
+			stripped.props = append(stripped.props, p.clone())
+			continue
+		}
+		m, err := unmarshalMedia([]byte(p.rest()), kind)
+		if err != nil || m.data == nil || opts.MaxSize > 0 && len(m.data) <= opts.MaxSize {
+			stripped.props = append(stripped.props, p.clone())
+			continue
+		}
+		if opts.Externalize == nil {
+			continue
+		}
+
+		uri, err := opts.Externalize(p.clone(), m.data, m.mediaType)
+		if err != nil {
+			return Card{}, vCardErrf("unable to externalize %s: %w", p.Name, err)
+		}
+		p = p.clone()
+		p.Params = slices.DeleteFunc(p.Params, func(param Param) bool {
+			return strings.EqualFold(param.Name, "ENCODING") || param.Values == nil && strings.EqualFold(param.Name, "BASE64")
+		})
+		p, err = withRest(p, Photo{URI: uri, MediaType: m.mediaType}, version)
+		if err != nil {
+			return Card{}, vCardErrf("unable to externalize %s: %w", p.Name, err)
+		}
+		stripped.props = append(stripped.props, p)
+	}
+	return stripped, nil
+}
@@ -0,0 +1,63 @@
+package vcard
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestStripBinary(t *testing.T) {
+
+	card := Card{}
+	err := Unmarshal([]byte(crlfy(`BEGIN:VCARD
+VERSION:3.0
+FN:Alex
+N:;Alex;;;
+PHOTO;ENCODING=b;TYPE=JPEG:/9j/4AAQSkZJRg==
+LOGO;VALUE=uri:http://example.com/logo.png
+SOUND;ENCODING=b;TYPE=MP3:AAAA
+KEY;ENCODING=b;TYPE=PGP:AAECAwQFBgcICQ==
+END:VCARD
+`)), &card)
+	assertEq(t, err, nil)
+
+	stripped, err := StripBinary(card, StripOptions{})
+	assertEq(t, err, nil)
+	b, err := Marshal(stripped)
+	assertEq(t, err, nil)
+	assertStringLinesEq(t, string(b), crlfy(`BEGIN:VCARD
+VERSION:3.0
+FN:Alex
+N:;Alex;;;
+LOGO;VALUE=uri:http://example.com/logo.png
+END:VCARD
+`))
+
+	// SOUND of 3 bytes is small enough
+	externalized := []string{}
+	stripped, err = StripBinary(card, StripOptions{MaxSize: 3, Externalize: func(p Property, data []byte, mediaType string) (string, error) {
+		externalized = append(externalized, p.Name+" "+mediaType)
+		return "https://cdn.example.com/" + p.Name, nil
+	}})
+	assertEq(t, err, nil)
+	assertSlicesEq(t, externalized, []string{"PHOTO image/jpeg", "KEY application/pgp-keys"})
+	b, err = Marshal(stripped)
+	assertEq(t, err, nil)
+	assertStringLinesEq(t, string(b), crlfy(`BEGIN:VCARD
+VERSION:3.0
+FN:Alex
+N:;Alex;;;
+PHOTO;VALUE=uri;TYPE=JPEG:https://cdn.example.com/PHOTO
+LOGO;VALUE=uri:http://example.com/logo.png
+SOUND;ENCODING=b;TYPE=MP3:AAAA
+KEY;VALUE=uri;TYPE=PGP:https://cdn.example.com/KEY
+END:VCARD
+`))
+
+	// Original card is not modified
+	assertEq(t, card.Len(), 7)
+	assertEq(t, card.Properties()[3].Params.Has("ENCODING"), true)
+
+	failure := errors.New("storage is down")
+	_, err = StripBinary(card, StripOptions{Externalize: func(Property, []byte, string) (string, error) { return "", failure }})
+	assertErrIs(t, err, failure, "unable to externalize PHOTO: storage is down")
+}
@@ -0,0 +1,116 @@
+package vcard
+
+import (
+	"slices"
+	"strings"
+)
+
+// Value of TEL property as per https://datatracker.ietf.org/doc/html/rfc6350#section-6.4.1
+//
+// Tel implements [VCardFieldMarshaler] and [VCardFieldUnmarshaler], so it can be used as a type
+// of struct fields instead of string:
+//
+//	type Contact struct {
+//		FN  string `vCard:"required"`
+//		TEL Tel
+//	}
+type Tel struct {
+	// Number as written in a vCard e.g. "+1 555 555 5555". URI form "tel:+1-555-555-5555"
+	// of vCard 4.0 is decoded without "tel:" prefix.
+	Number string
+
+	// Values of TYPE parameter e.g. "cell", "work".
+	Types []string
+
+	// Preference in range 1..100 where 1 is the most preferred. 0 means no preference.
+	Pref int
+}
+
+// Reports whether TYPE parameter of the number contains typ compared case-insensitively.
+func (t Tel) HasType(typ string) bool {
+	return slices.ContainsFunc(t.Types, func(s string) bool { return strings.EqualFold(s, typ) })
+}
+
+// Returns the number as a tel URI as per https://datatracker.ietf.org/doc/html/rfc3966
+// e.g. "tel:+1-555-555-5555". Spaces are replaced with visual separators.
+func (t Tel) URI() string {
+	number := strings.TrimPrefix(t.Number, "tel:")
+	return "tel:" + strings.Join(strings.Fields(number), "-")
+}
+
+// Returns the number in E.164 format e.g. "+15555555555".
+//
+// Numbers without an international prefix ("+" or "00") are treated as national numbers
+// of region which is an ISO 3166 code e.g. "US" or "DE". National trunk prefix is removed.
+// Returns [ErrValidation] if region is unknown or the number is not valid.
+func (t Tel) E164(region string) (string, error) {
+	number := strings.TrimPrefix(t.Number, "tel:")
+	number, _, _ = strings.Cut(number, ";") // drops extensions and URI parameters
+
+	international := strings.HasPrefix(strings.TrimSpace(number), "+")
+	digits := make([]byte, 0, len(number))
+	for i := 0; i < len(number); i++ {
+		c := number[i]
+		switch {
+		case c >= '0' && c <= '9':
+			digits = append(digits, c)
+		case c == '+' || c == '-' || c == '.' || c == '(' || c == ')' || c == ' ':
+		default:
+			return "", validationErrf("telephone number %q contains invalid character %q", t.Number, c)
+		}
+	}
+	s := string(digits)
+
+	if !international && strings.HasPrefix(s, "00") {
+		international = true
+		s = s[2:]
+	}
+	if !international {
+		region = strings.ToUpper(region)
+		code, found := callingCodes[region]
+		if !found {
+			return "", validationErrf("unknown region %q of telephone number %q", region, t.Number)
+		}
+		switch {
+		case code == "1" && len(s) == 11 && s[0] == '1':
+			s = s[1:]
+		case region != "IT" && strings.HasPrefix(s, "0"):
+			s = s[1:]
+		}
+		s = code + s
+	}
+	if len(s) < 7 || len(s) > 15 || s[0] == '0' {
+		return "", validationErrf("telephone number %q can't be represented in E.164 format", t.Number)
+	}
+	return "+" + s, nil
+}
+
+// Country calling codes of regions supported by [Tel.E164].
+var callingCodes = map[string]string{
+	"AR": "54", "AT": "43", "AU": "61", "BE": "32", "BR": "55", "CA": "1", "CH": "41",
+	"CN": "86", "CZ": "420", "DE": "49", "DK": "45", "ES": "34", "FI": "358", "FR": "33",
+	"GB": "44", "GR": "30", "IE": "353", "IL": "972", "IN": "91", "IT": "39", "JP": "81",
+	"KR": "82", "MX": "52", "NL": "31", "NO": "47", "NZ": "64", "PL": "48", "PT": "351",
+	"RU": "7", "SE": "46", "TR": "90", "UA": "380", "US": "1", "ZA": "27",
+}
+
+// Encodes the number e.g. ";TYPE=cell;PREF=1:+1 555 555 5555".
+func (t Tel) MarshalVCardField() ([]byte, error) {
+	b := appendParam([]byte{}, "TYPE", t.Types...)
+	b = appendPref(b, t.Pref)
+	b = append(b, ':')
+	return append(b, t.Number...), nil
+}
+
+// Decodes the number from ";TYPE=cell:+1 555 555 5555" or ";VALUE=uri:tel:+1-555-555-5555".
+func (t *Tel) UnmarshalVCardField(data []byte) error {
+	params, value, _ := splitParamsValue(string(data))
+
+	t.Number = strings.TrimPrefix(value, "tel:")
+	t.Types = withoutValue(paramValues(params, "TYPE"), "pref")
+	if len(t.Types) == 0 {
+		t.Types = nil
+	}
+	t.Pref = paramPref(params)
+	return nil
+}
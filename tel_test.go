@@ -0,0 +1,107 @@
+package vcard
+
+import "testing"
+
+func TestTelUnmarshal(t *testing.T) {
+
+	tel := Tel{}
+	err := tel.UnmarshalVCardField([]byte(";TYPE=cell,voice;PREF=1:+1 555 555 5555"))
+
+	assertEq(t, err, nil)
+	assertEq(t, tel.Number, "+1 555 555 5555")
+	assertSlicesEq(t, tel.Types, []string{"cell", "voice"})
+	assertEq(t, tel.Pref, 1)
+	assertEq(t, tel.HasType("CELL"), true)
+	assertEq(t, tel.HasType("fax"), false)
+
+	err = tel.UnmarshalVCardField([]byte(";VALUE=uri:tel:+1-555-555-5555"))
+
+	assertEq(t, err, nil)
+	assertEq(t, tel.Number, "+1-555-555-5555")
+	assertEq(t, len(tel.Types), 0)
+	assertEq(t, tel.Pref, 0)
+
+	// vCard 2.1 and 3.0
+	err = tel.UnmarshalVCardField([]byte(";WORK;PREF:555"))
+
+	assertEq(t, err, nil)
+	assertSlicesEq(t, tel.Types, []string{"WORK"})
+	assertEq(t, tel.Pref, 1)
+}
+
+func TestTelMarshal(t *testing.T) {
+
+	b, err := Tel{Number: "+1 555", Types: []string{"cell", "work"}, Pref: 2}.MarshalVCardField()
+
+	assertEq(t, err, nil)
+	assertStringsEq(t, string(b), ";TYPE=cell,work;PREF=2:+1 555")
+
+	b, err = Tel{Number: "555"}.MarshalVCardField()
+
+	assertEq(t, err, nil)
+	assertStringsEq(t, string(b), ":555")
+}
+
+func TestTelURI(t *testing.T) {
+
+	assertStringsEq(t, Tel{Number: "+1 555 555 5555"}.URI(), "tel:+1-555-555-5555")
+	assertStringsEq(t, Tel{Number: "tel:+1-555"}.URI(), "tel:+1-555")
+}
+
+func TestTelE164(t *testing.T) {
+
+	cases := []struct {
+		number string
+		region string
+		e164   string
+	}{
+		{"+1 (555) 555-5555", "", "+15555555555"},
+		{"(555) 555-5555", "US", "+15555555555"},
+		{"1-555-555-5555", "us", "+15555555555"},
+		{"030 1234567", "DE", "+49301234567"},
+		{"0049 30 1234567", "", "+49301234567"},
+		{"06 1234 5678", "IT", "+390612345678"},
+		{"tel:+44-20-7946-0958;ext=12", "", "+442079460958"},
+	}
+	for _, c := range cases {
+		e164, err := Tel{Number: c.number}.E164(c.region)
+
+		assertEq(t, err, nil)
+		assertStringsEq(t, e164, c.e164)
+	}
+
+	_, err := Tel{Number: "555 5555"}.E164("XX")
+	assertErrIs(t, err, ErrValidation, `unknown region "XX"`)
+
+	_, err = Tel{Number: "+1 555 CALL"}.E164("")
+	assertErrIs(t, err, ErrValidation, "invalid character")
+
+	_, err = Tel{Number: "+1 555"}.E164("")
+	assertErrIs(t, err, ErrValidation, "can't be represented in E.164")
+}
+
+type TelStruct struct {
+	FN  string `vCard:"required"`
+	TEL Tel
+}
+
+func TestTelField(t *testing.T) {
+
+	b, err := Marshal(TelStruct{FN: "Alex", TEL: Tel{Number: "555", Types: []string{"cell"}}})
+
+	exp := `BEGIN:VCARD
+VERSION:4.0
+FN:Alex
+TEL;TYPE=cell:555
+END:VCARD
+`
+	assertEq(t, err, nil)
+	assertStringsEq(t, string(b), crlfy(exp))
+
+	s := TelStruct{}
+	err = Unmarshal(b, &s)
+
+	assertEq(t, err, nil)
+	assertEq(t, s.TEL.Number, "555")
+	assertSlicesEq(t, s.TEL.Types, []string{"cell"})
+}
@@ -0,0 +1,40 @@
+package vcard
+
+import "slices"
+
+// Returns a copy of the card with properties of the template which the card does not contain,
+// e.g. to provision employee cards with the company ORG, a default TZ and standard X- properties:
+//
+//	template := vcard.Card{}
+//	template.Add("ORG", "Acme Inc.")
+//	template.Add("TZ", "Europe/Berlin")
+//	template.Add("X-DEPARTMENT-CODE", "000")
+//
+//	for i := range cards {
+//		cards[i] = vcard.ApplyTemplate(cards[i], template)
+//	}
+//
+// Properties are compared by name, so a card with its own ORG keeps it and all values of a name
+// missing in the card are copied e.g. several CATEGORIES. Properties are appended in order of the template.
+//
+// VERSION, UID and REV of the template are never copied because they identify the template itself,
+// and neither are properties which are not defined in the version of the card e.g. KIND in 3.0.
+// Records nested into the template e.g. AGENT of 2.1 are not copied.
+func ApplyTemplate(card Card, template Card) Card {
+	applied := Card{props: card.Properties()}
+	version := card.Version()
+
+	missing := map[string]bool{}
+	for _, p := range topLevelProperties(template.props) {
+		if slices.Contains([]string{"VERSION", "UID", "REV", "BEGIN", "END"}, p.Name) {
+			continue
+		}
+		if _, checked := missing[p.Name]; !checked {
+			missing[p.Name] = card.count(p.Name) == 0 && (version == "" || propertyDefinedIn(p.Name, version))
+		}
+		if missing[p.Name] {
+			applied.props = append(applied.props, p.clone())
+		}
+	}
+	return applied
+}
@@ -0,0 +1,40 @@
+package vcard
+
+import "testing"
+
+func TestApplyTemplate(t *testing.T) {
+
+	template := Card{}
+	template.Add("VERSION", "4.0")
+	template.Add("UID", "urn:uuid:template")
+	template.Add("ORG", "Acme Inc.")
+	template.Add("TZ", "Europe/Berlin")
+	template.Add("KIND", "individual")
+	template.Add("CATEGORIES", "staff")
+	template.Add("CATEGORIES", "berlin")
+	template.Add("X-DEPARTMENT-CODE", "000")
+
+	card := Card{}
+	card.Add("VERSION", "3.0")
+	card.Add("FN", "Alex")
+	card.Add("N", "Smith;Alex;;;")
+	card.Add("ORG", "Acme Labs")
+
+	applied := ApplyTemplate(card, template)
+	b, err := Marshal(applied)
+	assertEq(t, err, nil)
+	assertStringLinesEq(t, string(b), crlfy(`BEGIN:VCARD
+VERSION:3.0
+FN:Alex
+N:Smith;Alex;;;
+ORG:Acme Labs
+TZ:Europe/Berlin
+CATEGORIES:staff
+CATEGORIES:berlin
+X-DEPARTMENT-CODE:000
+END:VCARD
+`))
+
+	// The card is not modified
+	assertEq(t, len(card.Properties()), 4)
+}
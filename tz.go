@@ -0,0 +1,151 @@
+package vcard
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Value of TZ property as per https://datatracker.ietf.org/doc/html/rfc6350#section-6.5.1
+//
+// Time zone is either an IANA zone name e.g. "America/New_York" or a fixed UTC offset e.g. -05:00.
+// vCard 4.0 prefers names, while vCard 2.1 and 3.0 use offsets, so names are encoded as
+// ";VALUE=text:America/New_York" in vCard 3.0 and converted to the current offset in vCard 2.1.
+type TZ struct {
+	// IANA zone name. Empty name means the zone is a fixed Offset.
+	Name string
+
+	// Offset from UTC used when Name is empty.
+	Offset time.Duration
+}
+
+// Creates TZ from a location. Locations created with [time.LoadLocation] keep their names,
+// other locations including [time.Local] are converted to their current offset.
+func NewTZ(loc *time.Location) TZ {
+	name := loc.String()
+	if name != "Local" {
+		if _, err := time.LoadLocation(name); err == nil {
+			return TZ{Name: name}
+		}
+	}
+	_, offset := time.Now().In(loc).Zone()
+	return TZ{Offset: time.Duration(offset) * time.Second}
+}
+
+// Returns location of the zone. Zones defined by an offset are fixed zones named like "UTC-05:00".
+func (tz TZ) Location() (*time.Location, error) {
+	if tz.Name == "" {
+		return time.FixedZone("UTC"+formatUTCOffset(tz.Offset, true), int(tz.Offset/time.Second)), nil
+	}
+	loc, err := time.LoadLocation(tz.Name)
+	if err != nil {
+		return nil, vCardErrf("unknown time zone %q: %w", tz.Name, err)
+	}
+	return loc, nil
+}
+
+// Returns offset of the zone from UTC at the moment t. Offset of a named zone depends on
+// daylight saving time.
+func (tz TZ) OffsetAt(t time.Time) (time.Duration, error) {
+	if tz.Name == "" {
+		return tz.Offset, nil
+	}
+	loc, err := tz.Location()
+	if err != nil {
+		return 0, err
+	}
+	_, offset := t.In(loc).Zone()
+	return time.Duration(offset) * time.Second, nil
+}
+
+// Encodes the zone as an IANA name in vCard 4.0.
+func (tz TZ) MarshalVCardField() ([]byte, error) {
+	return tz.MarshalVCardFieldVersion("4.0")
+}
+
+// Encodes the zone in a form defined by the vCard version e.g. ":America/New_York" or
+// ";VALUE=utc-offset:-0500" for vCard 4.0 and ":-05:00" for vCard 3.0.
+func (tz TZ) MarshalVCardFieldVersion(version string) ([]byte, error) {
+	if tz.Name != "" {
+		switch version {
+		case "2.1":
+			offset, err := tz.OffsetAt(time.Now())
+			if err != nil {
+				return nil, err
+			}
+			return []byte(":" + formatUTCOffset(offset, true)), nil
+		case "3.0":
+			return []byte(";VALUE=text:" + escapeText(tz.Name)), nil
+		}
+		return []byte(":" + escapeText(tz.Name)), nil
+	}
+	if version == "4.0" {
+		return []byte(";VALUE=utc-offset:" + formatUTCOffset(tz.Offset, false)), nil
+	}
+	return []byte(":" + formatUTCOffset(tz.Offset, true)), nil
+}
+
+// Decodes the zone from an IANA name or a UTC offset like "-05:00", "-0500" or "Z".
+// Text values of vCard 3.0 like "-05:00; EST; Raleigh/North America" are decoded as offsets.
+func (tz *TZ) UnmarshalVCardField(data []byte) error {
+	_, value, _ := splitParamsValue(string(data))
+	value = strings.TrimSpace(unescapeText(value))
+
+	first, _, _ := strings.Cut(value, ";")
+	if offset, ok := parseUTCOffset(strings.TrimSpace(first)); ok {
+		*tz = TZ{Offset: offset}
+		return nil
+	}
+	if value == "" {
+		return parsingErrf("TZ is empty")
+	}
+	*tz = TZ{Name: value}
+	return nil
+}
+
+// Formats offset as "+hh:mm" or "+hhmm".
+func formatUTCOffset(offset time.Duration, colon bool) string {
+	sign := '+'
+	if offset < 0 {
+		sign = '-'
+		offset = -offset
+	}
+	h := int(offset / time.Hour)
+	m := int(offset % time.Hour / time.Minute)
+	if colon {
+		return fmt.Sprintf("%c%02d:%02d", sign, h, m)
+	}
+	return fmt.Sprintf("%c%02d%02d", sign, h, m)
+}
+
+// Parses UTC offset as per https://datatracker.ietf.org/doc/html/rfc6350#section-4.7
+// accepting "+hh", "+hhmm", "+hh:mm" and "Z".
+func parseUTCOffset(s string) (time.Duration, bool) {
+	if s == "Z" || s == "z" {
+		return 0, true
+	}
+	if len(s) < 3 || (s[0] != '+' && s[0] != '-') {
+		return 0, false
+	}
+	digits := strings.Replace(s[1:], ":", "", 1)
+	if len(digits) != 2 && len(digits) != 4 {
+		return 0, false
+	}
+	h, err := strconv.Atoi(digits[:2])
+	if err != nil || h > 23 {
+		return 0, false
+	}
+	m := 0
+	if len(digits) == 4 {
+		m, err = strconv.Atoi(digits[2:])
+		if err != nil || m > 59 {
+			return 0, false
+		}
+	}
+	offset := time.Duration(h)*time.Hour + time.Duration(m)*time.Minute
+	if s[0] == '-' {
+		offset = -offset
+	}
+	return offset, true
+}
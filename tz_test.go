@@ -0,0 +1,85 @@
+package vcard
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTZUnmarshal(t *testing.T) {
+
+	cases := map[string]TZ{
+		":America/New_York":            {Name: "America/New_York"},
+		";VALUE=text:America/New_York": {Name: "America/New_York"},
+		";VALUE=utc-offset:-0500":      {Offset: -5 * time.Hour},
+		":+05:30":                      {Offset: 5*time.Hour + 30*time.Minute},
+		":-03":                         {Offset: -3 * time.Hour},
+		":Z":                           {},
+		`;VALUE=text:-05:00\; EST\; Raleigh/North America`: {Offset: -5 * time.Hour},
+	}
+	for data, exp := range cases {
+		tz := TZ{}
+		err := tz.UnmarshalVCardField([]byte(data))
+
+		assertEq(t, err, nil)
+		assertEq(t, tz, exp)
+	}
+
+	tz := TZ{}
+	assertErrIs(t, tz.UnmarshalVCardField([]byte(":")), ErrParsing, "TZ is empty")
+}
+
+func TestTZMarshal(t *testing.T) {
+
+	named := TZ{Name: "Europe/Berlin"}
+	offset := TZ{Offset: -(4*time.Hour + 30*time.Minute)}
+
+	cases := []struct {
+		tz      TZ
+		version string
+		exp     string
+	}{
+		{named, "4.0", ":Europe/Berlin"},
+		{named, "3.0", ";VALUE=text:Europe/Berlin"},
+		{offset, "4.0", ";VALUE=utc-offset:-0430"},
+		{offset, "3.0", ":-04:30"},
+		{offset, "2.1", ":-04:30"},
+		{TZ{Name: "UTC"}, "2.1", ":+00:00"},
+	}
+	for _, c := range cases {
+		b, err := c.tz.MarshalVCardFieldVersion(c.version)
+
+		assertEq(t, err, nil)
+		assertStringsEq(t, string(b), c.exp)
+	}
+
+	_, err := TZ{Name: "Nowhere/Atlantis"}.MarshalVCardFieldVersion("2.1")
+	assertErrIs(t, err, ErrVCard, `unknown time zone "Nowhere/Atlantis"`)
+}
+
+func TestTZLocation(t *testing.T) {
+
+	winter := time.Date(2024, time.January, 15, 12, 0, 0, 0, time.UTC)
+	summer := time.Date(2024, time.July, 15, 12, 0, 0, 0, time.UTC)
+
+	tz := TZ{Name: "America/New_York"}
+
+	offset, err := tz.OffsetAt(winter)
+	assertEq(t, err, nil)
+	assertEq(t, offset, -5*time.Hour)
+
+	offset, err = tz.OffsetAt(summer)
+	assertEq(t, err, nil)
+	assertEq(t, offset, -4*time.Hour)
+
+	loc, err := TZ{Offset: 2 * time.Hour}.Location()
+	assertEq(t, err, nil)
+	assertEq(t, loc.String(), "UTC+02:00")
+	assertEq(t, winter.In(loc).Hour(), 14)
+
+	assertEq(t, NewTZ(loc), TZ{Offset: 2 * time.Hour})
+	assertEq(t, NewTZ(time.UTC), TZ{Name: "UTC"})
+
+	ny, err := tz.Location()
+	assertEq(t, err, nil)
+	assertEq(t, NewTZ(ny), tz)
+}
@@ -0,0 +1,94 @@
+package vcard
+
+import (
+	"crypto/rand"
+	"strings"
+)
+
+// Value of UID property as per https://datatracker.ietf.org/doc/html/rfc6350#section-6.7.6
+// e.g. "urn:uuid:f81d4fae-7dec-11d0-a765-00a0c91e6bf6" or free text of older versions.
+//
+// UID is a string, so it can be used as a type of struct fields like any other string.
+type UID string
+
+// Creates a UID with a new random (version 4) UUID e.g. "urn:uuid:f81d4fae-7dec-41d0-a765-00a0c91e6bf6".
+func NewUID() UID {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b) // never returns an error
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	const hex = "0123456789abcdef"
+	s := make([]byte, 0, 36)
+	for i, c := range b {
+		if i == 4 || i == 6 || i == 8 || i == 10 {
+			s = append(s, '-')
+		}
+		s = append(s, hex[c>>4], hex[c&0x0f])
+	}
+	return UID("urn:uuid:" + string(s))
+}
+
+// Returns lower-cased UUID of the UID e.g. "f81d4fae-7dec-11d0-a765-00a0c91e6bf6" of
+// "urn:uuid:F81D4FAE-7DEC-11D0-A765-00A0C91E6BF6". Bare UUIDs of free text UIDs are recognized too.
+// Returns false if the UID is not a UUID.
+func (u UID) UUID() (string, bool) {
+	s := strings.TrimSpace(string(u))
+	if len(s) >= 9 && strings.EqualFold(s[:9], "urn:uuid:") {
+		s = s[9:]
+	}
+	if !isUUID(s) {
+		return "", false
+	}
+	return strings.ToLower(s), true
+}
+
+// Reports whether the UID is a UUID either in URN form or as free text.
+func (u UID) IsUUID() bool {
+	_, ok := u.UUID()
+	return ok
+}
+
+// Returns the UID as "urn:uuid:..." URN if it is a UUID, otherwise the UID is returned as is.
+func (u UID) URN() UID {
+	if uuid, ok := u.UUID(); ok {
+		return UID("urn:uuid:" + uuid)
+	}
+	return u
+}
+
+// Reports whether UIDs identify the same object. UUIDs are compared case-insensitively
+// regardless of "urn:uuid:" prefix, so "urn:uuid:F81D4FAE-..." equals "f81d4fae-...".
+// Other UIDs are compared exactly after trimming whitespace.
+func (u UID) Equal(other UID) bool {
+	return u.Key() == other.Key()
+}
+
+// Returns a form of the UID used for comparison e.g. "f81d4fae-7dec-11d0-a765-00a0c91e6bf6" of
+// "urn:uuid:F81D4FAE-7DEC-11D0-A765-00A0C91E6BF6". UIDs which are [UID.Equal] have the same key,
+// so it is suitable for a unique column of a database or a key of a map.
+func (u UID) Key() string {
+	if uuid, ok := u.UUID(); ok {
+		return uuid
+	}
+	return strings.TrimSpace(string(u))
+}
+
+func isUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case i == 8 || i == 13 || i == 18 || i == 23:
+			if c != '-' {
+				return false
+			}
+		case c >= '0' && c <= '9', c >= 'a' && c <= 'f', c >= 'A' && c <= 'F':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,57 @@
+package vcard
+
+import "testing"
+
+func TestUID(t *testing.T) {
+
+	u := UID("urn:uuid:F81D4FAE-7DEC-11D0-A765-00A0C91E6BF6")
+
+	uuid, ok := u.UUID()
+	assertEq(t, ok, true)
+	assertStringsEq(t, uuid, "f81d4fae-7dec-11d0-a765-00a0c91e6bf6")
+
+	assertEq(t, u.Equal("f81d4fae-7dec-11d0-a765-00a0c91e6bf6"), true)
+	assertEq(t, u.Equal("urn:uuid:f81d4fae-7dec-11d0-a765-00a0c91e6bf7"), false)
+	assertStringsEq(t, string(UID("F81D4FAE-7DEC-11D0-A765-00A0C91E6BF6").URN()), "urn:uuid:f81d4fae-7dec-11d0-a765-00a0c91e6bf6")
+
+	free := UID("19950401-080045-40000F192713-0052")
+	assertEq(t, free.IsUUID(), false)
+	assertStringsEq(t, string(free.URN()), string(free))
+	assertEq(t, free.Equal(" 19950401-080045-40000F192713-0052"), true)
+	assertEq(t, free.Equal("19950401-080045-40000f192713-0052"), false)
+}
+
+func TestNewUID(t *testing.T) {
+
+	u := NewUID()
+
+	uuid, ok := u.UUID()
+	assertEq(t, ok, true)
+	assertStringsEq(t, string(u), "urn:uuid:"+uuid)
+	assertEq(t, uuid[14], byte('4'))
+	assertEq(t, NewUID() == u, false)
+}
+
+func TestUIDField(t *testing.T) {
+
+	type Contact struct {
+		FN  string
+		UID UID
+	}
+
+	text := crlfy(`BEGIN:VCARD
+VERSION:4.0
+FN:Alex
+UID:urn:uuid:f81d4fae-7dec-11d0-a765-00a0c91e6bf6
+END:VCARD
+`)
+	c := Contact{}
+	err := Unmarshal([]byte(text), &c)
+
+	assertEq(t, err, nil)
+	assertEq(t, c.UID, UID("urn:uuid:f81d4fae-7dec-11d0-a765-00a0c91e6bf6"))
+
+	b, err := Marshal(c)
+	assertEq(t, err, nil)
+	assertStringLinesEq(t, string(b), text)
+}
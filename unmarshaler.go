@@ -2,18 +2,24 @@ package vcard
 
 import (
 	"bytes"
-	"fmt"
+	"encoding"
+	"errors"
 	"io"
+	"net/url"
 	"reflect"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"unicode"
 )
 
-// Deserializes a vCard document into a Go value using default set of [Schema]s.
+// Deserializes a vCard document into a Go value using globally registered [Schema]s.
+// Initially these are [DefaultSchemas], see [RegisterSchema].
 //
 // v has to be a pointer to a slice, struct or a map.
 func Unmarshal(data []byte, v any) error {
-	return UnmarshalSchema(data, v, DefaultSchemas)
+	return UnmarshalSchema(data, v, registeredSchemas())
 }
 
 // Deserializes a vCard document into a Go value using provided set of [Schema]s.
@@ -33,6 +39,20 @@ type Decoder struct {
 	schemas map[string]Schema
 
 	smartStrings bool
+	tagKey       string
+	fallbackKey  string
+	extensions   bool
+	strictURIs   bool
+	warn         func(*ValidationError)
+	profile      Profile
+
+	mapper func(string) string
+	mapped sync.Map // schemas prepared with mapper
+
+	factories []decoderFactory
+
+	// maps version string to schema prepared by the caller of UnmarshalPrepared
+	prepared map[string]PreparedSchema
 
 	// TODO: Decoder setting to be precise about line formatting
 	// e.g. ignore spaces and newline sequence
@@ -57,6 +77,7 @@ func NewDecoder(r io.Reader, schemas []Schema) *Decoder {
 		r:            r,
 		schemas:      m,
 		smartStrings: true,
+		tagKey:       defaultTagKey,
 	}
 }
 
@@ -71,11 +92,46 @@ func (d *Decoder) SetSmartStrings(smartStrings bool) *Decoder {
 	return d
 }
 
+// Sets key of struct tags used to match fields with properties. Defaults to "vCard".
+//
+// See [Encoder.SetTagKey] for more info.
+func (d *Decoder) SetTagKey(key string) *Decoder {
+	d.tagKey = key
+	return d
+}
+
+// Sets key of struct tags used for fields without a tag of [Decoder.SetTagKey] e.g. "json".
+//
+// See [Encoder.SetFallbackTagKey] for more info.
+func (d *Decoder) SetFallbackTagKey(key string) *Decoder {
+	d.fallbackKey = key
+	d.mapped.Clear()
+	return d
+}
+
+// Sets a function which maps names of struct fields without a name in a tag to property names.
+//
+// See [Encoder.SetFieldNameMapper] for more info.
+func (d *Decoder) SetFieldNameMapper(mapper func(goField string) string) *Decoder {
+	d.mapper = mapper
+	d.mapped.Clear()
+	return d
+}
+
+// Toggles strict decoding of fields of type [url.URL]. Disabled by default.
+//
+// In strict mode, values which can't be parsed as absolute URIs e.g. "example.com" result in
+// [ErrParsing]. Otherwise such values are skipped and fields are left unchanged.
+func (d *Decoder) SetStrictURIs(strict bool) *Decoder {
+	d.strictURIs = strict
+	return d
+}
+
 // Decodes a vCard document into pointer v using provided schema.
 //
 // Returns [ErrParsing] in case of a malformed vCard document recived from Writer.
 //
-// v has to be a pointer to a struct, map or a slice.
+// v has to be a pointer to a struct, map, slice or an array.
 func (d *Decoder) Decode(v any) error {
 	b, err := io.ReadAll(d.r)
 	if err != nil {
@@ -97,28 +153,60 @@ func (d *Decoder) Decode(v any) error {
 
 func (d *Decoder) decode(s string, v reflect.Value) (string, error) {
 	switch v.Kind() {
-	case reflect.Map:
-		return d.decodeMap(s, v)
-	case reflect.Struct:
-		return d.decodeStruct(s, v)
 	case reflect.Slice:
 		return d.decodeSlice(s, v)
 	case reflect.Array:
 		return d.decodeArray(s, v)
+	case reflect.Map:
+		if v.IsNil() {
+			return s, vCardErrf("decoding is only possible into not-nil map")
+		}
 	}
-	return s, vCardErrf("unable to decode into %s type. Use struct, map or a slice", v.Type())
+
+	s, err := d.decodeRecord(s, v)
+	if err != nil {
+		return s, err
+	}
+	if len(strings.TrimSpace(s)) != 0 {
+		return s, leftTokensErrf("after successfully decoding a %s", v.Kind())
+	}
+	return s, nil
 }
 
-func (d *Decoder) decodeMap(data string, ma reflect.Value) (string, error) {
-	if ma.IsNil() {
-		return data, vCardErrf("decoding is only possible into not-nil map")
+// Decodes a single record into v and returns the rest of the document.
+func (d *Decoder) decodeRecord(s string, v reflect.Value) (string, error) {
+	if v.CanAddr() && v.Kind() != reflect.Pointer {
+		if u, ok := v.Addr().Interface().(VCardUnmarshaler); ok {
+			return d.decodeUnmarshaler(s, u)
+		}
 	}
+	switch v.Kind() {
+	case reflect.Map:
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+		return d.decodeMap(s, v)
+	case reflect.Struct:
+		return d.decodeStruct(s, v)
+	case reflect.Pointer:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return d.decodeRecord(s, v.Elem())
+	case reflect.Interface:
+		return d.decodeInterface(s, v)
+	}
+	return s, vCardErrf("unable to decode into %s type. Use struct, map or a slice", v.Type())
+}
+
+// Passes raw bytes of a single record from BEGIN:VCARD to END:VCARD to u.
+func (d *Decoder) decodeUnmarshaler(data string, u VCardUnmarshaler) (string, error) {
 
 	s, err := d.decodeRecordHeader(data)
 	if err != nil {
 		return data, err
 	}
-	m, schema, s, err := d.decodeVCardFieldsIntoMap(s)
+	_, s, err = d.decodeContentLines(s)
 	if err != nil {
 		return data, err
 	}
@@ -127,13 +215,31 @@ func (d *Decoder) decodeMap(data string, ma reflect.Value) (string, error) {
 		return data, err
 	}
 
-	err = d.fillMap(ma, m, schema)
+	err = u.UnmarshalVCard([]byte(data[:len(data)-len(s)]))
+	if err != nil {
+		return data, vCardErrf("error during unmarshaling %T: %w", u, err)
+	}
+	return s, nil
+}
+
+func (d *Decoder) decodeMap(data string, ma reflect.Value) (string, error) {
+
+	s, err := d.decodeRecordHeader(data)
+	if err != nil {
+		return data, err
+	}
+	m, _, schema, s, err := d.decodeVCardFieldsIntoMap(s)
+	if err != nil {
+		return data, err
+	}
+	s, err = d.decodeRecordFooter(s)
 	if err != nil {
 		return data, err
 	}
 
-	if len(strings.TrimSpace(s)) != 0 {
-		return s, leftTokensErrf("after successfully decoding a struct")
+	err = d.fillMap(ma, m, schema)
+	if err != nil {
+		return data, err
 	}
 
 	return s, nil
@@ -148,8 +254,7 @@ func (d *Decoder) fillMap(ma reflect.Value, m map[string]string, schema Schema)
 
 	elem := ma.Type().Elem()
 
-	switch elem.Kind() {
-	case reflect.String:
+	if elem.Kind() == reflect.String {
 		newMap := make(map[string]string, len(schema.fields))
 
 		for req := range schema.fields {
@@ -160,59 +265,45 @@ func (d *Decoder) fillMap(ma reflect.Value, m map[string]string, schema Schema)
 			newMap[req] = v
 		}
 		ma.Set(reflect.ValueOf(newMap))
+		return nil
+	}
 
-	case reflect.Struct:
-		if !elem.Implements(reflect.TypeFor[VCardFieldUnmarshaler]()) {
+	if !decodableType(elem) {
+		if elem.Kind() == reflect.Struct {
 			return vCardErrf("unable to decode into a map where value has type %s that does not implement VCardFieldUnmarshaler", elem)
 		}
+		return vCardErrf("unable to decode into a map where value has unsupported type %s. Use string or struct that implements VCardFieldUnmarshaler", elem)
+	}
 
-		for field := range schema.fields {
-			v, found := m[field]
-			if !found {
-				continue
-			}
-
-			value := reflect.Zero(elem)
-			i := value.Interface().(VCardFieldUnmarshaler)
-
-			err := i.UnmarshalVCardField([]byte(v))
-			if err != nil {
-				return vCardErrf("error while unmarshaling a value for a key %q: %w", field, err)
-			}
-			ma.SetMapIndex(reflect.ValueOf(field), value)
+	for field := range schema.fields {
+		v, found := m[field]
+		if !found {
+			continue
 		}
 
-	case reflect.Interface:
-		for field := range schema.fields {
-			v, found := m[field]
-			if !found {
-				continue
-			}
-
-			value := reflect.Zero(elem)
-			i, ok := value.Interface().(VCardFieldUnmarshaler)
-			if !ok {
-				return vCardErrf("unable to decode a value for a map key %q because it has type %s which does not implement VCardFieldUnmarshaler", key, elem)
-			}
-
-			err := i.UnmarshalVCardField([]byte(v))
-			if err != nil {
-				return vCardErrf("error while unmarshaling a value for a key %q: %w", field, err)
-			}
-			ma.SetMapIndex(reflect.ValueOf(field), value)
+		value := reflect.New(elem).Elem()
+		ok, err := d.unmarshalValue(value, v)
+		if err != nil {
+			return vCardErrf("error while unmarshaling a value for a key %q: %w", field, err)
 		}
+		if !ok {
+			return vCardErrf("unable to decode a value for a map key %q because it has type %s which does not implement VCardFieldUnmarshaler", field, elem)
+		}
+		ma.SetMapIndex(reflect.ValueOf(field), value)
 	}
-
-	return vCardErrf("unable to decode into a map where value has unsupported type %s. Use string or struct that implements VCardFieldUnmarshaler", key)
+	return nil
 }
 
 func (d *Decoder) decodeStruct(data string, struc reflect.Value) (string, error) {
+	if struc.Type() == cardType {
+		return d.decodeCard(data, struc)
+	}
 
 	s, err := d.decodeRecordHeader(data)
 	if err != nil {
 		return data, err
 	}
-	m, schema, s, err := d.decodeVCardFieldsIntoMap(s)
+	m, props, schema, s, err := d.decodeVCardFieldsIntoMap(s)
 	if err != nil {
 		return data, err
 	}
@@ -221,157 +312,645 @@ func (d *Decoder) decodeStruct(data string, struc reflect.Value) (string, error)
 		return data, err
 	}
 
-	err = d.fillStruct(struc, m, schema)
+	err = d.fillStruct(struc, m, props, schema)
 	if err != nil {
 		return data, err
 	}
 
-	if len(strings.TrimSpace(s)) != 0 {
-		return s, leftTokensErrf("after successfully decoding a struct")
+	return s, nil
+}
+
+func (d *Decoder) decodeCard(data string, card reflect.Value) (string, error) {
+
+	c, s, err := d.decodeCardRecord(data)
+	if err != nil {
+		return data, err
 	}
+	card.Set(reflect.ValueOf(c))
 
 	return s, nil
 }
 
-func (d *Decoder) fillStruct(struc reflect.Value, m map[string]string, schema Schema) error {
+func (d *Decoder) decodeCardRecord(data string) (Card, string, error) {
 
-	for req := range schema.requiredFields {
-		matches := false
-		for i := range struc.NumField() {
+	s, err := d.decodeRecordHeader(data)
+	if err != nil {
+		return Card{}, data, err
+	}
+	props, s, err := d.decodeContentLines(s)
+	if err != nil {
+		return Card{}, data, err
+	}
+	s, err = d.decodeRecordFooter(s)
+	if err != nil {
+		return Card{}, data, err
+	}
 
-			field := struc.Type().Field(i)
-			vCardName := field.Name
+	if d.profile.importCard != nil {
+		c, err := d.profile.Import(Card{props: props})
+		return c, s, err
+	}
+	return Card{props: props}, s, nil
+}
 
-			tag := field.Tag.Get("vCard")
-			if tag != "" {
-				vCardName = tag
-			}
-			if req == vCardName {
-				matches = true
-			}
-		}
+func (d *Decoder) fillStruct(struc reflect.Value, m map[string]string, props []Property, schema Schema) error {
 
-		if !matches {
-			return vCardErrf("struct %s does not contain a field %q or field tagged `vCard:\"%s\"` required by the schema", struc.Type(), req, req)
-		}
+	p, found := d.prepared[schema.version]
+	if d.mapper != nil {
+		p = schema.prepareMapped(struc.Type(), d.tagKey, d.fallbackKey, d.mapper, &d.mapped)
+	} else if !found || p.typ != struc.Type() || p.tagKey != d.tagKey || p.fallbackTagKey != d.fallbackKey {
+		p = schema.prepareTags(struc.Type(), d.tagKey, d.fallbackKey)
+	}
+	if p.missing != "" {
+		return vCardErrf("struct %s does not contain a field %q or field tagged `vCard:\"%s\"` required by the schema", struc.Type(), p.missing, p.missing)
 	}
 
-	for i := range struc.NumField() {
-		field := struc.Type().Field(i)
-		fieldValue := struc.Field(i)
+	// Indices of properties decoded into fields, the rest field gets every other property
+	consumed := map[int]bool{}
 
-		vCardName := field.Name
+	for _, field := range p.fields {
+		fieldValue := struc.Field(field.index)
 
-		tag := field.Tag.Get("vCard")
-		if tag != "" {
-			vCardName = tag
+		key := field.name
+		serField, found := m[key]
+		for _, alias := range field.aliases {
+			if found {
+				break
+			}
+			key = alias
+			serField, found = m[alias]
 		}
-
-		_, found := schema.fields[vCardName]
-		if !found {
-			continue
+		consumedIdx := -1
+		if found {
+			consumedIdx = decodedIndex(props, key, schema)
+		}
+		if field.typ != "" {
+			serField, consumedIdx, found = propertyOfType(props, field.name, field.typ)
+		}
+		if consumedIdx >= 0 {
+			consumed[consumedIdx] = true
+		}
+		if !found && field.def != "" {
+			serField, found = ":"+field.def, true
 		}
-		serField, found := m[vCardName]
 		if !found {
+			if field.required {
+				return parsingErrf("document does not contain a field %q required by field %q %sof struct %s", field.name, field.goName, field.taggedMsg, struc.Type())
+			}
 			continue
 		}
 
 		// Everything is alright, we need to decode this field into v
 		if !fieldValue.CanSet() {
-			return vCardErrf("unable to set a field %q of struct %s for unexpected reason", field.Name, fieldValue.Type())
-		}
-		taggedMsg := ""
-		if tag != "" {
-			taggedMsg = fmt.Sprintf("tagged `vCard:\"%s\"` ", tag)
-		}
-
-		switch field.Type.Kind() {
-		case reflect.String:
-			if !d.smartStrings {
-				fieldValue.SetString(serField)
-			} else {
-				if serField[0] == ':' {
-					fieldValue.SetString(serField[1:])
-				} else {
-					fieldValue.SetString(serField)
-				}
-			}
-		case reflect.Struct, reflect.Interface:
-			v, ok := fieldValue.Interface().(VCardFieldUnmarshaler)
-			if !ok {
-				return vCardErrf("field %q %sof type %s has type %s which does not implement VCardFieldUnmarshaler", field.Name, taggedMsg, struc.Type(), fieldValue.Type())
-			}
-			err := v.UnmarshalVCardField([]byte(serField))
-			if err != nil {
-				return vCardErrf("error during unmarshaling field %q %sof struct %s: %w", field.Name, taggedMsg, struc.Type(), err)
+			return vCardErrf("unable to set a field %q of struct %s for unexpected reason", field.goName, fieldValue.Type())
+		}
+		taggedMsg := field.taggedMsg
+
+		ok, err := d.unmarshalValue(fieldValue, serField)
+		if err != nil {
+			return vCardErrf("error during unmarshaling field %q %sof struct %s: %w", field.goName, taggedMsg, struc.Type(), err)
+		}
+		if !ok {
+			switch fieldValue.Kind() {
+			case reflect.Struct, reflect.Interface:
+				return vCardErrf("field %q %sof type %s has type %s which does not implement VCardFieldUnmarshaler", field.goName, taggedMsg, struc.Type(), fieldValue.Type())
+			default:
+				return vCardErrf("field %q %sof type %s has unsupported type %s. Use string or struct that implements VCardFieldUnmarshaler", field.goName, taggedMsg, struc.Type(), fieldValue.Type())
 			}
-		default:
-			return vCardErrf("field %q %sof type %shas unsupported type %s. Use string or struct that implements VCardFieldUnmarshaler", field.Name, taggedMsg, struc.Type(), field.Type)
 		}
 	}
 
+	if p.rest >= 0 {
+		return fillRest(struc.Field(p.rest), props, consumed)
+	}
 	return nil
 }
 
-func (d *Decoder) decodeVCardFieldsIntoMap(s string) (map[string]string, Schema, string, error) {
+// Returns rest and index of the last property with a given name and TYPE parameter. The type is
+// removed from the rest e.g. ";TYPE=CELL,VOICE:555" becomes ";TYPE=VOICE:555" for type CELL.
+func propertyOfType(props []Property, name string, typ string) (string, int, bool) {
+	rest, index, found := "", -1, false
 
-	m := make(map[string]string)
-	offset := 0
+	for i, p := range props {
+		if p.Name != name {
+			continue
+		}
+		params := strings.Builder{}
+		matches := false
 
-	for line := range strings.Lines(s) {
-		trimmed := strings.TrimSpace(line)
-		if trimmed == expectedFooter {
-			break
+		for _, param := range splitParams(p.Params.String()) {
+			k, v, hasValue := strings.Cut(param, "=")
+
+			// vCard 2.1 allows types without a parameter name e.g. TEL;CELL:555
+			if !hasValue && strings.EqualFold(k, typ) {
+				matches = true
+				continue
+			}
+			if !strings.EqualFold(k, "TYPE") {
+				params.WriteString(";" + param)
+				continue
+			}
+			types := []string{}
+			for t := range strings.SplitSeq(unquoteParamValue(v), ",") {
+				if strings.EqualFold(t, typ) {
+					matches = true
+					continue
+				}
+				types = append(types, t)
+			}
+			if len(types) > 0 {
+				params.WriteString(";TYPE=" + strings.Join(types, ","))
+			}
 		}
-		offset += len(line)
+		if matches {
+			rest, index, found = params.String()+":"+p.Value, i, true
+		}
+	}
+	return rest, index, found
+}
 
-		parseErr := parsingErrf("unable to decode line %q. Should have format %q", line, "KEY:VALUE\r\n")
+// Decodes rest of a content line e.g. ":Alex" or ";TYPE=CELL:555" into v which has to be settable.
+//
+// Values are decoded using [VCardFieldUnmarshaler], then [encoding.TextUnmarshaler] and then by kind.
+// Nil pointers are allocated. ok is false if type of v is not supported.
+func (d *Decoder) unmarshalValue(v reflect.Value, rest string) (ok bool, err error) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return d.unmarshalValue(v.Elem(), rest)
+	case reflect.Interface:
+		// Only non-nil pointers stored in an interface can be decoded into
+		if v.IsNil() || v.Elem().Kind() != reflect.Pointer {
+			return false, nil
+		}
+		return d.unmarshalValue(v.Elem(), rest)
+	}
 
-		idx := strings.IndexFunc(trimmed, func(r rune) bool {
-			return !unicode.IsLetter(r)
-		})
-		if idx == -1 {
-			return m, Schema{}, s, parseErr
+	if v.Type() == timeType {
+		_, value, _ := splitParamsValue(rest)
+		t, err := parseTime(value)
+		if err != nil {
+			return true, err
 		}
+		v.Set(reflect.ValueOf(t))
+		return true, nil
+	}
+	if v.Type() == urlType {
+		_, value, _ := splitParamsValue(rest)
+		u, err := url.Parse(value)
+		if err == nil && d.strictURIs && !u.IsAbs() {
+			err = errors.New("URI has no scheme")
+		}
+		if err != nil {
+			if d.strictURIs {
+				return true, parsingErrf("invalid URI %q: %w", value, err)
+			}
+			return true, nil
+		}
+		v.Set(reflect.ValueOf(*u))
+		return true, nil
+	}
+	if u, found := asInterface[VCardFieldUnmarshaler](v); found {
+		return true, u.UnmarshalVCardField([]byte(rest))
+	}
+	if u, found := asInterface[encoding.TextUnmarshaler](v); found {
+		_, value, _ := splitParamsValue(rest)
+		return true, u.UnmarshalText([]byte(value))
+	}
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.String {
+		_, value, _ := splitParamsValue(rest)
+		list := splitTextList(value)
+		v.Set(reflect.MakeSlice(v.Type(), len(list), len(list)))
+		for i, s := range list {
+			v.Index(i).SetString(s)
+		}
+		return true, nil
+	}
+	if isScalarKind(v.Kind()) {
+		_, value, _ := splitParamsValue(rest)
+		return true, unmarshalScalar(v, strings.TrimSpace(value))
+	}
+	if v.Kind() == reflect.String {
+		if d.smartStrings && rest != "" && rest[0] == ':' {
+			rest = rest[1:]
+		}
+		v.SetString(rest)
+		return true, nil
+	}
+	return false, nil
+}
 
-		key := trimmed[:idx]
-		value := trimmed[idx:]
+// Reports whether values of type t may be supported by [Decoder.unmarshalValue].
+func decodableType(t reflect.Type) bool {
+	switch {
+	case reflect.PointerTo(t).Implements(reflect.TypeFor[VCardFieldUnmarshaler]()),
+		reflect.PointerTo(t).Implements(reflect.TypeFor[encoding.TextUnmarshaler]()),
+		t == urlType, t == timeType:
+		return true
+	case t.Kind() == reflect.Pointer:
+		return decodableType(t.Elem())
+	case t.Kind() == reflect.Slice:
+		return t.Elem().Kind() == reflect.String
+	}
+	return t.Kind() == reflect.String || isScalarKind(t.Kind())
+}
 
-		if key == "" || value == "" {
-			return m, Schema{}, s, parseErr
+// Decodes a number or a bool into v with strconv. See [isScalarKind].
+func unmarshalScalar(v reflect.Value, value string) error {
+	var err error
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var n int64
+		n, err = strconv.ParseInt(value, 10, v.Type().Bits())
+		if err == nil {
+			v.SetInt(n)
 		}
-		m[key] = value
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var n uint64
+		n, err = strconv.ParseUint(value, 10, v.Type().Bits())
+		if err == nil {
+			v.SetUint(n)
+		}
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		f, err = strconv.ParseFloat(value, v.Type().Bits())
+		if err == nil {
+			v.SetFloat(f)
+		}
+	case reflect.Bool:
+		var b bool
+		b, err = strconv.ParseBool(value)
+		if err == nil {
+			v.SetBool(b)
+		}
+	}
+	if err != nil {
+		return parsingErrf("unable to decode %q as %s: %w", value, v.Type(), err)
 	}
+	return nil
+}
+
+func (d *Decoder) decodeVCardFieldsIntoMap(s string) (map[string]string, []Property, Schema, string, error) {
+
+	m := make(map[string]string)
 
-	s = s[offset:]
+	props, s, err := d.decodeContentLines(s)
+	if err != nil {
+		return m, props, Schema{}, s, err
+	}
+	depth := 0
+	for _, p := range props {
+		// Properties of nested records e.g. AGENT of vCard 2.1 are not fields of the record
+		switch {
+		case p.Name == "BEGIN":
+			depth++
+		case p.Name == "END":
+			depth--
+		case depth == 0:
+			m[p.Name] = p.rest()
+		}
+	}
 
 	ver, found := m["VERSION"]
 	if !found {
-		return m, Schema{}, s, parsingErrf("field %q was not found", "VERSION")
+		return m, props, Schema{}, s, parsingErrf("field %q was not found", "VERSION")
 	}
 	ver = ver[1:]
 
-	schema, found := d.schemas[ver]
+	schema, found := d.schemaFor(ver)
 	if !found {
-		return m, Schema{}, s, parsingErrf("schema for version %q was not provided to Decoder", ver)
+		return m, props, Schema{}, s, parsingErrf("schema for version %q was not provided to Decoder", ver)
+	}
+
+	for name, aliases := range schema.aliases {
+		for _, alias := range aliases {
+			if _, found := m[name]; found {
+				break
+			}
+			if v, found := m[alias]; found {
+				m[name] = v
+			}
+		}
+	}
+
+	for name, def := range schema.defaults {
+		if _, found := m[name]; !found {
+			m[name] = ":" + def
+		}
 	}
 
-	for req := range schema.requiredFields {
+	for _, req := range schema.derive().required {
 		_, found := m[req]
 		if !found {
-			return m, schema, s, parsingErrf("document does not contain a field %q required by the schema", req)
+			return m, props, schema, s, parsingErrf("document does not contain a field %q required by the schema", req)
 		}
 	}
 
-	return m, schema, s, nil
+	err = d.checkProperties(schema, props)
+	if err != nil {
+		return m, props, schema, s, err
+	}
+
+	return m, props, schema, s, nil
 }
 
+// Checks decoded properties against cardinalities, parameters and validators defined by the schema.
+func (d *Decoder) checkProperties(s Schema, props []Property) error {
+	err := s.checkCardinality(propertyNames(props))
+	if err != nil {
+		return err
+	}
+	for _, p := range props {
+		err := s.checkParams(p.Name, p.Params.String())
+		if err != nil {
+			return err
+		}
+		err = reportViolation(d.warn, s.validate(p.Name, p.Value))
+		if err != nil {
+			return err
+		}
+	}
+	return s.checkConstraints(func(name string) []string {
+		values := []string{}
+		for _, p := range props {
+			if p.Name == canonicalPropertyName(name) {
+				values = append(values, p.Value)
+			}
+		}
+		return values
+	})
+}
+
+// Reads content lines of a single record up to END:VCARD. Folded lines and soft line breaks
+// of QUOTED-PRINTABLE values of vCard 2.1 are joined together. Records nested into the record
+// e.g. AGENT of vCard 2.1 are kept as properties from their BEGIN:VCARD to END:VCARD.
+//
+// Returns the rest of s starting at END:VCARD.
+func (d *Decoder) decodeContentLines(s string) ([]Property, string, error) {
+
+	props := []Property{}
+	offset := 0
+	depth := 0
+
+	// Logical line which may consist of multiple folded physical lines
+	unfolded := ""
+	flush := func() error {
+		if unfolded == "" {
+			return nil
+		}
+		p, err := ParseProperty(unfolded)
+		if err != nil {
+			return err
+		}
+		props = append(props, p)
+		unfolded = ""
+		return nil
+	}
+
+	for line := range strings.Lines(s) {
+		content := strings.TrimRight(line, "\r\n")
+
+		if strings.HasSuffix(unfolded, "=") && isQuotedPrintable(unfolded) {
+			offset += len(line)
+			unfolded = unfolded[:len(unfolded)-1] + content
+			continue
+		}
+		if content != "" && (content[0] == ' ' || content[0] == '\t') {
+			offset += len(line)
+			unfolded += content[1:]
+			continue
+		}
+		switch trimmed := strings.TrimSpace(content); {
+		case strings.EqualFold(trimmed, expectedFooter) && depth == 0:
+			err := flush()
+			if err != nil {
+				return props, s, err
+			}
+			return props, s[offset:], nil
+		case strings.EqualFold(trimmed, expectedFooter):
+			depth--
+		case strings.EqualFold(trimmed, expectedHeader):
+			depth++
+		}
+		offset += len(line)
+
+		err := flush()
+		if err != nil {
+			return props, s, err
+		}
+		unfolded = strings.TrimSpace(content)
+	}
+	err := flush()
+	if err != nil {
+		return props, s, err
+	}
+
+	return props, s[offset:], nil
+}
+
+// Reports whether a content line has ENCODING=QUOTED-PRINTABLE or QUOTED-PRINTABLE parameter of vCard 2.1.
+func isQuotedPrintable(line string) bool {
+	nameEnd := strings.IndexAny(line, ";:")
+	if nameEnd < 0 || line[nameEnd] == ':' {
+		return false
+	}
+	params, _, _ := splitParamsValue(line[nameEnd:])
+	return slices.ContainsFunc(parseParams(params), func(p Param) bool {
+		return strings.EqualFold(p.Name, "QUOTED-PRINTABLE") ||
+			strings.EqualFold(p.Name, "ENCODING") && slices.ContainsFunc(p.Values, func(v string) bool {
+				return strings.EqualFold(v, "QUOTED-PRINTABLE")
+			})
+	})
+}
+
+// Parses unfolded content line of a form "[group.]name[;param=value...]:value"
+// as per https://datatracker.ietf.org/doc/html/rfc6350#section-3.3
+//
+// Returns [ErrParsing] if the line is malformed. This is the tokenizer used by [Decoder].
+func ParseProperty(line string) (Property, error) {
+
+	parseErr := parsingErrf("unable to decode line %q. Should have format %q", line, "KEY:VALUE\r\n")
+
+	nameEnd := strings.IndexAny(line, ";:")
+	if nameEnd == -1 {
+		return Property{}, parseErr
+	}
+	p := Property{}
+
+	name := line[:nameEnd]
+	if dot := strings.IndexByte(name, '.'); dot != -1 {
+		p.Group = name[:dot]
+		name = name[dot+1:]
+	}
+	if name == "" || strings.IndexFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_'
+	}) != -1 {
+		return Property{}, parseErr
+	}
+	p.Name = strings.ToUpper(name)
+
+	params, value, found := splitParamsValue(line[nameEnd:])
+	if !found {
+		return Property{}, parseErr
+	}
+	p.Params = parseParams(params)
+	p.Value = value
+
+	return p, nil
+}
+
+// Splits ";param=value...:value" at the first `:` which is not a part of a quoted parameter value.
+func splitParamsValue(s string) (string, string, bool) {
+	quoted := false
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			quoted = !quoted
+		case ':':
+			if !quoted {
+				return s[:i], s[i+1:], true
+			}
+		}
+	}
+	return s, "", false
+}
+
+// Decodes every record of a document into a new element of a slice.
+//
+// Like encoding/json, the slice length is reset to zero before elements are appended.
 func (d *Decoder) decodeSlice(s string, v reflect.Value) (string, error) {
-	panic("TODO: decodeSlice")
+	v.SetLen(0)
+	elemType := v.Type().Elem()
+
+	for i := 0; strings.TrimSpace(s) != ""; i++ {
+		elem := reflect.New(elemType).Elem()
+
+		rest, err := d.decodeRecord(strings.TrimLeft(s, " \t\r\n"), elem)
+		if err != nil {
+			return s, vCardErrf("error during unmarshaling slice member idx=%v: %w", i, err)
+		}
+		v.Set(reflect.Append(v, elem))
+		s = rest
+	}
+	return s, nil
+}
+
+// Decodes a record into an interface using a factory registered with [Decoder.RegisterFactory].
+func (d *Decoder) decodeInterface(data string, v reflect.Value) (string, error) {
+
+	s, err := d.decodeRecordHeader(data)
+	if err != nil {
+		return data, err
+	}
+	props, _, err := d.decodeContentLines(s)
+	if err != nil {
+		return data, err
+	}
+
+	factory := d.factoryFor(Card{props: props})
+	if factory == nil {
+		return data, vCardErrf("unable to decode into %s because no factory matches the record. Use Decoder.RegisterFactory", v.Type())
+	}
+
+	target := reflect.ValueOf(factory())
+	if !target.IsValid() || (target.Kind() == reflect.Pointer || target.Kind() == reflect.Map) && target.IsNil() {
+		return data, vCardErrf("factory returned nil for %s", v.Type())
+	}
+	if !target.Type().AssignableTo(v.Type()) {
+		return data, vCardErrf("factory returned %s which is not assignable to %s", target.Type(), v.Type())
+	}
+
+	// Factory may return a struct value which is not addressable, decode into its copy instead
+	if target.Kind() != reflect.Pointer && target.Kind() != reflect.Map {
+		ptr := reflect.New(target.Type())
+		ptr.Elem().Set(target)
+		target = ptr.Elem()
+	}
+
+	s, err = d.decodeRecord(data, target)
+	if err != nil {
+		return data, err
+	}
+	v.Set(target)
+
+	return s, nil
+}
+
+type decoderFactory struct {
+	property string
+	value    string
+	factory  func() any
+}
+
+// Registers a factory used to decode a record into an interface e.g. an element of []ContactLike.
+//
+// Factory is used for records where the value of property equals value (case-insensitive).
+// Factories are checked in order of registration and the first match wins. Empty property
+// registers a fallback factory used when nothing else matches.
+//
+// Factory has to return a pointer to a new value (or a map) which is assignable to the interface.
+//
+//	dec.RegisterFactory("KIND", "org", func() any { return &Org{} }).
+//		RegisterFactory("", "", func() any { return &Person{} })
+func (d *Decoder) RegisterFactory(property string, value string, factory func() any) *Decoder {
+	d.factories = append(d.factories, decoderFactory{strings.ToUpper(property), value, factory})
+	return d
 }
 
+// Registers a factory for records of a given KIND e.g. "individual", "group" or "org".
+//
+// Records without KIND property are treated as "individual" as per
+// https://datatracker.ietf.org/doc/html/rfc6350#section-6.1.4
+func (d *Decoder) RegisterKind(kind string, factory func() any) *Decoder {
+	return d.RegisterFactory("KIND", kind, factory)
+}
+
+func (d *Decoder) factoryFor(card Card) func() any {
+	var fallback func() any
+
+	for _, f := range d.factories {
+		if f.property == "" {
+			if fallback == nil {
+				fallback = f.factory
+			}
+			continue
+		}
+		value, found := card.Get(f.property)
+		if !found && f.property == "KIND" {
+			value, found = "individual", true
+		}
+		if found && strings.EqualFold(value, f.value) {
+			return f.factory
+		}
+	}
+	return fallback
+}
+
+// Decodes records of a document into elements of an array in order.
+//
+// Like encoding/json, elements without a record are set to zero values. Unlike encoding/json,
+// records which don't fit into the array result in [ErrLeftoverTokens].
 func (d *Decoder) decodeArray(s string, v reflect.Value) (string, error) {
-	panic("TODO: decodeArray")
+	i := 0
+	for ; i < v.Len() && strings.TrimSpace(s) != ""; i++ {
+		elem := reflect.New(v.Type().Elem()).Elem()
+
+		rest, err := d.decodeRecord(strings.TrimLeft(s, " \t\r\n"), elem)
+		if err != nil {
+			return s, vCardErrf("error during unmarshaling array member idx=%v: %w", i, err)
+		}
+		v.Index(i).Set(elem)
+		s = rest
+	}
+	for ; i < v.Len(); i++ {
+		v.Index(i).SetZero()
+	}
+	if len(strings.TrimSpace(s)) != 0 {
+		return s, leftTokensErrf("after successfully decoding an array of length %v", v.Len())
+	}
+	return s, nil
 }
 
 const expectedHeader = "BEGIN:VCARD"
@@ -383,7 +962,7 @@ func (d *Decoder) decodeRecordHeader(s string) (string, error) {
 
 	lineLen := 0
 	for line := range strings.Lines(s) {
-		if strings.TrimSpace(line) != expectedHeader {
+		if !strings.EqualFold(strings.TrimSpace(line), expectedHeader) {
 			return s, parsingErrf("expected %q but found %q", expectedHeader, line)
 		}
 		lineLen = len(line)
@@ -402,7 +981,7 @@ func (d *Decoder) decodeRecordFooter(s string) (string, error) {
 
 	lineLen := 0
 	for line := range strings.Lines(s) {
-		if strings.TrimSpace(line) != expectedFooter {
+		if !strings.EqualFold(strings.TrimSpace(line), expectedFooter) {
 			return s, parsingErrf("expected %q but found %q", expectedFooter, line)
 		}
 		lineLen = len(line)
@@ -445,6 +1024,16 @@ func (d *Decoder) decodeRecordFooter(s string) (string, error) {
 //
 //			return nil
 //		}
+//
+// See [Tel] for a complete implementation.
 type VCardFieldUnmarshaler interface {
 	UnmarshalVCardField(data []byte) error
 }
+
+// Implemented by types that need custom Unmarshaling logic for an entire card.
+//
+// UnmarshalVCard receives a complete record from BEGIN:VCARD to END:VCARD.
+// See [VCardMarshaler].
+type VCardUnmarshaler interface {
+	UnmarshalVCard(data []byte) error
+}
@@ -1,6 +1,11 @@
 package vcard
 
-import "testing"
+import (
+	"bytes"
+	"net/url"
+	"testing"
+	"time"
+)
 
 func TestDecEmptyStruct(t *testing.T) {
 
@@ -88,4 +93,314 @@ END:VCARD
 	}
 
 	assertMapsEq(t, s, exp)
-}
\ No newline at end of file
+}
+func TestDecSliceOfStructs(t *testing.T) {
+
+	text := `BEGIN:VCARD
+VERSION:4.0
+FN:Alex 1
+END:VCARD
+
+BEGIN:VCARD
+VERSION:4.0
+FN:Alex 2
+END:VCARD
+`
+	s := []StringUser{{N: "Stale"}}
+	err := Unmarshal([]byte(text), &s)
+
+	assertEq(t, err, nil)
+	assertSlicesEq(t, s, []StringUser{{FN: "Alex 1"}, {FN: "Alex 2"}})
+}
+
+func TestDecSliceOfCards(t *testing.T) {
+
+	text := `BEGIN:VCARD
+VERSION:3.0
+N:;Alex;;;
+FN:Alex 1
+END:VCARD
+BEGIN:VCARD
+VERSION:4.0
+FN:Alex 2
+END:VCARD
+`
+	cards := []*Card{}
+	err := Unmarshal([]byte(text), &cards)
+
+	assertEq(t, err, nil)
+	assertEq(t, len(cards), 2)
+	assertEq(t, cards[0].Version(), "3.0")
+	assertEq(t, cards[1].Version(), "4.0")
+}
+
+func TestDecSliceMemberError(t *testing.T) {
+
+	text := `BEGIN:VCARD
+VERSION:4.0
+FN:Alex 1
+END:VCARD
+BEGIN:VCARD
+VERSION:4.0
+END:VCARD
+`
+	s := []StringUser{}
+	err := Unmarshal([]byte(text), &s)
+
+	assertErrIs(t, err, ErrParsing, "slice member idx=1")
+}
+
+func TestDecArray(t *testing.T) {
+
+	text := `BEGIN:VCARD
+VERSION:4.0
+FN:Alex 1
+END:VCARD
+BEGIN:VCARD
+VERSION:4.0
+FN:Alex 2
+END:VCARD
+`
+	a := [3]StringUser{{N: "Stale"}, {N: "Stale"}, {N: "Stale"}}
+	err := Unmarshal([]byte(text), &a)
+
+	assertEq(t, err, nil)
+	assertEq(t, a, [3]StringUser{{FN: "Alex 1"}, {FN: "Alex 2"}, {}})
+
+	short := [1]StringUser{}
+	err = Unmarshal([]byte(text), &short)
+
+	assertErrIs(t, err, ErrLeftoverTokens, "array of length 1")
+}
+
+type ContactLike interface {
+	DisplayName() string
+}
+
+type Person struct {
+	FN string
+}
+
+func (p *Person) DisplayName() string { return p.FN }
+
+type Organization struct {
+	FN  string
+	ORG string
+}
+
+func (o *Organization) DisplayName() string { return o.ORG }
+
+func TestDecInterfaceByKind(t *testing.T) {
+
+	text := `BEGIN:VCARD
+VERSION:4.0
+FN:Alex
+END:VCARD
+BEGIN:VCARD
+VERSION:4.0
+KIND:org
+FN:Example
+ORG:Example Company
+END:VCARD
+`
+	contacts := []ContactLike{}
+
+	dec := NewDecoder(bytes.NewReader([]byte(text)), DefaultSchemas).
+		RegisterKind("individual", func() any { return &Person{} }).
+		RegisterKind("org", func() any { return &Organization{} })
+
+	err := dec.Decode(&contacts)
+
+	assertEq(t, err, nil)
+	assertEq(t, len(contacts), 2)
+	assertEq(t, contacts[0].DisplayName(), "Alex")
+	assertEq(t, contacts[1].DisplayName(), "Example Company")
+
+	_, isOrg := contacts[1].(*Organization)
+	assertEq(t, isOrg, true)
+}
+
+func TestDecInterfaceFallbackAndMissingFactory(t *testing.T) {
+
+	text := `BEGIN:VCARD
+VERSION:4.0
+KIND:group
+FN:Friends
+END:VCARD
+`
+	contacts := []ContactLike{}
+
+	err := NewDecoder(bytes.NewReader([]byte(text)), DefaultSchemas).
+		RegisterKind("org", func() any { return &Organization{} }).
+		Decode(&contacts)
+
+	assertErrIs(t, err, ErrVCard, "no factory matches the record")
+
+	err = NewDecoder(bytes.NewReader([]byte(text)), DefaultSchemas).
+		RegisterKind("org", func() any { return &Organization{} }).
+		RegisterFactory("", "", func() any { return &Person{} }).
+		Decode(&contacts)
+
+	assertEq(t, err, nil)
+	assertEq(t, contacts[0].DisplayName(), "Friends")
+}
+
+func TestDecInterfaceFactoryWrongType(t *testing.T) {
+
+	text := `BEGIN:VCARD
+VERSION:4.0
+FN:Alex
+END:VCARD
+`
+	contacts := []ContactLike{}
+
+	err := NewDecoder(bytes.NewReader([]byte(text)), DefaultSchemas).
+		RegisterFactory("", "", func() any { return &StringUser{} }).
+		Decode(&contacts)
+
+	assertErrIs(t, err, ErrVCard, "is not assignable to vcard.ContactLike")
+
+	err = NewDecoder(bytes.NewReader([]byte(text)), DefaultSchemas).
+		RegisterFactory("", "", func() any { var p *Person; return p }).
+		Decode(&contacts)
+
+	assertErrIs(t, err, ErrVCard, "factory returned nil for vcard.ContactLike")
+}
+
+func TestDecInterfaceFactoryReturnsNil(t *testing.T) {
+
+	text := `BEGIN:VCARD
+VERSION:4.0
+FN:Alex
+END:VCARD
+`
+	contacts := []ContactLike{}
+
+	err := NewDecoder(bytes.NewReader([]byte(text)), DefaultSchemas).
+		RegisterFactory("", "", func() any { return nil }).
+		Decode(&contacts)
+
+	assertErrIs(t, err, ErrVCard, "factory returned nil for vcard.ContactLike")
+}
+
+type RecordUnmarshalerImpl struct {
+	raw string
+}
+
+func (r *RecordUnmarshalerImpl) UnmarshalVCard(data []byte) error {
+	r.raw = string(data)
+	return nil
+}
+
+func TestDecRecordUnmarshaler(t *testing.T) {
+
+	text := `BEGIN:VCARD
+VERSION:4.0
+FN:Alex 1
+END:VCARD
+BEGIN:VCARD
+VERSION:4.0
+FN:Alex 2
+END:VCARD
+`
+	s := []RecordUnmarshalerImpl{}
+	err := Unmarshal([]byte(crlfy(text)), &s)
+
+	assertEq(t, err, nil)
+	assertEq(t, len(s), 2)
+	assertStringsEq(t, s[1].raw, "BEGIN:VCARD\r\nVERSION:4.0\r\nFN:Alex 2\r\nEND:VCARD\r\n")
+}
+
+func TestDecStructTextUnmarshaler(t *testing.T) {
+
+	text := `BEGIN:VCARD
+VERSION:4.0
+FN:Alex
+GENDER:F
+REV;VALUE=timestamp:2024-01-02T03:04:05Z
+END:VCARD
+`
+	s := TextMarshalerStruct{}
+	err := Unmarshal([]byte(crlfy(text)), &s)
+
+	assertEq(t, err, nil)
+	assertStringsEq(t, s.FN, "Alex")
+	assertEq(t, s.GENDER, textGenderFemale)
+	assertEq(t, s.REV, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	assertEq(t, s.NOTE, nil)
+}
+
+func TestDecTextUnmarshalerError(t *testing.T) {
+
+	text := `BEGIN:VCARD
+VERSION:4.0
+FN:Alex
+GENDER:X
+END:VCARD
+`
+	s := TextMarshalerStruct{}
+	err := Unmarshal([]byte(crlfy(text)), &s)
+
+	assertErrIs(t, err, ErrVCard, `unknown gender "X"`)
+}
+
+func TestDecCardinalityViolation(t *testing.T) {
+
+	text := `BEGIN:VCARD
+VERSION:4.0
+FN:Alex
+BDAY:19900101
+BDAY:19910101
+END:VCARD
+`
+	m := map[string]string{}
+	err := Unmarshal([]byte(crlfy(text)), &m)
+
+	assertErrIs(t, err, ErrValidation, `"BDAY" occurs 2 times`)
+}
+
+func TestDecParamNotAccepted(t *testing.T) {
+
+	text := `BEGIN:VCARD
+VERSION:4.0
+FN:Alex
+EMAIL;TYPE=HOME,BANANA:alex@example.com
+END:VCARD
+`
+	schema := SchemaV4.WithParams("EMAIL", ParamDef{Name: "TYPE", Values: []string{"HOME", "WORK"}})
+
+	m := map[string]string{}
+	err := UnmarshalSchema([]byte(crlfy(text)), &m, []Schema{schema})
+
+	assertErrIs(t, err, ErrValidation, `parameter TYPE of property "EMAIL"`)
+}
+
+type URLStruct struct {
+	FN     string
+	URL    url.URL
+	SOURCE *url.URL
+}
+
+func TestDecURL(t *testing.T) {
+
+	text := `BEGIN:VCARD
+VERSION:4.0
+FN:Alex
+URL:https://example.com/alex?lang=en
+SOURCE:example.com/alex.vcf
+END:VCARD
+`
+	s := URLStruct{}
+	err := Unmarshal([]byte(crlfy(text)), &s)
+
+	assertEq(t, err, nil)
+	assertStringsEq(t, s.URL.String(), "https://example.com/alex?lang=en")
+	assertStringsEq(t, s.SOURCE.String(), "example.com/alex.vcf")
+
+	s = URLStruct{}
+	err = NewDecoder(bytes.NewReader([]byte(crlfy(text))), DefaultSchemas).
+		SetStrictURIs(true).
+		Decode(&s)
+
+	assertErrIs(t, err, ErrParsing, `invalid URI "example.com/alex.vcf"`)
+}
@@ -0,0 +1,109 @@
+package vcard
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Checks a raw value of a property e.g. "alex@example.com" of EMAIL. See [Schema.WithValidators].
+type Validator func(value string) error
+
+// Property value rejected by a [Validator]. Matches [ErrValidation] and the error returned
+// by the validator with [errors.Is].
+type ValidationError struct {
+	Property string
+	Value    string
+	Err      error
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%v: property %s has invalid value %q: %v", ErrValidation, e.Property, e.Value, e.Err)
+}
+
+func (e *ValidationError) Unwrap() []error {
+	return []error{ErrValidation, e.Err}
+}
+
+// Creates a validator which accepts values matching re.
+func MatchRegexp(re *regexp.Regexp) Validator {
+	return func(value string) error {
+		if !re.MatchString(value) {
+			return fmt.Errorf("value does not match %s", re)
+		}
+		return nil
+	}
+}
+
+// Accepts syntactically valid email addresses as a sanity check of EMAIL. See [Email.Validate].
+func ValidEmail(value string) error {
+	return validEmailAddress(value)
+}
+
+// Accepts geo: URIs as per https://datatracker.ietf.org/doc/html/rfc6350#section-6.5.2
+func ValidGeoURI(value string) error {
+	if len(value) < 4 || !strings.EqualFold(value[:4], "geo:") {
+		return errors.New("value has to be a geo: URI")
+	}
+	return nil
+}
+
+// Returns a copy of the schema where values of property name are checked by validators.
+// Validators are added to ones already defined for the property. Field is added to the schema
+// if it is not there yet.
+//
+// Validators receive raw values without parameters, TEXT values are not unescaped.
+// [Encoder] and [Decoder] return [*ValidationError] for the first rejected value unless
+// a warning function is set with [Encoder.SetValidationWarnings].
+//
+//	schema := vcard.SchemaV4.
+//		WithValidators("EMAIL", vcard.ValidEmail).
+//		WithValidators("GEO", vcard.ValidGeoURI)
+func (s Schema) WithValidators(name string, validators ...Validator) Schema {
+	s = s.clone()
+
+	s.fields[name] = struct{}{}
+	key := canonicalPropertyName(name)
+	s.validators[key] = slices.Concat(s.validators[key], validators)
+
+	return s
+}
+
+// Runs validators of a property. Returns [*ValidationError] for the first rejected value.
+func (s Schema) validate(name string, value string) error {
+	for _, v := range s.validators[canonicalPropertyName(name)] {
+		err := v(value)
+		if err != nil {
+			return &ValidationError{canonicalPropertyName(name), value, err}
+		}
+	}
+	return nil
+}
+
+// Reports violation to warn function if it is set, otherwise returns err.
+func reportViolation(warn func(*ValidationError), err error) error {
+	var verr *ValidationError
+	if warn != nil && errors.As(err, &verr) {
+		warn(verr)
+		return nil
+	}
+	return err
+}
+
+// Sets a function called for every value rejected by schema validators instead of failing.
+// Nil restores default behavior of returning [*ValidationError]. See [Schema.WithValidators].
+//
+// warn has to be safe for concurrent use when parallelism is enabled.
+func (e *Encoder) SetValidationWarnings(warn func(*ValidationError)) *Encoder {
+	e.warn = warn
+	return e
+}
+
+// Sets a function called for every value rejected by schema validators instead of failing.
+// See [Encoder.SetValidationWarnings].
+func (d *Decoder) SetValidationWarnings(warn func(*ValidationError)) *Decoder {
+	d.warn = warn
+	return d
+}
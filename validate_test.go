@@ -0,0 +1,56 @@
+package vcard
+
+import (
+	"bytes"
+	"errors"
+	"regexp"
+	"testing"
+)
+
+var validatedSchema = SchemaV4.
+	WithValidators("EMAIL", ValidEmail).
+	WithValidators("GEO", ValidGeoURI).
+	WithValidators("X-EMPLOYEE-ID", MatchRegexp(regexp.MustCompile(`^E\d+$`)))
+
+func TestMarshalValidation(t *testing.T) {
+
+	m := map[string]string{"FN": "Alex", "EMAIL": "alex.example.com"}
+
+	_, err := MarshalSchema(m, validatedSchema)
+
+	assertErrIs(t, err, ErrValidation, `property EMAIL has invalid value "alex.example.com"`)
+
+	var verr *ValidationError
+	assertEq(t, errors.As(err, &verr), true)
+	assertEq(t, verr.Property, "EMAIL")
+
+	m = map[string]string{"FN": "Alex", "GEO": "geo:37.386013,-122.082932", "X-EMPLOYEE-ID": "E42"}
+	_, err = MarshalSchema(m, validatedSchema)
+	assertEq(t, err, nil)
+}
+
+func TestUnmarshalValidationWarnings(t *testing.T) {
+
+	text := `BEGIN:VCARD
+VERSION:4.0
+FN:Alex
+GEO:37.386013,-122.082932
+X-EMPLOYEE-ID:42
+END:VCARD
+`
+	warnings := []*ValidationError{}
+
+	m := map[string]string{}
+	err := NewDecoder(bytes.NewReader([]byte(crlfy(text))), []Schema{validatedSchema}).
+		SetValidationWarnings(func(err *ValidationError) { warnings = append(warnings, err) }).
+		Decode(&m)
+
+	assertEq(t, err, nil)
+	assertEq(t, len(warnings), 2)
+	assertEq(t, warnings[0].Property, "GEO")
+	assertEq(t, warnings[1].Property, "X-EMPLOYEE-ID")
+	assertStringsEq(t, m["GEO"], ":37.386013,-122.082932")
+
+	err = UnmarshalSchema([]byte(crlfy(text)), &m, []Schema{validatedSchema})
+	assertErrIs(t, err, ErrValidation, "geo: URI")
+}
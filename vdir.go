@@ -0,0 +1,146 @@
+package vcard
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Address book stored as a vdir: a directory with one .vcf file per card and optional metadata
+// files "displayname" and "color". See https://vdirsyncer.pimutils.org/en/stable/vdir.html
+type Vdir struct {
+	// Content of "displayname" file e.g. "Work". Empty if there is no such file.
+	DisplayName string
+
+	// Content of "color" file e.g. "#ff0000". Empty if there is no such file.
+	Color string
+
+	// Cards in order of file names.
+	Items []VdirItem
+}
+
+// Card stored in a file of a vdir.
+type VdirItem struct {
+	// Name of the file e.g. "urn%3Auuid%3A1234.vcf". Files created by other applications
+	// may be named differently from UIDs of their cards.
+	Name string
+
+	// Modification time of the file which vdir uses as ETag.
+	ModTime time.Time
+
+	Card Card
+}
+
+// Reads a vdir from the root of fsys e.g. os.DirFS("~/.contacts/work"). Files without .vcf extension
+// and hidden files e.g. temporary ones of writers are skipped. Write vdirs with [DirStore].
+//
+// Returns [ErrParsing] if a file contains malformed vCard and [ErrVCard] if a file contains
+// other than exactly one vCard.
+func ReadVdir(fsys fs.FS) (Vdir, error) {
+	items, err := readVdirItems(fsys)
+	if err != nil {
+		return Vdir{}, err
+	}
+	v := Vdir{Items: items}
+	for name, field := range map[string]*string{"displayname": &v.DisplayName, "color": &v.Color} {
+		b, err := fs.ReadFile(fsys, name)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return Vdir{}, vCardErrf("unable to read %s: %w", name, err)
+		}
+		*field = strings.TrimSpace(string(b))
+	}
+	return v, nil
+}
+
+func readVdirItems(fsys fs.FS) ([]VdirItem, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, vCardErrf("unable to read vdir: %w", err)
+	}
+	items := make([]VdirItem, 0, len(entries))
+	for _, e := range entries {
+		if e.IsDir() || strings.HasPrefix(e.Name(), ".") || !strings.EqualFold(path.Ext(e.Name()), ".vcf") {
+			continue
+		}
+		item, err := readVdirItem(fsys, e.Name())
+		if errors.Is(err, fs.ErrNotExist) {
+			// Deleted by another process after ReadDir
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func readVdirItem(fsys fs.FS, name string) (VdirItem, error) {
+	info, err := fs.Stat(fsys, name)
+	if err != nil {
+		return VdirItem{}, err
+	}
+	b, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return VdirItem{}, err
+	}
+	cards, err := parseCards(string(b))
+	if err != nil {
+		return VdirItem{}, vCardErrf("unable to read %s: %w", name, err)
+	}
+	if len(cards) != 1 {
+		return VdirItem{}, vCardErrf("file %s has to contain exactly one vCard, found %v", name, len(cards))
+	}
+	return VdirItem{name, info.ModTime(), cards[0]}, nil
+}
+
+// Writes "displayname" metadata file of the vdir. Empty name deletes the file.
+func (s *DirStore) SetDisplayName(name string) error {
+	return s.setMetadata("displayname", name)
+}
+
+// Writes "color" metadata file of the vdir e.g. "#ff0000". Empty color deletes the file.
+func (s *DirStore) SetColor(color string) error {
+	return s.setMetadata("color", color)
+}
+
+func (s *DirStore) setMetadata(name string, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := filepath.Join(s.dir, name)
+	if value == "" {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return vCardErrf("unable to delete %s: %w", name, err)
+		}
+		return nil
+	}
+	if err := writeFileAtomic(path, []byte(value)); err != nil {
+		return vCardErrf("unable to write %s: %w", name, err)
+	}
+	return nil
+}
+
+// Writes data into a hidden temporary file next to path and renames it to path,
+// so readers observe either the previous content or the new one.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+	}
+	return err
+}
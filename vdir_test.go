@@ -0,0 +1,69 @@
+package vcard
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestReadVdir(t *testing.T) {
+
+	modTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	fsys := fstest.MapFS{
+		"displayname":               {Data: []byte("Work\n")},
+		"color":                     {Data: []byte("#ff0000")},
+		"urn%3Auuid%3A1.vcf":        {Data: []byte("BEGIN:VCARD\r\nVERSION:4.0\r\nUID:urn:uuid:1\r\nFN:Alex\r\nEND:VCARD\r\n"), ModTime: modTime},
+		"sam.VCF":                   {Data: []byte("BEGIN:VCARD\r\nVERSION:3.0\r\nUID:2\r\nFN:Sam\r\nN:;Sam;;;\r\nEND:VCARD\r\n")},
+		".tmp-123":                  {Data: []byte("BEGIN:VCARD\r\n")},
+		".hidden.vcf":               {Data: []byte("BEGIN:VCARD\r\n")},
+		"notes.txt":                 {Data: []byte("not a card")},
+		"nested/urn%3Auuid%3A3.vcf": {Data: []byte("BEGIN:VCARD\r\n")},
+	}
+
+	v, err := ReadVdir(fsys)
+	assertEq(t, err, nil)
+	assertEq(t, v.DisplayName, "Work")
+	assertEq(t, v.Color, "#ff0000")
+	assertEq(t, len(v.Items), 2)
+	assertEq(t, v.Items[0].Name, "sam.VCF")
+	assertEq(t, v.Items[1].Name, "urn%3Auuid%3A1.vcf")
+	assertEq(t, v.Items[1].ModTime, modTime)
+	fn, _ := v.Items[1].Card.FN()
+	assertEq(t, fn, "Alex")
+
+	v, err = ReadVdir(fstest.MapFS{})
+	assertEq(t, err, nil)
+	assertEq(t, v.DisplayName, "")
+	assertEq(t, len(v.Items), 0)
+
+	_, err = ReadVdir(fstest.MapFS{"two.vcf": {Data: []byte("BEGIN:VCARD\r\nVERSION:4.0\r\nFN:A\r\nEND:VCARD\r\nBEGIN:VCARD\r\nVERSION:4.0\r\nFN:B\r\nEND:VCARD\r\n")}})
+	assertErrIs(t, err, ErrVCard, "file two.vcf has to contain exactly one vCard, found 2")
+}
+
+func TestDirStoreVdir(t *testing.T) {
+
+	s, err := NewDirStore(t.TempDir())
+	assertEq(t, err, nil)
+
+	assertEq(t, s.Put(newStoreCard("urn:uuid:1", "Alex")), nil)
+	assertEq(t, s.SetDisplayName("Work"), nil)
+	assertEq(t, s.SetColor("#00ff00"), nil)
+
+	v, err := ReadVdir(os.DirFS(s.dir))
+	assertEq(t, err, nil)
+	assertEq(t, v.DisplayName, "Work")
+	assertEq(t, v.Color, "#00ff00")
+	assertEq(t, len(v.Items), 1)
+	assertEq(t, v.Items[0].Name, "urn%3Auuid%3A1.vcf")
+
+	assertEq(t, s.SetColor(""), nil)
+	assertEq(t, s.SetColor(""), nil)
+	_, err = os.Stat(filepath.Join(s.dir, "color"))
+	assertEq(t, os.IsNotExist(err), true)
+
+	entries, err := os.ReadDir(s.dir)
+	assertEq(t, err, nil)
+	assertEq(t, len(entries), 2)
+}
@@ -0,0 +1,302 @@
+package vcard
+
+import (
+	"slices"
+	"strings"
+)
+
+// Versions of vCard specification each standard property is defined in
+// as per https://en.wikipedia.org/wiki/VCard#Properties
+//
+// Properties missing from the table e.g. X- extensions are allowed in every version.
+var propertyVersions = map[string][]string{
+	"ADR":           {"2.1", "3.0", "4.0"},
+	"AGENT":         {"2.1", "3.0"},
+	"ANNIVERSARY":   {"4.0"},
+	"BDAY":          {"2.1", "3.0", "4.0"},
+	"BEGIN":         {"2.1", "3.0", "4.0"},
+	"BIRTHPLACE":    {"4.0"},
+	"CALADRURI":     {"4.0"},
+	"CALURI":        {"4.0"},
+	"CATEGORIES":    {"3.0", "4.0"},
+	"CLASS":         {"3.0"},
+	"CLIENTPIDMAP":  {"4.0"},
+	"CONTACT-URI":   {"4.0"},
+	"DEATHDATE":     {"4.0"},
+	"DEATHPLACE":    {"4.0"},
+	"EMAIL":         {"2.1", "3.0", "4.0"},
+	"END":           {"2.1", "3.0", "4.0"},
+	"EXPERTISE":     {"4.0"},
+	"FBURL":         {"4.0"},
+	"FN":            {"2.1", "3.0", "4.0"},
+	"GENDER":        {"4.0"},
+	"GEO":           {"2.1", "3.0", "4.0"},
+	"HOBBY":         {"4.0"},
+	"IMPP":          {"3.0", "4.0"},
+	"INTEREST":      {"4.0"},
+	"KEY":           {"2.1", "3.0", "4.0"},
+	"KIND":          {"4.0"},
+	"LABEL":         {"2.1", "3.0"},
+	"LANG":          {"4.0"},
+	"LOGO":          {"2.1", "3.0", "4.0"},
+	"MAILER":        {"2.1", "3.0"},
+	"MEMBER":        {"4.0"},
+	"N":             {"2.1", "3.0", "4.0"},
+	"NAME":          {"3.0"},
+	"NICKNAME":      {"3.0", "4.0"},
+	"NOTE":          {"2.1", "3.0", "4.0"},
+	"ORG":           {"2.1", "3.0", "4.0"},
+	"ORG-DIRECTORY": {"4.0"},
+	"PHOTO":         {"2.1", "3.0", "4.0"},
+	"PRODID":        {"3.0", "4.0"},
+	"PROFILE":       {"3.0"},
+	"RELATED":       {"4.0"},
+	"REV":           {"2.1", "3.0", "4.0"},
+	"ROLE":          {"2.1", "3.0", "4.0"},
+	"SORT-STRING":   {"3.0"},
+	"SOUND":         {"2.1", "3.0", "4.0"},
+	"SOURCE":        {"3.0", "4.0"},
+	"TEL":           {"2.1", "3.0", "4.0"},
+	"TITLE":         {"2.1", "3.0", "4.0"},
+	"TZ":            {"2.1", "3.0", "4.0"},
+	"UID":           {"2.1", "3.0", "4.0"},
+	"URL":           {"2.1", "3.0", "4.0"},
+	"VERSION":       {"2.1", "3.0", "4.0"},
+	"XML":           {"4.0"},
+}
+
+// vCard 4.0 properties which have a widely recognized X- equivalent in older versions.
+var legacyPropertyNames = map[string]string{
+	"ANNIVERSARY": "X-ANNIVERSARY",
+	"GENDER":      "X-GENDER",
+	"KIND":        "X-ADDRESSBOOKSERVER-KIND",
+	"MEMBER":      "X-ADDRESSBOOKSERVER-MEMBER",
+}
+
+// Reports whether property name is defined in a given version of vCard specification.
+// Name may contain a group e.g. "item1.TEL". Unknown properties and versions are always allowed.
+func propertyDefinedIn(name string, version string) bool {
+	versions, found := propertyVersions[canonicalPropertyName(name)]
+	if !found || !slices.Contains([]string{"2.1", "3.0", "4.0"}, version) {
+		return true
+	}
+	return slices.Contains(versions, version)
+}
+
+// Strips a group and normalizes property name e.g. "item1.sort_string" -> "SORT-STRING".
+// Underscore is accepted because Go identifiers cannot contain `-`.
+func canonicalPropertyName(name string) string {
+	if dot := strings.IndexByte(name, '.'); dot != -1 {
+		name = name[dot+1:]
+	}
+	return strings.ReplaceAll(strings.ToUpper(name), "_", "-")
+}
+
+// Defines what [Encoder] does with properties which are not defined in the version
+// of the schema used for encoding, e.g. GENDER or KIND when encoding with [SchemaV3].
+type DowngradePolicy int
+
+const (
+	// Writes every field as is. Default.
+	DowngradeKeep DowngradePolicy = iota
+
+	// Drops properties not defined in the target version.
+	DowngradeDrop
+
+	// Translates properties into an equivalent representation of the target version
+	// and drops those without one:
+	//
+	//	- LABEL becomes LABEL parameter of ADR in 4.0 and back in 3.0 and 2.1.
+	//	- SORT-STRING becomes SORT-AS parameter of N in 4.0 and SORT-AS of N or ORG becomes
+	//	  SORT-STRING in 3.0.
+	//	- KIND, MEMBER, GENDER and ANNIVERSARY become their X- equivalents in 3.0 and 2.1.
+	DowngradeTranslate
+)
+
+// Sets how properties not defined in the schema version are handled. Defaults to [DowngradeKeep].
+func (e *Encoder) SetDowngradePolicy(policy DowngradePolicy) *Encoder {
+	e.downgradePolicy = policy
+	return e
+}
+
+func (e *Encoder) downgrade(fields []encodedField, version string) []encodedField {
+	if e.downgradePolicy == DowngradeKeep {
+		return fields
+	}
+	if e.downgradePolicy == DowngradeTranslate {
+		fields = translateFields(fields, version)
+	}
+
+	kept := make([]encodedField, 0, len(fields))
+	for _, f := range fields {
+		if propertyDefinedIn(f.name, version) {
+			kept = append(kept, f)
+		}
+	}
+	return kept
+}
+
+func translateFields(fields []encodedField, version string) []encodedField {
+	translated := make([]encodedField, 0, len(fields))
+
+	switch version {
+	case "4.0":
+		labels := []string{}
+		sortString := ""
+		for _, f := range fields {
+			switch canonicalPropertyName(f.name) {
+			case "LABEL":
+				_, value, _ := splitParamsValue(f.rest)
+				labels = append(labels, unescapeText(value))
+			case "SORT-STRING":
+				_, value, _ := splitParamsValue(f.rest)
+				sortString = unescapeText(value)
+			default:
+				translated = append(translated, f)
+			}
+		}
+		// Parameters are attached after all fields are collected because ADR and N
+		// may go after LABEL and SORT-STRING
+		for _, label := range labels {
+			translated = attachParam(translated, "ADR", "LABEL", label, ":;;;;;;")
+		}
+		if sortString != "" {
+			translated = attachSortAs(translated, sortString)
+		}
+	case "3.0", "2.1":
+		sortString, orgSortString := "", ""
+		for _, f := range fields {
+			name := canonicalPropertyName(f.name)
+			if name == "N" || name == "ORG" {
+				params, value, _ := splitParamsValue(f.rest)
+				params, sortAs, found := removeParam(params, "SORT-AS")
+				if found && name == "N" && sortString == "" {
+					sortString = strings.ReplaceAll(sortAs, ",", " ")
+				}
+				if found && name == "ORG" && orgSortString == "" {
+					orgSortString = strings.ReplaceAll(sortAs, ",", " ")
+				}
+				if found {
+					f.rest = params + ":" + value
+				}
+			}
+			if legacy, found := legacyPropertyNames[name]; found {
+				translated = append(translated, encodedField{legacy, f.rest})
+				continue
+			}
+			if name == "ADR" {
+				params, value, _ := splitParamsValue(f.rest)
+				params, label, found := removeParam(params, "LABEL")
+				translated = append(translated, encodedField{f.name, params + ":" + value})
+				if found {
+					translated = append(translated, encodedField{"LABEL", ":" + escapeText(label)})
+				}
+				continue
+			}
+			translated = append(translated, f)
+		}
+		if sortString == "" {
+			sortString = orgSortString
+		}
+		hasSortString := slices.ContainsFunc(translated, func(f encodedField) bool {
+			return canonicalPropertyName(f.name) == "SORT-STRING"
+		})
+		if sortString != "" && !hasSortString {
+			translated = append(translated, encodedField{"SORT-STRING", ":" + escapeText(sortString)})
+		}
+	default:
+		return fields
+	}
+	return translated
+}
+
+// Adds a parameter to the first property with a given name which does not have it yet.
+// New property with value is created if there is no such property.
+func attachParam(fields []encodedField, name string, param string, paramValue string, value string) []encodedField {
+	for i, f := range fields {
+		if canonicalPropertyName(f.name) != name {
+			continue
+		}
+		params, v, _ := splitParamsValue(f.rest)
+		if _, _, found := removeParam(params, param); found {
+			continue
+		}
+		fields[i].rest = params + ";" + param + "=" + quoteParamValue(paramValue) + ":" + v
+		return fields
+	}
+	return append(fields, encodedField{name, ";" + param + "=" + quoteParamValue(paramValue) + value})
+}
+
+// Adds SORT-AS parameter with SORT-STRING value to N.
+func attachSortAs(fields []encodedField, sortString string) []encodedField {
+	for i, f := range fields {
+		if canonicalPropertyName(f.name) != "N" {
+			continue
+		}
+		params, v, _ := splitParamsValue(f.rest)
+		fields[i].rest = params + ";SORT-AS=" + quoteParamValue(sortString) + ":" + v
+	}
+	return fields
+}
+
+// Removes parameter from a raw ";param=value..." string and returns its unquoted value.
+func removeParam(params string, name string) (string, string, bool) {
+	kept := strings.Builder{}
+	value := ""
+	found := false
+
+	for _, p := range splitParams(params) {
+		k, v, _ := strings.Cut(p, "=")
+		if !found && strings.EqualFold(k, name) {
+			value = unquoteParamValue(v)
+			found = true
+			continue
+		}
+		kept.WriteByte(';')
+		kept.WriteString(p)
+	}
+	return kept.String(), value, found
+}
+
+// Splits raw ";param=value;param=value" string at `;` which are not a part of quoted values.
+func splitParams(params string) []string {
+	parts := []string{}
+	quoted := false
+	start := 0
+	for i := 0; i < len(params); i++ {
+		switch params[i] {
+		case '"':
+			quoted = !quoted
+		case ';':
+			if !quoted {
+				if i > start {
+					parts = append(parts, params[start:i])
+				}
+				start = i + 1
+			}
+		}
+	}
+	if start < len(params) {
+		parts = append(parts, params[start:])
+	}
+	return parts
+}
+
+// Quotes parameter value and escapes characters as per https://datatracker.ietf.org/doc/html/rfc6868
+func quoteParamValue(s string) string {
+	r := strings.NewReplacer("^", "^^", "\r\n", "^n", "\n", "^n", `"`, "^'")
+	return `"` + r.Replace(s) + `"`
+}
+
+// Reverts [quoteParamValue].
+func unquoteParamValue(s string) string {
+	s = strings.TrimPrefix(strings.TrimSuffix(s, `"`), `"`)
+	r := strings.NewReplacer("^^", "^", "^n", "\n", "^N", "\n", "^'", `"`)
+	return r.Replace(s)
+}
+
+// Escapes TEXT value as per https://datatracker.ietf.org/doc/html/rfc6350#section-3.4
+func escapeText(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, "\r\n", `\n`, "\n", `\n`, ",", `\,`, ";", `\;`)
+	return r.Replace(s)
+}
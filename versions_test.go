@@ -0,0 +1,110 @@
+package vcard
+
+import (
+	"bytes"
+	"testing"
+)
+
+type VersionedUser struct {
+	FN          string
+	N           string
+	ADR         string
+	LABEL       string
+	KIND        string
+	GENDER      string
+	SORT_STRING string
+}
+
+var VersionedSchemaV4 = SchemaFor[VersionedUser]("4.0")
+var VersionedSchemaV3 = SchemaFor[VersionedUser]("3.0")
+
+func TestDowngradeKeepByDefault(t *testing.T) {
+
+	s := VersionedUser{FN: "Alex", N: "Alex;;;;", KIND: "individual"}
+
+	b, _ := MarshalSchema(s, VersionedSchemaV3)
+
+	assertEq(t, bytes.Contains(b, []byte("KIND:individual")), true)
+}
+
+func TestDowngradeDrop(t *testing.T) {
+
+	s := VersionedUser{
+		FN:     "Alex",
+		N:      "Alex;;;;",
+		ADR:    ";;Main St;;;;",
+		LABEL:  "Main St",
+		KIND:   "individual",
+		GENDER: "M",
+	}
+
+	var buf bytes.Buffer
+	err := NewEncoder(&buf).SetDowngradePolicy(DowngradeDrop).EncodeSchema(s, VersionedSchemaV3)
+
+	exp := `BEGIN:VCARD
+VERSION:3.0
+FN:Alex
+N:Alex;;;;
+ADR:;;Main St;;;;
+LABEL:Main St
+SORT_STRING:
+END:VCARD
+`
+	assertEq(t, err, nil)
+	assertStringsEq(t, buf.String(), crlfy(exp))
+}
+
+func TestDowngradeTranslateTo3(t *testing.T) {
+
+	s := VersionedUser{
+		FN:     "Alex",
+		N:      "Alex;;;;",
+		ADR:    `;LABEL="Main St^nSpringfield";TYPE=HOME:;;Main St;Springfield;;;`,
+		KIND:   "org",
+		GENDER: "M",
+	}
+
+	var buf bytes.Buffer
+	err := NewEncoder(&buf).SetDowngradePolicy(DowngradeTranslate).EncodeSchema(s, VersionedSchemaV3)
+
+	exp := `BEGIN:VCARD
+VERSION:3.0
+FN:Alex
+N:Alex;;;;
+ADR;TYPE=HOME:;;Main St;Springfield;;;
+LABEL:Main St\nSpringfield
+LABEL:
+X-ADDRESSBOOKSERVER-KIND:org
+X-GENDER:M
+SORT_STRING:
+END:VCARD
+`
+	assertEq(t, err, nil)
+	assertStringsEq(t, buf.String(), crlfy(exp))
+}
+
+func TestDowngradeTranslateTo4(t *testing.T) {
+
+	s := VersionedUser{
+		FN:          "Alex",
+		LABEL:       "Main St\\nSpringfield",
+		SORT_STRING: "Alex",
+		N:           "Alex;;;;",
+		ADR:         ";TYPE=HOME:;;Main St;Springfield;;;",
+	}
+
+	var buf bytes.Buffer
+	err := NewEncoder(&buf).SetDowngradePolicy(DowngradeTranslate).EncodeSchema(s, VersionedSchemaV4)
+
+	exp := `BEGIN:VCARD
+VERSION:4.0
+FN:Alex
+N;SORT-AS="Alex":Alex;;;;
+ADR;TYPE=HOME;LABEL="Main St^nSpringfield":;;Main St;Springfield;;;
+KIND:
+GENDER:
+END:VCARD
+`
+	assertEq(t, err, nil)
+	assertStringsEq(t, buf.String(), crlfy(exp))
+}
@@ -0,0 +1,61 @@
+package vcard
+
+import (
+	"errors"
+	"strings"
+)
+
+// Returned by a function passed to [Card.Walk] or [Transform] to delete the visited property.
+var DeleteProperty = errors.New("delete property")
+
+// Calls fn for every property of the card in order, so it can modify the property in place:
+//
+//	err := card.Walk(func(p *vcard.Property) error {
+//		if p.Name == "X-SKYPE-USERNAME" {
+//			p.Name = "X-SKYPE"
+//		}
+//		return nil
+//	})
+//
+// If fn returns [DeleteProperty] the property is removed from the card. Any other error stops
+// walking and is returned, keeping changes made so far. Names are upper-cased after fn returns.
+//
+// Properties of records nested into the card e.g. AGENT of vCard 2.1 are visited too, but BEGIN and
+// END lines of such records are not.
+func (c *Card) Walk(fn func(p *Property) error) error {
+	for i := 0; i < len(c.props); i++ {
+		p := &c.props[i]
+		if p.Name == "BEGIN" || p.Name == "END" {
+			continue
+		}
+		err := fn(p)
+		p.Name = strings.ToUpper(p.Name)
+		switch {
+		case errors.Is(err, DeleteProperty):
+			c.props = append(c.props[:i], c.props[i+1:]...)
+			i--
+		case err != nil:
+			return err
+		}
+	}
+	return nil
+}
+
+// Calls [Card.Walk] with fn for every card in place e.g. to fix a TYPE value across an address book:
+//
+//	err := vcard.Transform(cards, func(p *vcard.Property) error {
+//		if p.Name == "TEL" && slices.Contains(p.Params.Values("TYPE"), "mobile") {
+//			p.Params.Set("TYPE", "cell")
+//		}
+//		return nil
+//	})
+//
+// Stops at the first error of fn and returns it with the index of the card.
+func Transform(cards []Card, fn func(p *Property) error) error {
+	for i := range cards {
+		if err := cards[i].Walk(fn); err != nil {
+			return vCardErrf("error during transforming card %d: %w", i, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package vcard
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWalk(t *testing.T) {
+
+	card := Card{}
+	err := Unmarshal([]byte(crlfy(`BEGIN:VCARD
+VERSION:2.1
+N:Doe;John
+X-SKYPE-USERNAME:johndoe
+TEL;TYPE=mobile:555
+AGENT:
+BEGIN:VCARD
+VERSION:2.1
+N:Agent
+X-SKYPE-USERNAME:agent
+END:VCARD
+NOTE:secret
+END:VCARD
+`)), &card)
+	assertEq(t, err, nil)
+
+	visited := 0
+	err = card.Walk(func(p *Property) error {
+		visited++
+		switch p.Name {
+		case "X-SKYPE-USERNAME":
+			p.Name = "x-skype"
+		case "TEL":
+			p.Params.Set("TYPE", "cell")
+		case "NOTE":
+			return DeleteProperty
+		}
+		return nil
+	})
+	assertEq(t, err, nil)
+	assertEq(t, visited, 9)
+
+	b, err := Marshal(card)
+	assertEq(t, err, nil)
+	assertStringLinesEq(t, string(b), crlfy(`BEGIN:VCARD
+VERSION:2.1
+N:Doe;John
+X-SKYPE:johndoe
+TEL;TYPE=cell:555
+AGENT:
+BEGIN:VCARD
+VERSION:2.1
+N:Agent
+X-SKYPE:agent
+END:VCARD
+END:VCARD
+`))
+}
+
+func TestTransform(t *testing.T) {
+
+	cards := []Card{{}, {}}
+	cards[0].Add("FN", "Alex")
+	cards[1].Add("FN", "Bob")
+	cards[1].Add("NOTE", "stop")
+
+	stop := errors.New("stop")
+	err := Transform(cards, func(p *Property) error {
+		if p.Name == "NOTE" {
+			return stop
+		}
+		p.Value += "!"
+		return nil
+	})
+	assertErrIs(t, err, stop, "error during transforming card 1: stop")
+
+	fn, _ := cards[0].FN()
+	assertEq(t, fn, "Alex!")
+	fn, _ = cards[1].FN()
+	assertEq(t, fn, "Bob!")
+}